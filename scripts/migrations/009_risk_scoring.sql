@@ -0,0 +1,9 @@
+-- Migration: Additive risk scoring
+-- Each rule contributes a score when it matches; the sum is stored per transaction
+
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS score INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS risk_score INTEGER NOT NULL DEFAULT 0;
+
+-- Create index for risk score lookups
+CREATE INDEX IF NOT EXISTS idx_transactions_risk_score ON transactions(risk_score DESC);
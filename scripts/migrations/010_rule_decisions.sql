@@ -0,0 +1,6 @@
+-- Migration: Deterministic decision resolution
+-- Terminal rules stop evaluation; transactions record the rule that decided the outcome
+
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS stop_processing BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS decided_by_rule_id UUID;
@@ -0,0 +1,21 @@
+-- Migration: Rule backtesting
+-- Transactions keep the original event payload so candidate rules can be replayed against history
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS event_payload JSONB;
+
+CREATE TABLE IF NOT EXISTS backtests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    from_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    to_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    rules JSONB NOT NULL,
+    replace_rule_set BOOLEAN NOT NULL DEFAULT false,
+    sample_limit INTEGER NOT NULL DEFAULT 20,
+    result JSONB,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_backtests_created_at ON backtests(created_at DESC);
@@ -0,0 +1,9 @@
+-- Migration: Shadow (monitor-only) rules
+-- Shadow rules are evaluated on live traffic but never affect the decision
+
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS mode VARCHAR(20) NOT NULL DEFAULT 'active';
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS shadow_matched_rules JSONB NOT NULL DEFAULT '[]';
+
+-- Supports counting shadow rule matches by rule name
+CREATE INDEX IF NOT EXISTS idx_transactions_shadow_matched_rules ON transactions USING GIN (shadow_matched_rules);
@@ -0,0 +1,49 @@
+-- Migration: Immutable rule versions
+-- Every create, update and rollback stores a full snapshot of the rule
+
+CREATE TABLE IF NOT EXISTS rule_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    rule_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (rule_id, version)
+);
+
+-- Versions are kept after a rule is deleted so past decisions stay explainable
+CREATE INDEX IF NOT EXISTS idx_rule_versions_rule_id ON rule_versions(rule_id, version DESC);
+
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS version_id UUID;
+
+-- Backfill version 1 for rules created before versioning
+INSERT INTO rule_versions (rule_id, version, snapshot, created_at)
+SELECT r.id, r.version,
+       jsonb_build_object(
+           'id', r.id,
+           'name', r.name,
+           'description', COALESCE(r.description, ''),
+           'action', r.action,
+           'priority', r.priority,
+           'score', r.score,
+           'enabled', r.enabled,
+           'mode', r.mode,
+           'stop_processing', r.stop_processing,
+           'conditions', r.conditions,
+           'schema_id', r.schema_id,
+           'created_at', r.created_at,
+           'updated_at', r.updated_at
+       ),
+       r.updated_at
+FROM rules r
+WHERE r.version_id IS NULL
+ON CONFLICT (rule_id, version) DO NOTHING;
+
+UPDATE rules r
+SET version_id = v.id
+FROM rule_versions v
+WHERE v.rule_id = r.id AND v.version = r.version AND r.version_id IS NULL;
+
+ALTER TABLE rules ALTER COLUMN version_id SET NOT NULL;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS matched_rule_versions JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,19 @@
+-- Migration: Rule health
+-- Per-rule evaluation error counters flushed periodically by the workers
+
+CREATE TABLE IF NOT EXISTS rule_health (
+    rule_id UUID PRIMARY KEY,
+    evaluations BIGINT NOT NULL DEFAULT 0,
+    errors BIGINT NOT NULL DEFAULT 0,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_error_kind VARCHAR(50),
+    last_error_at TIMESTAMP WITH TIME ZONE,
+    last_match_at TIMESTAMP WITH TIME ZONE,
+    flagged BOOLEAN NOT NULL DEFAULT false,
+    auto_disabled BOOLEAN NOT NULL DEFAULT false,
+    flagged_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rule_health_flagged ON rule_health(flagged) WHERE flagged = true;
@@ -0,0 +1,7 @@
+-- Migration: Rule evaluation timeouts
+-- Rules may override the worker's per-rule timeout; transactions record timeouts and the fallback applied
+
+ALTER TABLE rules ADD COLUMN IF NOT EXISTS timeout_ms INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS timed_out_rules JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS timeout_fallback BOOLEAN NOT NULL DEFAULT false;
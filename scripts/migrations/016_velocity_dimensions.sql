@@ -0,0 +1,4 @@
+-- Migration: Velocity on arbitrary event fields
+-- Velocity helpers keyed by any schema path filter transactions with event_payload containment
+
+CREATE INDEX IF NOT EXISTS idx_transactions_event_payload ON transactions USING GIN (event_payload jsonb_path_ops);
@@ -0,0 +1,34 @@
+-- Migration: Per-account behavioural baselines
+-- Maintained incrementally when transactions are saved so baseline helpers never scan transactions
+
+CREATE TABLE IF NOT EXISTS account_profiles (
+    account VARCHAR(255) PRIMARY KEY,
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    transaction_count BIGINT NOT NULL DEFAULT 0
+);
+
+-- Daily amount sums; mean and standard deviation over N days are derived from N rows at most
+CREATE TABLE IF NOT EXISTS account_daily_stats (
+    account VARCHAR(255) NOT NULL,
+    day DATE NOT NULL,
+    transaction_count BIGINT NOT NULL DEFAULT 0,
+    amount_sum NUMERIC NOT NULL DEFAULT 0,
+    amount_sum_squares NUMERIC NOT NULL DEFAULT 0,
+    PRIMARY KEY (account, day)
+);
+
+-- Backfill from existing transactions
+INSERT INTO account_profiles (account, first_seen_at, last_seen_at, transaction_count)
+SELECT origin, MIN(created_at), MAX(created_at), COUNT(*)
+FROM transactions
+WHERE origin <> ''
+GROUP BY origin
+ON CONFLICT (account) DO NOTHING;
+
+INSERT INTO account_daily_stats (account, day, transaction_count, amount_sum, amount_sum_squares)
+SELECT origin, created_at::date, COUNT(*), SUM(amount), SUM(amount * amount)
+FROM transactions
+WHERE origin <> ''
+GROUP BY origin, created_at::date
+ON CONFLICT (account, day) DO NOTHING;
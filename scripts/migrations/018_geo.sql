@@ -0,0 +1,13 @@
+-- Migration: Geo helpers
+-- Last known account location for impossible travel checks, and country risk scores managed through the API
+
+ALTER TABLE account_profiles ADD COLUMN IF NOT EXISTS last_latitude DOUBLE PRECISION;
+ALTER TABLE account_profiles ADD COLUMN IF NOT EXISTS last_longitude DOUBLE PRECISION;
+ALTER TABLE account_profiles ADD COLUMN IF NOT EXISTS last_location_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS country_risk (
+    country_code CHAR(2) PRIMARY KEY,
+    score INTEGER NOT NULL CHECK (score BETWEEN 0 AND 100),
+    notes TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
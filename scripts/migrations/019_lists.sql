@@ -0,0 +1,23 @@
+-- Migration: Managed lists
+-- Typed blocklists, allowlists and watchlists referenced by name from expressions with inList
+
+CREATE TABLE IF NOT EXISTS lists (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    type VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Values are stored normalized for the list type, so lookups are exact matches
+CREATE TABLE IF NOT EXISTS list_entries (
+    list_id UUID NOT NULL REFERENCES lists(id) ON DELETE CASCADE,
+    value VARCHAR(1024) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (list_id, value)
+);
+
+CREATE INDEX IF NOT EXISTS idx_list_entries_expires_at ON list_entries(expires_at) WHERE expires_at IS NOT NULL;
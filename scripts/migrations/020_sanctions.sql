@@ -0,0 +1,32 @@
+-- Migration: Sanctions lists
+-- Entries imported from published sanctions lists (OFAC SDN, EU, UN) with their aliases,
+-- screened by the sanctionsHit helper and POST /api/v1/screening
+
+CREATE TABLE IF NOT EXISTS sanctions_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source VARCHAR(20) NOT NULL,
+    source_id VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    name TEXT NOT NULL,
+    countries TEXT[] NOT NULL DEFAULT '{}',
+    programs TEXT[] NOT NULL DEFAULT '{}',
+    remarks TEXT NOT NULL DEFAULT '',
+    imported_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (source, source_id)
+);
+
+-- Other names of an entry; the primary name is only stored on the entry
+CREATE TABLE IF NOT EXISTS sanctions_aliases (
+    entry_id UUID NOT NULL REFERENCES sanctions_entries(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    PRIMARY KEY (entry_id, position)
+);
+
+-- Last import of each source
+CREATE TABLE IF NOT EXISTS sanctions_imports (
+    source VARCHAR(20) PRIMARY KEY,
+    file_name TEXT NOT NULL,
+    entry_count INTEGER NOT NULL,
+    imported_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,25 @@
+-- Migration: Expression macros
+-- Named expression snippets shared across rules; each use is inlined when a rule is compiled
+
+CREATE TABLE IF NOT EXISTS macros (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    expression TEXT NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    version_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Versions are kept after a macro is deleted so past rule versions stay explainable
+CREATE TABLE IF NOT EXISTS macro_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    macro_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (macro_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_macro_versions_macro_id ON macro_versions(macro_id, version DESC);
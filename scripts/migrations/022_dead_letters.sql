@@ -0,0 +1,17 @@
+-- Migration: Dead letters
+-- Queued events the worker gave up on, kept with their raw payload for inspection and replay
+
+CREATE TABLE IF NOT EXISTS dead_letters (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    queue VARCHAR(100) NOT NULL,
+    external_id VARCHAR(255),
+    payload TEXT NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letters_failed_at ON dead_letters(failed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_dead_letters_external_id ON dead_letters(external_id);
@@ -0,0 +1,4 @@
+-- Migration: Schema queue lanes
+-- Transactions sent with a schema are queued on its lane unless the request names one
+
+ALTER TABLE event_schemas ADD COLUMN IF NOT EXISTS lane VARCHAR(50) NOT NULL DEFAULT 'post_transaction';
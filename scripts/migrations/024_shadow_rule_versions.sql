@@ -0,0 +1,27 @@
+-- Migration: Shadow rule versions
+-- Shadow matches are stored by rule version, so stats survive renames and never merge rules sharing a name
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS shadow_matched_rule_versions JSONB NOT NULL DEFAULT '[]';
+
+-- Backfill matches stored by name before this migration
+-- Each name maps to the version with that name current when the transaction was created,
+-- or to the earliest version with that name when the rule was versioned afterwards
+UPDATE transactions t
+SET shadow_matched_rule_versions = backfill.versions
+FROM (
+    SELECT legacy.id, jsonb_agg(v.id ORDER BY m.position) AS versions
+    FROM transactions legacy
+    CROSS JOIN LATERAL jsonb_array_elements_text(legacy.shadow_matched_rules) WITH ORDINALITY AS m(name, position)
+    CROSS JOIN LATERAL (
+        SELECT rv.id
+        FROM rule_versions rv
+        WHERE rv.snapshot->>'name' = m.name
+        ORDER BY COALESCE(rv.created_at <= legacy.created_at, FALSE) DESC,
+                 CASE WHEN rv.created_at <= legacy.created_at THEN rv.created_at END DESC NULLS LAST,
+                 rv.created_at
+        LIMIT 1
+    ) v
+    WHERE legacy.shadow_matched_rule_versions = '[]' AND legacy.shadow_matched_rules <> '[]'
+    GROUP BY legacy.id
+) backfill
+WHERE t.id = backfill.id;
@@ -10,7 +10,10 @@ import (
 	"github.com/algo-shield/algo-shield/src/api/internal/routes"
 	"github.com/algo-shield/algo-shield/src/pkg/config"
 	"github.com/algo-shield/algo-shield/src/pkg/database"
+	"github.com/algo-shield/algo-shield/src/pkg/queue"
 	"github.com/gofiber/fiber/v2"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
 func main() {
@@ -38,6 +41,32 @@ func main() {
 		}
 	}()
 
+	// Queue transactions and dead letter replays on the transport the workers consume
+	var publisher queue.Publisher
+	switch cfg.Worker.Queue.Transport {
+	case queue.TransportRedisStreams:
+		publisher = queue.NewStreamPublisher(redis.Client)
+	case queue.TransportNATS:
+		nc, err := nats.Connect(cfg.Worker.Queue.NATS.URL)
+		if err != nil {
+			log.Fatalf("Failed to connect to NATS: %v", err)
+		}
+		defer func() {
+			if err := nc.Drain(); err != nil {
+				log.Printf("Error draining NATS connection: %v", err)
+			}
+		}()
+
+		js, err := jetstream.New(nc)
+		if err != nil {
+			log.Fatalf("Failed to create JetStream context: %v", err)
+		}
+		publisher = queue.NewJetStreamPublisher(js)
+	default:
+		publisher = queue.NewListPublisher(redis.Client)
+	}
+	log.Printf("Publishing transactions to %s", cfg.Worker.Queue.Transport)
+
 	// Create Fiber app with optimized settings
 	app := fiber.New(fiber.Config{
 		Prefork:               false,
@@ -51,7 +80,7 @@ func main() {
 	})
 
 	// Setup routes
-	routes.Setup(app, db.Pool, redis.Client, cfg)
+	routes.Setup(app, db.Pool, redis.Client, publisher, cfg)
 
 	// Graceful shutdown
 	c := make(chan os.Signal, 1)
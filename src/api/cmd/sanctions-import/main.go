@@ -0,0 +1,135 @@
+// Command sanctions-import replaces the stored entries of a sanctions list with a published file.
+//
+// Usage:
+//
+//	sanctions-import -source ofac -file sdn.xml
+//	sanctions-import -source ofac -file sdn.csv -alt alt.csv -add add.csv
+//	sanctions-import -source eu -file 20240101-FULL-1_1.xml
+//	sanctions-import -source un -file consolidated.xml
+//
+// Files are read from the local disk, as published by OFAC, the EU and the UN.
+// Running API servers and workers reload their screening index once the import is committed.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/config"
+	"github.com/algo-shield/algo-shield/src/pkg/database"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/algo-shield/algo-shield/src/pkg/sanctions"
+	"github.com/redis/go-redis/v9"
+)
+
+// importTimeout bounds writing the entries, full lists hold tens of thousands of names
+const importTimeout = 5 * time.Minute
+
+func main() {
+	source := flag.String("source", "", "sanctions list: ofac, eu or un")
+	file := flag.String("file", "", "path of the published list")
+	format := flag.String("format", "", "file format: xml or csv (default from the file extension)")
+	alt := flag.String("alt", "", "OFAC CSV only: path of ALT.CSV with aliases")
+	add := flag.String("add", "", "OFAC CSV only: path of ADD.CSV with addresses")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("-file is required")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	entries, err := parseFile(models.SanctionsSource(*source), *format, *file, *alt, *add)
+	if err != nil {
+		log.Fatalf("Failed to parse %s: %v", *file, err)
+	}
+	// An empty result is almost always the wrong file and would wipe the stored list
+	if len(entries) == 0 {
+		log.Fatalf("No entries found in %s, nothing imported", *file)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := database.NewPostgresPool(cfg.GetDatabaseDSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Redis only announces the import; without it services pick the entries up on restart
+	var redisClient *redis.Client
+	if client, err := database.NewRedisClient(cfg.GetRedisAddr()); err != nil {
+		log.Printf("Redis not available, running services will not reload sanctions until restarted: %v", err)
+	} else {
+		redisClient = client.Client
+		defer func() {
+			if err := redisClient.Close(); err != nil {
+				log.Printf("Error closing Redis connection: %v", err)
+			}
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	repo := sanctions.NewPostgresRepository(db.Pool, redisClient)
+	if err := repo.ReplaceSource(ctx, models.SanctionsSource(*source), filepath.Base(*file), entries); err != nil {
+		log.Fatalf("Failed to import %s entries: %v", *source, err)
+	}
+
+	log.Printf("Imported %d %s entries from %s", len(entries), *source, *file)
+}
+
+// parseFile parses a published list in the format of its source
+func parseFile(source models.SanctionsSource, format, path, altPath, addPath string) ([]models.SanctionsEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	switch {
+	case source == models.SanctionsSourceOFAC && format == "xml":
+		return sanctions.ParseOFACXML(f)
+	case source == models.SanctionsSourceOFAC && format == "csv":
+		alt, closeAlt, err := openOptional(altPath)
+		if err != nil {
+			return nil, err
+		}
+		defer closeAlt()
+		add, closeAdd, err := openOptional(addPath)
+		if err != nil {
+			return nil, err
+		}
+		defer closeAdd()
+		return sanctions.ParseOFACCSV(f, alt, add)
+	case source == models.SanctionsSourceEU && format == "xml":
+		return sanctions.ParseEUXML(f)
+	case source == models.SanctionsSourceUN && format == "xml":
+		return sanctions.ParseUNXML(f)
+	}
+	return nil, fmt.Errorf("unsupported source %q and format %q; use ofac (xml or csv), eu (xml) or un (xml)", source, format)
+}
+
+// openOptional opens a file when a path is given, returning a nil reader otherwise
+func openOptional(path string) (io.Reader, func(), error) {
+	if path == "" {
+		return nil, func() {}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { _ = f.Close() }, nil
+}
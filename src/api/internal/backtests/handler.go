@@ -0,0 +1,139 @@
+package backtests
+
+import (
+	"context"
+	"errors"
+
+	"github.com/algo-shield/algo-shield/src/api/internal"
+	"github.com/algo-shield/algo-shield/src/api/internal/rules"
+	"github.com/algo-shield/algo-shield/src/api/internal/schemas"
+	"github.com/algo-shield/algo-shield/src/api/internal/shared/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Handler handles HTTP requests for backtests
+type Handler struct {
+	service ServiceInterface
+}
+
+// NewHandler creates a new backtest handler
+func NewHandler(service ServiceInterface) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// CreateBacktest handles POST /api/v1/backtests
+// The backtest runs asynchronously on a worker; poll GetBacktest for the result
+func (h *Handler) CreateBacktest(c *fiber.Ctx) error {
+	var req CreateBacktestRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := validation.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	backtest, err := h.service.Create(ctx, &req)
+	if err != nil {
+		return createErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(backtest)
+}
+
+// GetBacktest handles GET /api/v1/backtests/:id
+func (h *Handler) GetBacktest(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid backtest ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	backtest, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrBacktestNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Backtest not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch backtest",
+		})
+	}
+
+	return c.JSON(backtest)
+}
+
+// ListBacktests handles GET /api/v1/backtests
+func (h *Handler) ListBacktests(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 50)
+	offset := c.QueryInt("offset", 0)
+
+	// Validate pagination parameters
+	if err := validation.ValidateLimit(limit); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err := validation.ValidateOffset(offset); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	backtests, err := h.service.List(ctx, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch backtests",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"backtests": backtests,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// createErrorResponse maps a backtest creation error to a response
+// Candidate rules are rejected with the same errors as rule create and update
+func createErrorResponse(c *fiber.Ctx, err error) error {
+	var exprErr *rules.ExpressionError
+	switch {
+	case errors.Is(err, ErrInvalidTimeRange):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.As(err, &exprErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":         err.Error(),
+			"compile_error": exprErr.CompileError,
+		})
+	case errors.Is(err, rules.ErrExpressionNotString), errors.Is(err, rules.ErrExpressionNoSchema),
+		errors.Is(err, schemas.ErrSchemaNotFound):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create backtest",
+		})
+	}
+}
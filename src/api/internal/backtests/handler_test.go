@@ -0,0 +1,185 @@
+package backtests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/api/internal/rules"
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Handler_CreateBacktest_WhenValid_ThenReturnsAccepted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Post("/backtests", handler.CreateBacktest)
+
+	service.EXPECT().Create(gomock.Any(), gomock.Any()).
+		Return(&models.Backtest{ID: uuid.New(), Status: models.BacktestPending}, nil)
+
+	body, err := json.Marshal(testRequest())
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/backtests", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+}
+
+func Test_Handler_CreateBacktest_WhenNoRules_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(NewMockServiceInterface(ctrl))
+
+	app := fiber.New()
+	app.Post("/backtests", handler.CreateBacktest)
+
+	request := testRequest()
+	request.Rules = nil
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/backtests", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_CreateBacktest_WhenRuleDoesNotCompile_ThenReturnsCompileError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Post("/backtests", handler.CreateBacktest)
+
+	service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, &RuleValidationError{
+		RuleName: "Large Amount",
+		Err:      &rules.ExpressionError{CompileError: expressions.CompileError{Message: "unknown name amout"}},
+	})
+
+	body, err := json.Marshal(testRequest())
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/backtests", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var result map[string]any
+	respBody, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(respBody, &result))
+	assert.Contains(t, result["error"], "Large Amount")
+	assert.NotNil(t, result["compile_error"])
+}
+
+func Test_Handler_CreateBacktest_WhenServiceFails_ThenReturnsInternalError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Post("/backtests", handler.CreateBacktest)
+
+	service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+
+	body, err := json.Marshal(testRequest())
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/backtests", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+func Test_Handler_GetBacktest_WhenCompleted_ThenReturnsResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Get("/backtests/:id", handler.GetBacktest)
+
+	id := uuid.New()
+	service.EXPECT().GetByID(gomock.Any(), id).Return(&models.Backtest{
+		ID:     id,
+		Status: models.BacktestCompleted,
+		Result: &models.BacktestResult{Evaluated: 10, Changed: 2},
+	}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/backtests/"+id.String(), nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result models.Backtest
+	respBody, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(respBody, &result))
+	require.NotNil(t, result.Result)
+	assert.Equal(t, 2, result.Result.Changed)
+}
+
+func Test_Handler_GetBacktest_WhenNotFound_ThenReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Get("/backtests/:id", handler.GetBacktest)
+
+	service.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(nil, ErrBacktestNotFound)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/backtests/"+uuid.NewString(), nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Handler_ListBacktests_WhenCalled_ThenReturnsBacktests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Get("/backtests", handler.ListBacktests)
+
+	service.EXPECT().List(gomock.Any(), 50, 0).Return([]models.Backtest{{ID: uuid.New()}}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/backtests", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,210 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/backtests/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/backtests/repository.go -destination=src/api/internal/backtests/mock_repository_test.go -package=backtests
+//
+
+// Package backtests is a generated GoMock package.
+package backtests
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobStore is a mock of JobStore interface.
+type MockJobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockJobStoreMockRecorder is the mock recorder for MockJobStore.
+type MockJobStoreMockRecorder struct {
+	mock *MockJobStore
+}
+
+// NewMockJobStore creates a new mock instance.
+func NewMockJobStore(ctrl *gomock.Controller) *MockJobStore {
+	mock := &MockJobStore{ctrl: ctrl}
+	mock.recorder = &MockJobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobStore) EXPECT() *MockJobStoreMockRecorder {
+	return m.recorder
+}
+
+// CompleteBacktest mocks base method.
+func (m *MockJobStore) CompleteBacktest(ctx context.Context, id uuid.UUID, result *models.BacktestResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteBacktest", ctx, id, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteBacktest indicates an expected call of CompleteBacktest.
+func (mr *MockJobStoreMockRecorder) CompleteBacktest(ctx, id, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteBacktest", reflect.TypeOf((*MockJobStore)(nil).CompleteBacktest), ctx, id, result)
+}
+
+// FailBacktest mocks base method.
+func (m *MockJobStore) FailBacktest(ctx context.Context, id uuid.UUID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailBacktest", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailBacktest indicates an expected call of FailBacktest.
+func (mr *MockJobStoreMockRecorder) FailBacktest(ctx, id, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailBacktest", reflect.TypeOf((*MockJobStore)(nil).FailBacktest), ctx, id, reason)
+}
+
+// GetBacktest mocks base method.
+func (m *MockJobStore) GetBacktest(ctx context.Context, id uuid.UUID) (*models.Backtest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBacktest", ctx, id)
+	ret0, _ := ret[0].(*models.Backtest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBacktest indicates an expected call of GetBacktest.
+func (mr *MockJobStoreMockRecorder) GetBacktest(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBacktest", reflect.TypeOf((*MockJobStore)(nil).GetBacktest), ctx, id)
+}
+
+// StartBacktest mocks base method.
+func (m *MockJobStore) StartBacktest(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartBacktest", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartBacktest indicates an expected call of StartBacktest.
+func (mr *MockJobStoreMockRecorder) StartBacktest(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartBacktest", reflect.TypeOf((*MockJobStore)(nil).StartBacktest), ctx, id)
+}
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CompleteBacktest mocks base method.
+func (m *MockRepository) CompleteBacktest(ctx context.Context, id uuid.UUID, result *models.BacktestResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteBacktest", ctx, id, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteBacktest indicates an expected call of CompleteBacktest.
+func (mr *MockRepositoryMockRecorder) CompleteBacktest(ctx, id, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteBacktest", reflect.TypeOf((*MockRepository)(nil).CompleteBacktest), ctx, id, result)
+}
+
+// CreateBacktest mocks base method.
+func (m *MockRepository) CreateBacktest(ctx context.Context, backtest *models.Backtest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBacktest", ctx, backtest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBacktest indicates an expected call of CreateBacktest.
+func (mr *MockRepositoryMockRecorder) CreateBacktest(ctx, backtest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBacktest", reflect.TypeOf((*MockRepository)(nil).CreateBacktest), ctx, backtest)
+}
+
+// FailBacktest mocks base method.
+func (m *MockRepository) FailBacktest(ctx context.Context, id uuid.UUID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailBacktest", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailBacktest indicates an expected call of FailBacktest.
+func (mr *MockRepositoryMockRecorder) FailBacktest(ctx, id, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailBacktest", reflect.TypeOf((*MockRepository)(nil).FailBacktest), ctx, id, reason)
+}
+
+// GetBacktest mocks base method.
+func (m *MockRepository) GetBacktest(ctx context.Context, id uuid.UUID) (*models.Backtest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBacktest", ctx, id)
+	ret0, _ := ret[0].(*models.Backtest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBacktest indicates an expected call of GetBacktest.
+func (mr *MockRepositoryMockRecorder) GetBacktest(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBacktest", reflect.TypeOf((*MockRepository)(nil).GetBacktest), ctx, id)
+}
+
+// ListBacktests mocks base method.
+func (m *MockRepository) ListBacktests(ctx context.Context, limit, offset int) ([]models.Backtest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBacktests", ctx, limit, offset)
+	ret0, _ := ret[0].([]models.Backtest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBacktests indicates an expected call of ListBacktests.
+func (mr *MockRepositoryMockRecorder) ListBacktests(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBacktests", reflect.TypeOf((*MockRepository)(nil).ListBacktests), ctx, limit, offset)
+}
+
+// StartBacktest mocks base method.
+func (m *MockRepository) StartBacktest(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartBacktest", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartBacktest indicates an expected call of StartBacktest.
+func (mr *MockRepositoryMockRecorder) StartBacktest(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartBacktest", reflect.TypeOf((*MockRepository)(nil).StartBacktest), ctx, id)
+}
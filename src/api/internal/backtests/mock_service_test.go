@@ -0,0 +1,170 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/api/internal/backtests/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/api/internal/backtests/service.go -destination=src/api/internal/backtests/mock_service_test.go -package=backtests
+//
+
+// Package backtests is a generated GoMock package.
+package backtests
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	uuid "github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRuleValidator is a mock of RuleValidator interface.
+type MockRuleValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuleValidatorMockRecorder
+	isgomock struct{}
+}
+
+// MockRuleValidatorMockRecorder is the mock recorder for MockRuleValidator.
+type MockRuleValidatorMockRecorder struct {
+	mock *MockRuleValidator
+}
+
+// NewMockRuleValidator creates a new mock instance.
+func NewMockRuleValidator(ctrl *gomock.Controller) *MockRuleValidator {
+	mock := &MockRuleValidator{ctrl: ctrl}
+	mock.recorder = &MockRuleValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRuleValidator) EXPECT() *MockRuleValidatorMockRecorder {
+	return m.recorder
+}
+
+// ValidateRule mocks base method.
+func (m *MockRuleValidator) ValidateRule(ctx context.Context, rule *models.Rule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateRule", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateRule indicates an expected call of ValidateRule.
+func (mr *MockRuleValidatorMockRecorder) ValidateRule(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateRule", reflect.TypeOf((*MockRuleValidator)(nil).ValidateRule), ctx, rule)
+}
+
+// MockQueuePusher is a mock of QueuePusher interface.
+type MockQueuePusher struct {
+	ctrl     *gomock.Controller
+	recorder *MockQueuePusherMockRecorder
+	isgomock struct{}
+}
+
+// MockQueuePusherMockRecorder is the mock recorder for MockQueuePusher.
+type MockQueuePusherMockRecorder struct {
+	mock *MockQueuePusher
+}
+
+// NewMockQueuePusher creates a new mock instance.
+func NewMockQueuePusher(ctrl *gomock.Controller) *MockQueuePusher {
+	mock := &MockQueuePusher{ctrl: ctrl}
+	mock.recorder = &MockQueuePusherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQueuePusher) EXPECT() *MockQueuePusherMockRecorder {
+	return m.recorder
+}
+
+// LPush mocks base method.
+func (m *MockQueuePusher) LPush(ctx context.Context, key string, values ...any) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LPush", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// LPush indicates an expected call of LPush.
+func (mr *MockQueuePusherMockRecorder) LPush(ctx, key any, values ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPush", reflect.TypeOf((*MockQueuePusher)(nil).LPush), varargs...)
+}
+
+// MockServiceInterface is a mock of ServiceInterface interface.
+type MockServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceInterfaceMockRecorder is the mock recorder for MockServiceInterface.
+type MockServiceInterfaceMockRecorder struct {
+	mock *MockServiceInterface
+}
+
+// NewMockServiceInterface creates a new mock instance.
+func NewMockServiceInterface(ctrl *gomock.Controller) *MockServiceInterface {
+	mock := &MockServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceInterface) EXPECT() *MockServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockServiceInterface) Create(ctx context.Context, req *CreateBacktestRequest) (*models.Backtest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*models.Backtest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockServiceInterfaceMockRecorder) Create(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockServiceInterface)(nil).Create), ctx, req)
+}
+
+// GetByID mocks base method.
+func (m *MockServiceInterface) GetByID(ctx context.Context, id uuid.UUID) (*models.Backtest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.Backtest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockServiceInterfaceMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockServiceInterface)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockServiceInterface) List(ctx context.Context, limit, offset int) ([]models.Backtest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
+	ret0, _ := ret[0].([]models.Backtest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockServiceInterfaceMockRecorder) List(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockServiceInterface)(nil).List), ctx, limit, offset)
+}
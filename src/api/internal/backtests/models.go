@@ -0,0 +1,19 @@
+package backtests
+
+import (
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+)
+
+// DefaultSampleLimit is the number of sample matches kept when the request does not set one
+const DefaultSampleLimit = 20
+
+// CreateBacktestRequest is the request body for starting a backtest
+type CreateBacktestRequest struct {
+	From           time.Time     `json:"from" validate:"required"`
+	To             time.Time     `json:"to" validate:"required"`
+	Rules          []models.Rule `json:"rules" validate:"required,min=1,max=100,dive"`
+	ReplaceRuleSet bool          `json:"replace_rule_set"` // Replay only the candidates instead of adding them to the active rules
+	SampleLimit    int           `json:"sample_limit" validate:"gte=0,lte=100"`
+}
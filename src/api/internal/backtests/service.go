@@ -0,0 +1,134 @@
+package backtests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/backtests"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// Service errors
+var (
+	ErrBacktestNotFound = errors.New("backtest not found")
+	ErrInvalidTimeRange = errors.New("from must be before to")
+)
+
+// RuleValidationError is returned when a candidate rule is rejected
+type RuleValidationError struct {
+	RuleName string
+	Err      error
+}
+
+func (e *RuleValidationError) Error() string {
+	return fmt.Sprintf("rule %q: %v", e.RuleName, e.Err)
+}
+
+func (e *RuleValidationError) Unwrap() error {
+	return e.Err
+}
+
+// RuleValidator compile-checks candidate rules before they are queued
+type RuleValidator interface {
+	ValidateRule(ctx context.Context, rule *models.Rule) error
+}
+
+// QueuePusher defines the interface for pushing backtest jobs to the worker queue
+type QueuePusher interface {
+	LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+}
+
+// ServiceInterface defines the interface for backtest business logic
+type ServiceInterface interface {
+	Create(ctx context.Context, req *CreateBacktestRequest) (*models.Backtest, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Backtest, error)
+	List(ctx context.Context, limit, offset int) ([]models.Backtest, error)
+}
+
+// Service provides business logic for backtest operations
+type Service struct {
+	repo      backtests.Repository
+	queue     QueuePusher
+	validator RuleValidator
+}
+
+// NewService creates a new backtest service with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewService(repo backtests.Repository, queue QueuePusher, validator RuleValidator) *Service {
+	return &Service{
+		repo:      repo,
+		queue:     queue,
+		validator: validator,
+	}
+}
+
+// Create validates the candidate rules, stores a pending backtest and queues it for a worker
+func (s *Service) Create(ctx context.Context, req *CreateBacktestRequest) (*models.Backtest, error) {
+	if !req.From.Before(req.To) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	rules := make([]models.Rule, len(req.Rules))
+	copy(rules, req.Rules)
+	for i := range rules {
+		if rules[i].ID == uuid.Nil {
+			rules[i].ID = uuid.New()
+		}
+		if err := s.validator.ValidateRule(ctx, &rules[i]); err != nil {
+			return nil, &RuleValidationError{RuleName: rules[i].Name, Err: err}
+		}
+	}
+
+	sampleLimit := req.SampleLimit
+	if sampleLimit == 0 {
+		sampleLimit = DefaultSampleLimit
+	}
+
+	backtest := &models.Backtest{
+		ID:             uuid.New(),
+		Status:         models.BacktestPending,
+		From:           req.From,
+		To:             req.To,
+		Rules:          rules,
+		ReplaceRuleSet: req.ReplaceRuleSet,
+		SampleLimit:    sampleLimit,
+		CreatedAt:      time.Now(),
+	}
+
+	if err := s.repo.CreateBacktest(ctx, backtest); err != nil {
+		return nil, err
+	}
+
+	if err := s.queue.LPush(ctx, models.BacktestQueueKey, backtest.ID.String()).Err(); err != nil {
+		// Never leave a job pending that no worker will pick up
+		if failErr := s.repo.FailBacktest(ctx, backtest.ID, "failed to queue backtest"); failErr != nil {
+			log.Printf("Failed to mark backtest %s as failed: %v", backtest.ID, failErr)
+		}
+		return nil, err
+	}
+
+	return backtest, nil
+}
+
+// GetByID retrieves a backtest and, once finished, its result
+func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*models.Backtest, error) {
+	backtest, err := s.repo.GetBacktest(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBacktestNotFound
+		}
+		return nil, err
+	}
+	return backtest, nil
+}
+
+// List returns backtests, most recent first
+func (s *Service) List(ctx context.Context, limit, offset int) ([]models.Backtest, error) {
+	return s.repo.ListBacktests(ctx, limit, offset)
+}
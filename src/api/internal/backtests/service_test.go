@@ -0,0 +1,123 @@
+package backtests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func testRequest() *CreateBacktestRequest {
+	schemaID := uuid.New()
+	return &CreateBacktestRequest{
+		From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		Rules: []models.Rule{{
+			Name:       "Large Amount",
+			Action:     models.ActionBlock,
+			SchemaID:   &schemaID,
+			Conditions: map[string]any{"custom_expression": "amount > 10000"},
+		}},
+	}
+}
+
+func Test_Service_Create_WhenValid_ThenStoresAndQueuesBacktest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	queue := NewMockQueuePusher(ctrl)
+	validator := NewMockRuleValidator(ctrl)
+	service := NewService(repo, queue, validator)
+
+	validator.EXPECT().ValidateRule(gomock.Any(), gomock.Any()).Return(nil)
+	repo.EXPECT().CreateBacktest(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, backtest *models.Backtest) error {
+			assert.Equal(t, models.BacktestPending, backtest.Status)
+			assert.Equal(t, DefaultSampleLimit, backtest.SampleLimit)
+			require.Len(t, backtest.Rules, 1)
+			assert.NotEqual(t, uuid.Nil, backtest.Rules[0].ID, "candidates get an ID so matches can be attributed")
+			return nil
+		})
+	queue.EXPECT().LPush(gomock.Any(), models.BacktestQueueKey, gomock.Any()).
+		Return(redis.NewIntCmd(context.Background()))
+
+	backtest, err := service.Create(context.Background(), testRequest())
+
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, backtest.ID)
+}
+
+func Test_Service_Create_WhenTimeRangeInverted_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewService(NewMockRepository(ctrl), NewMockQueuePusher(ctrl), NewMockRuleValidator(ctrl))
+	req := testRequest()
+	req.From, req.To = req.To, req.From
+
+	_, err := service.Create(context.Background(), req)
+
+	assert.ErrorIs(t, err, ErrInvalidTimeRange)
+}
+
+func Test_Service_Create_WhenRuleInvalid_ThenReturnsRuleValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validator := NewMockRuleValidator(ctrl)
+	service := NewService(NewMockRepository(ctrl), NewMockQueuePusher(ctrl), validator)
+	validationErr := errors.New("expression compile error")
+
+	validator.EXPECT().ValidateRule(gomock.Any(), gomock.Any()).Return(validationErr)
+
+	_, err := service.Create(context.Background(), testRequest())
+
+	var ruleErr *RuleValidationError
+	require.ErrorAs(t, err, &ruleErr)
+	assert.Equal(t, "Large Amount", ruleErr.RuleName)
+	assert.ErrorIs(t, err, validationErr)
+}
+
+func Test_Service_Create_WhenQueueFails_ThenMarksBacktestFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	queue := NewMockQueuePusher(ctrl)
+	validator := NewMockRuleValidator(ctrl)
+	service := NewService(repo, queue, validator)
+	cmd := redis.NewIntCmd(context.Background())
+	cmd.SetErr(errors.New("redis down"))
+
+	validator.EXPECT().ValidateRule(gomock.Any(), gomock.Any()).Return(nil)
+	repo.EXPECT().CreateBacktest(gomock.Any(), gomock.Any()).Return(nil)
+	queue.EXPECT().LPush(gomock.Any(), models.BacktestQueueKey, gomock.Any()).Return(cmd)
+	repo.EXPECT().FailBacktest(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := service.Create(context.Background(), testRequest())
+
+	assert.Error(t, err)
+}
+
+func Test_Service_GetByID_WhenNotFound_ThenReturnsErrBacktestNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	service := NewService(repo, nil, nil)
+
+	repo.EXPECT().GetBacktest(gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows)
+
+	_, err := service.GetByID(context.Background(), uuid.New())
+
+	assert.ErrorIs(t, err, ErrBacktestNotFound)
+}
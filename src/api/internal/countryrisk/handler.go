@@ -0,0 +1,124 @@
+package countryrisk
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/api/internal"
+	"github.com/algo-shield/algo-shield/src/api/internal/shared/validation"
+	"github.com/algo-shield/algo-shield/src/pkg/geo"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+)
+
+// Handler handles HTTP requests for the country risk table used by the countryRisk helper
+type Handler struct {
+	repo geo.CountryRiskRepository
+}
+
+// NewHandler creates a new country risk handler with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewHandler(repo geo.CountryRiskRepository) *Handler {
+	return &Handler{
+		repo: repo,
+	}
+}
+
+// UpsertCountryRiskRequest is the body of PUT /api/v1/country-risk/:code
+type UpsertCountryRiskRequest struct {
+	Score int    `json:"score"`
+	Notes string `json:"notes"`
+}
+
+// ListCountryRisks handles GET /api/v1/country-risk
+func (h *Handler) ListCountryRisks(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	risks, err := h.repo.ListCountryRisks(ctx)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch country risk scores",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"countries": risks,
+	})
+}
+
+// GetCountryRisk handles GET /api/v1/country-risk/:code
+func (h *Handler) GetCountryRisk(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	risk, err := h.repo.GetCountryRisk(ctx, c.Params("code"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Country risk not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch country risk",
+		})
+	}
+
+	return c.JSON(risk)
+}
+
+// UpsertCountryRisk handles PUT /api/v1/country-risk/:code
+// Workers pick up the new score on their next rules reload
+func (h *Handler) UpsertCountryRisk(c *fiber.Ctx) error {
+	var req UpsertCountryRiskRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	risk := models.CountryRisk{
+		CountryCode: geo.NormalizeCountryCode(c.Params("code")),
+		Score:       req.Score,
+		Notes:       req.Notes,
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := validation.ValidateStruct(&risk); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	if err := h.repo.UpsertCountryRisk(ctx, &risk); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save country risk",
+		})
+	}
+
+	return c.JSON(risk)
+}
+
+// DeleteCountryRisk handles DELETE /api/v1/country-risk/:code
+func (h *Handler) DeleteCountryRisk(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	if err := h.repo.DeleteCountryRisk(ctx, c.Params("code")); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Country risk not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete country risk",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,153 @@
+package countryrisk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Handler_ListCountryRisks_WhenRepositorySucceeds_ThenReturnsCountries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockCountryRiskRepository(ctrl)
+	handler := NewHandler(repo)
+
+	app := fiber.New()
+	app.Get("/country-risk", handler.ListCountryRisks)
+
+	repo.EXPECT().ListCountryRisks(gomock.Any()).Return([]models.CountryRisk{{CountryCode: "KP", Score: 100}}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/country-risk", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Countries []models.CountryRisk `json:"countries"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Countries, 1)
+	assert.Equal(t, 100, result.Countries[0].Score)
+}
+
+func Test_Handler_GetCountryRisk_WhenNotFound_ThenReturns404(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockCountryRiskRepository(ctrl)
+	handler := NewHandler(repo)
+
+	app := fiber.New()
+	app.Get("/country-risk/:code", handler.GetCountryRisk)
+
+	repo.EXPECT().GetCountryRisk(gomock.Any(), "ZZ").Return(nil, pgx.ErrNoRows)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/country-risk/ZZ", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Handler_UpsertCountryRisk_WhenValid_ThenSavesNormalizedCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockCountryRiskRepository(ctrl)
+	handler := NewHandler(repo)
+
+	app := fiber.New()
+	app.Put("/country-risk/:code", handler.UpsertCountryRisk)
+
+	repo.EXPECT().UpsertCountryRisk(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, risk *models.CountryRisk) error {
+			assert.Equal(t, "BR", risk.CountryCode)
+			assert.Equal(t, 40, risk.Score)
+			return nil
+		},
+	)
+
+	req := httptest.NewRequest("PUT", "/country-risk/br", bytes.NewBufferString(`{"score": 40, "notes": "medium"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func Test_Handler_UpsertCountryRisk_WhenInvalid_ThenReturns400(t *testing.T) {
+	cases := map[string]string{
+		"unknown country": "/country-risk/XX",
+		"score too high":  "/country-risk/BR",
+	}
+	bodies := map[string]string{
+		"unknown country": `{"score": 10}`,
+		"score too high":  `{"score": 101}`,
+	}
+
+	for name, path := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler := NewHandler(NewMockCountryRiskRepository(ctrl))
+
+			app := fiber.New()
+			app.Put("/country-risk/:code", handler.UpsertCountryRisk)
+
+			req := httptest.NewRequest("PUT", path, bytes.NewBufferString(bodies[name]))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func Test_Handler_DeleteCountryRisk_WhenNotFound_ThenReturns404(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockCountryRiskRepository(ctrl)
+	handler := NewHandler(repo)
+
+	app := fiber.New()
+	app.Delete("/country-risk/:code", handler.DeleteCountryRisk)
+
+	repo.EXPECT().DeleteCountryRisk(gomock.Any(), "BR").Return(pgx.ErrNoRows)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/country-risk/BR", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Handler_DeleteCountryRisk_WhenRepositoryFails_ThenReturns500(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockCountryRiskRepository(ctrl)
+	handler := NewHandler(repo)
+
+	app := fiber.New()
+	app.Delete("/country-risk/:code", handler.DeleteCountryRisk)
+
+	repo.EXPECT().DeleteCountryRisk(gomock.Any(), "BR").Return(errors.New("db down"))
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/country-risk/BR", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/geo/country_risk_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/geo/country_risk_repository.go -destination=src/api/internal/countryrisk/mock_repository_test.go -package=countryrisk -exclude_interfaces=CountryRiskReader
+//
+
+// Package countryrisk is a generated GoMock package.
+package countryrisk
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCountryRiskRepository is a mock of CountryRiskRepository interface.
+type MockCountryRiskRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCountryRiskRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCountryRiskRepositoryMockRecorder is the mock recorder for MockCountryRiskRepository.
+type MockCountryRiskRepositoryMockRecorder struct {
+	mock *MockCountryRiskRepository
+}
+
+// NewMockCountryRiskRepository creates a new mock instance.
+func NewMockCountryRiskRepository(ctrl *gomock.Controller) *MockCountryRiskRepository {
+	mock := &MockCountryRiskRepository{ctrl: ctrl}
+	mock.recorder = &MockCountryRiskRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCountryRiskRepository) EXPECT() *MockCountryRiskRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountryRiskScore mocks base method.
+func (m *MockCountryRiskRepository) CountryRiskScore(ctx context.Context, code string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountryRiskScore", ctx, code)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountryRiskScore indicates an expected call of CountryRiskScore.
+func (mr *MockCountryRiskRepositoryMockRecorder) CountryRiskScore(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountryRiskScore", reflect.TypeOf((*MockCountryRiskRepository)(nil).CountryRiskScore), ctx, code)
+}
+
+// DeleteCountryRisk mocks base method.
+func (m *MockCountryRiskRepository) DeleteCountryRisk(ctx context.Context, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCountryRisk", ctx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCountryRisk indicates an expected call of DeleteCountryRisk.
+func (mr *MockCountryRiskRepositoryMockRecorder) DeleteCountryRisk(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCountryRisk", reflect.TypeOf((*MockCountryRiskRepository)(nil).DeleteCountryRisk), ctx, code)
+}
+
+// GetCountryRisk mocks base method.
+func (m *MockCountryRiskRepository) GetCountryRisk(ctx context.Context, code string) (*models.CountryRisk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountryRisk", ctx, code)
+	ret0, _ := ret[0].(*models.CountryRisk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountryRisk indicates an expected call of GetCountryRisk.
+func (mr *MockCountryRiskRepositoryMockRecorder) GetCountryRisk(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountryRisk", reflect.TypeOf((*MockCountryRiskRepository)(nil).GetCountryRisk), ctx, code)
+}
+
+// ListCountryRisks mocks base method.
+func (m *MockCountryRiskRepository) ListCountryRisks(ctx context.Context) ([]models.CountryRisk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCountryRisks", ctx)
+	ret0, _ := ret[0].([]models.CountryRisk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCountryRisks indicates an expected call of ListCountryRisks.
+func (mr *MockCountryRiskRepositoryMockRecorder) ListCountryRisks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCountryRisks", reflect.TypeOf((*MockCountryRiskRepository)(nil).ListCountryRisks), ctx)
+}
+
+// UpsertCountryRisk mocks base method.
+func (m *MockCountryRiskRepository) UpsertCountryRisk(ctx context.Context, risk *models.CountryRisk) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCountryRisk", ctx, risk)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertCountryRisk indicates an expected call of UpsertCountryRisk.
+func (mr *MockCountryRiskRepositoryMockRecorder) UpsertCountryRisk(ctx, risk any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCountryRisk", reflect.TypeOf((*MockCountryRiskRepository)(nil).UpsertCountryRisk), ctx, risk)
+}
@@ -0,0 +1,243 @@
+package deadletters
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/api/internal"
+	"github.com/algo-shield/algo-shield/src/api/internal/shared/validation"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Handler handles HTTP requests for dead letters
+type Handler struct {
+	service ServiceInterface
+}
+
+// NewHandler creates a new dead letter handler
+func NewHandler(service ServiceInterface) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// ListDeadLetters handles GET /api/v1/dead-letters
+// Accepts the reason, external_id, from and to filters
+func (h *Handler) ListDeadLetters(c *fiber.Ctx) error {
+	filter, err := parseFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	limit := c.QueryInt("limit", 50)
+	offset := c.QueryInt("offset", 0)
+
+	// Validate pagination parameters
+	if err := validation.ValidateLimit(limit); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err := validation.ValidateOffset(offset); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	letters, err := h.service.List(ctx, filter, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch dead letters",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"dead_letters": letters,
+		"limit":        limit,
+		"offset":       offset,
+	})
+}
+
+// GetDeadLetter handles GET /api/v1/dead-letters/:id
+func (h *Handler) GetDeadLetter(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid dead letter ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	letter, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		return deadLetterError(c, err, "Failed to fetch dead letter")
+	}
+
+	return c.JSON(letter)
+}
+
+// ReplayDeadLetter handles POST /api/v1/dead-letters/:id/replay
+func (h *Handler) ReplayDeadLetter(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid dead letter ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	if err := h.service.Replay(ctx, id); err != nil {
+		return deadLetterError(c, err, "Failed to replay dead letter")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"replayed": 1,
+	})
+}
+
+// ReplayDeadLetters handles POST /api/v1/dead-letters/replay
+// Replays the dead letters matching the filters, or every one with all=true
+func (h *Handler) ReplayDeadLetters(c *fiber.Ctx) error {
+	filter, err := parseFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Large replays take longer than a regular request
+	ctx, cancel := context.WithTimeout(c.Context(), 4*internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	replayed, err := h.service.ReplayMatching(ctx, filter, c.QueryBool("all"))
+	if err != nil {
+		if errors.Is(err, ErrFilterRequired) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		// Report the progress so the replay can be resumed
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":    "Failed to replay dead letters",
+			"replayed": replayed,
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"replayed": replayed,
+	})
+}
+
+// DeleteDeadLetter handles DELETE /api/v1/dead-letters/:id
+func (h *Handler) DeleteDeadLetter(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid dead letter ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		return deadLetterError(c, err, "Failed to delete dead letter")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// PurgeDeadLetters handles DELETE /api/v1/dead-letters
+// Removes the dead letters matching the filters, or every one with all=true
+func (h *Handler) PurgeDeadLetters(c *fiber.Ctx) error {
+	filter, err := parseFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	purged, err := h.service.Purge(ctx, filter, c.QueryBool("all"))
+	if err != nil {
+		return deadLetterError(c, err, "Failed to purge dead letters")
+	}
+
+	return c.JSON(fiber.Map{
+		"purged": purged,
+	})
+}
+
+// parseFilter reads the reason, external_id and RFC 3339 from and to query parameters
+func parseFilter(c *fiber.Ctx) (models.DeadLetterFilter, error) {
+	filter := models.DeadLetterFilter{
+		Reason:     models.DeadLetterReason(c.Query("reason")),
+		ExternalID: c.Query("external_id"),
+	}
+
+	switch filter.Reason {
+	case "", models.DeadLetterProcessingFailed, models.DeadLetterInvalidPayload:
+	default:
+		return filter, errors.New("reason must be processing_failed or invalid_payload")
+	}
+
+	var err error
+	if filter.From, err = parseTime(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTime(c, "to"); err != nil {
+		return filter, err
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errors.New("from must be before to")
+	}
+
+	return filter, nil
+}
+
+// parseTime reads an optional RFC 3339 query parameter
+func parseTime(c *fiber.Ctx, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, errors.New(name + " must be an RFC 3339 timestamp")
+	}
+	return &parsed, nil
+}
+
+// deadLetterError maps a service error to a response
+func deadLetterError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, ErrDeadLetterNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Dead letter not found",
+		})
+	case errors.Is(err, ErrFilterRequired):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": fallback,
+		})
+	}
+}
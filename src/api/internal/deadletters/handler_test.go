@@ -0,0 +1,176 @@
+package deadletters
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Handler_ListDeadLetters_WhenFiltered_ThenPassesFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Get("/dead-letters", handler.ListDeadLetters)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	service.EXPECT().List(gomock.Any(), gomock.Any(), 10, 0).
+		DoAndReturn(func(_ any, filter models.DeadLetterFilter, _, _ int) ([]models.DeadLetter, error) {
+			assert.Equal(t, models.DeadLetterProcessingFailed, filter.Reason)
+			assert.Equal(t, "ext-1", filter.ExternalID)
+			require.NotNil(t, filter.From)
+			assert.True(t, from.Equal(*filter.From))
+			assert.Nil(t, filter.To)
+			return []models.DeadLetter{{ID: uuid.New(), ExternalID: "ext-1"}}, nil
+		})
+
+	req := httptest.NewRequest("GET", "/dead-letters?reason=processing_failed&external_id=ext-1&from=2025-01-01T00:00:00Z&limit=10", nil)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		DeadLetters []models.DeadLetter `json:"dead_letters"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.DeadLetters, 1)
+}
+
+func Test_Handler_ListDeadLetters_WhenUnknownReason_ThenReturns400(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(NewMockServiceInterface(ctrl))
+
+	app := fiber.New()
+	app.Get("/dead-letters", handler.ListDeadLetters)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/dead-letters?reason=timeout", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_GetDeadLetter_WhenMissing_ThenReturns404(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Get("/dead-letters/:id", handler.GetDeadLetter)
+
+	service.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(nil, ErrDeadLetterNotFound)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/dead-letters/"+uuid.NewString(), nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Handler_ReplayDeadLetter_WhenReplayed_ThenReturns202(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Post("/dead-letters/:id/replay", handler.ReplayDeadLetter)
+
+	id := uuid.New()
+	service.EXPECT().Replay(gomock.Any(), id).Return(nil)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/dead-letters/"+id.String()+"/replay", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+}
+
+func Test_Handler_ReplayDeadLetters_WhenAll_ThenReturnsCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Post("/dead-letters/replay", handler.ReplayDeadLetters)
+
+	service.EXPECT().ReplayMatching(gomock.Any(), models.DeadLetterFilter{}, true).Return(7, nil)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/dead-letters/replay?all=true", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+
+	var body map[string]int
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 7, body["replayed"])
+}
+
+func Test_Handler_ReplayDeadLetters_WhenNoFilter_ThenReturns400(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Post("/dead-letters/replay", handler.ReplayDeadLetters)
+
+	service.EXPECT().ReplayMatching(gomock.Any(), models.DeadLetterFilter{}, false).Return(0, ErrFilterRequired)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/dead-letters/replay", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_PurgeDeadLetters_WhenFiltered_ThenReturnsCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Delete("/dead-letters", handler.PurgeDeadLetters)
+
+	service.EXPECT().Purge(gomock.Any(), models.DeadLetterFilter{Reason: models.DeadLetterInvalidPayload}, false).Return(int64(4), nil)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/dead-letters?reason=invalid_payload", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body map[string]int64
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, int64(4), body["purged"])
+}
+
+func Test_Handler_PurgeDeadLetters_WhenFromAfterTo_ThenReturns400(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(NewMockServiceInterface(ctrl))
+
+	app := fiber.New()
+	app.Delete("/dead-letters", handler.PurgeDeadLetters)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/dead-letters?from=2025-02-01T00:00:00Z&to=2025-01-01T00:00:00Z", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/deadletters/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/deadletters/repository.go -destination=src/api/internal/deadletters/mock_repository_test.go -package=deadletters -exclude_interfaces=Writer
+//
+
+// Package deadletters is a generated GoMock package.
+package deadletters
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddDeadLetter mocks base method.
+func (m *MockRepository) AddDeadLetter(ctx context.Context, letter *models.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeadLetter", ctx, letter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDeadLetter indicates an expected call of AddDeadLetter.
+func (mr *MockRepositoryMockRecorder) AddDeadLetter(ctx, letter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeadLetter", reflect.TypeOf((*MockRepository)(nil).AddDeadLetter), ctx, letter)
+}
+
+// DeleteDeadLetter mocks base method.
+func (m *MockRepository) DeleteDeadLetter(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeadLetter", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeadLetter indicates an expected call of DeleteDeadLetter.
+func (mr *MockRepositoryMockRecorder) DeleteDeadLetter(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeadLetter", reflect.TypeOf((*MockRepository)(nil).DeleteDeadLetter), ctx, id)
+}
+
+// DeleteDeadLetters mocks base method.
+func (m *MockRepository) DeleteDeadLetters(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeadLetters", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeadLetters indicates an expected call of DeleteDeadLetters.
+func (mr *MockRepositoryMockRecorder) DeleteDeadLetters(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeadLetters", reflect.TypeOf((*MockRepository)(nil).DeleteDeadLetters), ctx, ids)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockRepository) GetDeadLetter(ctx context.Context, id uuid.UUID) (*models.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", ctx, id)
+	ret0, _ := ret[0].(*models.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockRepositoryMockRecorder) GetDeadLetter(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockRepository)(nil).GetDeadLetter), ctx, id)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockRepository) ListDeadLetters(ctx context.Context, filter models.DeadLetterFilter, limit, offset int) ([]models.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, filter, limit, offset)
+	ret0, _ := ret[0].([]models.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockRepositoryMockRecorder) ListDeadLetters(ctx, filter, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockRepository)(nil).ListDeadLetters), ctx, filter, limit, offset)
+}
+
+// PurgeDeadLetters mocks base method.
+func (m *MockRepository) PurgeDeadLetters(ctx context.Context, filter models.DeadLetterFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeadLetters", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeadLetters indicates an expected call of PurgeDeadLetters.
+func (mr *MockRepositoryMockRecorder) PurgeDeadLetters(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeadLetters", reflect.TypeOf((*MockRepository)(nil).PurgeDeadLetters), ctx, filter)
+}
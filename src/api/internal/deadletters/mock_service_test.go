@@ -0,0 +1,174 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mock_service_test.go -package=deadletters
+//
+
+// Package deadletters is a generated GoMock package.
+package deadletters
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQueuePublisher is a mock of QueuePublisher interface.
+type MockQueuePublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockQueuePublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockQueuePublisherMockRecorder is the mock recorder for MockQueuePublisher.
+type MockQueuePublisherMockRecorder struct {
+	mock *MockQueuePublisher
+}
+
+// NewMockQueuePublisher creates a new mock instance.
+func NewMockQueuePublisher(ctrl *gomock.Controller) *MockQueuePublisher {
+	mock := &MockQueuePublisher{ctrl: ctrl}
+	mock.recorder = &MockQueuePublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQueuePublisher) EXPECT() *MockQueuePublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockQueuePublisher) Publish(ctx context.Context, lane models.QueueLane, payloads ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, lane}
+	for _, a := range payloads {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Publish", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockQueuePublisherMockRecorder) Publish(ctx, lane any, payloads ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, lane}, payloads...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockQueuePublisher)(nil).Publish), varargs...)
+}
+
+// MockServiceInterface is a mock of ServiceInterface interface.
+type MockServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceInterfaceMockRecorder is the mock recorder for MockServiceInterface.
+type MockServiceInterfaceMockRecorder struct {
+	mock *MockServiceInterface
+}
+
+// NewMockServiceInterface creates a new mock instance.
+func NewMockServiceInterface(ctrl *gomock.Controller) *MockServiceInterface {
+	mock := &MockServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceInterface) EXPECT() *MockServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockServiceInterface) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockServiceInterfaceMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockServiceInterface)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockServiceInterface) GetByID(ctx context.Context, id uuid.UUID) (*models.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockServiceInterfaceMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockServiceInterface)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockServiceInterface) List(ctx context.Context, filter models.DeadLetterFilter, limit, offset int) ([]models.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter, limit, offset)
+	ret0, _ := ret[0].([]models.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockServiceInterfaceMockRecorder) List(ctx, filter, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockServiceInterface)(nil).List), ctx, filter, limit, offset)
+}
+
+// Purge mocks base method.
+func (m *MockServiceInterface) Purge(ctx context.Context, filter models.DeadLetterFilter, all bool) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, filter, all)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockServiceInterfaceMockRecorder) Purge(ctx, filter, all any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockServiceInterface)(nil).Purge), ctx, filter, all)
+}
+
+// Replay mocks base method.
+func (m *MockServiceInterface) Replay(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockServiceInterfaceMockRecorder) Replay(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockServiceInterface)(nil).Replay), ctx, id)
+}
+
+// ReplayMatching mocks base method.
+func (m *MockServiceInterface) ReplayMatching(ctx context.Context, filter models.DeadLetterFilter, all bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayMatching", ctx, filter, all)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayMatching indicates an expected call of ReplayMatching.
+func (mr *MockServiceInterfaceMockRecorder) ReplayMatching(ctx, filter, all any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayMatching", reflect.TypeOf((*MockServiceInterface)(nil).ReplayMatching), ctx, filter, all)
+}
@@ -0,0 +1,69 @@
+//go:build integration
+
+package deadletters_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/api/internal/testutil"
+	"github.com/algo-shield/algo-shield/src/pkg/deadletters"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegration_DeadLettersRepository_FiltersAndDeletes(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	repo := deadletters.NewPostgresRepository(testDB.Postgres)
+	ctx := context.Background()
+
+	now := time.Now()
+	failed := &models.DeadLetter{
+		Queue: "transaction:queue", ExternalID: "ext-1", Payload: `{"external_id":"ext-1"}`,
+		Reason: models.DeadLetterProcessingFailed, Error: "timeout", Attempts: 3,
+		ReceivedAt: now.Add(-2 * time.Hour), FailedAt: now.Add(-time.Hour),
+	}
+	invalid := &models.DeadLetter{
+		Queue: "transaction:queue", Payload: "not json",
+		Reason: models.DeadLetterInvalidPayload, Error: "invalid character",
+		ReceivedAt: now, FailedAt: now,
+	}
+	require.NoError(t, repo.AddDeadLetter(ctx, failed))
+	require.NoError(t, repo.AddDeadLetter(ctx, invalid))
+
+	all, err := repo.ListDeadLetters(ctx, models.DeadLetterFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, invalid.ID, all[0].ID, "most recent first")
+	assert.Empty(t, all[0].ExternalID)
+
+	byReason, err := repo.ListDeadLetters(ctx, models.DeadLetterFilter{Reason: models.DeadLetterProcessingFailed}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, byReason, 1)
+	assert.Equal(t, "ext-1", byReason[0].ExternalID)
+	assert.Equal(t, 3, byReason[0].Attempts)
+
+	since := now.Add(-30 * time.Minute)
+	recent, err := repo.ListDeadLetters(ctx, models.DeadLetterFilter{From: &since}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+	assert.Equal(t, invalid.ID, recent[0].ID)
+
+	got, err := repo.GetDeadLetter(ctx, failed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, failed.Payload, got.Payload)
+
+	purged, err := repo.PurgeDeadLetters(ctx, models.DeadLetterFilter{Reason: models.DeadLetterInvalidPayload})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	deleted, err := repo.DeleteDeadLetters(ctx, []uuid.UUID{failed.ID, uuid.New()})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	assert.ErrorIs(t, repo.DeleteDeadLetter(ctx, failed.ID), pgx.ErrNoRows)
+}
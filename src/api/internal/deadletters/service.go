@@ -0,0 +1,169 @@
+package deadletters
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/deadletters"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Service errors
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrFilterRequired     = errors.New("set a filter, or all=true to include every dead letter")
+)
+
+// replayBatchSize bounds the dead letters replayed per round trip
+const replayBatchSize = 500
+
+// QueuePublisher defines the interface for queueing replayed events over the workers' transport
+type QueuePublisher interface {
+	Publish(ctx context.Context, lane models.QueueLane, payloads ...string) error
+}
+
+// ServiceInterface defines the interface for dead letter business logic
+type ServiceInterface interface {
+	List(ctx context.Context, filter models.DeadLetterFilter, limit, offset int) ([]models.DeadLetter, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.DeadLetter, error)
+	Replay(ctx context.Context, id uuid.UUID) error
+	// ReplayMatching returns how many dead letters were replayed, also when it fails part way
+	ReplayMatching(ctx context.Context, filter models.DeadLetterFilter, all bool) (int, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, filter models.DeadLetterFilter, all bool) (int64, error)
+}
+
+// Service provides business logic for dead letter operations
+type Service struct {
+	repo  deadletters.Repository
+	queue QueuePublisher
+}
+
+// NewService creates a new dead letter service with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewService(repo deadletters.Repository, queue QueuePublisher) *Service {
+	return &Service{
+		repo:  repo,
+		queue: queue,
+	}
+}
+
+// List returns the dead letters matching the filter, most recent first
+func (s *Service) List(ctx context.Context, filter models.DeadLetterFilter, limit, offset int) ([]models.DeadLetter, error) {
+	return s.repo.ListDeadLetters(ctx, filter, limit, offset)
+}
+
+// GetByID retrieves a dead letter with its raw payload
+func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*models.DeadLetter, error) {
+	letter, err := s.repo.GetDeadLetter(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+	return letter, nil
+}
+
+// Replay queues a dead letter's payload again and removes the dead letter
+// If the event fails again, the worker records a new dead letter
+func (s *Service) Replay(ctx context.Context, id uuid.UUID) error {
+	letter, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.queue.Publish(ctx, replayLane(letter), letter.Payload); err != nil {
+		return err
+	}
+
+	// Already queued, so a dead letter that vanished meanwhile is not an error
+	if err := s.repo.DeleteDeadLetter(ctx, id); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	return nil
+}
+
+// ReplayMatching replays the dead letters matching the filter, in batches
+// Only dead letters that failed before the replay started are included, so events
+// failing again are not replayed twice
+func (s *Service) ReplayMatching(ctx context.Context, filter models.DeadLetterFilter, all bool) (int, error) {
+	if filter.IsEmpty() && !all {
+		return 0, ErrFilterRequired
+	}
+
+	now := time.Now()
+	if filter.To == nil || filter.To.After(now) {
+		filter.To = &now
+	}
+
+	replayed := 0
+	for {
+		letters, err := s.repo.ListDeadLetters(ctx, filter, replayBatchSize, 0)
+		if err != nil {
+			return replayed, err
+		}
+		if len(letters) == 0 {
+			return replayed, nil
+		}
+
+		// One publish per lane, in the order the dead letters were listed
+		var lanes []models.QueueLane
+		payloads := make(map[models.QueueLane][]string)
+		ids := make(map[models.QueueLane][]uuid.UUID)
+		for _, letter := range letters {
+			lane := replayLane(&letter)
+			if _, ok := payloads[lane]; !ok {
+				lanes = append(lanes, lane)
+			}
+			payloads[lane] = append(payloads[lane], letter.Payload)
+			ids[lane] = append(ids[lane], letter.ID)
+		}
+
+		for _, lane := range lanes {
+			if err := s.queue.Publish(ctx, lane, payloads[lane]...); err != nil {
+				return replayed, err
+			}
+			if _, err := s.repo.DeleteDeadLetters(ctx, ids[lane]); err != nil {
+				return replayed, err
+			}
+			replayed += len(ids[lane])
+		}
+
+		if len(letters) < replayBatchSize {
+			return replayed, nil
+		}
+	}
+}
+
+// replayLane returns the lane a dead letter is replayed into, over the configured transport
+// Backfill events return to the backfill lane. Everything else goes to the post_transaction
+// lane, decision requests included: their caller stopped waiting long ago
+func replayLane(letter *models.DeadLetter) models.QueueLane {
+	if lane, ok := models.QueueLaneForKey(letter.Queue); ok && lane == models.LaneBackfill {
+		return lane
+	}
+	return models.LanePostTransaction
+}
+
+// Delete removes a dead letter without replaying it
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.DeleteDeadLetter(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrDeadLetterNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Purge removes the dead letters matching the filter without replaying them
+func (s *Service) Purge(ctx context.Context, filter models.DeadLetterFilter, all bool) (int64, error) {
+	if filter.IsEmpty() && !all {
+		return 0, ErrFilterRequired
+	}
+	return s.repo.PurgeDeadLetters(ctx, filter)
+}
@@ -0,0 +1,209 @@
+package deadletters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Service_GetByID_WhenMissing_ThenReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	repo.EXPECT().GetDeadLetter(gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows)
+	service := NewService(repo, NewMockQueuePublisher(ctrl))
+
+	_, err := service.GetByID(context.Background(), uuid.New())
+
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+}
+
+func Test_Service_Replay_WhenFound_ThenPushesPayloadAndDeletesDeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	letter := &models.DeadLetter{ID: uuid.New(), Queue: "transaction:decision:queue", Payload: `{"external_id":"ext-1"}`}
+	repo := NewMockRepository(ctrl)
+	queue := NewMockQueuePublisher(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().GetDeadLetter(gomock.Any(), letter.ID).Return(letter, nil),
+		queue.EXPECT().Publish(gomock.Any(), models.LanePostTransaction, letter.Payload).Return(nil),
+		repo.EXPECT().DeleteDeadLetter(gomock.Any(), letter.ID).Return(nil),
+	)
+	service := NewService(repo, queue)
+
+	err := service.Replay(context.Background(), letter.ID)
+
+	assert.NoError(t, err)
+}
+
+func Test_Service_Replay_WhenPushFails_ThenKeepsDeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	letter := &models.DeadLetter{ID: uuid.New(), Payload: "{}"}
+	repo := NewMockRepository(ctrl)
+	queue := NewMockQueuePublisher(ctrl)
+	repo.EXPECT().GetDeadLetter(gomock.Any(), letter.ID).Return(letter, nil)
+	queue.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("redis down"))
+	service := NewService(repo, queue)
+
+	err := service.Replay(context.Background(), letter.ID)
+
+	assert.Error(t, err)
+}
+
+func Test_Service_ReplayMatching_WhenNoFilter_ThenRequiresAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewService(NewMockRepository(ctrl), NewMockQueuePublisher(ctrl))
+
+	_, err := service.ReplayMatching(context.Background(), models.DeadLetterFilter{}, false)
+
+	assert.ErrorIs(t, err, ErrFilterRequired)
+}
+
+func Test_Service_ReplayMatching_WhenAll_ThenReplaysDeadLettersThatFailedBeforeNow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	letters := []models.DeadLetter{
+		{ID: uuid.New(), Payload: `{"external_id":"ext-1"}`},
+		{ID: uuid.New(), Payload: `{"external_id":"ext-2"}`},
+	}
+	repo := NewMockRepository(ctrl)
+	queue := NewMockQueuePublisher(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().ListDeadLetters(gomock.Any(), gomock.Any(), replayBatchSize, 0).
+			DoAndReturn(func(_ context.Context, filter models.DeadLetterFilter, _, _ int) ([]models.DeadLetter, error) {
+				require.NotNil(t, filter.To)
+				assert.WithinDuration(t, time.Now(), *filter.To, time.Second)
+				return letters, nil
+			}),
+		queue.EXPECT().Publish(gomock.Any(), models.LanePostTransaction, letters[0].Payload, letters[1].Payload).Return(nil),
+		repo.EXPECT().DeleteDeadLetters(gomock.Any(), []uuid.UUID{letters[0].ID, letters[1].ID}).Return(int64(2), nil),
+	)
+	service := NewService(repo, queue)
+
+	replayed, err := service.ReplayMatching(context.Background(), models.DeadLetterFilter{}, true)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, replayed)
+}
+
+func Test_Service_ReplayMatching_WhenLettersFromSeveralLanes_ThenReplaysBackfillToItsLane(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	letters := []models.DeadLetter{
+		{ID: uuid.New(), Queue: "transaction:queue:backfill", Payload: `{"external_id":"ext-1"}`},
+		{ID: uuid.New(), Queue: "transactions.realtime", Payload: `{"external_id":"ext-2"}`},
+		{ID: uuid.New(), Queue: "transaction:stream:backfill", Payload: `{"external_id":"ext-3"}`},
+	}
+	repo := NewMockRepository(ctrl)
+	queue := NewMockQueuePublisher(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().ListDeadLetters(gomock.Any(), gomock.Any(), replayBatchSize, 0).Return(letters, nil),
+		queue.EXPECT().Publish(gomock.Any(), models.LaneBackfill, letters[0].Payload, letters[2].Payload).Return(nil),
+		repo.EXPECT().DeleteDeadLetters(gomock.Any(), []uuid.UUID{letters[0].ID, letters[2].ID}).Return(int64(2), nil),
+		queue.EXPECT().Publish(gomock.Any(), models.LanePostTransaction, letters[1].Payload).Return(nil),
+		repo.EXPECT().DeleteDeadLetters(gomock.Any(), []uuid.UUID{letters[1].ID}).Return(int64(1), nil),
+	)
+	service := NewService(repo, queue)
+
+	replayed, err := service.ReplayMatching(context.Background(), models.DeadLetterFilter{}, true)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, replayed)
+}
+
+func Test_Service_ReplayMatching_WhenFullBatch_ThenContinuesUntilEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	batch := make([]models.DeadLetter, replayBatchSize)
+	for i := range batch {
+		batch[i] = models.DeadLetter{ID: uuid.New(), Payload: "{}"}
+	}
+	repo := NewMockRepository(ctrl)
+	queue := NewMockQueuePublisher(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().ListDeadLetters(gomock.Any(), gomock.Any(), replayBatchSize, 0).Return(batch, nil),
+		queue.EXPECT().Publish(gomock.Any(), models.LanePostTransaction, gomock.Any()).Return(nil),
+		repo.EXPECT().DeleteDeadLetters(gomock.Any(), gomock.Len(replayBatchSize)).Return(int64(replayBatchSize), nil),
+		repo.EXPECT().ListDeadLetters(gomock.Any(), gomock.Any(), replayBatchSize, 0).Return([]models.DeadLetter{}, nil),
+	)
+	service := NewService(repo, queue)
+
+	replayed, err := service.ReplayMatching(context.Background(), models.DeadLetterFilter{Reason: models.DeadLetterProcessingFailed}, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, replayBatchSize, replayed)
+}
+
+func Test_Service_ReplayMatching_WhenDeleteFails_ThenReturnsProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	queue := NewMockQueuePublisher(ctrl)
+	repo.EXPECT().ListDeadLetters(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]models.DeadLetter{{ID: uuid.New(), Payload: "{}"}}, nil)
+	queue.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	repo.EXPECT().DeleteDeadLetters(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("database down"))
+	service := NewService(repo, queue)
+
+	replayed, err := service.ReplayMatching(context.Background(), models.DeadLetterFilter{ExternalID: "ext-1"}, false)
+
+	assert.Error(t, err)
+	assert.Zero(t, replayed)
+}
+
+func Test_Service_Purge_WhenNoFilter_ThenRequiresAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewService(NewMockRepository(ctrl), NewMockQueuePublisher(ctrl))
+
+	_, err := service.Purge(context.Background(), models.DeadLetterFilter{}, false)
+
+	assert.ErrorIs(t, err, ErrFilterRequired)
+}
+
+func Test_Service_Purge_WhenFiltered_ThenDeletesMatching(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	filter := models.DeadLetterFilter{Reason: models.DeadLetterInvalidPayload}
+	repo := NewMockRepository(ctrl)
+	repo.EXPECT().PurgeDeadLetters(gomock.Any(), filter).Return(int64(3), nil)
+	service := NewService(repo, NewMockQueuePublisher(ctrl))
+
+	purged, err := service.Purge(context.Background(), filter, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
+
+func Test_Service_Delete_WhenMissing_ThenReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	repo.EXPECT().DeleteDeadLetter(gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+	service := NewService(repo, NewMockQueuePublisher(ctrl))
+
+	err := service.Delete(context.Background(), uuid.New())
+
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+}
@@ -0,0 +1,308 @@
+package lists
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+
+	"github.com/algo-shield/algo-shield/src/api/internal"
+	"github.com/algo-shield/algo-shield/src/api/internal/shared/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Handler handles HTTP requests for managed lists
+type Handler struct {
+	service ServiceInterface
+}
+
+// NewHandler creates a new list handler
+func NewHandler(service ServiceInterface) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// CreateList handles POST /api/v1/lists
+func (h *Handler) CreateList(c *fiber.Ctx) error {
+	var req CreateListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := validation.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	list, err := h.service.Create(ctx, &req)
+	if err != nil {
+		if errors.Is(err, ErrListNameExists) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "A list with this name already exists",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create list",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(list)
+}
+
+// GetList handles GET /api/v1/lists/:id
+func (h *Handler) GetList(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	list, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		return listError(c, err, "Failed to fetch list")
+	}
+
+	return c.JSON(list)
+}
+
+// ListLists handles GET /api/v1/lists
+func (h *Handler) ListLists(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	lists, err := h.service.List(ctx)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch lists",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"lists": lists,
+	})
+}
+
+// UpdateList handles PUT /api/v1/lists/:id
+func (h *Handler) UpdateList(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	var req UpdateListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := validation.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	list, err := h.service.Update(ctx, id, &req)
+	if err != nil {
+		return listError(c, err, "Failed to update list")
+	}
+
+	return c.JSON(list)
+}
+
+// DeleteList handles DELETE /api/v1/lists/:id
+func (h *Handler) DeleteList(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		return listError(c, err, "Failed to delete list")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListEntries handles GET /api/v1/lists/:id/entries
+func (h *Handler) ListEntries(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	limit := c.QueryInt("limit", 50)
+	offset := c.QueryInt("offset", 0)
+
+	// Validate pagination parameters
+	if err := validation.ValidateLimit(limit); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err := validation.ValidateOffset(offset); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	entries, err := h.service.ListEntries(ctx, id, limit, offset)
+	if err != nil {
+		return listError(c, err, "Failed to fetch list entries")
+	}
+
+	return c.JSON(fiber.Map{
+		"entries": entries,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// AddEntries handles POST /api/v1/lists/:id/entries
+// Values that do not fit the list type are reported in the result, the rest are added
+func (h *Handler) AddEntries(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	var req AddEntriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := validation.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	result, err := h.service.AddEntries(ctx, id, req.Entries)
+	if err != nil {
+		return listError(c, err, "Failed to add list entries")
+	}
+
+	return c.JSON(result)
+}
+
+// UploadEntries handles POST /api/v1/lists/:id/entries/upload
+// Accepts a CSV file either as the multipart form field "file" or as the raw request body
+func (h *Handler) UploadEntries(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	var body io.Reader = bytes.NewReader(c.Body())
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid upload",
+			})
+		}
+		defer func() { _ = file.Close() }()
+		body = file
+	}
+
+	// Large imports take longer than a regular request
+	ctx, cancel := context.WithTimeout(c.Context(), 4*internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	result, err := h.service.ImportCSV(ctx, id, body)
+	if err != nil {
+		return listError(c, err, "Failed to import list entries")
+	}
+
+	return c.JSON(result)
+}
+
+// DeleteEntry handles DELETE /api/v1/lists/:id/entries/:value
+// The value must be URL-encoded, e.g. an email as user%40example.com
+func (h *Handler) DeleteEntry(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid list ID",
+		})
+	}
+
+	value, err := url.PathUnescape(c.Params("value"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid entry value",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	if err := h.service.DeleteEntry(ctx, id, value); err != nil {
+		return listError(c, err, "Failed to delete list entry")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// listError maps service errors to HTTP responses, falling back to a 500 with the given message
+func listError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, ErrListNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "List not found",
+		})
+	case errors.Is(err, ErrEntryNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "List entry not found",
+		})
+	case errors.Is(err, ErrListNameExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "A list with this name already exists",
+		})
+	case errors.Is(err, ErrTooManyEntries), errors.Is(err, ErrInvalidCSV):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+}
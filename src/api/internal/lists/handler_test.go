@@ -0,0 +1,169 @@
+package lists
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Handler_CreateList_WhenTypeUnknown_ThenReturns400(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(NewMockServiceInterface(ctrl))
+
+	app := fiber.New()
+	app.Post("/lists", handler.CreateList)
+
+	body := `{"name":"blocked","type":"phone"}`
+	req := httptest.NewRequest("POST", "/lists", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_CreateList_WhenNameExists_ThenReturns409(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Post("/lists", handler.CreateList)
+
+	service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, ErrListNameExists)
+
+	body := `{"name":"blocked","type":"ip"}`
+	req := httptest.NewRequest("POST", "/lists", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func Test_Handler_GetList_WhenNotFound_ThenReturns404(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Get("/lists/:id", handler.GetList)
+
+	id := uuid.New()
+	service.EXPECT().GetByID(gomock.Any(), id).Return(nil, ErrListNotFound)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/lists/"+id.String(), nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Handler_UploadEntries_WhenMultipartFile_ThenImportsFileContents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Post("/lists/:id/entries/upload", handler.UploadEntries)
+
+	id := uuid.New()
+	service.EXPECT().ImportCSV(gomock.Any(), id, gomock.Any()).Return(&ImportResult{Imported: 1, Rejected: []RejectedEntry{}}, nil)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "accounts.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("acc-1\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/lists/"+id.String()+"/entries/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result ImportResult
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, 1, result.Imported)
+}
+
+func Test_Handler_UploadEntries_WhenCSVInvalid_ThenReturns400(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Post("/lists/:id/entries/upload", handler.UploadEntries)
+
+	service.EXPECT().ImportCSV(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, ErrInvalidCSV)
+
+	req := httptest.NewRequest("POST", "/lists/"+uuid.NewString()+"/entries/upload", bytes.NewBufferString("\"unterminated\n"))
+	req.Header.Set("Content-Type", "text/csv")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_DeleteEntry_WhenValueEncoded_ThenPassesDecodedValue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Delete("/lists/:id/entries/:value", handler.DeleteEntry)
+
+	id := uuid.New()
+	service.EXPECT().DeleteEntry(gomock.Any(), id, "fraud@example.com").Return(nil)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/lists/"+id.String()+"/entries/fraud%40example.com", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+}
+
+func Test_Handler_ListEntries_WhenSucceeds_ThenReturnsEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Get("/lists/:id/entries", handler.ListEntries)
+
+	id := uuid.New()
+	service.EXPECT().ListEntries(gomock.Any(), id, 10, 0).Return([]models.ListEntry{{Value: "acc-1"}}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/lists/"+id.String()+"/entries?limit=10", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,188 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/lists/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/lists/repository.go -destination=src/api/internal/lists/mock_repository_test.go -package=lists -exclude_interfaces=Reader
+//
+
+// Package lists is a generated GoMock package.
+package lists
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ActiveEntries mocks base method.
+func (m *MockRepository) ActiveEntries(ctx context.Context, listID uuid.UUID) ([]models.ListEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveEntries", ctx, listID)
+	ret0, _ := ret[0].([]models.ListEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveEntries indicates an expected call of ActiveEntries.
+func (mr *MockRepositoryMockRecorder) ActiveEntries(ctx, listID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveEntries", reflect.TypeOf((*MockRepository)(nil).ActiveEntries), ctx, listID)
+}
+
+// CreateList mocks base method.
+func (m *MockRepository) CreateList(ctx context.Context, list *models.List) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateList", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateList indicates an expected call of CreateList.
+func (mr *MockRepositoryMockRecorder) CreateList(ctx, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateList", reflect.TypeOf((*MockRepository)(nil).CreateList), ctx, list)
+}
+
+// DeleteEntry mocks base method.
+func (m *MockRepository) DeleteEntry(ctx context.Context, listID uuid.UUID, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEntry", ctx, listID, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEntry indicates an expected call of DeleteEntry.
+func (mr *MockRepositoryMockRecorder) DeleteEntry(ctx, listID, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockRepository)(nil).DeleteEntry), ctx, listID, value)
+}
+
+// DeleteList mocks base method.
+func (m *MockRepository) DeleteList(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteList", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteList indicates an expected call of DeleteList.
+func (mr *MockRepositoryMockRecorder) DeleteList(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteList", reflect.TypeOf((*MockRepository)(nil).DeleteList), ctx, id)
+}
+
+// GetList mocks base method.
+func (m *MockRepository) GetList(ctx context.Context, id uuid.UUID) (*models.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, id)
+	ret0, _ := ret[0].(*models.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockRepositoryMockRecorder) GetList(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockRepository)(nil).GetList), ctx, id)
+}
+
+// GetListByName mocks base method.
+func (m *MockRepository) GetListByName(ctx context.Context, name string) (*models.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByName", ctx, name)
+	ret0, _ := ret[0].(*models.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListByName indicates an expected call of GetListByName.
+func (mr *MockRepositoryMockRecorder) GetListByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByName", reflect.TypeOf((*MockRepository)(nil).GetListByName), ctx, name)
+}
+
+// ListEntries mocks base method.
+func (m *MockRepository) ListEntries(ctx context.Context, listID uuid.UUID, limit, offset int) ([]models.ListEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", ctx, listID, limit, offset)
+	ret0, _ := ret[0].([]models.ListEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockRepositoryMockRecorder) ListEntries(ctx, listID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockRepository)(nil).ListEntries), ctx, listID, limit, offset)
+}
+
+// ListLists mocks base method.
+func (m *MockRepository) ListLists(ctx context.Context) ([]models.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLists", ctx)
+	ret0, _ := ret[0].([]models.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLists indicates an expected call of ListLists.
+func (mr *MockRepositoryMockRecorder) ListLists(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLists", reflect.TypeOf((*MockRepository)(nil).ListLists), ctx)
+}
+
+// UpdateList mocks base method.
+func (m *MockRepository) UpdateList(ctx context.Context, list *models.List) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateList", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateList indicates an expected call of UpdateList.
+func (mr *MockRepositoryMockRecorder) UpdateList(ctx, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateList", reflect.TypeOf((*MockRepository)(nil).UpdateList), ctx, list)
+}
+
+// UpsertEntries mocks base method.
+func (m *MockRepository) UpsertEntries(ctx context.Context, listID uuid.UUID, entries []models.ListEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertEntries", ctx, listID, entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertEntries indicates an expected call of UpsertEntries.
+func (mr *MockRepositoryMockRecorder) UpsertEntries(ctx, listID, entries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertEntries", reflect.TypeOf((*MockRepository)(nil).UpsertEntries), ctx, listID, entries)
+}
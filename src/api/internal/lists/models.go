@@ -0,0 +1,53 @@
+package lists
+
+import (
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+)
+
+// MaxImportEntries bounds the number of entries accepted by a single add or CSV upload
+const MaxImportEntries = 100000
+
+// maxRejectedReported bounds the rejected entries listed in an ImportResult
+const maxRejectedReported = 100
+
+// CreateListRequest is the request body for creating a list
+type CreateListRequest struct {
+	Name        string          `json:"name" validate:"required,min=1,max=100"`
+	Description string          `json:"description" validate:"max=1000"`
+	Type        models.ListType `json:"type" validate:"required,oneof=account ip email bin country name"`
+}
+
+// UpdateListRequest is the request body for updating a list, its type cannot change
+type UpdateListRequest struct {
+	Name        string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+// EntryRequest is a value to add to a list
+type EntryRequest struct {
+	Value     string     `json:"value"`
+	Note      string     `json:"note,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// AddEntriesRequest is the request body for adding entries to a list
+type AddEntriesRequest struct {
+	Entries []EntryRequest `json:"entries" validate:"required,min=1,max=1000"`
+}
+
+// RejectedEntry is an entry that was not added, with the reason
+type RejectedEntry struct {
+	Line  int    `json:"line"` // 1-based position in the request or CSV file
+	Value string `json:"value"`
+	Error string `json:"error"`
+}
+
+// ImportResult reports how many entries were added or updated and which were rejected
+// Valid entries are imported even when others are rejected
+type ImportResult struct {
+	Imported      int             `json:"imported"`
+	RejectedCount int             `json:"rejected_count"`
+	Rejected      []RejectedEntry `json:"rejected"` // First rejected entries only
+}
@@ -0,0 +1,81 @@
+//go:build integration
+
+package lists_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/api/internal/testutil"
+	"github.com/algo-shield/algo-shield/src/pkg/lists"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createList(t *testing.T, repo lists.Repository, name string, listType models.ListType) *models.List {
+	t.Helper()
+	now := time.Now()
+	list := &models.List{ID: uuid.New(), Name: name, Type: listType, CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, repo.CreateList(context.Background(), list))
+	return list
+}
+
+func TestIntegration_ListsRepository_UpsertEntries_ReplacesNoteAndExpiry(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	repo := lists.NewPostgresRepository(testDB.Postgres, testDB.Redis)
+	ctx := context.Background()
+	list := createList(t, repo, "blocked-accounts", models.ListTypeAccount)
+	expired := time.Now().Add(-time.Hour)
+
+	require.NoError(t, repo.UpsertEntries(ctx, list.ID, []models.ListEntry{
+		{Value: "acc-1", Note: "first"},
+		{Value: "acc-2", ExpiresAt: &expired},
+	}))
+	require.NoError(t, repo.UpsertEntries(ctx, list.ID, []models.ListEntry{{Value: "acc-1", Note: "second"}}))
+
+	entries, err := repo.ListEntries(ctx, list.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "second", entries[0].Note)
+
+	stored, err := repo.GetList(ctx, list.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.EntryCount)
+
+	active, err := repo.ActiveEntries(ctx, list.ID)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, "acc-1", active[0].Value)
+}
+
+func TestIntegration_ListService_ContainsIP_MatchesNetworks(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	repo := lists.NewPostgresRepository(testDB.Postgres, testDB.Redis)
+	ctx := context.Background()
+	list := createList(t, repo, "hosting", models.ListTypeIP)
+
+	require.NoError(t, repo.UpsertEntries(ctx, list.ID, []models.ListEntry{
+		{Value: "10.0.0.0/8"},
+		{Value: "2001:db8::/32"},
+		{Value: "192.0.2.7"},
+	}))
+	cache := lists.NewListService(repo, nil)
+
+	for ip, expected := range map[string]bool{
+		"10.1.2.3":        true,
+		"::ffff:10.1.2.3": true,
+		"2001:db8::1":     true,
+		"192.0.2.7":       true,
+		"192.0.2.8":       false,
+		"11.0.0.1":        false,
+		"garbage":         false,
+	} {
+		contains, err := cache.ContainsIP(ctx, "hosting", ip)
+
+		require.NoError(t, err, ip)
+		assert.Equal(t, expected, contains, ip)
+	}
+}
@@ -0,0 +1,282 @@
+package lists
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/lists"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Service errors
+var (
+	ErrListNotFound   = errors.New("list not found")
+	ErrListNameExists = errors.New("list with this name already exists")
+	ErrEntryNotFound  = errors.New("list entry not found")
+	ErrTooManyEntries = fmt.Errorf("at most %d entries can be imported at once", MaxImportEntries)
+	ErrInvalidCSV     = errors.New("invalid CSV")
+)
+
+// ServiceInterface defines the interface for list business logic
+type ServiceInterface interface {
+	Create(ctx context.Context, req *CreateListRequest) (*models.List, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.List, error)
+	List(ctx context.Context) ([]models.List, error)
+	Update(ctx context.Context, id uuid.UUID, req *UpdateListRequest) (*models.List, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListEntries(ctx context.Context, id uuid.UUID, limit, offset int) ([]models.ListEntry, error)
+	AddEntries(ctx context.Context, id uuid.UUID, entries []EntryRequest) (*ImportResult, error)
+	ImportCSV(ctx context.Context, id uuid.UUID, r io.Reader) (*ImportResult, error)
+	DeleteEntry(ctx context.Context, id uuid.UUID, value string) error
+}
+
+// Service provides business logic for list operations
+type Service struct {
+	repo lists.Repository
+}
+
+// NewService creates a new list service with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewService(repo lists.Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+// Create creates an empty list
+func (s *Service) Create(ctx context.Context, req *CreateListRequest) (*models.List, error) {
+	if err := s.ensureNameAvailable(ctx, req.Name); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	list := &models.List{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Description: req.Description,
+		Type:        req.Type,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.repo.CreateList(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetByID returns a list with its count of unexpired entries
+func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*models.List, error) {
+	list, err := s.repo.GetList(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrListNotFound
+	}
+
+	return list, err
+}
+
+// List returns every list
+func (s *Service) List(ctx context.Context) ([]models.List, error) {
+	return s.repo.ListLists(ctx)
+}
+
+// Update renames or redescribes a list
+// Renaming breaks expressions that reference the old name
+func (s *Service) Update(ctx context.Context, id uuid.UUID, req *UpdateListRequest) (*models.List, error) {
+	list, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" && req.Name != list.Name {
+		if err := s.ensureNameAvailable(ctx, req.Name); err != nil {
+			return nil, err
+		}
+		list.Name = req.Name
+	}
+	list.Description = req.Description
+	list.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateList(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Delete removes a list and its entries
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	err := s.repo.DeleteList(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrListNotFound
+	}
+
+	return err
+}
+
+// ListEntries pages through the unexpired entries of a list
+func (s *Service) ListEntries(ctx context.Context, id uuid.UUID, limit, offset int) ([]models.ListEntry, error) {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListEntries(ctx, id, limit, offset)
+}
+
+// AddEntries normalizes and adds entries, rejecting values that do not fit the list type
+func (s *Service) AddEntries(ctx context.Context, id uuid.UUID, entries []EntryRequest) (*ImportResult, error) {
+	if len(entries) > MaxImportEntries {
+		return nil, ErrTooManyEntries
+	}
+
+	list, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Values repeated in the request keep their last note and expiry,
+	// a single upsert statement cannot touch the same row twice
+	valid := make([]models.ListEntry, 0, len(entries))
+	positions := make(map[string]int, len(entries))
+	result := &ImportResult{Rejected: make([]RejectedEntry, 0)}
+	now := time.Now()
+	for i, req := range entries {
+		entry, err := normalizeEntry(list.Type, req, now)
+		if err != nil {
+			result.reject(i+1, req.Value, err)
+			continue
+		}
+		if pos, ok := positions[entry.Value]; ok {
+			valid[pos] = entry
+			continue
+		}
+		positions[entry.Value] = len(valid)
+		valid = append(valid, entry)
+	}
+
+	if len(valid) > 0 {
+		if err := s.repo.UpsertEntries(ctx, id, valid); err != nil {
+			return nil, err
+		}
+	}
+	result.Imported = len(valid)
+
+	return result, nil
+}
+
+// ImportCSV adds the entries of a CSV file with the columns value, note and expires_at (RFC 3339)
+// Only value is required. A first row whose value is "value" is treated as a header.
+func (s *Service) ImportCSV(ctx context.Context, id uuid.UUID, r io.Reader) (*ImportResult, error) {
+	entries, err := parseEntriesCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.AddEntries(ctx, id, entries)
+}
+
+// DeleteEntry removes a value from a list
+func (s *Service) DeleteEntry(ctx context.Context, id uuid.UUID, value string) error {
+	list, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	normalized, err := models.NormalizeListValue(list.Type, value)
+	if err != nil {
+		return ErrEntryNotFound
+	}
+
+	err = s.repo.DeleteEntry(ctx, id, normalized)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrEntryNotFound
+	}
+
+	return err
+}
+
+// ensureNameAvailable returns ErrListNameExists when another list has the name
+func (s *Service) ensureNameAvailable(ctx context.Context, name string) error {
+	existing, err := s.repo.GetListByName(ctx, name)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	if existing != nil {
+		return ErrListNameExists
+	}
+
+	return nil
+}
+
+// reject records a rejected entry, listing only the first ones
+func (r *ImportResult) reject(line int, value string, err error) {
+	r.RejectedCount++
+	if len(r.Rejected) < maxRejectedReported {
+		r.Rejected = append(r.Rejected, RejectedEntry{Line: line, Value: value, Error: err.Error()})
+	}
+}
+
+// normalizeEntry validates an entry for the list type
+// Entries that already expired are rejected so typos in expires_at are noticed
+func normalizeEntry(listType models.ListType, req EntryRequest, now time.Time) (models.ListEntry, error) {
+	value, err := models.NormalizeListValue(listType, req.Value)
+	if err != nil {
+		return models.ListEntry{}, err
+	}
+
+	entry := models.ListEntry{Value: value, Note: req.Note, ExpiresAt: req.ExpiresAt}
+	if entry.Expired(now) {
+		return models.ListEntry{}, errors.New("expires_at is in the past")
+	}
+
+	return entry, nil
+}
+
+// parseEntriesCSV reads entry requests from CSV rows of value, note and expires_at
+// An unparseable expiry date fails the whole file so a misformatted column is not silently dropped
+func parseEntriesCSV(r io.Reader) ([]EntryRequest, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	entries := make([]EntryRequest, 0)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "value") {
+			continue
+		}
+		if len(entries) == MaxImportEntries {
+			return nil, ErrTooManyEntries
+		}
+
+		entry := EntryRequest{Value: record[0]}
+		if len(record) > 1 {
+			entry.Note = strings.TrimSpace(record[1])
+		}
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(record[2]))
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: expires_at must be an RFC 3339 timestamp", ErrInvalidCSV, line)
+			}
+			entry.ExpiresAt = &expiresAt
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,159 @@
+package lists
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Service_Create_WhenNameExists_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetListByName(gomock.Any(), "blocked-ips").Return(&models.List{ID: uuid.New()}, nil)
+	service := NewService(mockRepo)
+
+	list, err := service.Create(context.Background(), &CreateListRequest{Name: "blocked-ips", Type: models.ListTypeIP})
+
+	assert.Nil(t, list)
+	assert.ErrorIs(t, err, ErrListNameExists)
+}
+
+func Test_Service_GetByID_WhenMissing_ThenReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetList(gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows)
+	service := NewService(mockRepo)
+
+	_, err := service.GetByID(context.Background(), uuid.New())
+
+	assert.ErrorIs(t, err, ErrListNotFound)
+}
+
+func Test_Service_AddEntries_WhenSomeValuesInvalid_ThenImportsValidAndReportsRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.New()
+	past := time.Now().Add(-time.Hour)
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetList(gomock.Any(), id).Return(&models.List{ID: id, Type: models.ListTypeIP}, nil)
+	mockRepo.EXPECT().UpsertEntries(gomock.Any(), id, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uuid.UUID, entries []models.ListEntry) error {
+			require.Len(t, entries, 2)
+			assert.Equal(t, "10.0.0.1", entries[0].Value)
+			assert.Equal(t, "second", entries[0].Note)
+			assert.Equal(t, "2001:db8::1", entries[1].Value)
+			return nil
+		})
+	service := NewService(mockRepo)
+
+	result, err := service.AddEntries(context.Background(), id, []EntryRequest{
+		{Value: " 10.0.0.1 ", Note: "first"},
+		{Value: "not-an-ip"},
+		{Value: "2001:DB8::1"},
+		{Value: "10.0.0.1", Note: "second"},
+		{Value: "10.0.0.2", ExpiresAt: &past},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 2, result.RejectedCount)
+	require.Len(t, result.Rejected, 2)
+	assert.Equal(t, 2, result.Rejected[0].Line)
+	assert.Equal(t, 5, result.Rejected[1].Line)
+}
+
+func Test_Service_AddEntries_WhenAllValuesInvalid_ThenDoesNotWrite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.New()
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetList(gomock.Any(), id).Return(&models.List{ID: id, Type: models.ListTypeBIN}, nil)
+	service := NewService(mockRepo)
+
+	result, err := service.AddEntries(context.Background(), id, []EntryRequest{{Value: "12"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, 1, result.RejectedCount)
+}
+
+func Test_Service_ImportCSV_WhenFileHasHeaderAndExpiry_ThenImportsEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.New()
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetList(gomock.Any(), id).Return(&models.List{ID: id, Type: models.ListTypeEmail}, nil)
+	mockRepo.EXPECT().UpsertEntries(gomock.Any(), id, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uuid.UUID, entries []models.ListEntry) error {
+			require.Len(t, entries, 2)
+			assert.Equal(t, "fraud@example.com", entries[0].Value)
+			assert.Equal(t, "chargeback", entries[0].Note)
+			require.NotNil(t, entries[1].ExpiresAt)
+			assert.Equal(t, 2099, entries[1].ExpiresAt.Year())
+			return nil
+		})
+	service := NewService(mockRepo)
+
+	csv := "value,note,expires_at\nFraud@Example.com,chargeback\nmule@example.com,,2099-01-01T00:00:00Z\n"
+	result, err := service.ImportCSV(context.Background(), id, strings.NewReader(csv))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 0, result.RejectedCount)
+}
+
+func Test_Service_ImportCSV_WhenExpiryUnparseable_ThenReturnsInvalidCSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewService(NewMockRepository(ctrl))
+
+	_, err := service.ImportCSV(context.Background(), uuid.New(), strings.NewReader("a@example.com,,tomorrow\n"))
+
+	assert.ErrorIs(t, err, ErrInvalidCSV)
+}
+
+func Test_Service_DeleteEntry_WhenValueNotNormalized_ThenDeletesNormalizedValue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.New()
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetList(gomock.Any(), id).Return(&models.List{ID: id, Type: models.ListTypeCountry}, nil)
+	mockRepo.EXPECT().DeleteEntry(gomock.Any(), id, "KP").Return(nil)
+	service := NewService(mockRepo)
+
+	err := service.DeleteEntry(context.Background(), id, "kp")
+
+	require.NoError(t, err)
+}
+
+func Test_Service_DeleteEntry_WhenValueMissing_ThenReturnsEntryNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.New()
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetList(gomock.Any(), id).Return(&models.List{ID: id, Type: models.ListTypeAccount}, nil)
+	mockRepo.EXPECT().DeleteEntry(gomock.Any(), id, "acc-1").Return(pgx.ErrNoRows)
+	service := NewService(mockRepo)
+
+	err := service.DeleteEntry(context.Background(), id, "acc-1")
+
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+}
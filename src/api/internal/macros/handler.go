@@ -0,0 +1,221 @@
+package macros
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/algo-shield/algo-shield/src/api/internal"
+	"github.com/algo-shield/algo-shield/src/api/internal/shared/validation"
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Handler handles HTTP requests for expression macros
+type Handler struct {
+	service ServiceInterface
+}
+
+// NewHandler creates a new macro handler
+func NewHandler(service ServiceInterface) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// CreateMacro handles POST /api/v1/macros
+func (h *Handler) CreateMacro(c *fiber.Ctx) error {
+	var req MacroRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := validation.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	macro, err := h.service.Create(ctx, &req)
+	if err != nil {
+		return macroError(c, err, "Failed to create macro")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(macro)
+}
+
+// GetMacro handles GET /api/v1/macros/:id
+func (h *Handler) GetMacro(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid macro ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	macro, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		return macroError(c, err, "Failed to fetch macro")
+	}
+
+	return c.JSON(macro)
+}
+
+// ListMacros handles GET /api/v1/macros
+func (h *Handler) ListMacros(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	macros, err := h.service.List(ctx)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch macros",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"macros": macros,
+	})
+}
+
+// UpdateMacro handles PUT /api/v1/macros/:id
+// Workers recompile the rules using the macro once the change is stored
+func (h *Handler) UpdateMacro(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid macro ID",
+		})
+	}
+
+	var req MacroRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := validation.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	macro, err := h.service.Update(ctx, id, &req)
+	if err != nil {
+		return macroError(c, err, "Failed to update macro")
+	}
+
+	return c.JSON(macro)
+}
+
+// DeleteMacro handles DELETE /api/v1/macros/:id
+func (h *Handler) DeleteMacro(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid macro ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		return macroError(c, err, "Failed to delete macro")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListVersions handles GET /api/v1/macros/:id/versions
+func (h *Handler) ListVersions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid macro ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	versions, err := h.service.ListVersions(ctx, id)
+	if err != nil {
+		return macroError(c, err, "Failed to fetch macro versions")
+	}
+
+	return c.JSON(fiber.Map{
+		"versions": versions,
+	})
+}
+
+// GetVersion handles GET /api/v1/macros/:id/versions/:version
+func (h *Handler) GetVersion(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid macro ID",
+		})
+	}
+
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil || version < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid macro version",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	macroVersion, err := h.service.GetVersion(ctx, id, version)
+	if err != nil {
+		return macroError(c, err, "Failed to fetch macro version")
+	}
+
+	return c.JSON(macroVersion)
+}
+
+// macroError maps a service error to a response
+func macroError(c *fiber.Ctx, err error, message string) error {
+	var macroErr *expressions.MacroError
+	switch {
+	case errors.Is(err, ErrMacroNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Macro not found",
+		})
+	case errors.Is(err, ErrVersionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Macro version not found",
+		})
+	case errors.Is(err, ErrMacroNameExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "A macro with this name already exists",
+		})
+	case errors.Is(err, ErrMacroInUse):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.As(err, &macroErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": macroErr.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+}
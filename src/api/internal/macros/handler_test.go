@@ -0,0 +1,137 @@
+package macros
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Handler_CreateMacro_WhenExpressionMissing_ThenReturns400(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(NewMockServiceInterface(ctrl))
+
+	app := fiber.New()
+	app.Post("/macros", handler.CreateMacro)
+
+	req := httptest.NewRequest("POST", "/macros", bytes.NewBufferString(`{"name":"highAmount"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_CreateMacro_WhenCycle_ThenReturns400WithPath(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Post("/macros", handler.CreateMacro)
+
+	service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, &expressions.MacroError{
+		Name: "a",
+		Err:  fmt.Errorf("%w: a -> b -> a", expressions.ErrMacroCycle),
+	})
+
+	req := httptest.NewRequest("POST", "/macros", bytes.NewBufferString(`{"name":"a","expression":"b"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Contains(t, body["error"], "a -> b -> a")
+}
+
+func Test_Handler_CreateMacro_WhenValid_ThenReturns201(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Post("/macros", handler.CreateMacro)
+
+	service.EXPECT().Create(gomock.Any(), &MacroRequest{Name: "highAmount", Expression: "amount > 1000"}).
+		Return(&models.Macro{ID: uuid.New(), Name: "highAmount", Expression: "amount > 1000", Version: 1}, nil)
+
+	req := httptest.NewRequest("POST", "/macros", bytes.NewBufferString(`{"name":"highAmount","expression":"amount > 1000"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+}
+
+func Test_Handler_DeleteMacro_WhenInUse_ThenReturns409(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Delete("/macros/:id", handler.DeleteMacro)
+
+	id := uuid.New()
+	service.EXPECT().Delete(gomock.Any(), id).Return(fmt.Errorf("%w rule big-transfer", ErrMacroInUse))
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/macros/"+id.String(), nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func Test_Handler_GetVersion_WhenNotFound_ThenReturns404(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewMockServiceInterface(ctrl)
+	handler := NewHandler(service)
+
+	app := fiber.New()
+	app.Get("/macros/:id/versions/:version", handler.GetVersion)
+
+	id := uuid.New()
+	service.EXPECT().GetVersion(gomock.Any(), id, 3).Return(nil, ErrVersionNotFound)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/macros/"+id.String()+"/versions/3", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Handler_GetVersion_WhenVersionInvalid_ThenReturns400(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(NewMockServiceInterface(ctrl))
+
+	app := fiber.New()
+	app.Get("/macros/:id/versions/:version", handler.GetVersion)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/macros/"+uuid.New().String()+"/versions/0", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
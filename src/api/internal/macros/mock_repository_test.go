@@ -0,0 +1,145 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/macros/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/macros/repository.go -destination=src/api/internal/macros/mock_repository_test.go -package=macros -exclude_interfaces=Reader
+//
+
+// Package macros is a generated GoMock package.
+package macros
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateMacro mocks base method.
+func (m *MockRepository) CreateMacro(ctx context.Context, macro *models.Macro) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMacro", ctx, macro)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMacro indicates an expected call of CreateMacro.
+func (mr *MockRepositoryMockRecorder) CreateMacro(ctx, macro any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMacro", reflect.TypeOf((*MockRepository)(nil).CreateMacro), ctx, macro)
+}
+
+// DeleteMacro mocks base method.
+func (m *MockRepository) DeleteMacro(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMacro", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMacro indicates an expected call of DeleteMacro.
+func (mr *MockRepositoryMockRecorder) DeleteMacro(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMacro", reflect.TypeOf((*MockRepository)(nil).DeleteMacro), ctx, id)
+}
+
+// GetMacro mocks base method.
+func (m *MockRepository) GetMacro(ctx context.Context, id uuid.UUID) (*models.Macro, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMacro", ctx, id)
+	ret0, _ := ret[0].(*models.Macro)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMacro indicates an expected call of GetMacro.
+func (mr *MockRepositoryMockRecorder) GetMacro(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMacro", reflect.TypeOf((*MockRepository)(nil).GetMacro), ctx, id)
+}
+
+// GetMacroVersion mocks base method.
+func (m *MockRepository) GetMacroVersion(ctx context.Context, macroID uuid.UUID, version int) (*models.MacroVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMacroVersion", ctx, macroID, version)
+	ret0, _ := ret[0].(*models.MacroVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMacroVersion indicates an expected call of GetMacroVersion.
+func (mr *MockRepositoryMockRecorder) GetMacroVersion(ctx, macroID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMacroVersion", reflect.TypeOf((*MockRepository)(nil).GetMacroVersion), ctx, macroID, version)
+}
+
+// ListMacroVersions mocks base method.
+func (m *MockRepository) ListMacroVersions(ctx context.Context, macroID uuid.UUID) ([]models.MacroVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMacroVersions", ctx, macroID)
+	ret0, _ := ret[0].([]models.MacroVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMacroVersions indicates an expected call of ListMacroVersions.
+func (mr *MockRepositoryMockRecorder) ListMacroVersions(ctx, macroID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMacroVersions", reflect.TypeOf((*MockRepository)(nil).ListMacroVersions), ctx, macroID)
+}
+
+// ListMacros mocks base method.
+func (m *MockRepository) ListMacros(ctx context.Context) ([]models.Macro, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMacros", ctx)
+	ret0, _ := ret[0].([]models.Macro)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMacros indicates an expected call of ListMacros.
+func (mr *MockRepositoryMockRecorder) ListMacros(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMacros", reflect.TypeOf((*MockRepository)(nil).ListMacros), ctx)
+}
+
+// UpdateMacro mocks base method.
+func (m *MockRepository) UpdateMacro(ctx context.Context, macro *models.Macro) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMacro", ctx, macro)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMacro indicates an expected call of UpdateMacro.
+func (mr *MockRepositoryMockRecorder) UpdateMacro(ctx, macro any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMacro", reflect.TypeOf((*MockRepository)(nil).UpdateMacro), ctx, macro)
+}
@@ -0,0 +1,9 @@
+package macros
+
+// MacroRequest is the request body for creating or replacing a macro
+// The name is how expressions reference the macro, so it must be an identifier
+type MacroRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+	Expression  string `json:"expression" validate:"required,max=10000"`
+}
@@ -0,0 +1,65 @@
+//go:build integration
+
+package macros_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/api/internal/testutil"
+	"github.com/algo-shield/algo-shield/src/pkg/macros"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegration_MacrosRepository_UpdateMacro_StoresVersions(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	repo := macros.NewPostgresRepository(testDB.Postgres, testDB.Redis)
+	ctx := context.Background()
+
+	now := time.Now()
+	macro := &models.Macro{ID: uuid.New(), Name: "highAmount", Expression: "amount > 1000", CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, repo.CreateMacro(ctx, macro))
+	firstVersionID := macro.VersionID
+
+	macro.Expression = "amount > 5000"
+	macro.UpdatedAt = time.Now()
+	require.NoError(t, repo.UpdateMacro(ctx, macro))
+	assert.Equal(t, 2, macro.Version)
+
+	stored, err := repo.GetMacro(ctx, macro.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "amount > 5000", stored.Expression)
+	assert.Equal(t, macro.VersionID, stored.VersionID)
+
+	versions, err := repo.ListMacroVersions(ctx, macro.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+	assert.Equal(t, firstVersionID, versions[1].ID)
+	assert.Equal(t, "amount > 1000", versions[1].Macro.Expression)
+}
+
+func TestIntegration_MacrosRepository_DeleteMacro_KeepsVersions(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	repo := macros.NewPostgresRepository(testDB.Postgres, testDB.Redis)
+	ctx := context.Background()
+
+	now := time.Now()
+	macro := &models.Macro{ID: uuid.New(), Name: "riskyCountry", Expression: `country in ["KP"]`, CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, repo.CreateMacro(ctx, macro))
+
+	require.NoError(t, repo.DeleteMacro(ctx, macro.ID))
+	assert.ErrorIs(t, repo.DeleteMacro(ctx, macro.ID), pgx.ErrNoRows)
+
+	_, err := repo.GetMacro(ctx, macro.ID)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	version, err := repo.GetMacroVersion(ctx, macro.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "riskyCountry", version.Macro.Name)
+}
@@ -0,0 +1,260 @@
+package macros
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/algo-shield/algo-shield/src/pkg/macros"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Service errors
+var (
+	ErrMacroNotFound   = errors.New("macro not found")
+	ErrVersionNotFound = errors.New("macro version not found")
+	ErrMacroNameExists = errors.New("macro with this name already exists")
+	ErrMacroInUse      = errors.New("macro is used by")
+)
+
+// RuleLister lists the rules whose expressions may reference macros
+type RuleLister interface {
+	ListRules(ctx context.Context) ([]models.Rule, error)
+}
+
+// ServiceInterface defines the interface for macro business logic
+type ServiceInterface interface {
+	Create(ctx context.Context, req *MacroRequest) (*models.Macro, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Macro, error)
+	List(ctx context.Context) ([]models.Macro, error)
+	Update(ctx context.Context, id uuid.UUID, req *MacroRequest) (*models.Macro, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListVersions(ctx context.Context, id uuid.UUID) ([]models.MacroVersion, error)
+	GetVersion(ctx context.Context, id uuid.UUID, version int) (*models.MacroVersion, error)
+}
+
+// Service provides business logic for macro operations
+// Every change is validated against the other macros, so the stored set never has a cycle
+type Service struct {
+	repo  macros.Repository
+	rules RuleLister
+}
+
+// NewService creates a new macro service with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewService(repo macros.Repository, rules RuleLister) *Service {
+	return &Service{
+		repo:  repo,
+		rules: rules,
+	}
+}
+
+// Create validates and stores a new macro as its first version
+func (s *Service) Create(ctx context.Context, req *MacroRequest) (*models.Macro, error) {
+	all, err := s.repo.ListMacros(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if findByName(all, req.Name) != nil {
+		return nil, ErrMacroNameExists
+	}
+
+	now := time.Now()
+	macro := &models.Macro{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Description: req.Description,
+		Expression:  req.Expression,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if _, err := expressions.NewMacros(append(all, *macro)); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateMacro(ctx, macro); err != nil {
+		return nil, err
+	}
+
+	return macro, nil
+}
+
+// GetByID returns the current version of a macro
+func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*models.Macro, error) {
+	macro, err := s.repo.GetMacro(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMacroNotFound
+	}
+
+	return macro, err
+}
+
+// List returns every macro
+func (s *Service) List(ctx context.Context) ([]models.Macro, error) {
+	return s.repo.ListMacros(ctx)
+}
+
+// Update replaces a macro, storing the result as its next version
+// Renaming is refused while rules or macros still reference the old name
+func (s *Service) Update(ctx context.Context, id uuid.UUID, req *MacroRequest) (*models.Macro, error) {
+	all, err := s.repo.ListMacros(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current := findByID(all, id)
+	if current == nil {
+		return nil, ErrMacroNotFound
+	}
+
+	if req.Name != current.Name {
+		if findByName(all, req.Name) != nil {
+			return nil, ErrMacroNameExists
+		}
+		if err := s.ensureUnused(ctx, all, current); err != nil {
+			return nil, err
+		}
+	}
+
+	macro := *current
+	macro.Name = req.Name
+	macro.Description = req.Description
+	macro.Expression = req.Expression
+	macro.UpdatedAt = time.Now()
+
+	updated := make([]models.Macro, 0, len(all))
+	for _, other := range all {
+		if other.ID != id {
+			updated = append(updated, other)
+		}
+	}
+	if _, err := expressions.NewMacros(append(updated, macro)); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.UpdateMacro(ctx, &macro)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMacroNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &macro, nil
+}
+
+// Delete removes a macro, refusing while rules or macros still reference it
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	all, err := s.repo.ListMacros(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := findByID(all, id)
+	if current == nil {
+		return ErrMacroNotFound
+	}
+
+	if err := s.ensureUnused(ctx, all, current); err != nil {
+		return err
+	}
+
+	err = s.repo.DeleteMacro(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrMacroNotFound
+	}
+
+	return err
+}
+
+// ListVersions returns every version of a macro, newest first
+// Versions are kept after a macro is deleted
+func (s *Service) ListVersions(ctx context.Context, id uuid.UUID) ([]models.MacroVersion, error) {
+	versions, err := s.repo.ListMacroVersions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every macro has at least its first version
+	if len(versions) == 0 {
+		return nil, ErrMacroNotFound
+	}
+
+	return versions, nil
+}
+
+// GetVersion returns a single version of a macro
+func (s *Service) GetVersion(ctx context.Context, id uuid.UUID, version int) (*models.MacroVersion, error) {
+	macroVersion, err := s.repo.GetMacroVersion(ctx, id, version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrVersionNotFound
+	}
+
+	return macroVersion, err
+}
+
+// ensureUnused returns ErrMacroInUse, naming the users, when a rule or another
+// macro references the macro by its current name
+func (s *Service) ensureUnused(ctx context.Context, all []models.Macro, macro *models.Macro) error {
+	set, err := expressions.NewMacros(all)
+	if err != nil {
+		return err
+	}
+
+	users := make([]string, 0)
+	for _, other := range all {
+		if other.ID != macro.ID && references(set, other.Expression, macro.Name) {
+			users = append(users, "macro "+other.Name)
+		}
+	}
+
+	rules, err := s.rules.ListRules(ctx)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		expression, ok := rule.Conditions["custom_expression"].(string)
+		if ok && references(set, expression, macro.Name) {
+			users = append(users, "rule "+rule.Name)
+		}
+	}
+
+	if len(users) > 0 {
+		return fmt.Errorf("%w %s", ErrMacroInUse, strings.Join(users, ", "))
+	}
+
+	return nil
+}
+
+// references reports whether an expression uses the named macro directly
+func references(set *expressions.Macros, expression, name string) bool {
+	return slices.Contains(set.References(expression), name)
+}
+
+// findByID returns the macro with the ID, or nil
+func findByID(all []models.Macro, id uuid.UUID) *models.Macro {
+	for i := range all {
+		if all[i].ID == id {
+			return &all[i]
+		}
+	}
+	return nil
+}
+
+// findByName returns the macro with the name, or nil
+func findByName(all []models.Macro, name string) *models.Macro {
+	for i := range all {
+		if all[i].Name == name {
+			return &all[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package macros
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Service_Create_WhenValid_ThenStoresMacro(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{{ID: uuid.New(), Name: "highAmount", Expression: "amount > 1000"}}, nil)
+	repo.EXPECT().CreateMacro(gomock.Any(), gomock.Any()).Return(nil)
+	service := NewService(repo, NewMockRuleLister(ctrl))
+
+	macro, err := service.Create(context.Background(), &MacroRequest{Name: "highRisk", Expression: "highAmount and country == \"KP\""})
+
+	require.NoError(t, err)
+	assert.Equal(t, "highRisk", macro.Name)
+	assert.NotEqual(t, uuid.Nil, macro.ID)
+}
+
+func Test_Service_Create_WhenNameExists_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{{ID: uuid.New(), Name: "highAmount", Expression: "amount > 1000"}}, nil)
+	service := NewService(repo, NewMockRuleLister(ctrl))
+
+	_, err := service.Create(context.Background(), &MacroRequest{Name: "highAmount", Expression: "true"})
+
+	assert.ErrorIs(t, err, ErrMacroNameExists)
+}
+
+func Test_Service_Update_WhenIntroducesCycle_ThenReturnsMacroError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	a := models.Macro{ID: uuid.New(), Name: "a", Expression: "b or amount > 1"}
+	b := models.Macro{ID: uuid.New(), Name: "b", Expression: "amount > 100"}
+	repo := NewMockRepository(ctrl)
+	repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{a, b}, nil)
+	service := NewService(repo, NewMockRuleLister(ctrl))
+
+	_, err := service.Update(context.Background(), b.ID, &MacroRequest{Name: "b", Expression: "a and true"})
+
+	var macroErr *expressions.MacroError
+	require.ErrorAs(t, err, &macroErr)
+	assert.ErrorIs(t, err, expressions.ErrMacroCycle)
+}
+
+func Test_Service_Update_WhenValid_ThenStoresNextVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := models.Macro{ID: uuid.New(), Name: "highAmount", Expression: "amount > 1000", Version: 1}
+	repo := NewMockRepository(ctrl)
+	repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{current}, nil)
+	repo.EXPECT().UpdateMacro(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, macro *models.Macro) error {
+		assert.Equal(t, current.ID, macro.ID)
+		assert.Equal(t, "amount > 5000", macro.Expression)
+		macro.Version = 2
+		return nil
+	})
+	service := NewService(repo, NewMockRuleLister(ctrl))
+
+	macro, err := service.Update(context.Background(), current.ID, &MacroRequest{Name: "highAmount", Expression: "amount > 5000"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, macro.Version)
+}
+
+func Test_Service_Update_WhenRenamingReferencedMacro_ThenReturnsInUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := models.Macro{ID: uuid.New(), Name: "highAmount", Expression: "amount > 1000"}
+	repo := NewMockRepository(ctrl)
+	repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{current}, nil)
+	rules := NewMockRuleLister(ctrl)
+	rules.EXPECT().ListRules(gomock.Any()).Return([]models.Rule{
+		{Name: "big-transfer", Conditions: map[string]any{"custom_expression": "highAmount"}},
+	}, nil)
+	service := NewService(repo, rules)
+
+	_, err := service.Update(context.Background(), current.ID, &MacroRequest{Name: "largeAmount", Expression: "amount > 1000"})
+
+	assert.ErrorIs(t, err, ErrMacroInUse)
+	assert.Contains(t, err.Error(), "rule big-transfer")
+}
+
+func Test_Service_Delete_WhenReferencedByMacro_ThenReturnsInUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	highAmount := models.Macro{ID: uuid.New(), Name: "highAmount", Expression: "amount > 1000"}
+	highRisk := models.Macro{ID: uuid.New(), Name: "highRisk", Expression: "highAmount and true"}
+	repo := NewMockRepository(ctrl)
+	repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{highAmount, highRisk}, nil)
+	rules := NewMockRuleLister(ctrl)
+	rules.EXPECT().ListRules(gomock.Any()).Return([]models.Rule{}, nil)
+	service := NewService(repo, rules)
+
+	err := service.Delete(context.Background(), highAmount.ID)
+
+	assert.ErrorIs(t, err, ErrMacroInUse)
+	assert.Contains(t, err.Error(), "macro highRisk")
+}
+
+func Test_Service_Delete_WhenUnused_ThenDeletes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	macro := models.Macro{ID: uuid.New(), Name: "highAmount", Expression: "amount > 1000"}
+	repo := NewMockRepository(ctrl)
+	repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{macro}, nil)
+	repo.EXPECT().DeleteMacro(gomock.Any(), macro.ID).Return(nil)
+	rules := NewMockRuleLister(ctrl)
+	rules.EXPECT().ListRules(gomock.Any()).Return([]models.Rule{
+		{Name: "let-shadowed", Conditions: map[string]any{"custom_expression": "let highAmount = 1; highAmount > 0"}},
+	}, nil)
+	service := NewService(repo, rules)
+
+	assert.NoError(t, service.Delete(context.Background(), macro.ID))
+}
+
+func Test_Service_Delete_WhenNotFound_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{}, nil)
+	service := NewService(repo, NewMockRuleLister(ctrl))
+
+	assert.ErrorIs(t, service.Delete(context.Background(), uuid.New()), ErrMacroNotFound)
+}
@@ -1,28 +1,46 @@
 package routes
 
 import (
+	"context"
 	"strings"
 
 	"github.com/algo-shield/algo-shield/src/api/internal/auth"
+	"github.com/algo-shield/algo-shield/src/api/internal/backtests"
 	"github.com/algo-shield/algo-shield/src/api/internal/branding"
+	"github.com/algo-shield/algo-shield/src/api/internal/countryrisk"
+	"github.com/algo-shield/algo-shield/src/api/internal/deadletters"
 	"github.com/algo-shield/algo-shield/src/api/internal/groups"
 	"github.com/algo-shield/algo-shield/src/api/internal/health"
+	"github.com/algo-shield/algo-shield/src/api/internal/lists"
+	"github.com/algo-shield/algo-shield/src/api/internal/macros"
 	"github.com/algo-shield/algo-shield/src/api/internal/permissions"
 	"github.com/algo-shield/algo-shield/src/api/internal/roles"
 	"github.com/algo-shield/algo-shield/src/api/internal/rules"
 	"github.com/algo-shield/algo-shield/src/api/internal/schemas"
+	"github.com/algo-shield/algo-shield/src/api/internal/screening"
 	"github.com/algo-shield/algo-shield/src/api/internal/shared/middleware"
 	"github.com/algo-shield/algo-shield/src/api/internal/transactions"
 	"github.com/algo-shield/algo-shield/src/api/internal/user"
+	backtestspkg "github.com/algo-shield/algo-shield/src/pkg/backtests"
 	"github.com/algo-shield/algo-shield/src/pkg/config"
+	deadletterspkg "github.com/algo-shield/algo-shield/src/pkg/deadletters"
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/algo-shield/algo-shield/src/pkg/geo"
+	listspkg "github.com/algo-shield/algo-shield/src/pkg/lists"
+	macrospkg "github.com/algo-shield/algo-shield/src/pkg/macros"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/algo-shield/algo-shield/src/pkg/queue"
 	rulespkg "github.com/algo-shield/algo-shield/src/pkg/rules"
+	"github.com/algo-shield/algo-shield/src/pkg/sanctions"
+	"github.com/algo-shield/algo-shield/src/pkg/timeline"
 	"github.com/algo-shield/algo-shield/src/pkg/tokenrevoke"
+	transactionspkg "github.com/algo-shield/algo-shield/src/pkg/transactions"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
-func Setup(app *fiber.App, db *pgxpool.Pool, redis *redis.Client, cfg *config.Config) {
+func Setup(app *fiber.App, db *pgxpool.Pool, redis *redis.Client, publisher queue.Publisher, cfg *config.Config) {
 	// Middleware
 	app.Use(middleware.Logger())
 	app.Use(middleware.SecurityHeaders()) // Security headers for Brave compatibility
@@ -38,6 +56,13 @@ func Setup(app *fiber.App, db *pgxpool.Pool, redis *redis.Client, cfg *config.Co
 	ruleRepo := rulespkg.NewPostgresRepository(db, redis)
 	brandingRepo := branding.NewPostgresRepository(db, redis)
 	schemaRepo := schemas.NewPostgresRepository(db, redis)
+	historyRepo := transactionspkg.NewPostgresHistoryRepository(db)
+	backtestRepo := backtestspkg.NewPostgresRepository(db)
+	countryRiskRepo := geo.NewPostgresCountryRiskRepository(db)
+	listRepo := listspkg.NewPostgresRepository(db, redis)
+	sanctionsRepo := sanctions.NewPostgresRepository(db, redis)
+	macroRepo := macrospkg.NewPostgresRepository(db, redis)
+	deadLetterRepo := deadletterspkg.NewPostgresRepository(db)
 
 	// Create services with dependency injection (business layer - receives interfaces)
 	roleService := roles.NewService(roleRepo)
@@ -46,20 +71,53 @@ func Setup(app *fiber.App, db *pgxpool.Pool, redis *redis.Client, cfg *config.Co
 	tokenRevokeService := tokenrevoke.NewService(redis)
 	authService := auth.NewService(cfg, userService, tokenRevokeService)
 	permissionsService := permissions.NewService(permissionsUserRepo, roleService, groupService)
-	transactionService := transactions.NewService(transactionRepo, redis)
-	brandingService := branding.NewService(brandingRepo)
 	schemaService := schemas.NewService(schemaRepo)
+	decisionReplies := transactions.NewReplyRouter(redis)
+	transactionService := transactions.NewService(transactionRepo, publisher, redis, schemaService, decisionReplies, transactions.DecisionConfig{
+		Timeout:  cfg.API.Decision.Timeout,
+		Fallback: models.RuleAction(cfg.API.Decision.Fallback),
+	}, cfg.API.IdempotencyTTL)
+	brandingService := branding.NewService(brandingRepo)
+	listService := lists.NewService(listRepo)
+	macroService := macros.NewService(macroRepo, ruleRepo)
+	deadLetterService := deadletters.NewService(deadLetterRepo, publisher)
+	screener := sanctions.NewScreener(sanctionsRepo, redis, cfg.Sanctions.MatchThreshold)
+	listCache := listspkg.NewListService(listRepo, redis)   // Same cache and fuzzy index as the workers
+	eventTimeline := timeline.NewRedisTimeline(redis, 0, 0) // Read only: workers record events and trim timelines
+	ruleTester := rules.NewTester(schemaService, macroRepo, expressions.Lookups{
+		History:     historyRepo,
+		CountryRisk: countryRiskRepo,
+		Lists:       listCache,
+		Sanctions:   screener,
+		Timeline:    eventTimeline,
+	})
+	backtestService := backtests.NewService(backtestRepo, redis, ruleTester)
 
 	// Create handlers with dependency injection (presentation layer - receives interfaces)
 	authHandler := auth.NewHandler(authService, userService)
 	permissionsHandler := permissions.NewHandler(permissionsService)
 	roleHandler := roles.NewHandler(roleService)
 	groupHandler := groups.NewHandler(groupService)
-	transactionHandler := transactions.NewHandler(transactionService)
-	ruleHandler := rules.NewHandler(ruleRepo)
+	transactionHandler := transactions.NewHandler(transactionService, cfg.API.Decision.Timeout)
+	ruleHandler := rules.NewHandler(ruleRepo, ruleTester, rulespkg.NewPostgresHealthRepository(db))
 	healthHandler := health.NewHandler(db, redis)
 	brandingHandler := branding.NewHandler(brandingService)
 	schemaHandler := schemas.NewHandler(schemaService)
+	backtestHandler := backtests.NewHandler(backtestService)
+	countryRiskHandler := countryrisk.NewHandler(countryRiskRepo)
+	listHandler := lists.NewHandler(listService)
+	macroHandler := macros.NewHandler(macroService)
+	deadLetterHandler := deadletters.NewHandler(deadLetterService)
+	screeningHandler := screening.NewHandler(screener, sanctionsRepo)
+
+	// Route decision replies from workers to waiting synchronous requests
+	go decisionReplies.Listen(context.Background())
+
+	// Reload the screening index when a sanctions import is announced
+	go screener.SubscribeToInvalidations(context.Background())
+
+	// Reload the lists seen by rule tests when a list changes
+	go listCache.SubscribeToInvalidations(context.Background())
 
 	// Health routes (public)
 	app.Get("/health", healthHandler.Health)
@@ -82,20 +140,37 @@ func Setup(app *fiber.App, db *pgxpool.Pool, redis *redis.Client, cfg *config.Co
 	// Transaction routes (protected)
 	transactionsGroup := v1.Group("/transactions")
 	transactionsGroup.Post("/", transactionHandler.ProcessTransaction)
+	transactionsGroup.Post("/decide", transactionHandler.Decide)
 	transactionsGroup.Get("/", transactionHandler.ListTransactions)
+	transactionsGroup.Get("/shadow-stats", transactionHandler.ShadowRuleStats) // Registered before /:id so it is not parsed as an ID
 	transactionsGroup.Get("/:id", transactionHandler.GetTransaction)
 
 	// Rule routes (protected)
 	rulesGroup := v1.Group("/rules")
 	rulesGroup.Get("/", ruleHandler.ListRules)
 	rulesGroup.Get("/:id", ruleHandler.GetRule)
+	rulesGroup.Get("/:id/health", ruleHandler.GetHealth)
+	rulesGroup.Get("/:id/versions", ruleHandler.ListVersions)
+	rulesGroup.Get("/:id/versions/diff", ruleHandler.DiffVersions)
+	rulesGroup.Get("/:id/versions/:version", ruleHandler.GetVersion)
 
 	// Rule modification requires rule_editor or admin role
 	rulesProtected := rulesGroup.Group("", middleware.RequireAnyRole("admin", "rule_editor"))
 	rulesProtected.Post("/", ruleHandler.CreateRule)
+	rulesProtected.Post("/test", ruleHandler.TestRule)
 	rulesProtected.Put("/:id", ruleHandler.UpdateRule)
+	rulesProtected.Post("/:id/rollback/:version", ruleHandler.RollbackRule)
 	rulesProtected.Delete("/:id", ruleHandler.DeleteRule)
 
+	// Backtest routes (protected)
+	backtestsGroup := v1.Group("/backtests")
+	backtestsGroup.Get("/", backtestHandler.ListBacktests)
+	backtestsGroup.Get("/:id", backtestHandler.GetBacktest)
+
+	// Starting a backtest requires rule_editor or admin role
+	backtestsProtected := backtestsGroup.Group("", middleware.RequireAnyRole("admin", "rule_editor"))
+	backtestsProtected.Post("/", backtestHandler.CreateBacktest)
+
 	// Schema routes (protected)
 	schemasGroup := v1.Group("/schemas")
 	schemasGroup.Get("/", schemaHandler.ListSchemas)
@@ -108,6 +183,50 @@ func Setup(app *fiber.App, db *pgxpool.Pool, redis *redis.Client, cfg *config.Co
 	schemasProtected.Delete("/:id", schemaHandler.DeleteSchema)
 	schemasProtected.Post("/:id/parse", schemaHandler.ParseSchema)
 
+	// Country risk routes (protected)
+	countryRiskGroup := v1.Group("/country-risk")
+	countryRiskGroup.Get("/", countryRiskHandler.ListCountryRisks)
+	countryRiskGroup.Get("/:code", countryRiskHandler.GetCountryRisk)
+
+	// Country risk modification requires rule_editor or admin role
+	countryRiskProtected := countryRiskGroup.Group("", middleware.RequireAnyRole("admin", "rule_editor"))
+	countryRiskProtected.Put("/:code", countryRiskHandler.UpsertCountryRisk)
+	countryRiskProtected.Delete("/:code", countryRiskHandler.DeleteCountryRisk)
+
+	// List routes (protected)
+	listsGroup := v1.Group("/lists")
+	listsGroup.Get("/", listHandler.ListLists)
+	listsGroup.Get("/:id", listHandler.GetList)
+	listsGroup.Get("/:id/entries", listHandler.ListEntries)
+
+	// List modification requires rule_editor or admin role
+	listsProtected := listsGroup.Group("", middleware.RequireAnyRole("admin", "rule_editor"))
+	listsProtected.Post("/", listHandler.CreateList)
+	listsProtected.Put("/:id", listHandler.UpdateList)
+	listsProtected.Delete("/:id", listHandler.DeleteList)
+	listsProtected.Post("/:id/entries", listHandler.AddEntries)
+	listsProtected.Post("/:id/entries/upload", listHandler.UploadEntries)
+	listsProtected.Delete("/:id/entries/:value", listHandler.DeleteEntry)
+
+	// Macro routes (protected)
+	macrosGroup := v1.Group("/macros")
+	macrosGroup.Get("/", macroHandler.ListMacros)
+	macrosGroup.Get("/:id", macroHandler.GetMacro)
+	macrosGroup.Get("/:id/versions", macroHandler.ListVersions)
+	macrosGroup.Get("/:id/versions/:version", macroHandler.GetVersion)
+
+	// Macro modification requires rule_editor or admin role
+	macrosProtected := macrosGroup.Group("", middleware.RequireAnyRole("admin", "rule_editor"))
+	macrosProtected.Post("/", macroHandler.CreateMacro)
+	macrosProtected.Put("/:id", macroHandler.UpdateMacro)
+	macrosProtected.Delete("/:id", macroHandler.DeleteMacro)
+
+	// Screening routes (protected)
+	// Sanctions lists are imported with the sanctions-import command, not through the API
+	screeningGroup := v1.Group("/screening")
+	screeningGroup.Post("/", screeningHandler.Screen)
+	screeningGroup.Get("/imports", screeningHandler.ListImports)
+
 	// Permissions management (admin only)
 	permissionsGroup := v1.Group("/permissions", middleware.RequireRole("admin"))
 	permissionsGroup.Get("/users", permissionsHandler.ListUsers)
@@ -126,6 +245,15 @@ func Setup(app *fiber.App, db *pgxpool.Pool, redis *redis.Client, cfg *config.Co
 	groupsGroup.Get("/", groupHandler.ListGroups)
 	groupsGroup.Get("/:id", groupHandler.GetGroup)
 
+	// Dead letter management (admin only)
+	deadLettersGroup := v1.Group("/dead-letters", middleware.RequireRole("admin"))
+	deadLettersGroup.Get("/", deadLetterHandler.ListDeadLetters)
+	deadLettersGroup.Delete("/", deadLetterHandler.PurgeDeadLetters)
+	deadLettersGroup.Post("/replay", deadLetterHandler.ReplayDeadLetters)
+	deadLettersGroup.Get("/:id", deadLetterHandler.GetDeadLetter)
+	deadLettersGroup.Delete("/:id", deadLetterHandler.DeleteDeadLetter)
+	deadLettersGroup.Post("/:id/replay", deadLetterHandler.ReplayDeadLetter)
+
 	// Branding management (admin only)
 	v1.Put("/branding", middleware.RequireRole("admin"), brandingHandler.UpdateBranding)
 
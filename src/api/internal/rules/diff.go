@@ -0,0 +1,117 @@
+package rules
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+)
+
+// diffIgnoredFields are rule fields that change on every version and carry no content
+var diffIgnoredFields = map[string]bool{
+	"id":         true,
+	"version":    true,
+	"version_id": true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// FieldChange describes a single field that differs between two rule versions
+// Condition keys are reported individually, e.g. "conditions.custom_expression"
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// VersionDiff is the response of the rule version diff endpoint
+type VersionDiff struct {
+	RuleID  uuid.UUID     `json:"rule_id"`
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// DiffRules returns the fields that differ between two rule snapshots, sorted by field name
+// Fields are compared by their JSON representation, as exposed by the API
+func DiffRules(from, to *models.Rule) ([]FieldChange, error) {
+	fromFields, err := ruleFields(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := ruleFields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]FieldChange, 0)
+	for _, field := range unionKeys(fromFields, toFields) {
+		if diffIgnoredFields[field] {
+			continue
+		}
+
+		fromValue, toValue := fromFields[field], toFields[field]
+		if field == "conditions" {
+			changes = append(changes, diffConditions(fromValue, toValue)...)
+			continue
+		}
+
+		if !reflect.DeepEqual(fromValue, toValue) {
+			changes = append(changes, FieldChange{Field: field, From: fromValue, To: toValue})
+		}
+	}
+
+	return changes, nil
+}
+
+// diffConditions reports each changed condition key separately
+func diffConditions(from, to any) []FieldChange {
+	fromConditions, _ := from.(map[string]any)
+	toConditions, _ := to.(map[string]any)
+
+	changes := make([]FieldChange, 0)
+	for _, key := range unionKeys(fromConditions, toConditions) {
+		if !reflect.DeepEqual(fromConditions[key], toConditions[key]) {
+			changes = append(changes, FieldChange{
+				Field: "conditions." + key,
+				From:  fromConditions[key],
+				To:    toConditions[key],
+			})
+		}
+	}
+
+	return changes
+}
+
+// ruleFields converts a rule to its JSON field map
+func ruleFields(rule *models.Rule) (map[string]any, error) {
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// unionKeys returns the sorted keys present in either map
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,48 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiffRules_WhenOnlyVersionMetadataDiffers_ThenReturnsNoChanges(t *testing.T) {
+	from := &models.Rule{ID: uuid.New(), Name: "Rule", Version: 1, VersionID: uuid.New(), UpdatedAt: time.Now()}
+	to := &models.Rule{ID: from.ID, Name: "Rule", Version: 2, VersionID: uuid.New(), UpdatedAt: time.Now().Add(time.Hour)}
+
+	changes, err := DiffRules(from, to)
+
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func Test_DiffRules_WhenConditionKeysAddedAndRemoved_ThenReportsEachKey(t *testing.T) {
+	from := &models.Rule{Conditions: map[string]any{"amount": ">1000", "currency": "USD"}}
+	to := &models.Rule{Conditions: map[string]any{"amount": ">1000", "custom_expression": "amount > 5"}}
+
+	changes, err := DiffRules(from, to)
+
+	require.NoError(t, err)
+	assert.Equal(t, []FieldChange{
+		{Field: "conditions.currency", From: "USD", To: nil},
+		{Field: "conditions.custom_expression", From: nil, To: "amount > 5"},
+	}, changes)
+}
+
+func Test_DiffRules_WhenScalarFieldsChange_ThenReportsSortedChanges(t *testing.T) {
+	from := &models.Rule{Name: "Rule", Priority: 10, Enabled: true, Mode: models.RuleModeActive}
+	to := &models.Rule{Name: "Rule", Priority: 20, Enabled: false, Mode: models.RuleModeShadow}
+
+	changes, err := DiffRules(from, to)
+
+	require.NoError(t, err)
+	assert.Equal(t, []FieldChange{
+		{Field: "enabled", From: true, To: false},
+		{Field: "mode", From: "active", To: "shadow"},
+		{Field: "priority", From: float64(10), To: float64(20)},
+	}, changes)
+}
@@ -3,9 +3,11 @@ package rules
 import (
 	"context"
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/algo-shield/algo-shield/src/api/internal"
+	"github.com/algo-shield/algo-shield/src/api/internal/schemas"
 	"github.com/algo-shield/algo-shield/src/api/internal/shared/validation"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/algo-shield/algo-shield/src/pkg/rules"
@@ -14,15 +16,21 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+var errInvalidVersion = errors.New("rule version must be positive")
+
 type Handler struct {
-	repo rules.Repository
+	repo   rules.Repository
+	tester RuleTester
+	health rules.HealthReader
 }
 
 // NewHandler creates a new rule handler with dependency injection
-// Follows Dependency Inversion Principle - receives interface, not concrete type
-func NewHandler(repo rules.Repository) *Handler {
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewHandler(repo rules.Repository, tester RuleTester, health rules.HealthReader) *Handler {
 	return &Handler{
-		repo: repo,
+		repo:   repo,
+		tester: tester,
+		health: health,
 	}
 }
 
@@ -41,6 +49,18 @@ func (h *Handler) CreateRule(c *fiber.Ctx) error {
 		})
 	}
 
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	if err := h.tester.ValidateRule(ctx, &rule); err != nil {
+		return expressionValidationError(c, err)
+	}
+
+	// Rules without a mode take part in decisions
+	if rule.Mode == "" {
+		rule.Mode = models.RuleModeActive
+	}
+
 	// Set timestamps
 	now := time.Now()
 	if rule.ID == uuid.Nil {
@@ -49,8 +69,6 @@ func (h *Handler) CreateRule(c *fiber.Ctx) error {
 	rule.CreatedAt = now
 	rule.UpdatedAt = now
 
-	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
-	defer cancel()
 	if err := h.repo.CreateRule(ctx, &rule); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create rule",
@@ -130,6 +148,15 @@ func (h *Handler) UpdateRule(c *fiber.Ctx) error {
 
 	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
 	defer cancel()
+
+	if err := h.tester.ValidateRule(ctx, &rule); err != nil {
+		return expressionValidationError(c, err)
+	}
+
+	if rule.Mode == "" {
+		rule.Mode = models.RuleModeActive
+	}
+
 	if err := h.repo.UpdateRule(ctx, &rule); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -168,3 +195,283 @@ func (h *Handler) DeleteRule(c *fiber.Ctx) error {
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// TestRule compiles an expression against a schema and evaluates it against sample events
+// without saving anything. Compile errors are reported in the response body.
+func (h *Handler) TestRule(c *fiber.Ctx) error {
+	var req TestRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := validation.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if req.SchemaID == uuid.Nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "schema_id is required",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+	result, err := h.tester.Test(ctx, &req)
+	if err != nil {
+		if errors.Is(err, schemas.ErrSchemaNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Schema not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to test rule",
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// expressionValidationError maps a rule expression validation error to a response
+func expressionValidationError(c *fiber.Ctx, err error) error {
+	var exprErr *ExpressionError
+	switch {
+	case errors.As(err, &exprErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":         exprErr.Error(),
+			"compile_error": exprErr.CompileError,
+		})
+	case errors.Is(err, ErrExpressionNotString), errors.Is(err, ErrExpressionNoSchema):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, schemas.ErrSchemaNotFound):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Schema not found",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to validate rule expression",
+		})
+	}
+}
+
+// GetHealth returns the evaluation health of a rule on live traffic
+// A rule that was never evaluated reports an empty summary
+func (h *Handler) GetHealth(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	if _, err := h.repo.GetRule(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Rule not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch rule",
+		})
+	}
+
+	health, err := h.health.GetRuleHealth(ctx, id)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch rule health",
+			})
+		}
+		health = &models.RuleHealth{RuleID: id}
+	}
+
+	return c.JSON(health)
+}
+
+// ListVersions lists the stored versions of a rule, newest first
+func (h *Handler) ListVersions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	versions, err := h.repo.ListRuleVersions(ctx, id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch rule versions",
+		})
+	}
+
+	// Every rule has at least its first version
+	if len(versions) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Rule not found",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"versions": versions,
+	})
+}
+
+// GetVersion retrieves a single version of a rule
+func (h *Handler) GetVersion(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule ID",
+		})
+	}
+
+	version, err := parseVersion(c.Params("version"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule version",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	ruleVersion, err := h.repo.GetRuleVersion(ctx, id, version)
+	if err != nil {
+		return versionLookupError(c, err)
+	}
+
+	return c.JSON(ruleVersion)
+}
+
+// DiffVersions compares two versions of a rule field by field
+// The versions are given by the from and to query parameters
+func (h *Handler) DiffVersions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule ID",
+		})
+	}
+
+	from, err := parseVersion(c.Query("from"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid from version",
+		})
+	}
+	to, err := parseVersion(c.Query("to"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid to version",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	fromVersion, err := h.repo.GetRuleVersion(ctx, id, from)
+	if err != nil {
+		return versionLookupError(c, err)
+	}
+	toVersion, err := h.repo.GetRuleVersion(ctx, id, to)
+	if err != nil {
+		return versionLookupError(c, err)
+	}
+
+	changes, err := DiffRules(&fromVersion.Rule, &toVersion.Rule)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to diff rule versions",
+		})
+	}
+
+	return c.JSON(VersionDiff{
+		RuleID:  id,
+		From:    from,
+		To:      to,
+		Changes: changes,
+	})
+}
+
+// RollbackRule restores the content of a previous version
+// History is never rewritten: the restored content is saved as a new version
+func (h *Handler) RollbackRule(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule ID",
+		})
+	}
+
+	version, err := parseVersion(c.Params("version"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule version",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	target, err := h.repo.GetRuleVersion(ctx, id, version)
+	if err != nil {
+		return versionLookupError(c, err)
+	}
+
+	rule := target.Rule
+	rule.ID = id
+	rule.UpdatedAt = time.Now()
+
+	// The schema may have changed since the version was saved
+	if err := h.tester.ValidateRule(ctx, &rule); err != nil {
+		return expressionValidationError(c, err)
+	}
+
+	if err := h.repo.UpdateRule(ctx, &rule); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Rule not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to roll back rule",
+		})
+	}
+
+	return c.JSON(rule)
+}
+
+// parseVersion parses a positive rule version number
+func parseVersion(value string) (int, error) {
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if version < 1 {
+		return 0, errInvalidVersion
+	}
+	return version, nil
+}
+
+// versionLookupError maps a rule version lookup error to a response
+func versionLookupError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Rule version not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to fetch rule version",
+	})
+}
@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/algo-shield/algo-shield/src/api/internal/schemas"
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -25,7 +27,7 @@ func Test_Handler_NewHandler_WhenCalled_ThenReturnsHandler(t *testing.T) {
 
 	repo := NewMockRepository(ctrl)
 
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	assert.NotNil(t, handler)
 	assert.Equal(t, repo, handler.repo)
@@ -36,7 +38,7 @@ func Test_Handler_CreateRule_WhenValidRule_ThenCreatesRule(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Post("/rules", handler.CreateRule)
@@ -76,7 +78,7 @@ func Test_Handler_CreateRule_WhenInvalidJSON_ThenReturnsBadRequest(t *testing.T)
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Post("/rules", handler.CreateRule)
@@ -99,7 +101,7 @@ func Test_Handler_CreateRule_WhenValidationFails_ThenReturnsBadRequest(t *testin
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Post("/rules", handler.CreateRule)
@@ -129,7 +131,7 @@ func Test_Handler_CreateRule_WhenRepositoryFails_ThenReturnsInternalError(t *tes
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Post("/rules", handler.CreateRule)
@@ -165,7 +167,7 @@ func Test_Handler_GetRule_WhenRuleExists_ThenReturnsRule(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Get("/rules/:id", handler.GetRule)
@@ -204,7 +206,7 @@ func Test_Handler_GetRule_WhenInvalidID_ThenReturnsBadRequest(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Get("/rules/:id", handler.GetRule)
@@ -226,7 +228,7 @@ func Test_Handler_GetRule_WhenRuleNotFound_ThenReturnsNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Get("/rules/:id", handler.GetRule)
@@ -251,7 +253,7 @@ func Test_Handler_GetRule_WhenRepositoryFails_ThenReturnsInternalError(t *testin
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Get("/rules/:id", handler.GetRule)
@@ -276,7 +278,7 @@ func Test_Handler_ListRules_WhenRulesExist_ThenReturnsRules(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Get("/rules", handler.ListRules)
@@ -323,7 +325,7 @@ func Test_Handler_ListRules_WhenRepositoryFails_ThenReturnsInternalError(t *test
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Get("/rules", handler.ListRules)
@@ -347,7 +349,7 @@ func Test_Handler_UpdateRule_WhenValidRule_ThenUpdatesRule(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Put("/rules/:id", handler.UpdateRule)
@@ -387,7 +389,7 @@ func Test_Handler_UpdateRule_WhenInvalidID_ThenReturnsBadRequest(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Put("/rules/:id", handler.UpdateRule)
@@ -421,7 +423,7 @@ func Test_Handler_UpdateRule_WhenInvalidJSON_ThenReturnsBadRequest(t *testing.T)
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Put("/rules/:id", handler.UpdateRule)
@@ -446,7 +448,7 @@ func Test_Handler_UpdateRule_WhenValidationFails_ThenReturnsBadRequest(t *testin
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Put("/rules/:id", handler.UpdateRule)
@@ -477,7 +479,7 @@ func Test_Handler_UpdateRule_WhenRuleNotFound_ThenReturnsNotFound(t *testing.T)
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Put("/rules/:id", handler.UpdateRule)
@@ -514,7 +516,7 @@ func Test_Handler_UpdateRule_WhenRepositoryFails_ThenReturnsInternalError(t *tes
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Put("/rules/:id", handler.UpdateRule)
@@ -551,7 +553,7 @@ func Test_Handler_DeleteRule_WhenRuleExists_ThenDeletesRule(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Delete("/rules/:id", handler.DeleteRule)
@@ -572,7 +574,7 @@ func Test_Handler_DeleteRule_WhenInvalidID_ThenReturnsBadRequest(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Delete("/rules/:id", handler.DeleteRule)
@@ -594,7 +596,7 @@ func Test_Handler_DeleteRule_WhenRuleNotFound_ThenReturnsNotFound(t *testing.T)
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Delete("/rules/:id", handler.DeleteRule)
@@ -619,7 +621,7 @@ func Test_Handler_DeleteRule_WhenRepositoryFails_ThenReturnsInternalError(t *tes
 	defer ctrl.Finish()
 
 	repo := NewMockRepository(ctrl)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
 
 	app := fiber.New()
 	app.Delete("/rules/:id", handler.DeleteRule)
@@ -638,3 +640,568 @@ func Test_Handler_DeleteRule_WhenRepositoryFails_ThenReturnsInternalError(t *tes
 	require.NoError(t, err)
 	assert.Contains(t, string(body), "Failed to delete rule")
 }
+
+func Test_Handler_CreateRule_WhenExpressionInvalid_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	tester := NewMockRuleTester(ctrl)
+	handler := NewHandler(repo, tester, nil)
+
+	app := fiber.New()
+	app.Post("/rules", handler.CreateRule)
+
+	schemaID := uuid.New()
+	rule := models.Rule{
+		Name:       "Broken Rule",
+		Action:     models.ActionBlock,
+		SchemaID:   &schemaID,
+		Conditions: map[string]any{"custom_expression": "amount +"},
+	}
+
+	tester.EXPECT().ValidateRule(gomock.Any(), gomock.Any()).
+		Return(&ExpressionError{CompileError: expressions.CompileError{Message: "unexpected token EOF", Line: 1, Column: 8}})
+
+	body, err := json.Marshal(rule)
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var result map[string]any
+	respBody, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(respBody, &result))
+	compileErr, ok := result["compile_error"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, float64(8), compileErr["column"])
+}
+
+func Test_Handler_UpdateRule_WhenSchemaNotFound_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	tester := NewMockRuleTester(ctrl)
+	handler := NewHandler(repo, tester, nil)
+
+	app := fiber.New()
+	app.Put("/rules/:id", handler.UpdateRule)
+
+	schemaID := uuid.New()
+	rule := models.Rule{
+		Name:       "Rule",
+		Action:     models.ActionBlock,
+		SchemaID:   &schemaID,
+		Conditions: map[string]any{"custom_expression": "amount > 1"},
+	}
+
+	tester.EXPECT().ValidateRule(gomock.Any(), gomock.Any()).Return(schemas.ErrSchemaNotFound)
+
+	body, err := json.Marshal(rule)
+	require.NoError(t, err)
+	req := httptest.NewRequest("PUT", "/rules/"+uuid.New().String(), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_TestRule_WhenValidRequest_ThenReturnsResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	tester := NewMockRuleTester(ctrl)
+	handler := NewHandler(repo, tester, nil)
+
+	app := fiber.New()
+	app.Post("/rules/test", handler.TestRule)
+
+	testReq := TestRuleRequest{
+		Expression: "amount > 1000",
+		SchemaID:   uuid.New(),
+		Events:     []map[string]any{{"amount": 5000.0}},
+	}
+
+	tester.EXPECT().Test(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *TestRuleRequest) (*TestRuleResponse, error) {
+			assert.Equal(t, testReq.Expression, req.Expression)
+			assert.Equal(t, testReq.SchemaID, req.SchemaID)
+			return &TestRuleResponse{
+				Compiled: true,
+				Results:  []EventResult{{Index: 0, Matched: true}},
+			}, nil
+		},
+	)
+
+	body, err := json.Marshal(testReq)
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/rules/test", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result TestRuleResponse
+	respBody, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(respBody, &result))
+	assert.True(t, result.Compiled)
+	require.Len(t, result.Results, 1)
+	assert.True(t, result.Results[0].Matched)
+}
+
+func Test_Handler_TestRule_WhenNoEvents_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(NewMockRepository(ctrl), NewMockRuleTester(ctrl), nil)
+
+	app := fiber.New()
+	app.Post("/rules/test", handler.TestRule)
+
+	body, err := json.Marshal(TestRuleRequest{Expression: "amount > 1", SchemaID: uuid.New()})
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/rules/test", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_TestRule_WhenSchemaNotFound_ThenReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tester := NewMockRuleTester(ctrl)
+	handler := NewHandler(NewMockRepository(ctrl), tester, nil)
+
+	app := fiber.New()
+	app.Post("/rules/test", handler.TestRule)
+
+	tester.EXPECT().Test(gomock.Any(), gomock.Any()).Return(nil, schemas.ErrSchemaNotFound)
+
+	body, err := json.Marshal(TestRuleRequest{
+		Expression: "amount > 1",
+		SchemaID:   uuid.New(),
+		Events:     []map[string]any{{"amount": 5000.0}},
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/rules/test", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Handler_CreateRule_WhenModeOmitted_ThenDefaultsToActive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
+
+	app := fiber.New()
+	app.Post("/rules", handler.CreateRule)
+
+	repo.EXPECT().CreateRule(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, r *models.Rule) error {
+			assert.Equal(t, models.RuleModeActive, r.Mode)
+			return nil
+		},
+	)
+
+	body, err := json.Marshal(models.Rule{
+		Name:       "Test Rule",
+		Action:     models.ActionBlock,
+		Conditions: map[string]any{"amount": ">1000"},
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+}
+
+func Test_Handler_CreateRule_WhenModeInvalid_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(NewMockRepository(ctrl), NewTester(nil, nil, expressions.Lookups{}), nil)
+
+	app := fiber.New()
+	app.Post("/rules", handler.CreateRule)
+
+	body, err := json.Marshal(models.Rule{
+		Name:       "Test Rule",
+		Action:     models.ActionBlock,
+		Mode:       "monitor",
+		Conditions: map[string]any{"amount": ">1000"},
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_ListVersions_WhenVersionsExist_ThenReturnsVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
+
+	app := fiber.New()
+	app.Get("/rules/:id/versions", handler.ListVersions)
+
+	ruleID := uuid.New()
+	versions := []models.RuleVersion{
+		{ID: uuid.New(), RuleID: ruleID, Version: 2, Rule: models.Rule{ID: ruleID, Name: "Rule v2"}},
+		{ID: uuid.New(), RuleID: ruleID, Version: 1, Rule: models.Rule{ID: ruleID, Name: "Rule v1"}},
+	}
+
+	repo.EXPECT().ListRuleVersions(gomock.Any(), ruleID).Return(versions, nil)
+
+	req := httptest.NewRequest("GET", "/rules/"+ruleID.String()+"/versions", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Versions []models.RuleVersion `json:"versions"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Versions, 2)
+	assert.Equal(t, 2, result.Versions[0].Version)
+	assert.Equal(t, "Rule v1", result.Versions[1].Rule.Name)
+}
+
+func Test_Handler_ListVersions_WhenNoVersions_ThenReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
+
+	app := fiber.New()
+	app.Get("/rules/:id/versions", handler.ListVersions)
+
+	repo.EXPECT().ListRuleVersions(gomock.Any(), gomock.Any()).Return([]models.RuleVersion{}, nil)
+
+	req := httptest.NewRequest("GET", "/rules/"+uuid.New().String()+"/versions", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Handler_GetVersion_WhenVersionNotFound_ThenReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
+
+	app := fiber.New()
+	app.Get("/rules/:id/versions/:version", handler.GetVersion)
+
+	ruleID := uuid.New()
+	repo.EXPECT().GetRuleVersion(gomock.Any(), ruleID, 7).Return(nil, pgx.ErrNoRows)
+
+	req := httptest.NewRequest("GET", "/rules/"+ruleID.String()+"/versions/7", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Handler_GetVersion_WhenVersionInvalid_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
+
+	app := fiber.New()
+	app.Get("/rules/:id/versions/:version", handler.GetVersion)
+
+	req := httptest.NewRequest("GET", "/rules/"+uuid.New().String()+"/versions/0", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_DiffVersions_WhenVersionsDiffer_ThenReturnsChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
+
+	app := fiber.New()
+	app.Get("/rules/:id/versions/diff", handler.DiffVersions)
+
+	ruleID := uuid.New()
+	v1 := &models.RuleVersion{RuleID: ruleID, Version: 1, Rule: models.Rule{
+		ID: ruleID, Name: "Rule", Action: models.ActionReview, Version: 1,
+		Conditions: map[string]any{"custom_expression": "amount > 1000"},
+	}}
+	v2 := &models.RuleVersion{RuleID: ruleID, Version: 2, Rule: models.Rule{
+		ID: ruleID, Name: "Rule", Action: models.ActionBlock, Version: 2,
+		Conditions: map[string]any{"custom_expression": "amount > 5000"},
+	}}
+
+	repo.EXPECT().GetRuleVersion(gomock.Any(), ruleID, 1).Return(v1, nil)
+	repo.EXPECT().GetRuleVersion(gomock.Any(), ruleID, 2).Return(v2, nil)
+
+	req := httptest.NewRequest("GET", "/rules/"+ruleID.String()+"/versions/diff?from=1&to=2", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result VersionDiff
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, 1, result.From)
+	assert.Equal(t, 2, result.To)
+	assert.Equal(t, []FieldChange{
+		{Field: "action", From: "review", To: "block"},
+		{Field: "conditions.custom_expression", From: "amount > 1000", To: "amount > 5000"},
+	}, result.Changes)
+}
+
+func Test_Handler_DiffVersions_WhenToMissing_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
+
+	app := fiber.New()
+	app.Get("/rules/:id/versions/diff", handler.DiffVersions)
+
+	req := httptest.NewRequest("GET", "/rules/"+uuid.New().String()+"/versions/diff?from=1", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_RollbackRule_WhenVersionExists_ThenSavesItAsNewVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
+
+	app := fiber.New()
+	app.Post("/rules/:id/rollback/:version", handler.RollbackRule)
+
+	ruleID := uuid.New()
+	target := &models.RuleVersion{RuleID: ruleID, Version: 1, Rule: models.Rule{
+		ID: ruleID, Name: "Original", Action: models.ActionReview, Version: 1, VersionID: uuid.New(),
+		Conditions: map[string]any{"amount": ">1000"},
+	}}
+
+	repo.EXPECT().GetRuleVersion(gomock.Any(), ruleID, 1).Return(target, nil)
+	repo.EXPECT().UpdateRule(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, r *models.Rule) error {
+			assert.Equal(t, ruleID, r.ID)
+			assert.Equal(t, "Original", r.Name)
+			assert.Equal(t, models.ActionReview, r.Action)
+			r.Version = 4
+			return nil
+		},
+	)
+
+	req := httptest.NewRequest("POST", "/rules/"+ruleID.String()+"/rollback/1", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result models.Rule
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, 4, result.Version)
+}
+
+func Test_Handler_RollbackRule_WhenVersionNotFound_ThenReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), nil)
+
+	app := fiber.New()
+	app.Post("/rules/:id/rollback/:version", handler.RollbackRule)
+
+	repo.EXPECT().GetRuleVersion(gomock.Any(), gomock.Any(), 3).Return(nil, pgx.ErrNoRows)
+
+	req := httptest.NewRequest("POST", "/rules/"+uuid.New().String()+"/rollback/3", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Handler_RollbackRule_WhenExpressionNoLongerCompiles_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	tester := NewMockRuleTester(ctrl)
+	handler := NewHandler(repo, tester, nil)
+
+	app := fiber.New()
+	app.Post("/rules/:id/rollback/:version", handler.RollbackRule)
+
+	ruleID := uuid.New()
+	target := &models.RuleVersion{RuleID: ruleID, Version: 1, Rule: models.Rule{ID: ruleID, Name: "Original"}}
+
+	repo.EXPECT().GetRuleVersion(gomock.Any(), ruleID, 1).Return(target, nil)
+	tester.EXPECT().ValidateRule(gomock.Any(), gomock.Any()).Return(schemas.ErrSchemaNotFound)
+
+	req := httptest.NewRequest("POST", "/rules/"+ruleID.String()+"/rollback/1", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_GetHealth_WhenHealthRecorded_ThenReturnsHealth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	health := NewMockHealthReader(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), health)
+
+	app := fiber.New()
+	app.Get("/rules/:id/health", handler.GetHealth)
+
+	ruleID := uuid.New()
+	repo.EXPECT().GetRule(gomock.Any(), ruleID).Return(&models.Rule{ID: ruleID}, nil)
+	health.EXPECT().GetRuleHealth(gomock.Any(), ruleID).Return(&models.RuleHealth{
+		RuleID:              ruleID,
+		Evaluations:         10,
+		Errors:              4,
+		ErrorRate:           0.4,
+		ConsecutiveFailures: 4,
+		LastError:           "boom",
+		LastErrorKind:       models.RuleErrorRuntime,
+		Flagged:             true,
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/rules/"+ruleID.String()+"/health", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result models.RuleHealth
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, 0.4, result.ErrorRate)
+	assert.Equal(t, models.RuleErrorRuntime, result.LastErrorKind)
+	assert.True(t, result.Flagged)
+}
+
+func Test_Handler_GetHealth_WhenNeverEvaluated_ThenReturnsEmptyHealth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	health := NewMockHealthReader(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), health)
+
+	app := fiber.New()
+	app.Get("/rules/:id/health", handler.GetHealth)
+
+	ruleID := uuid.New()
+	repo.EXPECT().GetRule(gomock.Any(), ruleID).Return(&models.Rule{ID: ruleID}, nil)
+	health.EXPECT().GetRuleHealth(gomock.Any(), ruleID).Return(nil, pgx.ErrNoRows)
+
+	req := httptest.NewRequest("GET", "/rules/"+ruleID.String()+"/health", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result models.RuleHealth
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, ruleID, result.RuleID)
+	assert.Zero(t, result.Evaluations)
+}
+
+func Test_Handler_GetHealth_WhenRuleNotFound_ThenReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), NewMockHealthReader(ctrl))
+
+	app := fiber.New()
+	app.Get("/rules/:id/health", handler.GetHealth)
+
+	repo.EXPECT().GetRule(gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows)
+
+	req := httptest.NewRequest("GET", "/rules/"+uuid.New().String()+"/health", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Handler_GetHealth_WhenHealthLookupFails_ThenReturnsInternalError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockRepository(ctrl)
+	health := NewMockHealthReader(ctrl)
+	handler := NewHandler(repo, NewTester(nil, nil, expressions.Lookups{}), health)
+
+	app := fiber.New()
+	app.Get("/rules/:id/health", handler.GetHealth)
+
+	repo.EXPECT().GetRule(gomock.Any(), gomock.Any()).Return(&models.Rule{}, nil)
+	health.EXPECT().GetRuleHealth(gomock.Any(), gomock.Any()).Return(nil, errors.New("db down"))
+
+	req := httptest.NewRequest("GET", "/rules/"+uuid.New().String()+"/health", nil)
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
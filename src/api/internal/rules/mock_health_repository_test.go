@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/rules/health_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/rules/health_repository.go -destination=src/api/internal/rules/mock_health_repository_test.go -package=rules HealthReader
+//
+
+// Package rules is a generated GoMock package.
+package rules
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHealthReader is a mock of HealthReader interface.
+type MockHealthReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthReaderMockRecorder is the mock recorder for MockHealthReader.
+type MockHealthReaderMockRecorder struct {
+	mock *MockHealthReader
+}
+
+// NewMockHealthReader creates a new mock instance.
+func NewMockHealthReader(ctrl *gomock.Controller) *MockHealthReader {
+	mock := &MockHealthReader{ctrl: ctrl}
+	mock.recorder = &MockHealthReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthReader) EXPECT() *MockHealthReaderMockRecorder {
+	return m.recorder
+}
+
+// GetRuleHealth mocks base method.
+func (m *MockHealthReader) GetRuleHealth(ctx context.Context, ruleID uuid.UUID) (*models.RuleHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuleHealth", ctx, ruleID)
+	ret0, _ := ret[0].(*models.RuleHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRuleHealth indicates an expected call of GetRuleHealth.
+func (mr *MockHealthReaderMockRecorder) GetRuleHealth(ctx, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuleHealth", reflect.TypeOf((*MockHealthReader)(nil).GetRuleHealth), ctx, ruleID)
+}
+
+// MockHealthRepository is a mock of HealthRepository interface.
+type MockHealthRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthRepositoryMockRecorder is the mock recorder for MockHealthRepository.
+type MockHealthRepositoryMockRecorder struct {
+	mock *MockHealthRepository
+}
+
+// NewMockHealthRepository creates a new mock instance.
+func NewMockHealthRepository(ctrl *gomock.Controller) *MockHealthRepository {
+	mock := &MockHealthRepository{ctrl: ctrl}
+	mock.recorder = &MockHealthRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthRepository) EXPECT() *MockHealthRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FlagRule mocks base method.
+func (m *MockHealthRepository) FlagRule(ctx context.Context, ruleID uuid.UUID, autoDisabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlagRule", ctx, ruleID, autoDisabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlagRule indicates an expected call of FlagRule.
+func (mr *MockHealthRepositoryMockRecorder) FlagRule(ctx, ruleID, autoDisabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagRule", reflect.TypeOf((*MockHealthRepository)(nil).FlagRule), ctx, ruleID, autoDisabled)
+}
+
+// GetRuleHealth mocks base method.
+func (m *MockHealthRepository) GetRuleHealth(ctx context.Context, ruleID uuid.UUID) (*models.RuleHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuleHealth", ctx, ruleID)
+	ret0, _ := ret[0].(*models.RuleHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRuleHealth indicates an expected call of GetRuleHealth.
+func (mr *MockHealthRepositoryMockRecorder) GetRuleHealth(ctx, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuleHealth", reflect.TypeOf((*MockHealthRepository)(nil).GetRuleHealth), ctx, ruleID)
+}
+
+// RecordHealth mocks base method.
+func (m *MockHealthRepository) RecordHealth(ctx context.Context, delta models.RuleHealthDelta) (*models.RuleHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordHealth", ctx, delta)
+	ret0, _ := ret[0].(*models.RuleHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordHealth indicates an expected call of RecordHealth.
+func (mr *MockHealthRepositoryMockRecorder) RecordHealth(ctx, delta any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordHealth", reflect.TypeOf((*MockHealthRepository)(nil).RecordHealth), ctx, delta)
+}
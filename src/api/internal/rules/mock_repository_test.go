@@ -124,6 +124,36 @@ func (mr *MockRepositoryMockRecorder) GetRule(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRule", reflect.TypeOf((*MockRepository)(nil).GetRule), ctx, id)
 }
 
+// GetRuleVersion mocks base method.
+func (m *MockRepository) GetRuleVersion(ctx context.Context, ruleID uuid.UUID, version int) (*models.RuleVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuleVersion", ctx, ruleID, version)
+	ret0, _ := ret[0].(*models.RuleVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRuleVersion indicates an expected call of GetRuleVersion.
+func (mr *MockRepositoryMockRecorder) GetRuleVersion(ctx, ruleID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuleVersion", reflect.TypeOf((*MockRepository)(nil).GetRuleVersion), ctx, ruleID, version)
+}
+
+// ListRuleVersions mocks base method.
+func (m *MockRepository) ListRuleVersions(ctx context.Context, ruleID uuid.UUID) ([]models.RuleVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRuleVersions", ctx, ruleID)
+	ret0, _ := ret[0].([]models.RuleVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRuleVersions indicates an expected call of ListRuleVersions.
+func (mr *MockRepositoryMockRecorder) ListRuleVersions(ctx, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRuleVersions", reflect.TypeOf((*MockRepository)(nil).ListRuleVersions), ctx, ruleID)
+}
+
 // ListRules mocks base method.
 func (m *MockRepository) ListRules(ctx context.Context) ([]models.Rule, error) {
 	m.ctrl.T.Helper()
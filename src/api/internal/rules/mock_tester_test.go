@@ -0,0 +1,151 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/api/internal/rules/tester.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/api/internal/rules/tester.go -destination=src/api/internal/rules/mock_tester_test.go -package=rules
+//
+
+// Package rules is a generated GoMock package.
+package rules
+
+import (
+	context "context"
+	reflect "reflect"
+
+	schemas "github.com/algo-shield/algo-shield/src/api/internal/schemas"
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSchemaGetter is a mock of SchemaGetter interface.
+type MockSchemaGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchemaGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockSchemaGetterMockRecorder is the mock recorder for MockSchemaGetter.
+type MockSchemaGetterMockRecorder struct {
+	mock *MockSchemaGetter
+}
+
+// NewMockSchemaGetter creates a new mock instance.
+func NewMockSchemaGetter(ctrl *gomock.Controller) *MockSchemaGetter {
+	mock := &MockSchemaGetter{ctrl: ctrl}
+	mock.recorder = &MockSchemaGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchemaGetter) EXPECT() *MockSchemaGetterMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockSchemaGetter) GetByID(ctx context.Context, id uuid.UUID) (*schemas.EventSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*schemas.EventSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockSchemaGetterMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSchemaGetter)(nil).GetByID), ctx, id)
+}
+
+// MockMacroLister is a mock of MacroLister interface.
+type MockMacroLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockMacroListerMockRecorder
+	isgomock struct{}
+}
+
+// MockMacroListerMockRecorder is the mock recorder for MockMacroLister.
+type MockMacroListerMockRecorder struct {
+	mock *MockMacroLister
+}
+
+// NewMockMacroLister creates a new mock instance.
+func NewMockMacroLister(ctrl *gomock.Controller) *MockMacroLister {
+	mock := &MockMacroLister{ctrl: ctrl}
+	mock.recorder = &MockMacroListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMacroLister) EXPECT() *MockMacroListerMockRecorder {
+	return m.recorder
+}
+
+// ListMacros mocks base method.
+func (m *MockMacroLister) ListMacros(ctx context.Context) ([]models.Macro, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMacros", ctx)
+	ret0, _ := ret[0].([]models.Macro)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMacros indicates an expected call of ListMacros.
+func (mr *MockMacroListerMockRecorder) ListMacros(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMacros", reflect.TypeOf((*MockMacroLister)(nil).ListMacros), ctx)
+}
+
+// MockRuleTester is a mock of RuleTester interface.
+type MockRuleTester struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuleTesterMockRecorder
+	isgomock struct{}
+}
+
+// MockRuleTesterMockRecorder is the mock recorder for MockRuleTester.
+type MockRuleTesterMockRecorder struct {
+	mock *MockRuleTester
+}
+
+// NewMockRuleTester creates a new mock instance.
+func NewMockRuleTester(ctrl *gomock.Controller) *MockRuleTester {
+	mock := &MockRuleTester{ctrl: ctrl}
+	mock.recorder = &MockRuleTesterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRuleTester) EXPECT() *MockRuleTesterMockRecorder {
+	return m.recorder
+}
+
+// Test mocks base method.
+func (m *MockRuleTester) Test(ctx context.Context, req *TestRuleRequest) (*TestRuleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Test", ctx, req)
+	ret0, _ := ret[0].(*TestRuleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Test indicates an expected call of Test.
+func (mr *MockRuleTesterMockRecorder) Test(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Test", reflect.TypeOf((*MockRuleTester)(nil).Test), ctx, req)
+}
+
+// ValidateRule mocks base method.
+func (m *MockRuleTester) ValidateRule(ctx context.Context, rule *models.Rule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateRule", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateRule indicates an expected call of ValidateRule.
+func (mr *MockRuleTesterMockRecorder) ValidateRule(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateRule", reflect.TypeOf((*MockRuleTester)(nil).ValidateRule), ctx, rule)
+}
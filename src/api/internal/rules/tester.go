@@ -0,0 +1,178 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/algo-shield/algo-shield/src/api/internal/schemas"
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Tester errors
+var (
+	ErrExpressionNotString = errors.New("custom_expression must be a string")
+	ErrExpressionNoSchema  = errors.New("schema_id is required for rules with a custom_expression")
+)
+
+// ExpressionError is returned when a rule expression fails to compile
+type ExpressionError struct {
+	CompileError expressions.CompileError
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("expression compile error at line %d, column %d: %s",
+		e.CompileError.Line, e.CompileError.Column, e.CompileError.Message)
+}
+
+// SchemaGetter loads the schema an expression is compiled against
+type SchemaGetter interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*schemas.EventSchema, error)
+}
+
+// MacroLister loads the macros inlined into rule expressions
+type MacroLister interface {
+	ListMacros(ctx context.Context) ([]models.Macro, error)
+}
+
+// RuleTester validates rule expressions and runs them against sample events
+type RuleTester interface {
+	// ValidateRule rejects rules whose custom_expression does not compile to a boolean
+	ValidateRule(ctx context.Context, rule *models.Rule) error
+	// Test compiles an expression and evaluates it against each sample event
+	Test(ctx context.Context, req *TestRuleRequest) (*TestRuleResponse, error)
+}
+
+// TestRuleRequest is the request body for dry-running an expression
+type TestRuleRequest struct {
+	Expression  string           `json:"expression" validate:"required"`
+	SchemaID    uuid.UUID        `json:"schema_id"`
+	Events      []map[string]any `json:"events" validate:"required,min=1,max=100"`
+	LiveHelpers bool             `json:"live_helpers"` // Query transaction history for velocity helpers instead of returning zero
+}
+
+// TestRuleResponse reports the compile outcome and the per-event results
+type TestRuleResponse struct {
+	Compiled     bool                      `json:"compiled"`
+	CompileError *expressions.CompileError `json:"compile_error,omitempty"`
+	Results      []EventResult             `json:"results"`
+}
+
+// EventResult is the outcome of evaluating an expression against one sample event
+type EventResult struct {
+	Index   int    `json:"index"`
+	Matched bool   `json:"matched"`
+	Error   string `json:"error,omitempty"` // Runtime error, the event did not match
+}
+
+// Tester compiles rule expressions the same way the worker does
+type Tester struct {
+	schemas SchemaGetter
+	macros  MacroLister
+	lookups expressions.Lookups
+}
+
+// NewTester creates a new rule tester with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+// macros may be nil, in which case expressions are compiled without macros
+func NewTester(schemas SchemaGetter, macros MacroLister, lookups expressions.Lookups) *Tester {
+	return &Tester{
+		schemas: schemas,
+		macros:  macros,
+		lookups: lookups,
+	}
+}
+
+// loadMacros loads the current macros, read on every call so edits apply immediately
+func (t *Tester) loadMacros(ctx context.Context) (*expressions.Macros, error) {
+	if t.macros == nil {
+		return nil, nil
+	}
+
+	all, err := t.macros.ListMacros(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return expressions.NewMacros(all)
+}
+
+// ValidateRule compiles the rule's custom_expression against its schema.
+// Rules without a custom_expression are left to the struct validator.
+func (t *Tester) ValidateRule(ctx context.Context, rule *models.Rule) error {
+	raw, ok := rule.Conditions["custom_expression"]
+	if !ok {
+		return nil
+	}
+
+	expression, ok := raw.(string)
+	if !ok {
+		return ErrExpressionNotString
+	}
+
+	if rule.SchemaID == nil {
+		return ErrExpressionNoSchema
+	}
+
+	schema, err := t.schemas.GetByID(ctx, *rule.SchemaID)
+	if err != nil {
+		return err
+	}
+
+	macros, err := t.loadMacros(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := expressions.CompileWithMacros(expression, schema.ExtractedFields, macros); err != nil {
+		return &ExpressionError{CompileError: expressions.DescribeCompileError(err)}
+	}
+
+	return nil
+}
+
+// Test compiles the expression and evaluates it against each sample event.
+// A compile failure is reported in the response rather than returned as an error.
+func (t *Tester) Test(ctx context.Context, req *TestRuleRequest) (*TestRuleResponse, error) {
+	schema, err := t.schemas.GetByID(ctx, req.SchemaID)
+	if err != nil {
+		return nil, err
+	}
+
+	macros, err := t.loadMacros(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := expressions.CompileWithMacros(req.Expression, schema.ExtractedFields, macros)
+	if err != nil {
+		compileErr := expressions.DescribeCompileError(err)
+		return &TestRuleResponse{
+			CompileError: &compileErr,
+			Results:      []EventResult{},
+		}, nil
+	}
+
+	// Helpers backed by stored data return zero unless live lookups were requested
+	var lookups expressions.Lookups
+	if req.LiveHelpers {
+		lookups = t.lookups
+	}
+
+	results := make([]EventResult, len(req.Events))
+	for i, event := range req.Events {
+		env := expressions.BuildEnv(ctx, event, schema.ExtractedFields, lookups)
+		matched, err := expressions.Run(program, env)
+		results[i] = EventResult{Index: i, Matched: matched}
+		if err != nil {
+			results[i].Error = err.Error()
+		}
+	}
+
+	return &TestRuleResponse{
+		Compiled: true,
+		Results:  results,
+	}, nil
+}
@@ -0,0 +1,291 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/api/internal/schemas"
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func testSchema(id uuid.UUID) *schemas.EventSchema {
+	return &schemas.EventSchema{
+		ID: id,
+		ExtractedFields: []schemas.ExtractedField{
+			{Path: "amount", Type: schemas.FieldTypeNumber},
+			{Path: "origin", Type: schemas.FieldTypeString},
+			{Path: "user.country", Type: schemas.FieldTypeString},
+		},
+	}
+}
+
+func Test_Tester_ValidateRule_WhenNoCustomExpression_ThenReturnsNil(t *testing.T) {
+	tester := NewTester(nil, nil, expressions.Lookups{})
+
+	err := tester.ValidateRule(context.Background(), &models.Rule{
+		Conditions: map[string]any{"amount": ">1000"},
+	})
+
+	assert.NoError(t, err)
+}
+
+func Test_Tester_ValidateRule_WhenExpressionCompiles_ThenReturnsNil(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	schemaGetter := NewMockSchemaGetter(ctrl)
+	schemaGetter.EXPECT().GetByID(gomock.Any(), schemaID).Return(testSchema(schemaID), nil)
+	tester := NewTester(schemaGetter, nil, expressions.Lookups{})
+
+	err := tester.ValidateRule(context.Background(), &models.Rule{
+		SchemaID:   &schemaID,
+		Conditions: map[string]any{"custom_expression": `amount > 1000 and user.country == "BR"`},
+	})
+
+	assert.NoError(t, err)
+}
+
+func Test_Tester_ValidateRule_WhenExpressionNotBoolean_ThenReturnsExpressionError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	schemaGetter := NewMockSchemaGetter(ctrl)
+	schemaGetter.EXPECT().GetByID(gomock.Any(), schemaID).Return(testSchema(schemaID), nil)
+	tester := NewTester(schemaGetter, nil, expressions.Lookups{})
+
+	err := tester.ValidateRule(context.Background(), &models.Rule{
+		SchemaID:   &schemaID,
+		Conditions: map[string]any{"custom_expression": `amount + 1`},
+	})
+
+	var exprErr *ExpressionError
+	require.ErrorAs(t, err, &exprErr)
+	assert.Contains(t, exprErr.CompileError.Message, "expected bool")
+}
+
+func Test_Tester_ValidateRule_WhenUnknownField_ThenReportsPosition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	schemaGetter := NewMockSchemaGetter(ctrl)
+	schemaGetter.EXPECT().GetByID(gomock.Any(), schemaID).Return(testSchema(schemaID), nil)
+	tester := NewTester(schemaGetter, nil, expressions.Lookups{})
+
+	err := tester.ValidateRule(context.Background(), &models.Rule{
+		SchemaID:   &schemaID,
+		Conditions: map[string]any{"custom_expression": `amount > 1 and unknown == 2`},
+	})
+
+	var exprErr *ExpressionError
+	require.ErrorAs(t, err, &exprErr)
+	assert.Equal(t, 1, exprErr.CompileError.Line)
+	assert.Equal(t, 15, exprErr.CompileError.Column)
+}
+
+func Test_Tester_ValidateRule_WhenExpressionNotString_ThenReturnsError(t *testing.T) {
+	tester := NewTester(nil, nil, expressions.Lookups{})
+
+	err := tester.ValidateRule(context.Background(), &models.Rule{
+		Conditions: map[string]any{"custom_expression": 42},
+	})
+
+	assert.ErrorIs(t, err, ErrExpressionNotString)
+}
+
+func Test_Tester_ValidateRule_WhenSchemaMissing_ThenReturnsError(t *testing.T) {
+	tester := NewTester(nil, nil, expressions.Lookups{})
+
+	err := tester.ValidateRule(context.Background(), &models.Rule{
+		Conditions: map[string]any{"custom_expression": "amount > 1"},
+	})
+
+	assert.ErrorIs(t, err, ErrExpressionNoSchema)
+}
+
+func Test_Tester_Test_WhenExpressionCompiles_ThenReturnsPerEventResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	schemaGetter := NewMockSchemaGetter(ctrl)
+	schemaGetter.EXPECT().GetByID(gomock.Any(), schemaID).Return(testSchema(schemaID), nil)
+	tester := NewTester(schemaGetter, nil, expressions.Lookups{})
+
+	result, err := tester.Test(context.Background(), &TestRuleRequest{
+		Expression: `amount > 1000 and velocityCount(origin, 3600) == 0`,
+		SchemaID:   schemaID,
+		Events: []map[string]any{
+			{"amount": 5000.0, "origin": "ACC001"},
+			{"amount": 10.0, "origin": "ACC001"},
+			{"origin": "ACC001"},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Compiled)
+	assert.Nil(t, result.CompileError)
+	require.Len(t, result.Results, 3)
+	assert.True(t, result.Results[0].Matched)
+	assert.False(t, result.Results[1].Matched)
+	assert.Empty(t, result.Results[1].Error)
+	assert.False(t, result.Results[2].Matched)
+	assert.NotEmpty(t, result.Results[2].Error, "missing amount is a runtime error")
+}
+
+func Test_Tester_Test_WhenLiveHelpers_ThenQueriesHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	schemaGetter := NewMockSchemaGetter(ctrl)
+	schemaGetter.EXPECT().GetByID(gomock.Any(), schemaID).Return(testSchema(schemaID), nil)
+	tester := NewTester(schemaGetter, nil, expressions.Lookups{History: stubHistory{count: 12}})
+
+	result, err := tester.Test(context.Background(), &TestRuleRequest{
+		Expression:  `velocityCount(origin, 3600) > 10`,
+		SchemaID:    schemaID,
+		Events:      []map[string]any{{"origin": "ACC001"}},
+		LiveHelpers: true,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Results[0].Matched)
+}
+
+func Test_Tester_Test_WhenCompileFails_ThenReturnsCompileError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	schemaGetter := NewMockSchemaGetter(ctrl)
+	schemaGetter.EXPECT().GetByID(gomock.Any(), schemaID).Return(testSchema(schemaID), nil)
+	tester := NewTester(schemaGetter, nil, expressions.Lookups{})
+
+	result, err := tester.Test(context.Background(), &TestRuleRequest{
+		Expression: `origin > 100`,
+		SchemaID:   schemaID,
+		Events:     []map[string]any{{"origin": "ACC001"}},
+	})
+
+	require.NoError(t, err)
+	assert.False(t, result.Compiled)
+	require.NotNil(t, result.CompileError)
+	assert.NotEmpty(t, result.CompileError.Message)
+	assert.Empty(t, result.Results)
+}
+
+func Test_Tester_Test_WhenSchemaNotFound_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaGetter := NewMockSchemaGetter(ctrl)
+	schemaGetter.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(nil, schemas.ErrSchemaNotFound)
+	tester := NewTester(schemaGetter, nil, expressions.Lookups{})
+
+	_, err := tester.Test(context.Background(), &TestRuleRequest{
+		Expression: `amount > 1`,
+		SchemaID:   uuid.New(),
+		Events:     []map[string]any{{"amount": 1.0}},
+	})
+
+	assert.True(t, errors.Is(err, schemas.ErrSchemaNotFound))
+}
+
+func Test_Tester_Test_WhenExpressionUsesMacro_ThenInlinesCurrentMacro(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	schemaGetter := NewMockSchemaGetter(ctrl)
+	schemaGetter.EXPECT().GetByID(gomock.Any(), schemaID).Return(testSchema(schemaID), nil)
+	macroLister := NewMockMacroLister(ctrl)
+	macroLister.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{
+		{Name: "highAmount", Expression: `amount > 1000`},
+	}, nil)
+	tester := NewTester(schemaGetter, macroLister, expressions.Lookups{})
+
+	result, err := tester.Test(context.Background(), &TestRuleRequest{
+		Expression: `highAmount and user.country == "BR"`,
+		SchemaID:   schemaID,
+		Events: []map[string]any{
+			{"amount": 5000.0, "user": map[string]any{"country": "BR"}},
+			{"amount": 10.0, "user": map[string]any{"country": "BR"}},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Compiled)
+	require.Len(t, result.Results, 2)
+	assert.True(t, result.Results[0].Matched)
+	assert.False(t, result.Results[1].Matched)
+}
+
+func Test_Tester_ValidateRule_WhenMacroMistyped_ThenReturnsExpressionError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	schemaGetter := NewMockSchemaGetter(ctrl)
+	schemaGetter.EXPECT().GetByID(gomock.Any(), schemaID).Return(testSchema(schemaID), nil)
+	macroLister := NewMockMacroLister(ctrl)
+	macroLister.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{
+		{Name: "large", Expression: `amount > "1000"`},
+	}, nil)
+	tester := NewTester(schemaGetter, macroLister, expressions.Lookups{})
+
+	err := tester.ValidateRule(context.Background(), &models.Rule{
+		SchemaID:   &schemaID,
+		Conditions: map[string]any{"custom_expression": `large`},
+	})
+
+	var exprErr *ExpressionError
+	assert.ErrorAs(t, err, &exprErr)
+}
+
+// stubHistory is a fixed-value transaction history for live helper tests
+type stubHistory struct {
+	count int
+	sum   float64
+}
+
+func (s stubHistory) CountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (int, error) {
+	return s.count, nil
+}
+
+func (s stubHistory) SumAmountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (float64, error) {
+	return s.sum, nil
+}
+
+func (s stubHistory) CountByFieldInTimeWindow(ctx context.Context, path string, value any, timeWindowSeconds int) (int, error) {
+	return s.count, nil
+}
+
+func (s stubHistory) AggregateAmountByFieldInTimeWindow(ctx context.Context, path string, value any, aggregate models.AmountAggregate, timeWindowSeconds int) (float64, error) {
+	return s.sum, nil
+}
+
+func (s stubHistory) CountDistinctByFieldInTimeWindow(ctx context.Context, distinctPath, path string, value any, timeWindowSeconds int) (int, error) {
+	return s.count, nil
+}
+
+func (s stubHistory) AmountStatsByAccount(ctx context.Context, account string, days int) (models.AmountStats, error) {
+	return models.AmountStats{}, nil
+}
+
+func (s stubHistory) FirstSeenByAccount(ctx context.Context, account string) (*time.Time, error) {
+	return nil, nil
+}
+
+func (s stubHistory) LastLocationByAccount(ctx context.Context, account string) (*models.AccountLocation, error) {
+	return nil, nil
+}
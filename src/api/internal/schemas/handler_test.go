@@ -87,6 +87,30 @@ func Test_Handler_CreateSchema_WhenEmptySampleJSON_ThenReturnsBadRequest(t *test
 	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 }
 
+func Test_Handler_CreateSchema_WhenUnknownLane_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockServiceInterface(ctrl)
+	handler := NewHandler(mockService)
+	app := fiber.New()
+	app.Post("/schemas", handler.CreateSchema)
+
+	req := CreateSchemaRequest{
+		Name:       "test-schema",
+		SampleJSON: map[string]any{"amount": 100},
+		Lane:       "urgent",
+	}
+	body, _ := json.Marshal(req)
+
+	httpReq := httptest.NewRequest("POST", "/schemas", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(httpReq)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
 func Test_Handler_CreateSchema_WhenNameExists_ThenReturnsConflict(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
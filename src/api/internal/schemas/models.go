@@ -3,28 +3,25 @@ package schemas
 import (
 	"time"
 
+	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/google/uuid"
 )
 
 // FieldType represents the inferred type of a JSON field
-type FieldType string
+// Shared with the worker so both sides type expressions the same way
+type FieldType = models.FieldType
 
 const (
-	FieldTypeString  FieldType = "string"
-	FieldTypeNumber  FieldType = "number"
-	FieldTypeBoolean FieldType = "boolean"
-	FieldTypeArray   FieldType = "array"
-	FieldTypeObject  FieldType = "object"
-	FieldTypeNull    FieldType = "null"
+	FieldTypeString  = models.FieldTypeString
+	FieldTypeNumber  = models.FieldTypeNumber
+	FieldTypeBoolean = models.FieldTypeBoolean
+	FieldTypeArray   = models.FieldTypeArray
+	FieldTypeObject  = models.FieldTypeObject
+	FieldTypeNull    = models.FieldTypeNull
 )
 
 // ExtractedField represents a field extracted from sample JSON
-type ExtractedField struct {
-	Path        string    `json:"path"`
-	Type        FieldType `json:"type"`
-	Nullable    bool      `json:"nullable"`
-	SampleValue any       `json:"sample_value,omitempty"`
-}
+type ExtractedField = models.ExtractedField
 
 // EventSchema represents a user-defined event schema
 type EventSchema struct {
@@ -33,22 +30,25 @@ type EventSchema struct {
 	Description     string           `json:"description,omitempty" validate:"max=1000"`
 	SampleJSON      map[string]any   `json:"sample_json" validate:"required"`
 	ExtractedFields []ExtractedField `json:"extracted_fields"`
+	Lane            models.QueueLane `json:"lane"`
 	CreatedAt       time.Time        `json:"created_at"`
 	UpdatedAt       time.Time        `json:"updated_at"`
 }
 
 // CreateSchemaRequest is the request body for creating a new schema
 type CreateSchemaRequest struct {
-	Name        string         `json:"name" validate:"required,min=1,max=255"`
-	Description string         `json:"description,omitempty" validate:"max=1000"`
-	SampleJSON  map[string]any `json:"sample_json" validate:"required"`
+	Name        string           `json:"name" validate:"required,min=1,max=255"`
+	Description string           `json:"description,omitempty" validate:"max=1000"`
+	SampleJSON  map[string]any   `json:"sample_json" validate:"required"`
+	Lane        models.QueueLane `json:"lane,omitempty" validate:"omitempty,oneof=realtime post_transaction backfill"`
 }
 
 // UpdateSchemaRequest is the request body for updating a schema
 type UpdateSchemaRequest struct {
-	Name        string         `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
-	Description string         `json:"description,omitempty" validate:"max=1000"`
-	SampleJSON  map[string]any `json:"sample_json,omitempty"`
+	Name        string           `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description string           `json:"description,omitempty" validate:"max=1000"`
+	SampleJSON  map[string]any   `json:"sample_json,omitempty"`
+	Lane        models.QueueLane `json:"lane,omitempty" validate:"omitempty,oneof=realtime post_transaction backfill"`
 }
 
 // SchemaListResponse is the response for listing schemas
@@ -59,8 +59,8 @@ func (r *PostgresRepository) Create(ctx context.Context, schema *EventSchema) er
 	}
 
 	query := `
-		INSERT INTO event_schemas (id, name, description, sample_json, extracted_fields, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO event_schemas (id, name, description, sample_json, extracted_fields, lane, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
 	_, err = r.db.Exec(ctx, query,
@@ -69,6 +69,7 @@ func (r *PostgresRepository) Create(ctx context.Context, schema *EventSchema) er
 		schema.Description,
 		sampleJSON,
 		extractedFields,
+		schema.Lane,
 		schema.CreatedAt,
 		schema.UpdatedAt,
 	)
@@ -82,7 +83,7 @@ func (r *PostgresRepository) Create(ctx context.Context, schema *EventSchema) er
 
 func (r *PostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*EventSchema, error) {
 	query := `
-		SELECT id, name, description, sample_json, extracted_fields, created_at, updated_at
+		SELECT id, name, description, sample_json, extracted_fields, lane, created_at, updated_at
 		FROM event_schemas
 		WHERE id = $1
 	`
@@ -96,6 +97,7 @@ func (r *PostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*EventS
 		&schema.Description,
 		&sampleJSON,
 		&extractedFields,
+		&schema.Lane,
 		&schema.CreatedAt,
 		&schema.UpdatedAt,
 	)
@@ -117,7 +119,7 @@ func (r *PostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*EventS
 
 func (r *PostgresRepository) GetByName(ctx context.Context, name string) (*EventSchema, error) {
 	query := `
-		SELECT id, name, description, sample_json, extracted_fields, created_at, updated_at
+		SELECT id, name, description, sample_json, extracted_fields, lane, created_at, updated_at
 		FROM event_schemas
 		WHERE name = $1
 	`
@@ -131,6 +133,7 @@ func (r *PostgresRepository) GetByName(ctx context.Context, name string) (*Event
 		&schema.Description,
 		&sampleJSON,
 		&extractedFields,
+		&schema.Lane,
 		&schema.CreatedAt,
 		&schema.UpdatedAt,
 	)
@@ -152,7 +155,7 @@ func (r *PostgresRepository) GetByName(ctx context.Context, name string) (*Event
 
 func (r *PostgresRepository) List(ctx context.Context) ([]EventSchema, error) {
 	query := `
-		SELECT id, name, description, sample_json, extracted_fields, created_at, updated_at
+		SELECT id, name, description, sample_json, extracted_fields, lane, created_at, updated_at
 		FROM event_schemas
 		ORDER BY name ASC
 	`
@@ -174,6 +177,7 @@ func (r *PostgresRepository) List(ctx context.Context) ([]EventSchema, error) {
 			&schema.Description,
 			&sampleJSON,
 			&extractedFields,
+			&schema.Lane,
 			&schema.CreatedAt,
 			&schema.UpdatedAt,
 		); err != nil {
@@ -207,7 +211,7 @@ func (r *PostgresRepository) Update(ctx context.Context, schema *EventSchema) er
 
 	query := `
 		UPDATE event_schemas
-		SET name = $2, description = $3, sample_json = $4, extracted_fields = $5, updated_at = $6
+		SET name = $2, description = $3, sample_json = $4, extracted_fields = $5, lane = $6, updated_at = $7
 		WHERE id = $1
 	`
 
@@ -217,6 +221,7 @@ func (r *PostgresRepository) Update(ctx context.Context, schema *EventSchema) er
 		schema.Description,
 		sampleJSON,
 		extractedFields,
+		schema.Lane,
 		schema.UpdatedAt,
 	)
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/algo-shield/algo-shield/src/api/internal/schemas"
 	"github.com/algo-shield/algo-shield/src/api/internal/testutil"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -223,6 +224,7 @@ func TestIntegration_SchemasRepository_Update_UpdatesSchema(t *testing.T) {
 	require.NoError(t, err)
 
 	schema.Description = "Updated description"
+	schema.Lane = models.LaneBackfill
 	schema.UpdatedAt = time.Now()
 	err = repo.Update(ctx, schema)
 
@@ -231,6 +233,7 @@ func TestIntegration_SchemasRepository_Update_UpdatesSchema(t *testing.T) {
 	result, err := repo.GetByID(ctx, schemaID)
 	require.NoError(t, err)
 	assert.Equal(t, "Updated description", result.Description)
+	assert.Equal(t, models.LaneBackfill, result.Lane)
 }
 
 func TestIntegration_SchemasRepository_Update_NotFound_ReturnsError(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 )
@@ -58,6 +59,11 @@ func (s *Service) Create(ctx context.Context, req *CreateSchemaRequest) (*EventS
 	// Extract fields from sample JSON
 	fields := ExtractFields(req.SampleJSON, "", 0)
 
+	lane := req.Lane
+	if lane == "" {
+		lane = models.LanePostTransaction
+	}
+
 	now := time.Now()
 	schema := &EventSchema{
 		ID:              uuid.New(),
@@ -65,6 +71,7 @@ func (s *Service) Create(ctx context.Context, req *CreateSchemaRequest) (*EventS
 		Description:     req.Description,
 		SampleJSON:      req.SampleJSON,
 		ExtractedFields: fields,
+		Lane:            lane,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
@@ -126,6 +133,10 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, req *UpdateSchemaReq
 		existing.Description = req.Description
 	}
 
+	if req.Lane != "" {
+		existing.Lane = req.Lane
+	}
+
 	// Re-extract fields if sample JSON is updated
 	if req.SampleJSON != nil {
 		existing.SampleJSON = req.SampleJSON
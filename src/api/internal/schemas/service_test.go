@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
@@ -35,6 +36,27 @@ func Test_Service_Create_WhenValidRequest_ThenCreatesSchema(t *testing.T) {
 	assert.Equal(t, req.Name, schema.Name)
 	assert.Equal(t, req.Description, schema.Description)
 	assert.NotEmpty(t, schema.ExtractedFields)
+	assert.Equal(t, models.LanePostTransaction, schema.Lane)
+}
+
+func Test_Service_Create_WhenLaneGiven_ThenKeepsLane(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	req := &CreateSchemaRequest{
+		Name:       "card-authorisation",
+		SampleJSON: map[string]any{"amount": 100.50},
+		Lane:       models.LaneRealtime,
+	}
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetByName(gomock.Any(), req.Name).Return(nil, pgx.ErrNoRows)
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	service := NewService(mockRepo)
+
+	schema, err := service.Create(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.LaneRealtime, schema.Lane)
 }
 
 func Test_Service_Create_WhenNameExists_ThenReturnsError(t *testing.T) {
@@ -169,6 +191,23 @@ func Test_Service_Update_WhenValidRequest_ThenUpdatesSchema(t *testing.T) {
 	assert.Equal(t, req.Description, schema.Description)
 }
 
+func Test_Service_Update_WhenLaneOmitted_ThenKeepsLane(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.New()
+	existing := &EventSchema{ID: id, Name: "imports", Lane: models.LaneBackfill}
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetByID(gomock.Any(), id).Return(existing, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	service := NewService(mockRepo)
+
+	schema, err := service.Update(context.Background(), id, &UpdateSchemaRequest{Description: "Nightly imports"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.LaneBackfill, schema.Lane)
+}
+
 func Test_Service_Update_WhenSchemaNotFound_ThenReturnsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
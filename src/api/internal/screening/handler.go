@@ -0,0 +1,96 @@
+package screening
+
+import (
+	"context"
+	"strings"
+
+	"github.com/algo-shield/algo-shield/src/api/internal"
+	"github.com/algo-shield/algo-shield/src/api/internal/shared/validation"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/algo-shield/algo-shield/src/pkg/sanctions"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Screener screens names against the sanctions lists
+type Screener interface {
+	Screen(ctx context.Context, req models.ScreeningRequest) ([]models.ScreeningMatch, error)
+}
+
+// ImportReader provides the last import of each sanctions source
+type ImportReader interface {
+	ListImports(ctx context.Context) ([]models.SanctionsImport, error)
+}
+
+// Handler handles HTTP requests for sanctions screening
+type Handler struct {
+	screener Screener
+	imports  ImportReader
+}
+
+// NewHandler creates a new screening handler with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewHandler(screener Screener, imports ImportReader) *Handler {
+	return &Handler{
+		screener: screener,
+		imports:  imports,
+	}
+}
+
+// Screen handles POST /api/v1/screening
+// Returns the sanctioned entries whose name or an alias is similar to the requested name, best first
+func (h *Handler) Screen(c *fiber.Ctx) error {
+	var req models.ScreeningRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := validation.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// The screener ignores unknown countries, an API caller is told instead
+	if strings.TrimSpace(req.Country) != "" {
+		code := sanctions.CountryCode(req.Country)
+		if code == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Unknown country",
+			})
+		}
+		req.Country = code
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	matches, err := h.screener.Screen(ctx, req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to screen name",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"matches": matches,
+	})
+}
+
+// ListImports handles GET /api/v1/screening/imports
+func (h *Handler) ListImports(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	imports, err := h.imports.ListImports(ctx)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch sanctions imports",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"imports": imports,
+	})
+}
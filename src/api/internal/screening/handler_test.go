@@ -0,0 +1,114 @@
+package screening
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newScreeningApp(handler *Handler) *fiber.App {
+	app := fiber.New()
+	app.Post("/screening", handler.Screen)
+	app.Get("/screening/imports", handler.ListImports)
+	return app
+}
+
+func postScreening(t *testing.T, app *fiber.App, body string) int {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/screening", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func Test_Handler_Screen_WhenMatches_ThenReturnsMatchesWithNormalizedCountry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	screener := NewMockScreener(ctrl)
+	app := newScreeningApp(NewHandler(screener, NewMockImportReader(ctrl)))
+
+	screener.EXPECT().Screen(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, req models.ScreeningRequest) ([]models.ScreeningMatch, error) {
+			assert.Equal(t, "Ivan Petrov", req.Name)
+			assert.Equal(t, "RU", req.Country)
+			assert.Equal(t, []models.SanctionsSource{models.SanctionsSourceOFAC}, req.Sources)
+			return []models.ScreeningMatch{{
+				Entry:       models.SanctionsEntry{Source: models.SanctionsSourceOFAC, SourceID: "123", Name: "Ivan PETROV"},
+				MatchedName: "Ivan PETROV",
+				Score:       1,
+			}}, nil
+		},
+	)
+
+	req := httptest.NewRequest("POST", "/screening", bytes.NewBufferString(`{"name": "Ivan Petrov", "country": "Russia", "sources": ["ofac"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Matches []models.ScreeningMatch `json:"matches"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Matches, 1)
+	assert.Equal(t, "123", result.Matches[0].Entry.SourceID)
+	assert.Equal(t, 1.0, result.Matches[0].Score)
+}
+
+func Test_Handler_Screen_WhenRequestInvalid_ThenReturns400WithoutScreening(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	app := newScreeningApp(NewHandler(NewMockScreener(ctrl), NewMockImportReader(ctrl)))
+
+	assert.Equal(t, fiber.StatusBadRequest, postScreening(t, app, `{"name": ""}`))
+	assert.Equal(t, fiber.StatusBadRequest, postScreening(t, app, `{"name": "Ivan Petrov", "threshold": 1.5}`))
+	assert.Equal(t, fiber.StatusBadRequest, postScreening(t, app, `{"name": "Ivan Petrov", "sources": ["interpol"]}`))
+	assert.Equal(t, fiber.StatusBadRequest, postScreening(t, app, `{"name": "Ivan Petrov", "country": "Atlantis"}`))
+	assert.Equal(t, fiber.StatusBadRequest, postScreening(t, app, `not json`))
+}
+
+func Test_Handler_Screen_WhenScreenerFails_ThenReturns500(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	screener := NewMockScreener(ctrl)
+	app := newScreeningApp(NewHandler(screener, NewMockImportReader(ctrl)))
+
+	screener.EXPECT().Screen(gomock.Any(), gomock.Any()).Return(nil, errors.New("database unavailable"))
+
+	assert.Equal(t, fiber.StatusInternalServerError, postScreening(t, app, `{"name": "Ivan Petrov"}`))
+}
+
+func Test_Handler_ListImports_WhenRepositorySucceeds_ThenReturnsImports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imports := NewMockImportReader(ctrl)
+	app := newScreeningApp(NewHandler(NewMockScreener(ctrl), imports))
+
+	imports.EXPECT().ListImports(gomock.Any()).Return([]models.SanctionsImport{{Source: models.SanctionsSourceUN, EntryCount: 1000}}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/screening/imports", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Imports []models.SanctionsImport `json:"imports"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Imports, 1)
+	assert.Equal(t, 1000, result.Imports[0].EntryCount)
+}
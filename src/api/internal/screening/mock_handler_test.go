@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/api/internal/screening/handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/api/internal/screening/handler.go -destination=src/api/internal/screening/mock_handler_test.go -package=screening
+//
+
+// Package screening is a generated GoMock package.
+package screening
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScreener is a mock of Screener interface.
+type MockScreener struct {
+	ctrl     *gomock.Controller
+	recorder *MockScreenerMockRecorder
+	isgomock struct{}
+}
+
+// MockScreenerMockRecorder is the mock recorder for MockScreener.
+type MockScreenerMockRecorder struct {
+	mock *MockScreener
+}
+
+// NewMockScreener creates a new mock instance.
+func NewMockScreener(ctrl *gomock.Controller) *MockScreener {
+	mock := &MockScreener{ctrl: ctrl}
+	mock.recorder = &MockScreenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScreener) EXPECT() *MockScreenerMockRecorder {
+	return m.recorder
+}
+
+// Screen mocks base method.
+func (m *MockScreener) Screen(ctx context.Context, req models.ScreeningRequest) ([]models.ScreeningMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Screen", ctx, req)
+	ret0, _ := ret[0].([]models.ScreeningMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Screen indicates an expected call of Screen.
+func (mr *MockScreenerMockRecorder) Screen(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Screen", reflect.TypeOf((*MockScreener)(nil).Screen), ctx, req)
+}
+
+// MockImportReader is a mock of ImportReader interface.
+type MockImportReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockImportReaderMockRecorder is the mock recorder for MockImportReader.
+type MockImportReaderMockRecorder struct {
+	mock *MockImportReader
+}
+
+// NewMockImportReader creates a new mock instance.
+func NewMockImportReader(ctrl *gomock.Controller) *MockImportReader {
+	mock := &MockImportReader{ctrl: ctrl}
+	mock.recorder = &MockImportReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportReader) EXPECT() *MockImportReaderMockRecorder {
+	return m.recorder
+}
+
+// ListImports mocks base method.
+func (m *MockImportReader) ListImports(ctx context.Context) ([]models.SanctionsImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImports", ctx)
+	ret0, _ := ret[0].([]models.SanctionsImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImports indicates an expected call of ListImports.
+func (mr *MockImportReaderMockRecorder) ListImports(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImports", reflect.TypeOf((*MockImportReader)(nil).ListImports), ctx)
+}
@@ -0,0 +1,51 @@
+//go:build integration
+
+package screening_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/api/internal/testutil"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/algo-shield/algo-shield/src/pkg/sanctions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegration_SanctionsRepository_ReplaceSource_ReplacesOnlyThatSource(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	repo := sanctions.NewPostgresRepository(testDB.Postgres, testDB.Redis)
+	ctx := context.Background()
+
+	require.NoError(t, repo.ReplaceSource(ctx, models.SanctionsSourceOFAC, "sdn-old.xml", []models.SanctionsEntry{
+		{SourceID: "1", Type: models.SanctionsEntityIndividual, Name: "Old Entry"},
+	}))
+	require.NoError(t, repo.ReplaceSource(ctx, models.SanctionsSourceUN, "consolidated.xml", []models.SanctionsEntry{
+		{SourceID: "KPi.001", Type: models.SanctionsEntityIndividual, Name: "IVAN PETROV", Countries: []string{"KP"}},
+	}))
+	require.NoError(t, repo.ReplaceSource(ctx, models.SanctionsSourceOFAC, "sdn.xml", []models.SanctionsEntry{
+		{SourceID: "7157", Type: models.SanctionsEntityIndividual, Name: "Ivan PETROV", Aliases: []string{"Ioann PETROFF", "I. PETROV"}, Countries: []string{"RU"}, Programs: []string{"RUSSIA-EO14024"}},
+		{SourceID: "7157", Type: models.SanctionsEntityIndividual, Name: "Duplicate"},
+	}))
+
+	entries, err := repo.ListEntries(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	bySource := make(map[models.SanctionsSource]models.SanctionsEntry)
+	for _, entry := range entries {
+		bySource[entry.Source] = entry
+	}
+	assert.Equal(t, "Ivan PETROV", bySource[models.SanctionsSourceOFAC].Name)
+	assert.Equal(t, []string{"Ioann PETROFF", "I. PETROV"}, bySource[models.SanctionsSourceOFAC].Aliases)
+	assert.Equal(t, []string{"RUSSIA-EO14024"}, bySource[models.SanctionsSourceOFAC].Programs)
+	assert.Empty(t, bySource[models.SanctionsSourceUN].Aliases)
+
+	imports, err := repo.ListImports(ctx)
+	require.NoError(t, err)
+	require.Len(t, imports, 2)
+	assert.Equal(t, models.SanctionsSourceOFAC, imports[0].Source)
+	assert.Equal(t, "sdn.xml", imports[0].FileName)
+	assert.Equal(t, 1, imports[0].EntryCount)
+}
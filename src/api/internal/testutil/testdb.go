@@ -94,6 +94,20 @@ func SetupTestDB(t *testing.T) *TestDB {
 	}
 }
 
+// migrationsPath is relative to the packages under src/api/internal
+const migrationsPath = "../../../../scripts/migrations"
+
+// RunMigration runs a migration again, for tests of how it upgrades existing rows
+func RunMigration(t *testing.T, pool *pgxpool.Pool, filename string) {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(migrationsPath, filename))
+	require.NoError(t, err)
+
+	_, err = pool.Exec(context.Background(), string(content))
+	require.NoError(t, err, "Failed to run migration %s", filename)
+}
+
 func runMigrations(t *testing.T, pool *pgxpool.Pool) {
 	t.Helper()
 	ctx := context.Background()
@@ -105,12 +119,26 @@ func runMigrations(t *testing.T, pool *pgxpool.Pool) {
 		"005_branding_config.sql",
 		"006_add_header_color.sql",
 		"007_event_schemas.sql",
+		"009_risk_scoring.sql",
+		"010_rule_decisions.sql",
+		"011_backtests.sql",
+		"012_shadow_rules.sql",
+		"013_rule_versions.sql",
+		"014_rule_health.sql",
+		"015_evaluation_timeouts.sql",
+		"016_velocity_dimensions.sql",
+		"017_account_stats.sql",
+		"018_geo.sql",
+		"019_lists.sql",
+		"020_sanctions.sql",
+		"021_macros.sql",
+		"022_dead_letters.sql",
+		"023_schema_lanes.sql",
+		"024_shadow_rule_versions.sql",
 	}
 
-	basePath := "../../../../scripts/migrations"
-
 	for _, filename := range migrationFiles {
-		filePath := filepath.Join(basePath, filename)
+		filePath := filepath.Join(migrationsPath, filename)
 		content, err := os.ReadFile(filePath)
 		if err != nil {
 			t.Logf("Warning: could not read migration file %s: %v", filename, err)
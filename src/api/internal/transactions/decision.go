@@ -0,0 +1,119 @@
+package transactions
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DecisionConfig configures synchronous decision requests
+type DecisionConfig struct {
+	Timeout  time.Duration     // Hard deadline for waiting on the worker
+	Fallback models.RuleAction // Decision returned when the deadline expires
+}
+
+// DecisionResponse is the response of a synchronous decision request
+type DecisionResponse struct {
+	Decision       models.RuleAction        `json:"decision"`
+	Status         models.TransactionStatus `json:"status"`
+	ExternalID     string                   `json:"external_id"`
+	TransactionID  *uuid.UUID               `json:"transaction_id,omitempty"`
+	RiskScore      int                      `json:"risk_score"`
+	MatchedRules   []string                 `json:"matched_rules"`
+	DecidedBy      *uuid.UUID               `json:"decided_by_rule_id,omitempty"`
+	ProcessingTime int64                    `json:"processing_time_ms"`
+	Fallback       bool                     `json:"fallback"`            // True when the deadline expired before the worker replied
+	Duplicate      bool                     `json:"duplicate,omitempty"` // True when the decision was made for an earlier request
+}
+
+// ReplyRouter routes decision replies published by workers to the waiting requests
+// A single subscription serves every in-flight request of this API instance
+type ReplyRouter struct {
+	redis   *redis.Client
+	mu      sync.Mutex
+	waiters map[string]chan models.TransactionResult
+}
+
+// NewReplyRouter creates a new decision reply router
+func NewReplyRouter(redisClient *redis.Client) *ReplyRouter {
+	return &ReplyRouter{
+		redis:   redisClient,
+		waiters: make(map[string]chan models.TransactionResult),
+	}
+}
+
+// Register registers a waiter for a correlation ID
+// The returned cancel function must be called once the caller stops waiting
+func (r *ReplyRouter) Register(correlationID string) (<-chan models.TransactionResult, func()) {
+	replies := make(chan models.TransactionResult, 1)
+
+	r.mu.Lock()
+	r.waiters[correlationID] = replies
+	r.mu.Unlock()
+
+	return replies, func() {
+		r.mu.Lock()
+		delete(r.waiters, correlationID)
+		r.mu.Unlock()
+	}
+}
+
+// Listen subscribes to decision replies and dispatches them to waiters
+// This is a blocking function that should be called in a goroutine
+func (r *ReplyRouter) Listen(ctx context.Context) {
+	if r.redis == nil {
+		log.Println("Redis not available, decision reply subscription disabled")
+		return
+	}
+
+	pubsub := r.redis.Subscribe(ctx, models.DecisionReplyChannel)
+	defer func() {
+		if err := pubsub.Close(); err != nil {
+			log.Printf("Error closing decision reply subscription: %v", err)
+		}
+	}()
+
+	log.Println("Subscribed to decision reply channel")
+
+	for {
+		msg, err := pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || err == redis.ErrClosed {
+				log.Println("Decision reply subscription stopped")
+				return
+			}
+			log.Printf("Error receiving decision reply: %v", err)
+			continue
+		}
+
+		r.dispatch(msg.Payload)
+	}
+}
+
+// dispatch delivers a reply payload to its waiter, dropping replies nobody waits for
+func (r *ReplyRouter) dispatch(payload string) {
+	var reply models.DecisionReply
+	if err := json.Unmarshal([]byte(payload), &reply); err != nil {
+		log.Printf("Invalid decision reply payload: %v", err)
+		return
+	}
+
+	r.mu.Lock()
+	replies, ok := r.waiters[reply.CorrelationID]
+	r.mu.Unlock()
+	if !ok {
+		// Reply for another API instance or for a request that already timed out
+		return
+	}
+
+	select {
+	case replies <- reply.Result:
+	default:
+	}
+}
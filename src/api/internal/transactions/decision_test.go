@@ -0,0 +1,52 @@
+package transactions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReplyRouter_Dispatch_WhenWaiterRegistered_ThenDeliversResult(t *testing.T) {
+	router := NewReplyRouter(nil)
+	replies, cancel := router.Register("corr-1")
+	defer cancel()
+	payload, err := json.Marshal(models.DecisionReply{
+		CorrelationID: "corr-1",
+		Result:        models.TransactionResult{Status: models.StatusInReview},
+	})
+	require.NoError(t, err)
+
+	router.dispatch(string(payload))
+
+	select {
+	case result := <-replies:
+		assert.Equal(t, models.StatusInReview, result.Status)
+	default:
+		t.Fatal("expected reply to be delivered")
+	}
+}
+
+func Test_ReplyRouter_Dispatch_WhenWaiterCancelled_ThenDropsResult(t *testing.T) {
+	router := NewReplyRouter(nil)
+	replies, cancel := router.Register("corr-1")
+	cancel()
+	payload, err := json.Marshal(models.DecisionReply{CorrelationID: "corr-1"})
+	require.NoError(t, err)
+
+	router.dispatch(string(payload))
+
+	assert.Empty(t, replies)
+}
+
+func Test_ReplyRouter_Dispatch_WhenPayloadInvalid_ThenIgnores(t *testing.T) {
+	router := NewReplyRouter(nil)
+	replies, cancel := router.Register("corr-1")
+	defer cancel()
+
+	router.dispatch("not-json")
+
+	assert.Empty(t, replies)
+}
@@ -2,23 +2,35 @@ package transactions
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/algo-shield/algo-shield/src/api/internal"
+	"github.com/algo-shield/algo-shield/src/api/internal/schemas"
 	"github.com/algo-shield/algo-shield/src/api/internal/shared/validation"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
+const (
+	// idempotencyKeyHeader lets clients retry a request without the transaction being processed twice
+	idempotencyKeyHeader = "Idempotency-Key"
+	// maxIdempotencyKeyLength matches transactions.external_id, where keys may be stored
+	maxIdempotencyKeyLength = 255
+)
+
 type Handler struct {
-	service Service
+	service         Service
+	decisionTimeout time.Duration // Time Decide waits for the worker, on top of the default request timeout
 }
 
 // NewHandler creates a new transaction handler with dependency injection
 // Follows Dependency Inversion Principle - receives interface, not concrete type
-func NewHandler(service Service) *Handler {
+func NewHandler(service Service, decisionTimeout time.Duration) *Handler {
 	return &Handler{
-		service: service,
+		service:         service,
+		decisionTimeout: decisionTimeout,
 	}
 }
 
@@ -37,29 +49,128 @@ func (h *Handler) ProcessTransaction(c *fiber.Ctx) error {
 		})
 	}
 
+	idempotencyKey := c.Get(idempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Idempotency-Key must be at most 255 characters",
+		})
+	}
+
+	route, err := queueRoute(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
 	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
 	defer cancel()
-	if err := h.service.ProcessTransaction(ctx, event); err != nil {
+	existing, err := h.service.ProcessTransaction(ctx, event, idempotencyKey, route)
+	if errors.Is(err, schemas.ErrSchemaNotFound) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Schema not found",
+		})
+	}
+	if errors.Is(err, ErrRequestInProgress) {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"status":      "queued",
+			"external_id": queuedExternalID(event, idempotencyKey),
+			"duplicate":   true,
+			"message":     "Transaction already queued for processing",
+		})
+	}
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to queue transaction",
 		})
 	}
 
-	// Try to extract external_id for response (if present)
-	externalID := "unknown"
-	if id, ok := event["external_id"].(string); ok {
-		externalID = id
-	} else if id, ok := event["id"].(string); ok {
-		externalID = id
+	// A repeated request gets the decision made for the first one
+	if existing != nil {
+		return c.JSON(existing)
 	}
 
 	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
 		"status":      "queued",
-		"external_id": externalID,
+		"external_id": queuedExternalID(event, idempotencyKey),
 		"message":     "Transaction queued for processing",
 	})
 }
 
+// queueRoute reads the lane and schema_id query parameters selecting the queue lane
+func queueRoute(c *fiber.Ctx) (Route, error) {
+	var route Route
+
+	if name := c.Query("lane"); name != "" {
+		lane, err := models.ParseQueueLane(name)
+		if err != nil {
+			return Route{}, err
+		}
+		route.Lane = lane
+	}
+
+	if param := c.Query("schema_id"); param != "" {
+		id, err := uuid.Parse(param)
+		if err != nil {
+			return Route{}, errors.New("invalid schema_id")
+		}
+		route.SchemaID = id
+	}
+
+	return route, nil
+}
+
+// queuedExternalID returns the external_id a queued event is saved under
+// Events without one are saved under their Idempotency-Key
+func queuedExternalID(event models.Event, idempotencyKey string) string {
+	if storedExternalID(event) == "" && idempotencyKey != "" {
+		return idempotencyKey
+	}
+	return externalIDFromEvent(event)
+}
+
+// Decide evaluates an event synchronously (pre-transaction mode) and returns the decision
+// The service enforces the decision deadline and falls back to the configured decision
+func (h *Handler) Decide(c *fiber.Ctx) error {
+	var event models.Event
+	if err := c.BodyParser(&event); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Basic validation: event must be a non-empty JSON object
+	if len(event) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Event must be a non-empty JSON object",
+		})
+	}
+
+	idempotencyKey := c.Get(idempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Idempotency-Key must be at most 255 characters",
+		})
+	}
+
+	// The idempotency claim and queue push get the default timeout, the wait gets the decision timeout
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT+h.decisionTimeout)
+	defer cancel()
+	decision, err := h.service.Decide(ctx, event, idempotencyKey)
+	if errors.Is(err, ErrRequestInProgress) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "A request with this idempotency key is still being processed",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to request decision",
+		})
+	}
+
+	return c.JSON(decision)
+}
+
 func (h *Handler) GetTransaction(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := uuid.Parse(idParam)
@@ -113,3 +224,47 @@ func (h *Handler) ListTransactions(c *fiber.Ctx) error {
 		"offset":       offset,
 	})
 }
+
+// ShadowRuleStats reports how often each shadow rule would have fired
+// Accepts optional RFC 3339 from and to query parameters, defaulting to the last 24 hours
+func (h *Handler) ShadowRuleStats(c *fiber.Ctx) error {
+	to := time.Now()
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "to must be an RFC 3339 timestamp",
+			})
+		}
+		to = parsed
+	}
+
+	from := to.Add(-DefaultShadowStatsWindow)
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "from must be an RFC 3339 timestamp",
+			})
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "from must be before to",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), internal.DEFAULT_TIMEOUT)
+	defer cancel()
+
+	stats, err := h.service.ShadowRuleStats(ctx, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch shadow rule stats",
+		})
+	}
+
+	return c.JSON(stats)
+}
@@ -2,12 +2,17 @@ package transactions
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/algo-shield/algo-shield/src/api/internal"
+	"github.com/algo-shield/algo-shield/src/api/internal/schemas"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -22,7 +27,7 @@ func Test_Handler_NewHandler_WhenCalled_ThenReturnsHandler(t *testing.T) {
 
 	mockService := NewMockTransactionService(ctrl)
 
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	require.NotNil(t, handler)
 	assert.Equal(t, mockService, handler.service)
@@ -33,7 +38,7 @@ func Test_Handler_ProcessTransaction_WhenValidEvent_ThenQueuesTransaction(t *tes
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Post("/transactions", handler.ProcessTransaction)
@@ -45,8 +50,8 @@ func Test_Handler_ProcessTransaction_WhenValidEvent_ThenQueuesTransaction(t *tes
 	}
 
 	mockService.EXPECT().
-		ProcessTransaction(gomock.Any(), gomock.Any()).
-		Return(nil)
+		ProcessTransaction(gomock.Any(), gomock.Any(), "", Route{}).
+		Return(nil, nil)
 
 	body, _ := json.Marshal(event)
 	req := httptest.NewRequest("POST", "/transactions", bytes.NewReader(body))
@@ -71,7 +76,7 @@ func Test_Handler_ProcessTransaction_WhenInvalidJSON_ThenReturnsBadRequest(t *te
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Post("/transactions", handler.ProcessTransaction)
@@ -90,7 +95,7 @@ func Test_Handler_ProcessTransaction_WhenEmptyEvent_ThenReturnsBadRequest(t *tes
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Post("/transactions", handler.ProcessTransaction)
@@ -118,7 +123,7 @@ func Test_Handler_ProcessTransaction_WhenServiceFails_ThenReturnsInternalError(t
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Post("/transactions", handler.ProcessTransaction)
@@ -129,8 +134,8 @@ func Test_Handler_ProcessTransaction_WhenServiceFails_ThenReturnsInternalError(t
 	}
 
 	mockService.EXPECT().
-		ProcessTransaction(gomock.Any(), gomock.Any()).
-		Return(errors.New("queue error"))
+		ProcessTransaction(gomock.Any(), gomock.Any(), "", Route{}).
+		Return(nil, errors.New("queue error"))
 
 	body, _ := json.Marshal(event)
 	req := httptest.NewRequest("POST", "/transactions", bytes.NewReader(body))
@@ -142,12 +147,179 @@ func Test_Handler_ProcessTransaction_WhenServiceFails_ThenReturnsInternalError(t
 	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 }
 
+func Test_Handler_ProcessTransaction_WhenLaneAndSchemaGiven_ThenPassesRoute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Post("/transactions", handler.ProcessTransaction)
+
+	schemaID := uuid.New()
+	mockService.EXPECT().
+		ProcessTransaction(gomock.Any(), gomock.Any(), "", Route{Lane: models.LaneBackfill, SchemaID: schemaID}).
+		Return(nil, nil)
+
+	body, _ := json.Marshal(models.Event{"external_id": "tx-123"})
+	req := httptest.NewRequest("POST", "/transactions?lane=backfill&schema_id="+schemaID.String(), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+}
+
+func Test_Handler_ProcessTransaction_WhenLaneUnknown_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Post("/transactions", handler.ProcessTransaction)
+
+	body, _ := json.Marshal(models.Event{"external_id": "tx-123"})
+	req := httptest.NewRequest("POST", "/transactions?lane=urgent", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_ProcessTransaction_WhenSchemaNotFound_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Post("/transactions", handler.ProcessTransaction)
+
+	schemaID := uuid.New()
+	mockService.EXPECT().
+		ProcessTransaction(gomock.Any(), gomock.Any(), "", Route{SchemaID: schemaID}).
+		Return(nil, schemas.ErrSchemaNotFound)
+
+	body, _ := json.Marshal(models.Event{"external_id": "tx-123"})
+	req := httptest.NewRequest("POST", "/transactions?schema_id="+schemaID.String(), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_ProcessTransaction_WhenRequestRepeated_ThenReturnsExistingDecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Post("/transactions", handler.ProcessTransaction)
+
+	transactionID := uuid.New()
+	mockService.EXPECT().
+		ProcessTransaction(gomock.Any(), gomock.Any(), "key-1", Route{}).
+		Return(&DecisionResponse{
+			Decision:      models.ActionBlock,
+			Status:        models.StatusRejected,
+			ExternalID:    "tx-123",
+			TransactionID: &transactionID,
+			MatchedRules:  []string{"rule-1"},
+			Duplicate:     true,
+		}, nil)
+
+	body, _ := json.Marshal(models.Event{"external_id": "tx-123"})
+	req := httptest.NewRequest("POST", "/transactions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "key-1")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	respBody, _ := io.ReadAll(resp.Body)
+	var result map[string]interface{}
+	err = json.Unmarshal(respBody, &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, "block", result["decision"])
+	assert.Equal(t, transactionID.String(), result["transaction_id"])
+	assert.Equal(t, true, result["duplicate"])
+}
+
+func Test_Handler_ProcessTransaction_WhenRepeatedRequestStillQueued_ThenReturnsAccepted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Post("/transactions", handler.ProcessTransaction)
+
+	mockService.EXPECT().
+		ProcessTransaction(gomock.Any(), gomock.Any(), "key-1", Route{}).
+		Return(nil, ErrRequestInProgress)
+
+	body, _ := json.Marshal(models.Event{"amount": 100.0})
+	req := httptest.NewRequest("POST", "/transactions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "key-1")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+
+	respBody, _ := io.ReadAll(resp.Body)
+	var result map[string]interface{}
+	err = json.Unmarshal(respBody, &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, "queued", result["status"])
+	assert.Equal(t, "key-1", result["external_id"])
+	assert.Equal(t, true, result["duplicate"])
+}
+
+func Test_Handler_ProcessTransaction_WhenIdempotencyKeyTooLong_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Post("/transactions", handler.ProcessTransaction)
+
+	body, _ := json.Marshal(models.Event{"external_id": "tx-123"})
+	req := httptest.NewRequest("POST", "/transactions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", strings.Repeat("k", 256))
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
 func Test_Handler_GetTransaction_WhenValidID_ThenReturnsTransaction(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Get("/transactions/:id", handler.GetTransaction)
@@ -186,7 +358,7 @@ func Test_Handler_GetTransaction_WhenInvalidID_ThenReturnsBadRequest(t *testing.
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Get("/transactions/:id", handler.GetTransaction)
@@ -211,7 +383,7 @@ func Test_Handler_GetTransaction_WhenNotFound_ThenReturnsNotFound(t *testing.T)
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Get("/transactions/:id", handler.GetTransaction)
@@ -235,7 +407,7 @@ func Test_Handler_ListTransactions_WhenSuccess_ThenReturnsTransactions(t *testin
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Get("/transactions", handler.ListTransactions)
@@ -270,7 +442,7 @@ func Test_Handler_ListTransactions_WhenCustomPagination_ThenUsesParams(t *testin
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Get("/transactions", handler.ListTransactions)
@@ -296,7 +468,7 @@ func Test_Handler_ListTransactions_WhenInvalidLimit_ThenReturnsBadRequest(t *tes
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Get("/transactions", handler.ListTransactions)
@@ -314,7 +486,7 @@ func Test_Handler_ListTransactions_WhenInvalidOffset_ThenReturnsBadRequest(t *te
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Get("/transactions", handler.ListTransactions)
@@ -332,7 +504,7 @@ func Test_Handler_ListTransactions_WhenServiceFails_ThenReturnsInternalError(t *
 	defer ctrl.Finish()
 
 	mockService := NewMockTransactionService(ctrl)
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, time.Second)
 
 	app := fiber.New()
 	app.Get("/transactions", handler.ListTransactions)
@@ -348,3 +520,207 @@ func Test_Handler_ListTransactions_WhenServiceFails_ThenReturnsInternalError(t *
 
 	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 }
+
+func Test_Handler_Decide_WhenValidEvent_ThenReturnsDecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Post("/transactions/decide", handler.Decide)
+
+	mockService.EXPECT().
+		Decide(gomock.Any(), gomock.Any(), "").
+		Return(&DecisionResponse{
+			Decision:     models.ActionBlock,
+			Status:       models.StatusRejected,
+			ExternalID:   "tx-123",
+			MatchedRules: []string{"rule-1"},
+		}, nil)
+
+	body, _ := json.Marshal(models.Event{"external_id": "tx-123"})
+	req := httptest.NewRequest("POST", "/transactions/decide", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	respBody, _ := io.ReadAll(resp.Body)
+	var result map[string]interface{}
+	err = json.Unmarshal(respBody, &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, "block", result["decision"])
+	assert.Equal(t, false, result["fallback"])
+}
+
+func Test_Handler_Decide_WhenCalled_ThenBoundsServiceByRequestAndDecisionTimeouts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Post("/transactions/decide", handler.Decide)
+
+	mockService.EXPECT().
+		Decide(gomock.Any(), gomock.Any(), "").
+		DoAndReturn(func(ctx context.Context, _ models.Event, _ string) (*DecisionResponse, error) {
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(internal.DEFAULT_TIMEOUT+time.Second), deadline, 100*time.Millisecond)
+			return &DecisionResponse{Decision: models.ActionAllow, Status: models.StatusApproved, MatchedRules: []string{}}, nil
+		})
+
+	body, _ := json.Marshal(models.Event{"external_id": "tx-123"})
+	req := httptest.NewRequest("POST", "/transactions/decide", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func Test_Handler_Decide_WhenEmptyEvent_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Post("/transactions/decide", handler.Decide)
+
+	req := httptest.NewRequest("POST", "/transactions/decide", bytes.NewReader([]byte("{}")))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Handler_Decide_WhenServiceFails_ThenReturnsInternalError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Post("/transactions/decide", handler.Decide)
+
+	mockService.EXPECT().
+		Decide(gomock.Any(), gomock.Any(), "").
+		Return(nil, errors.New("queue error"))
+
+	body, _ := json.Marshal(models.Event{"external_id": "tx-123"})
+	req := httptest.NewRequest("POST", "/transactions/decide", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+func Test_Handler_Decide_WhenRepeatedRequestStillQueued_ThenReturnsConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Post("/transactions/decide", handler.Decide)
+
+	mockService.EXPECT().
+		Decide(gomock.Any(), gomock.Any(), "key-1").
+		Return(nil, ErrRequestInProgress)
+
+	body, _ := json.Marshal(models.Event{"external_id": "tx-123"})
+	req := httptest.NewRequest("POST", "/transactions/decide", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "key-1")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func Test_Handler_ShadowRuleStats_WhenRangeGiven_ThenReturnsStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Get("/transactions/shadow-stats", handler.ShadowRuleStats)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().ShadowRuleStats(gomock.Any(), from, to).Return(&ShadowRuleStats{
+		From:              from,
+		To:                to,
+		TotalTransactions: 10,
+		Rules:             []ShadowRuleStat{{RuleName: "shadow", Matches: 3, MatchRate: 0.3}},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/transactions/shadow-stats?from=2025-01-01T00:00:00Z&to=2025-01-02T00:00:00Z", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var stats ShadowRuleStats
+	respBody, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(respBody, &stats))
+	assert.Equal(t, 10, stats.TotalTransactions)
+	require.Len(t, stats.Rules, 1)
+	assert.Equal(t, "shadow", stats.Rules[0].RuleName)
+}
+
+func Test_Handler_ShadowRuleStats_WhenNoRange_ThenDefaultsToLastDay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockTransactionService(ctrl)
+	handler := NewHandler(mockService, time.Second)
+
+	app := fiber.New()
+	app.Get("/transactions/shadow-stats", handler.ShadowRuleStats)
+
+	mockService.EXPECT().ShadowRuleStats(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, from, to time.Time) (*ShadowRuleStats, error) {
+			assert.Equal(t, DefaultShadowStatsWindow, to.Sub(from))
+			return &ShadowRuleStats{From: from, To: to, Rules: []ShadowRuleStat{}}, nil
+		})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/transactions/shadow-stats", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func Test_Handler_ShadowRuleStats_WhenInvalidTimestamp_ThenReturnsBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(NewMockTransactionService(ctrl), time.Second)
+
+	app := fiber.New()
+	app.Get("/transactions/shadow-stats", handler.ShadowRuleStats)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/transactions/shadow-stats?from=yesterday", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
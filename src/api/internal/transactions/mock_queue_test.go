@@ -12,7 +12,9 @@ package transactions
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	schemas "github.com/algo-shield/algo-shield/src/api/internal/schemas"
 	models "github.com/algo-shield/algo-shield/src/pkg/models"
 	uuid "github.com/google/uuid"
 	redis "github.com/redis/go-redis/v9"
@@ -43,6 +45,21 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// Decide mocks base method.
+func (m *MockService) Decide(ctx context.Context, event models.Event, idempotencyKey string) (*DecisionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decide", ctx, event, idempotencyKey)
+	ret0, _ := ret[0].(*DecisionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decide indicates an expected call of Decide.
+func (mr *MockServiceMockRecorder) Decide(ctx, event, idempotencyKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decide", reflect.TypeOf((*MockService)(nil).Decide), ctx, event, idempotencyKey)
+}
+
 // GetTransaction mocks base method.
 func (m *MockService) GetTransaction(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -74,58 +91,223 @@ func (mr *MockServiceMockRecorder) ListTransactions(ctx, limit, offset any) *gom
 }
 
 // ProcessTransaction mocks base method.
-func (m *MockService) ProcessTransaction(ctx context.Context, event models.Event) error {
+func (m *MockService) ProcessTransaction(ctx context.Context, event models.Event, idempotencyKey string, route Route) (*DecisionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, event, idempotencyKey, route)
+	ret0, _ := ret[0].(*DecisionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessTransaction indicates an expected call of ProcessTransaction.
+func (mr *MockServiceMockRecorder) ProcessTransaction(ctx, event, idempotencyKey, route any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockService)(nil).ProcessTransaction), ctx, event, idempotencyKey, route)
+}
+
+// ShadowRuleStats mocks base method.
+func (m *MockService) ShadowRuleStats(ctx context.Context, from, to time.Time) (*ShadowRuleStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShadowRuleStats", ctx, from, to)
+	ret0, _ := ret[0].(*ShadowRuleStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShadowRuleStats indicates an expected call of ShadowRuleStats.
+func (mr *MockServiceMockRecorder) ShadowRuleStats(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShadowRuleStats", reflect.TypeOf((*MockService)(nil).ShadowRuleStats), ctx, from, to)
+}
+
+// MockQueuePublisher is a mock of QueuePublisher interface.
+type MockQueuePublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockQueuePublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockQueuePublisherMockRecorder is the mock recorder for MockQueuePublisher.
+type MockQueuePublisherMockRecorder struct {
+	mock *MockQueuePublisher
+}
+
+// NewMockQueuePublisher creates a new mock instance.
+func NewMockQueuePublisher(ctrl *gomock.Controller) *MockQueuePublisher {
+	mock := &MockQueuePublisher{ctrl: ctrl}
+	mock.recorder = &MockQueuePublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQueuePublisher) EXPECT() *MockQueuePublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockQueuePublisher) Publish(ctx context.Context, lane models.QueueLane, payloads ...string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, event)
+	varargs := []any{ctx, lane}
+	for _, a := range payloads {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Publish", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ProcessTransaction indicates an expected call of ProcessTransaction.
-func (mr *MockServiceMockRecorder) ProcessTransaction(ctx, event any) *gomock.Call {
+// Publish indicates an expected call of Publish.
+func (mr *MockQueuePublisherMockRecorder) Publish(ctx, lane any, payloads ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockService)(nil).ProcessTransaction), ctx, event)
+	varargs := append([]any{ctx, lane}, payloads...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockQueuePublisher)(nil).Publish), varargs...)
 }
 
-// MockQueuePusher is a mock of QueuePusher interface.
-type MockQueuePusher struct {
+// MockIdempotencyStore is a mock of IdempotencyStore interface.
+type MockIdempotencyStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockQueuePusherMockRecorder
+	recorder *MockIdempotencyStoreMockRecorder
 	isgomock struct{}
 }
 
-// MockQueuePusherMockRecorder is the mock recorder for MockQueuePusher.
-type MockQueuePusherMockRecorder struct {
-	mock *MockQueuePusher
+// MockIdempotencyStoreMockRecorder is the mock recorder for MockIdempotencyStore.
+type MockIdempotencyStoreMockRecorder struct {
+	mock *MockIdempotencyStore
 }
 
-// NewMockQueuePusher creates a new mock instance.
-func NewMockQueuePusher(ctrl *gomock.Controller) *MockQueuePusher {
-	mock := &MockQueuePusher{ctrl: ctrl}
-	mock.recorder = &MockQueuePusherMockRecorder{mock}
+// NewMockIdempotencyStore creates a new mock instance.
+func NewMockIdempotencyStore(ctrl *gomock.Controller) *MockIdempotencyStore {
+	mock := &MockIdempotencyStore{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockQueuePusher) EXPECT() *MockQueuePusherMockRecorder {
+func (m *MockIdempotencyStore) EXPECT() *MockIdempotencyStoreMockRecorder {
 	return m.recorder
 }
 
-// LPush mocks base method.
-func (m *MockQueuePusher) LPush(ctx context.Context, key string, values ...any) *redis.IntCmd {
+// Del mocks base method.
+func (m *MockIdempotencyStore) Del(ctx context.Context, keys ...string) *redis.IntCmd {
 	m.ctrl.T.Helper()
-	varargs := []any{ctx, key}
-	for _, a := range values {
+	varargs := []any{ctx}
+	for _, a := range keys {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "LPush", varargs...)
+	ret := m.ctrl.Call(m, "Del", varargs...)
 	ret0, _ := ret[0].(*redis.IntCmd)
 	return ret0
 }
 
-// LPush indicates an expected call of LPush.
-func (mr *MockQueuePusherMockRecorder) LPush(ctx, key any, values ...any) *gomock.Call {
+// Del indicates an expected call of Del.
+func (mr *MockIdempotencyStoreMockRecorder) Del(ctx any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockIdempotencyStore)(nil).Del), varargs...)
+}
+
+// Get mocks base method.
+func (m *MockIdempotencyStore) Get(ctx context.Context, key string) *redis.StringCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*redis.StringCmd)
+	return ret0
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIdempotencyStoreMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIdempotencyStore)(nil).Get), ctx, key)
+}
+
+// SetNX mocks base method.
+func (m *MockIdempotencyStore) SetNX(ctx context.Context, key string, value any, expiration time.Duration) *redis.BoolCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", ctx, key, value, expiration)
+	ret0, _ := ret[0].(*redis.BoolCmd)
+	return ret0
+}
+
+// SetNX indicates an expected call of SetNX.
+func (mr *MockIdempotencyStoreMockRecorder) SetNX(ctx, key, value, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockIdempotencyStore)(nil).SetNX), ctx, key, value, expiration)
+}
+
+// MockSchemaGetter is a mock of SchemaGetter interface.
+type MockSchemaGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchemaGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockSchemaGetterMockRecorder is the mock recorder for MockSchemaGetter.
+type MockSchemaGetterMockRecorder struct {
+	mock *MockSchemaGetter
+}
+
+// NewMockSchemaGetter creates a new mock instance.
+func NewMockSchemaGetter(ctrl *gomock.Controller) *MockSchemaGetter {
+	mock := &MockSchemaGetter{ctrl: ctrl}
+	mock.recorder = &MockSchemaGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchemaGetter) EXPECT() *MockSchemaGetterMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockSchemaGetter) GetByID(ctx context.Context, id uuid.UUID) (*schemas.EventSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*schemas.EventSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockSchemaGetterMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSchemaGetter)(nil).GetByID), ctx, id)
+}
+
+// MockReplyWaiter is a mock of ReplyWaiter interface.
+type MockReplyWaiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockReplyWaiterMockRecorder
+	isgomock struct{}
+}
+
+// MockReplyWaiterMockRecorder is the mock recorder for MockReplyWaiter.
+type MockReplyWaiterMockRecorder struct {
+	mock *MockReplyWaiter
+}
+
+// NewMockReplyWaiter creates a new mock instance.
+func NewMockReplyWaiter(ctrl *gomock.Controller) *MockReplyWaiter {
+	mock := &MockReplyWaiter{ctrl: ctrl}
+	mock.recorder = &MockReplyWaiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReplyWaiter) EXPECT() *MockReplyWaiterMockRecorder {
+	return m.recorder
+}
+
+// Register mocks base method.
+func (m *MockReplyWaiter) Register(correlationID string) (<-chan models.TransactionResult, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", correlationID)
+	ret0, _ := ret[0].(<-chan models.TransactionResult)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockReplyWaiterMockRecorder) Register(correlationID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{ctx, key}, values...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPush", reflect.TypeOf((*MockQueuePusher)(nil).LPush), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockReplyWaiter)(nil).Register), correlationID)
 }
@@ -12,6 +12,7 @@ package transactions
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/algo-shield/algo-shield/src/pkg/models"
 	uuid "github.com/google/uuid"
@@ -42,6 +43,36 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountShadowMatches mocks base method.
+func (m *MockRepository) CountShadowMatches(ctx context.Context, from, to time.Time) ([]ShadowRuleStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountShadowMatches", ctx, from, to)
+	ret0, _ := ret[0].([]ShadowRuleStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountShadowMatches indicates an expected call of CountShadowMatches.
+func (mr *MockRepositoryMockRecorder) CountShadowMatches(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountShadowMatches", reflect.TypeOf((*MockRepository)(nil).CountShadowMatches), ctx, from, to)
+}
+
+// CountTransactions mocks base method.
+func (m *MockRepository) CountTransactions(ctx context.Context, from, to time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTransactions", ctx, from, to)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTransactions indicates an expected call of CountTransactions.
+func (mr *MockRepositoryMockRecorder) CountTransactions(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTransactions", reflect.TypeOf((*MockRepository)(nil).CountTransactions), ctx, from, to)
+}
+
 // GetTransaction mocks base method.
 func (m *MockRepository) GetTransaction(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -57,6 +88,21 @@ func (mr *MockRepositoryMockRecorder) GetTransaction(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockRepository)(nil).GetTransaction), ctx, id)
 }
 
+// GetTransactionByExternalID mocks base method.
+func (m *MockRepository) GetTransactionByExternalID(ctx context.Context, externalID string) (*models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionByExternalID", ctx, externalID)
+	ret0, _ := ret[0].(*models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionByExternalID indicates an expected call of GetTransactionByExternalID.
+func (mr *MockRepositoryMockRecorder) GetTransactionByExternalID(ctx, externalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByExternalID", reflect.TypeOf((*MockRepository)(nil).GetTransactionByExternalID), ctx, externalID)
+}
+
 // ListTransactions mocks base method.
 func (m *MockRepository) ListTransactions(ctx context.Context, limit, offset int) ([]models.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=service.go -destination=mock_transactionservice_test.go -package=transactions -mock_names=Service=MockTransactionService Service
+//	mockgen -source=service.go -destination=mock_transactionservice_test.go -package=transactions -mock_names=Service=MockTransactionService -exclude_interfaces=QueuePusher,ReplyWaiter,IdempotencyStore,SchemaGetter Service
 //
 
 // Package transactions is a generated GoMock package.
@@ -12,6 +12,7 @@ package transactions
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/algo-shield/algo-shield/src/pkg/models"
 	uuid "github.com/google/uuid"
@@ -42,6 +43,21 @@ func (m *MockTransactionService) EXPECT() *MockTransactionServiceMockRecorder {
 	return m.recorder
 }
 
+// Decide mocks base method.
+func (m *MockTransactionService) Decide(ctx context.Context, event models.Event, idempotencyKey string) (*DecisionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decide", ctx, event, idempotencyKey)
+	ret0, _ := ret[0].(*DecisionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decide indicates an expected call of Decide.
+func (mr *MockTransactionServiceMockRecorder) Decide(ctx, event, idempotencyKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decide", reflect.TypeOf((*MockTransactionService)(nil).Decide), ctx, event, idempotencyKey)
+}
+
 // GetTransaction mocks base method.
 func (m *MockTransactionService) GetTransaction(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -73,15 +89,31 @@ func (mr *MockTransactionServiceMockRecorder) ListTransactions(ctx, limit, offse
 }
 
 // ProcessTransaction mocks base method.
-func (m *MockTransactionService) ProcessTransaction(ctx context.Context, event models.Event) error {
+func (m *MockTransactionService) ProcessTransaction(ctx context.Context, event models.Event, idempotencyKey string, route Route) (*DecisionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, event)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, event, idempotencyKey, route)
+	ret0, _ := ret[0].(*DecisionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ProcessTransaction indicates an expected call of ProcessTransaction.
-func (mr *MockTransactionServiceMockRecorder) ProcessTransaction(ctx, event any) *gomock.Call {
+func (mr *MockTransactionServiceMockRecorder) ProcessTransaction(ctx, event, idempotencyKey, route any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockTransactionService)(nil).ProcessTransaction), ctx, event, idempotencyKey, route)
+}
+
+// ShadowRuleStats mocks base method.
+func (m *MockTransactionService) ShadowRuleStats(ctx context.Context, from, to time.Time) (*ShadowRuleStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShadowRuleStats", ctx, from, to)
+	ret0, _ := ret[0].(*ShadowRuleStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShadowRuleStats indicates an expected call of ShadowRuleStats.
+func (mr *MockTransactionServiceMockRecorder) ShadowRuleStats(ctx, from, to any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockTransactionService)(nil).ProcessTransaction), ctx, event)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShadowRuleStats", reflect.TypeOf((*MockTransactionService)(nil).ShadowRuleStats), ctx, from, to)
 }
@@ -2,6 +2,7 @@ package transactions
 
 import (
 	"context"
+	"time"
 
 	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/google/uuid"
@@ -16,7 +17,11 @@ type PostgresRepository struct {
 // Repository defines the interface for transaction data access operations
 type Repository interface {
 	GetTransaction(ctx context.Context, id uuid.UUID) (*models.Transaction, error)
+	// GetTransactionByExternalID returns pgx.ErrNoRows when no transaction has this external_id
+	GetTransactionByExternalID(ctx context.Context, externalID string) (*models.Transaction, error)
 	ListTransactions(ctx context.Context, limit, offset int) ([]models.Transaction, error)
+	CountTransactions(ctx context.Context, from, to time.Time) (int, error)
+	CountShadowMatches(ctx context.Context, from, to time.Time) ([]ShadowRuleStat, error)
 }
 
 func NewPostgresRepository(db *pgxpool.Pool) Repository {
@@ -24,17 +29,26 @@ func NewPostgresRepository(db *pgxpool.Pool) Repository {
 }
 
 func (r *PostgresRepository) GetTransaction(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+	return r.getTransactionWhere(ctx, "id", id)
+}
+
+func (r *PostgresRepository) GetTransactionByExternalID(ctx context.Context, externalID string) (*models.Transaction, error) {
+	return r.getTransactionWhere(ctx, "external_id", externalID)
+}
+
+// getTransactionWhere returns the transaction whose unique column has the given value
+func (r *PostgresRepository) getTransactionWhere(ctx context.Context, column string, value any) (*models.Transaction, error) {
 	var transaction models.Transaction
 
 	query := `
 		SELECT id, external_id, amount, currency, origin, destination, 
-		       type, status, processing_time, 
-		       matched_rules, metadata, created_at, processed_at
+		       type, status, risk_score, processing_time, 
+		       matched_rules, matched_rule_versions, shadow_matched_rules, shadow_matched_rule_versions, decided_by_rule_id, timed_out_rules, timeout_fallback, metadata, created_at, processed_at
 		FROM transactions
-		WHERE id = $1
+		WHERE ` + column + ` = $1
 	`
 
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := r.db.QueryRow(ctx, query, value).Scan(
 		&transaction.ID,
 		&transaction.ExternalID,
 		&transaction.Amount,
@@ -43,8 +57,15 @@ func (r *PostgresRepository) GetTransaction(ctx context.Context, id uuid.UUID) (
 		&transaction.Destination,
 		&transaction.Type,
 		&transaction.Status,
+		&transaction.RiskScore,
 		&transaction.ProcessingTime,
 		&transaction.MatchedRules,
+		&transaction.MatchedVersions,
+		&transaction.ShadowMatchedRules,
+		&transaction.ShadowVersions,
+		&transaction.DecidedBy,
+		&transaction.TimedOutRules,
+		&transaction.TimeoutFallback,
 		&transaction.Metadata,
 		&transaction.CreatedAt,
 		&transaction.ProcessedAt,
@@ -60,8 +81,8 @@ func (r *PostgresRepository) GetTransaction(ctx context.Context, id uuid.UUID) (
 func (r *PostgresRepository) ListTransactions(ctx context.Context, limit, offset int) ([]models.Transaction, error) {
 	query := `
 		SELECT id, external_id, amount, currency, origin, destination, 
-		       type, status, processing_time, 
-		       matched_rules, metadata, created_at, processed_at
+		       type, status, risk_score, processing_time, 
+		       matched_rules, matched_rule_versions, shadow_matched_rules, shadow_matched_rule_versions, decided_by_rule_id, timed_out_rules, timeout_fallback, metadata, created_at, processed_at
 		FROM transactions
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -85,8 +106,16 @@ func (r *PostgresRepository) ListTransactions(ctx context.Context, limit, offset
 			&transaction.Destination,
 			&transaction.Type,
 			&transaction.Status,
+			&transaction.RiskScore,
 			&transaction.ProcessingTime,
 			&transaction.MatchedRules,
+			&transaction.MatchedVersions,
+			&transaction.ShadowMatchedRules,
+			&transaction.ShadowVersions,
+			&transaction.ShadowVersions,
+			&transaction.DecidedBy,
+			&transaction.TimedOutRules,
+			&transaction.TimeoutFallback,
 			&transaction.Metadata,
 			&transaction.CreatedAt,
 			&transaction.ProcessedAt,
@@ -99,3 +128,61 @@ func (r *PostgresRepository) ListTransactions(ctx context.Context, limit, offset
 
 	return transactions, nil
 }
+
+// CountTransactions counts transactions created in [from, to)
+func (r *PostgresRepository) CountTransactions(ctx context.Context, from, to time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM transactions
+		WHERE created_at >= $1 AND created_at < $2
+	`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, from, to).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountShadowMatches counts shadow rule matches per rule for transactions created in [from, to)
+// Matches are grouped by rule ID across versions and reported under the rule's current name
+func (r *PostgresRepository) CountShadowMatches(ctx context.Context, from, to time.Time) ([]ShadowRuleStat, error) {
+	query := `
+		WITH matches AS (
+			SELECT rv.rule_id, COUNT(*) AS matches
+			FROM transactions t
+			CROSS JOIN LATERAL jsonb_array_elements_text(t.shadow_matched_rule_versions) AS v(version_id)
+			JOIN rule_versions rv ON rv.id = v.version_id::uuid
+			WHERE t.created_at >= $1 AND t.created_at < $2
+			GROUP BY rv.rule_id
+		)
+		SELECT m.rule_id, COALESCE(r.name, latest.name, ''), m.matches
+		FROM matches m
+		LEFT JOIN rules r ON r.id = m.rule_id
+		LEFT JOIN LATERAL (
+			SELECT snapshot->>'name' AS name
+			FROM rule_versions
+			WHERE rule_id = m.rule_id
+			ORDER BY version DESC
+			LIMIT 1
+		) latest ON true
+		ORDER BY m.matches DESC, m.rule_id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]ShadowRuleStat, 0)
+	for rows.Next() {
+		var stat ShadowRuleStat
+		if err := rows.Scan(&stat.RuleID, &stat.RuleName, &stat.Matches); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/algo-shield/algo-shield/src/api/internal/transactions"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -66,6 +67,36 @@ func TestIntegration_TransactionsRepository_GetTransaction_NotFound_ReturnsError
 	assert.Nil(t, result)
 }
 
+func TestIntegration_TransactionsRepository_GetTransactionByExternalID_ReturnsTransaction(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	repo := transactions.NewPostgresRepository(testDB.Postgres)
+	ctx := context.Background()
+
+	transactionID := uuid.New()
+	_, err := testDB.Postgres.Exec(ctx, `
+		INSERT INTO transactions (id, external_id, amount, currency, origin, destination, type, status, risk_score, processing_time, created_at)
+		VALUES ($1, 'ext-456', 10, 'USD', 'account1', 'account2', 'transfer', 'rejected', 90, 12, NOW())
+	`, transactionID)
+	require.NoError(t, err)
+
+	result, err := repo.GetTransactionByExternalID(ctx, "ext-456")
+
+	require.NoError(t, err)
+	assert.Equal(t, transactionID, result.ID)
+	assert.Equal(t, models.StatusRejected, result.Status)
+	assert.Equal(t, 90, result.RiskScore)
+}
+
+func TestIntegration_TransactionsRepository_GetTransactionByExternalID_NotFound_ReturnsErrNoRows(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	repo := transactions.NewPostgresRepository(testDB.Postgres)
+
+	result, err := repo.GetTransactionByExternalID(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+	assert.Nil(t, result)
+}
+
 func TestIntegration_TransactionsRepository_ListTransactions_ReturnsTransactions(t *testing.T) {
 	testDB := testutil.SetupTestDB(t)
 	repo := transactions.NewPostgresRepository(testDB.Postgres)
@@ -154,3 +185,70 @@ func TestIntegration_TransactionsRepository_ListTransactions_Empty_ReturnsEmpty(
 	require.NoError(t, err)
 	assert.NotNil(t, result)
 }
+
+func TestIntegration_TransactionsRepository_CountShadowMatches_GroupsByRuleAcrossVersions(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	repo := transactions.NewPostgresRepository(testDB.Postgres)
+	ctx := context.Background()
+	now := time.Now()
+
+	// The first rule was renamed between its versions; the second shares its old name
+	renamedID, namesakeID := uuid.New(), uuid.New()
+	renamedV1, renamedV2, namesakeV1 := uuid.New(), uuid.New(), uuid.New()
+	_, err := testDB.Postgres.Exec(ctx, `
+		INSERT INTO rule_versions (id, rule_id, version, snapshot)
+		VALUES ($1, $2, 1, '{"name": "velocity"}'), ($3, $2, 2, '{"name": "velocity v2"}'), ($4, $5, 1, '{"name": "velocity"}')
+	`, renamedV1, renamedID, renamedV2, namesakeV1, namesakeID)
+	require.NoError(t, err)
+
+	for i, versions := range [][]uuid.UUID{{renamedV1}, {renamedV2, namesakeV1}, {renamedV2}} {
+		versionsJSON, err := json.Marshal(versions)
+		require.NoError(t, err)
+		_, err = testDB.Postgres.Exec(ctx, `
+			INSERT INTO transactions (id, external_id, amount, currency, origin, destination, type, status, processing_time, shadow_matched_rule_versions, created_at)
+			VALUES ($1, $2, 100, 'USD', 'acc1', 'acc2', 'transfer', 'approved', 10, $3, $4)
+		`, uuid.New(), "ext-shadow-"+strconv.Itoa(i), versionsJSON, now)
+		require.NoError(t, err)
+	}
+
+	stats, err := repo.CountShadowMatches(ctx, now.Add(-time.Minute), now.Add(time.Minute))
+
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, renamedID, stats[0].RuleID)
+	assert.Equal(t, "velocity v2", stats[0].RuleName)
+	assert.Equal(t, 3, stats[0].Matches)
+	assert.Equal(t, namesakeID, stats[1].RuleID)
+	assert.Equal(t, 1, stats[1].Matches)
+}
+
+func TestIntegration_TransactionsRepository_CountShadowMatches_CountsMatchesStoredByName(t *testing.T) {
+	testDB := testutil.SetupTestDB(t)
+	repo := transactions.NewPostgresRepository(testDB.Postgres)
+	ctx := context.Background()
+	now := time.Now()
+
+	ruleID, v1, v2 := uuid.New(), uuid.New(), uuid.New()
+	_, err := testDB.Postgres.Exec(ctx, `
+		INSERT INTO rule_versions (id, rule_id, version, snapshot, created_at)
+		VALUES ($1, $2, 1, '{"name": "velocity"}', $4), ($3, $2, 2, '{"name": "velocity v2"}', $5)
+	`, v1, ruleID, v2, now.Add(-time.Hour), now.Add(-time.Minute))
+	require.NoError(t, err)
+
+	// Matches saved before shadow matches were stored by version only hold rule names
+	for i, names := range []string{`["velocity"]`, `["velocity v2"]`} {
+		_, err = testDB.Postgres.Exec(ctx, `
+			INSERT INTO transactions (id, external_id, amount, currency, origin, destination, type, status, processing_time, shadow_matched_rules, created_at)
+			VALUES ($1, $2, 100, 'USD', 'acc1', 'acc2', 'transfer', 'approved', 10, $3, $4)
+		`, uuid.New(), "ext-legacy-shadow-"+strconv.Itoa(i), names, now)
+		require.NoError(t, err)
+	}
+
+	testutil.RunMigration(t, testDB.Postgres, "024_shadow_rule_versions.sql")
+	stats, err := repo.CountShadowMatches(ctx, now.Add(-time.Minute), now.Add(time.Minute))
+
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, ruleID, stats[0].RuleID)
+	assert.Equal(t, 2, stats[0].Matches)
+}
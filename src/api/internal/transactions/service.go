@@ -3,48 +3,337 @@ package transactions
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"log"
+	"time"
 
+	"github.com/algo-shield/algo-shield/src/api/internal/schemas"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 )
 
+// ErrRequestInProgress is returned for a repeated request whose first attempt is still queued
+var ErrRequestInProgress = errors.New("a request with this idempotency key is still being processed")
+
+// Route selects the queue lane of a transaction
+// An explicit lane wins over the lane of the schema; with neither the event goes to post_transaction
+type Route struct {
+	Lane     models.QueueLane
+	SchemaID uuid.UUID
+}
+
 // Service defines the interface for transaction business logic
 // This interface follows Dependency Inversion Principle
 type Service interface {
-	ProcessTransaction(ctx context.Context, event models.Event) error
+	// ProcessTransaction queues an event and returns nil, or returns the decision already
+	// made for a repeated request. idempotencyKey may be empty to dedupe on the external_id
+	// Returns schemas.ErrSchemaNotFound when the route names an unknown schema
+	ProcessTransaction(ctx context.Context, event models.Event, idempotencyKey string, route Route) (*DecisionResponse, error)
+	Decide(ctx context.Context, event models.Event, idempotencyKey string) (*DecisionResponse, error)
 	GetTransaction(ctx context.Context, id uuid.UUID) (*models.Transaction, error)
 	ListTransactions(ctx context.Context, limit, offset int) ([]models.Transaction, error)
+	ShadowRuleStats(ctx context.Context, from, to time.Time) (*ShadowRuleStats, error)
+}
+
+// QueuePublisher defines interface for queueing events over the workers' transport
+// Follows Dependency Inversion Principle
+type QueuePublisher interface {
+	Publish(ctx context.Context, lane models.QueueLane, payloads ...string) error
 }
 
-// QueuePusher defines interface for pushing to queue
+// IdempotencyStore defines interface for claiming idempotency keys
 // Follows Dependency Inversion Principle
-type QueuePusher interface {
-	LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+type IdempotencyStore interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
 }
 
+// SchemaGetter loads the schema whose lane a transaction is queued on
+type SchemaGetter interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*schemas.EventSchema, error)
+}
+
+// ReplyWaiter defines interface for waiting on decision replies
+// Follows Dependency Inversion Principle
+type ReplyWaiter interface {
+	Register(correlationID string) (<-chan models.TransactionResult, func())
+}
+
+// idempotencyKeyPrefix prefixes the keys claimed by requests, holding the external_id they queued
+const idempotencyKeyPrefix = "transaction:idempotency:"
+
 type service struct {
-	repo      Repository
-	queuePush QueuePusher
+	repo           Repository
+	publisher      QueuePublisher
+	idempotency    IdempotencyStore
+	schemas        SchemaGetter
+	replies        ReplyWaiter
+	decisionCfg    DecisionConfig
+	idempotencyTTL time.Duration
 }
 
 // NewService creates a new transaction service with dependency injection
 // Follows Dependency Inversion Principle - receives interfaces, not concrete types
-func NewService(repo Repository, queuePush QueuePusher) Service {
+// idempotency may be nil to queue repeated requests again; the worker still saves them once
+func NewService(repo Repository, publisher QueuePublisher, idempotency IdempotencyStore, schemas SchemaGetter, replies ReplyWaiter, decisionCfg DecisionConfig, idempotencyTTL time.Duration) Service {
 	return &service{
-		repo:      repo,
-		queuePush: queuePush,
+		repo:           repo,
+		publisher:      publisher,
+		idempotency:    idempotency,
+		schemas:        schemas,
+		replies:        replies,
+		decisionCfg:    decisionCfg,
+		idempotencyTTL: idempotencyTTL,
 	}
 }
 
-func (s *service) ProcessTransaction(ctx context.Context, event models.Event) error {
+func (s *service) ProcessTransaction(ctx context.Context, event models.Event, idempotencyKey string, route Route) (*DecisionResponse, error) {
+	lane, err := s.lane(ctx, route)
+	if err != nil {
+		return nil, err
+	}
+
+	event, key := withIdempotencyKey(event, idempotencyKey)
+
+	existing, err := s.claim(ctx, key, storedExternalID(event))
+	if err != nil || existing != nil {
+		return existing, err
+	}
+	event = withExternalID(event)
+
 	eventJSON, err := json.Marshal(event)
 	if err != nil {
-		return err
+		s.release(ctx, key)
+		return nil, err
+	}
+
+	// Queue on the lane over the configured transport
+	if err := s.publisher.Publish(ctx, lane, string(eventJSON)); err != nil {
+		s.release(ctx, key)
+		return nil, err
+	}
+	return nil, nil
+}
+
+// lane returns the queue lane a route selects
+func (s *service) lane(ctx context.Context, route Route) (models.QueueLane, error) {
+	if route.Lane != "" {
+		return route.Lane, nil
+	}
+	if route.SchemaID == uuid.Nil {
+		return models.LanePostTransaction, nil
+	}
+
+	schema, err := s.schemas.GetByID(ctx, route.SchemaID)
+	if err != nil {
+		return "", err
+	}
+	if schema.Lane == "" {
+		return models.LanePostTransaction, nil
+	}
+	return schema.Lane, nil
+}
+
+// Decide queues an event on the realtime lane and waits for the worker's decision
+// When the deadline expires the configured fallback decision is returned and the
+// event is still processed asynchronously by the worker
+// A repeated request returns the decision already made instead of queueing the event again
+func (s *service) Decide(ctx context.Context, event models.Event, idempotencyKey string) (*DecisionResponse, error) {
+	event, key := withIdempotencyKey(event, idempotencyKey)
+
+	existing, err := s.claim(ctx, key, storedExternalID(event))
+	if err != nil || existing != nil {
+		return existing, err
+	}
+	event = withExternalID(event)
+
+	correlationID := uuid.NewString()
+
+	// Copy the event so the caller's map is not modified
+	request := make(models.Event, len(event)+1)
+	for k, v := range event {
+		request[k] = v
+	}
+	request[models.DecisionReplyToField] = correlationID
+
+	eventJSON, err := json.Marshal(request)
+	if err != nil {
+		s.release(ctx, key)
+		return nil, err
+	}
+
+	// Register before pushing so a fast reply is never missed
+	replies, cancel := s.replies.Register(correlationID)
+	defer cancel()
+
+	if err := s.publisher.Publish(ctx, models.LaneRealtime, string(eventJSON)); err != nil {
+		s.release(ctx, key)
+		return nil, err
+	}
+
+	externalID := externalIDFromEvent(event)
+	deadline := time.NewTimer(s.decisionCfg.Timeout)
+	defer deadline.Stop()
+
+	select {
+	case result := <-replies:
+		transactionID := result.TransactionID
+		return &DecisionResponse{
+			Decision:       models.DecisionForStatus(result.Status),
+			Status:         result.Status,
+			ExternalID:     externalID,
+			TransactionID:  &transactionID,
+			RiskScore:      result.RiskScore,
+			MatchedRules:   result.MatchedRules,
+			DecidedBy:      result.DecidedBy,
+			ProcessingTime: result.ProcessingTime,
+		}, nil
+	case <-deadline.C:
+	case <-ctx.Done():
+	}
+
+	log.Printf("Decision deadline expired for transaction %s, falling back to %s", externalID, s.decisionCfg.Fallback)
+	return &DecisionResponse{
+		Decision:     s.decisionCfg.Fallback,
+		Status:       models.StatusForDecision(s.decisionCfg.Fallback),
+		ExternalID:   externalID,
+		MatchedRules: []string{},
+		Fallback:     true,
+	}, nil
+}
+
+// withIdempotencyKey returns the event to queue and the key repeated requests are detected by
+// The Idempotency-Key becomes the external_id of events without one, so the worker saves the
+// transaction under it and its decision can be found again. Otherwise the external_id is the key.
+func withIdempotencyKey(event models.Event, idempotencyKey string) (models.Event, string) {
+	externalID := storedExternalID(event)
+	switch {
+	case idempotencyKey == "":
+		return event, externalID
+	case externalID != "":
+		return event, idempotencyKey
+	}
+
+	// Copy the event so the caller's map is not modified
+	keyed := make(models.Event, len(event)+1)
+	for k, v := range event {
+		keyed[k] = v
+	}
+	keyed["external_id"] = idempotencyKey
+	return keyed, idempotencyKey
+}
+
+// withExternalID gives an event without an external_id a new one before it is queued,
+// so the worker records every attempt at processing it under the same ID
+func withExternalID(event models.Event) models.Event {
+	if storedExternalID(event) != "" {
+		return event
+	}
+
+	// Copy the event so the caller's map is not modified
+	identified := make(models.Event, len(event)+1)
+	for k, v := range event {
+		identified[k] = v
+	}
+	identified["external_id"] = uuid.NewString()
+	return identified
+}
+
+// claim reserves an idempotency key for a new request
+// Returns nil when the request is new and must be queued. A repeated request gets the
+// decision of the transaction saved first, or ErrRequestInProgress while it is still queued.
+// Events without a key are never deduplicated
+func (s *service) claim(ctx context.Context, key, externalID string) (*DecisionResponse, error) {
+	if key == "" || s.idempotency == nil {
+		return nil, nil
+	}
+
+	// The transaction may be saved long after its key expired
+	if existing, err := s.existingDecision(ctx, externalID); existing != nil || err != nil {
+		return existing, err
+	}
+
+	claimed, err := s.idempotency.SetNX(ctx, idempotencyKeyPrefix+key, externalID, s.idempotencyTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	// The first request may have used another external_id under the same Idempotency-Key
+	firstID, err := s.idempotency.Get(ctx, idempotencyKeyPrefix+key).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	if firstID != externalID {
+		if existing, err := s.existingDecision(ctx, firstID); existing != nil || err != nil {
+			return existing, err
+		}
 	}
 
-	// Push to Redis queue
-	return s.queuePush.LPush(ctx, "transaction:queue", eventJSON).Err()
+	return nil, ErrRequestInProgress
+}
+
+// release frees the key of a request that could not be queued, so it can be retried
+func (s *service) release(ctx context.Context, key string) {
+	if key == "" || s.idempotency == nil {
+		return
+	}
+	if err := s.idempotency.Del(ctx, idempotencyKeyPrefix+key).Err(); err != nil {
+		log.Printf("Failed to release idempotency key %s: %v", key, err)
+	}
+}
+
+// existingDecision returns the decision saved for an external_id, or nil when there is none yet
+func (s *service) existingDecision(ctx context.Context, externalID string) (*DecisionResponse, error) {
+	if externalID == "" {
+		return nil, nil
+	}
+
+	transaction, err := s.repo.GetTransactionByExternalID(ctx, externalID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &DecisionResponse{
+		Decision:       models.DecisionForStatus(transaction.Status),
+		Status:         transaction.Status,
+		ExternalID:     transaction.ExternalID,
+		TransactionID:  &transaction.ID,
+		RiskScore:      transaction.RiskScore,
+		MatchedRules:   transaction.MatchedRules,
+		DecidedBy:      transaction.DecidedBy,
+		ProcessingTime: transaction.ProcessingTime,
+		Duplicate:      true,
+	}, nil
+}
+
+// storedExternalID returns the external_id the worker saves an event under, empty when it has none
+// Reads the same fields as the worker, in the same order
+func storedExternalID(event models.Event) string {
+	for _, field := range []string{"external_id", "id", "event_id"} {
+		if id, ok := event[field].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// externalIDFromEvent extracts the external_id of an event for responses
+func externalIDFromEvent(event models.Event) string {
+	if id, ok := event["external_id"].(string); ok {
+		return id
+	} else if id, ok := event["id"].(string); ok {
+		return id
+	}
+	return "unknown"
 }
 
 func (s *service) GetTransaction(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
@@ -54,3 +343,29 @@ func (s *service) GetTransaction(ctx context.Context, id uuid.UUID) (*models.Tra
 func (s *service) ListTransactions(ctx context.Context, limit, offset int) ([]models.Transaction, error) {
 	return s.repo.ListTransactions(ctx, limit, offset)
 }
+
+// ShadowRuleStats reports how often each shadow rule fired in [from, to)
+func (s *service) ShadowRuleStats(ctx context.Context, from, to time.Time) (*ShadowRuleStats, error) {
+	total, err := s.repo.CountTransactions(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.repo.CountShadowMatches(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if total > 0 {
+		for i := range rules {
+			rules[i].MatchRate = float64(rules[i].Matches) / float64(total)
+		}
+	}
+
+	return &ShadowRuleStats{
+		From:              from,
+		To:                to,
+		TotalTransactions: total,
+		Rules:             rules,
+	}, nil
+}
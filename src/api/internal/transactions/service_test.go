@@ -2,12 +2,15 @@ package transactions
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/algo-shield/algo-shield/src/api/internal/schemas"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,14 +30,63 @@ func Test_Service_ProcessTransaction_WhenSuccess_ThenPushesToQueue(t *testing.T)
 		"type":        "transfer",
 	}
 	mockRepo := NewMockRepository(ctrl)
-	mockQueue := NewMockQueuePusher(ctrl)
-	cmd := redis.NewIntCmd(context.Background())
-	mockQueue.EXPECT().LPush(gomock.Any(), "transaction:queue", gomock.Any()).Return(cmd)
-	service := NewService(mockRepo, mockQueue)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockQueue.EXPECT().Publish(gomock.Any(), models.LanePostTransaction, gomock.Any()).Return(nil)
+	service := NewService(mockRepo, mockQueue, nil, nil, nil, DecisionConfig{}, 0)
 
-	err := service.ProcessTransaction(context.Background(), event)
+	existing, err := service.ProcessTransaction(context.Background(), event, "", Route{})
 
 	assert.NoError(t, err)
+	assert.Nil(t, existing)
+}
+
+func Test_Service_ProcessTransaction_WhenLaneGiven_ThenQueuesOnLane(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockSchemas := NewMockSchemaGetter(ctrl)
+	mockQueue.EXPECT().Publish(gomock.Any(), models.LaneBackfill, gomock.Any()).Return(nil)
+	service := NewService(mockRepo, mockQueue, nil, mockSchemas, nil, DecisionConfig{}, 0)
+
+	route := Route{Lane: models.LaneBackfill, SchemaID: uuid.New()}
+	_, err := service.ProcessTransaction(context.Background(), models.Event{"external_id": "ext-123"}, "", route)
+
+	require.NoError(t, err)
+}
+
+func Test_Service_ProcessTransaction_WhenSchemaGiven_ThenQueuesOnSchemaLane(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockSchemas := NewMockSchemaGetter(ctrl)
+	mockSchemas.EXPECT().GetByID(gomock.Any(), schemaID).Return(&schemas.EventSchema{ID: schemaID, Lane: models.LaneRealtime}, nil)
+	mockQueue.EXPECT().Publish(gomock.Any(), models.LaneRealtime, gomock.Any()).Return(nil)
+	service := NewService(mockRepo, mockQueue, nil, mockSchemas, nil, DecisionConfig{}, 0)
+
+	_, err := service.ProcessTransaction(context.Background(), models.Event{"external_id": "ext-123"}, "", Route{SchemaID: schemaID})
+
+	require.NoError(t, err)
+}
+
+func Test_Service_ProcessTransaction_WhenSchemaUnknown_ThenReturnsErrorWithoutQueueing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockSchemas := NewMockSchemaGetter(ctrl)
+	mockSchemas.EXPECT().GetByID(gomock.Any(), schemaID).Return(nil, schemas.ErrSchemaNotFound)
+	service := NewService(mockRepo, mockQueue, nil, mockSchemas, nil, DecisionConfig{}, 0)
+
+	_, err := service.ProcessTransaction(context.Background(), models.Event{"external_id": "ext-123"}, "", Route{SchemaID: schemaID})
+
+	assert.ErrorIs(t, err, schemas.ErrSchemaNotFound)
 }
 
 func Test_Service_ProcessTransaction_WhenQueueFails_ThenReturnsError(t *testing.T) {
@@ -47,13 +99,11 @@ func Test_Service_ProcessTransaction_WhenQueueFails_ThenReturnsError(t *testing.
 		"currency":    "USD",
 	}
 	mockRepo := NewMockRepository(ctrl)
-	mockQueue := NewMockQueuePusher(ctrl)
-	cmd := redis.NewIntCmd(context.Background())
-	cmd.SetErr(errors.New("queue error"))
-	mockQueue.EXPECT().LPush(gomock.Any(), "transaction:queue", gomock.Any()).Return(cmd)
-	service := NewService(mockRepo, mockQueue)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockQueue.EXPECT().Publish(gomock.Any(), models.LanePostTransaction, gomock.Any()).Return(errors.New("queue error"))
+	service := NewService(mockRepo, mockQueue, nil, nil, nil, DecisionConfig{}, 0)
 
-	err := service.ProcessTransaction(context.Background(), event)
+	_, err := service.ProcessTransaction(context.Background(), event, "", Route{})
 
 	assert.Error(t, err)
 }
@@ -78,9 +128,9 @@ func Test_Service_GetTransaction_WhenExists_ThenReturnsTransaction(t *testing.T)
 		ProcessedAt:    &now,
 	}
 	mockRepo := NewMockRepository(ctrl)
-	mockQueue := NewMockQueuePusher(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
 	mockRepo.EXPECT().GetTransaction(gomock.Any(), txID).Return(expectedTx, nil)
-	service := NewService(mockRepo, mockQueue)
+	service := NewService(mockRepo, mockQueue, nil, nil, nil, DecisionConfig{}, 0)
 
 	tx, err := service.GetTransaction(context.Background(), txID)
 
@@ -94,9 +144,9 @@ func Test_Service_GetTransaction_WhenNotFound_ThenReturnsError(t *testing.T) {
 
 	txID := uuid.New()
 	mockRepo := NewMockRepository(ctrl)
-	mockQueue := NewMockQueuePusher(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
 	mockRepo.EXPECT().GetTransaction(gomock.Any(), txID).Return(nil, errors.New("not found"))
-	service := NewService(mockRepo, mockQueue)
+	service := NewService(mockRepo, mockQueue, nil, nil, nil, DecisionConfig{}, 0)
 
 	tx, err := service.GetTransaction(context.Background(), txID)
 
@@ -125,9 +175,9 @@ func Test_Service_ListTransactions_WhenSuccess_ThenReturnsTransactions(t *testin
 		},
 	}
 	mockRepo := NewMockRepository(ctrl)
-	mockQueue := NewMockQueuePusher(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
 	mockRepo.EXPECT().ListTransactions(gomock.Any(), 10, 0).Return(expectedTxs, nil)
-	service := NewService(mockRepo, mockQueue)
+	service := NewService(mockRepo, mockQueue, nil, nil, nil, DecisionConfig{}, 0)
 
 	txs, err := service.ListTransactions(context.Background(), 10, 0)
 
@@ -140,9 +190,9 @@ func Test_Service_ListTransactions_WhenRepositoryFails_ThenReturnsError(t *testi
 	defer ctrl.Finish()
 
 	mockRepo := NewMockRepository(ctrl)
-	mockQueue := NewMockQueuePusher(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
 	mockRepo.EXPECT().ListTransactions(gomock.Any(), 10, 0).Return(nil, errors.New("database error"))
-	service := NewService(mockRepo, mockQueue)
+	service := NewService(mockRepo, mockQueue, nil, nil, nil, DecisionConfig{}, 0)
 
 	txs, err := service.ListTransactions(context.Background(), 10, 0)
 
@@ -155,9 +205,9 @@ func Test_Service_ListTransactions_WhenEmptyResult_ThenReturnsEmptySlice(t *test
 	defer ctrl.Finish()
 
 	mockRepo := NewMockRepository(ctrl)
-	mockQueue := NewMockQueuePusher(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
 	mockRepo.EXPECT().ListTransactions(gomock.Any(), 10, 0).Return([]models.Transaction{}, nil)
-	service := NewService(mockRepo, mockQueue)
+	service := NewService(mockRepo, mockQueue, nil, nil, nil, DecisionConfig{}, 0)
 
 	txs, err := service.ListTransactions(context.Background(), 10, 0)
 
@@ -170,11 +220,295 @@ func Test_Service_ListTransactions_WhenDifferentPagination_ThenPassesCorrectPara
 	defer ctrl.Finish()
 
 	mockRepo := NewMockRepository(ctrl)
-	mockQueue := NewMockQueuePusher(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
 	mockRepo.EXPECT().ListTransactions(gomock.Any(), 50, 100).Return([]models.Transaction{}, nil)
-	service := NewService(mockRepo, mockQueue)
+	service := NewService(mockRepo, mockQueue, nil, nil, nil, DecisionConfig{}, 0)
 
 	_, err := service.ListTransactions(context.Background(), 50, 100)
 
 	assert.NoError(t, err)
 }
+
+func Test_Service_Decide_WhenWorkerReplies_ThenReturnsDecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transactionID := uuid.New()
+	event := models.Event{"external_id": "ext-123", "amount": 100.0}
+	replies := make(chan models.TransactionResult, 1)
+	replies <- models.TransactionResult{
+		TransactionID: transactionID,
+		Status:        models.StatusRejected,
+		RiskScore:     90,
+		MatchedRules:  []string{"rule-1"},
+	}
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockReplies := NewMockReplyWaiter(ctrl)
+	var correlationID string
+	mockReplies.EXPECT().Register(gomock.Any()).DoAndReturn(
+		func(id string) (<-chan models.TransactionResult, func()) {
+			correlationID = id
+			return replies, func() {}
+		})
+	mockQueue.EXPECT().Publish(gomock.Any(), models.LaneRealtime, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, lane models.QueueLane, payloads ...string) error {
+			assert.Contains(t, payloads[0], correlationID)
+			return nil
+		})
+	service := NewService(mockRepo, mockQueue, nil, nil, mockReplies, DecisionConfig{Timeout: time.Second, Fallback: models.ActionReview}, 0)
+
+	decision, err := service.Decide(context.Background(), event, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ActionBlock, decision.Decision)
+	assert.Equal(t, models.StatusRejected, decision.Status)
+	assert.Equal(t, "ext-123", decision.ExternalID)
+	assert.Equal(t, transactionID, *decision.TransactionID)
+	assert.Equal(t, 90, decision.RiskScore)
+	assert.False(t, decision.Fallback)
+	assert.NotContains(t, event, models.DecisionReplyToField, "caller's event must not be modified")
+}
+
+func Test_Service_Decide_WhenDeadlineExpires_ThenReturnsFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockReplies := NewMockReplyWaiter(ctrl)
+	mockReplies.EXPECT().Register(gomock.Any()).Return(make(chan models.TransactionResult), func() {})
+	mockQueue.EXPECT().Publish(gomock.Any(), models.LaneRealtime, gomock.Any()).Return(nil)
+	service := NewService(mockRepo, mockQueue, nil, nil, mockReplies, DecisionConfig{Timeout: time.Millisecond, Fallback: models.ActionReview}, 0)
+
+	decision, err := service.Decide(context.Background(), models.Event{"external_id": "ext-123"}, "")
+
+	require.NoError(t, err)
+	assert.True(t, decision.Fallback)
+	assert.Equal(t, models.ActionReview, decision.Decision)
+	assert.Equal(t, models.StatusInReview, decision.Status)
+	assert.Nil(t, decision.TransactionID)
+}
+
+func Test_Service_Decide_WhenQueueFails_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockReplies := NewMockReplyWaiter(ctrl)
+	mockReplies.EXPECT().Register(gomock.Any()).Return(make(chan models.TransactionResult), func() {})
+	mockQueue.EXPECT().Publish(gomock.Any(), models.LaneRealtime, gomock.Any()).Return(errors.New("queue error"))
+	service := NewService(mockRepo, mockQueue, nil, nil, mockReplies, DecisionConfig{Timeout: time.Second, Fallback: models.ActionReview}, 0)
+
+	_, err := service.Decide(context.Background(), models.Event{"external_id": "ext-123"}, "")
+
+	assert.Error(t, err)
+}
+
+func Test_Service_ProcessTransaction_WhenExternalIDIsNew_ThenClaimsItAndQueues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockKeys := NewMockIdempotencyStore(ctrl)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetTransactionByExternalID(gomock.Any(), "ext-123").Return(nil, pgx.ErrNoRows),
+		mockKeys.EXPECT().SetNX(gomock.Any(), "transaction:idempotency:ext-123", "ext-123", time.Hour).Return(redis.NewBoolResult(true, nil)),
+		mockQueue.EXPECT().Publish(gomock.Any(), models.LanePostTransaction, gomock.Any()).Return(nil),
+	)
+	service := NewService(mockRepo, mockQueue, mockKeys, nil, nil, DecisionConfig{}, time.Hour)
+
+	existing, err := service.ProcessTransaction(context.Background(), models.Event{"external_id": "ext-123"}, "", Route{})
+
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+}
+
+func Test_Service_ProcessTransaction_WhenTransactionAlreadySaved_ThenReturnsExistingDecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	saved := &models.Transaction{
+		ID:           uuid.New(),
+		ExternalID:   "ext-123",
+		Status:       models.StatusRejected,
+		RiskScore:    90,
+		MatchedRules: []string{"rule-1"},
+	}
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockKeys := NewMockIdempotencyStore(ctrl)
+	mockRepo.EXPECT().GetTransactionByExternalID(gomock.Any(), "ext-123").Return(saved, nil)
+	service := NewService(mockRepo, mockQueue, mockKeys, nil, nil, DecisionConfig{}, time.Hour)
+
+	existing, err := service.ProcessTransaction(context.Background(), models.Event{"external_id": "ext-123"}, "", Route{})
+
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, models.ActionBlock, existing.Decision)
+	assert.Equal(t, saved.ID, *existing.TransactionID)
+	assert.Equal(t, 90, existing.RiskScore)
+	assert.True(t, existing.Duplicate)
+}
+
+func Test_Service_ProcessTransaction_WhenKeyClaimedButNotSaved_ThenReturnsInProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockKeys := NewMockIdempotencyStore(ctrl)
+	mockRepo.EXPECT().GetTransactionByExternalID(gomock.Any(), "ext-123").Return(nil, pgx.ErrNoRows)
+	mockKeys.EXPECT().SetNX(gomock.Any(), "transaction:idempotency:ext-123", "ext-123", time.Hour).Return(redis.NewBoolResult(false, nil))
+	mockKeys.EXPECT().Get(gomock.Any(), "transaction:idempotency:ext-123").Return(redis.NewStringResult("ext-123", nil))
+	service := NewService(mockRepo, mockQueue, mockKeys, nil, nil, DecisionConfig{}, time.Hour)
+
+	existing, err := service.ProcessTransaction(context.Background(), models.Event{"external_id": "ext-123"}, "", Route{})
+
+	assert.ErrorIs(t, err, ErrRequestInProgress)
+	assert.Nil(t, existing)
+}
+
+func Test_Service_ProcessTransaction_WhenKeyUsedForAnotherSavedTransaction_ThenReturnsItsDecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	saved := &models.Transaction{ID: uuid.New(), ExternalID: "ext-first", Status: models.StatusApproved}
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockKeys := NewMockIdempotencyStore(ctrl)
+	mockRepo.EXPECT().GetTransactionByExternalID(gomock.Any(), "ext-second").Return(nil, pgx.ErrNoRows)
+	mockKeys.EXPECT().SetNX(gomock.Any(), "transaction:idempotency:key-1", "ext-second", time.Hour).Return(redis.NewBoolResult(false, nil))
+	mockKeys.EXPECT().Get(gomock.Any(), "transaction:idempotency:key-1").Return(redis.NewStringResult("ext-first", nil))
+	mockRepo.EXPECT().GetTransactionByExternalID(gomock.Any(), "ext-first").Return(saved, nil)
+	service := NewService(mockRepo, mockQueue, mockKeys, nil, nil, DecisionConfig{}, time.Hour)
+
+	existing, err := service.ProcessTransaction(context.Background(), models.Event{"external_id": "ext-second"}, "key-1", Route{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "ext-first", existing.ExternalID)
+	assert.Equal(t, models.ActionAllow, existing.Decision)
+}
+
+func Test_Service_ProcessTransaction_WhenEventHasNoExternalID_ThenQueuesItUnderIdempotencyKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	event := models.Event{"amount": 100.0}
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockKeys := NewMockIdempotencyStore(ctrl)
+	mockRepo.EXPECT().GetTransactionByExternalID(gomock.Any(), "key-1").Return(nil, pgx.ErrNoRows)
+	mockKeys.EXPECT().SetNX(gomock.Any(), "transaction:idempotency:key-1", "key-1", time.Hour).Return(redis.NewBoolResult(true, nil))
+	mockQueue.EXPECT().Publish(gomock.Any(), models.LanePostTransaction, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, lane models.QueueLane, payloads ...string) error {
+			assert.JSONEq(t, `{"amount": 100, "external_id": "key-1"}`, payloads[0])
+			return nil
+		})
+	service := NewService(mockRepo, mockQueue, mockKeys, nil, nil, DecisionConfig{}, time.Hour)
+
+	_, err := service.ProcessTransaction(context.Background(), event, "key-1", Route{})
+
+	require.NoError(t, err)
+	assert.NotContains(t, event, "external_id", "caller's event must not be modified")
+}
+
+func Test_Service_ProcessTransaction_WhenEventHasNoID_ThenQueuesItUnderNewExternalID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	event := models.Event{"amount": 100.0}
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockQueue.EXPECT().Publish(gomock.Any(), models.LanePostTransaction, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, lane models.QueueLane, payloads ...string) error {
+			var queued models.Event
+			require.NoError(t, json.Unmarshal([]byte(payloads[0]), &queued))
+			externalID, _ := queued["external_id"].(string)
+			assert.NoError(t, uuid.Validate(externalID))
+			return nil
+		})
+	service := NewService(mockRepo, mockQueue, nil, nil, nil, DecisionConfig{}, 0)
+
+	_, err := service.ProcessTransaction(context.Background(), event, "", Route{})
+
+	require.NoError(t, err)
+	assert.NotContains(t, event, "external_id", "caller's event must not be modified")
+}
+
+func Test_Service_ProcessTransaction_WhenQueueFails_ThenReleasesKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockKeys := NewMockIdempotencyStore(ctrl)
+	mockRepo.EXPECT().GetTransactionByExternalID(gomock.Any(), "ext-123").Return(nil, pgx.ErrNoRows)
+	mockKeys.EXPECT().SetNX(gomock.Any(), "transaction:idempotency:ext-123", "ext-123", time.Hour).Return(redis.NewBoolResult(true, nil))
+	mockQueue.EXPECT().Publish(gomock.Any(), models.LanePostTransaction, gomock.Any()).Return(errors.New("queue error"))
+	mockKeys.EXPECT().Del(gomock.Any(), "transaction:idempotency:ext-123").Return(redis.NewIntResult(1, nil))
+	service := NewService(mockRepo, mockQueue, mockKeys, nil, nil, DecisionConfig{}, time.Hour)
+
+	_, err := service.ProcessTransaction(context.Background(), models.Event{"external_id": "ext-123"}, "", Route{})
+
+	assert.Error(t, err)
+}
+
+func Test_Service_Decide_WhenTransactionAlreadySaved_ThenReturnsExistingDecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	saved := &models.Transaction{ID: uuid.New(), ExternalID: "ext-123", Status: models.StatusInReview}
+	mockRepo := NewMockRepository(ctrl)
+	mockQueue := NewMockQueuePublisher(ctrl)
+	mockKeys := NewMockIdempotencyStore(ctrl)
+	mockReplies := NewMockReplyWaiter(ctrl)
+	mockRepo.EXPECT().GetTransactionByExternalID(gomock.Any(), "ext-123").Return(saved, nil)
+	service := NewService(mockRepo, mockQueue, mockKeys, nil, mockReplies, DecisionConfig{Timeout: time.Second, Fallback: models.ActionAllow}, time.Hour)
+
+	decision, err := service.Decide(context.Background(), models.Event{"external_id": "ext-123"}, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ActionReview, decision.Decision)
+	assert.Equal(t, saved.ID, *decision.TransactionID)
+	assert.True(t, decision.Duplicate)
+	assert.False(t, decision.Fallback)
+}
+
+func Test_Service_ShadowRuleStats_WhenMatchesExist_ThenComputesMatchRates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().CountTransactions(gomock.Any(), from, to).Return(200, nil)
+	mockRepo.EXPECT().CountShadowMatches(gomock.Any(), from, to).Return([]ShadowRuleStat{
+		{RuleID: uuid.New(), RuleName: "New Velocity Rule", Matches: 50},
+		{RuleID: uuid.New(), RuleName: "New Geo Rule", Matches: 2},
+	}, nil)
+	service := NewService(mockRepo, nil, nil, nil, nil, DecisionConfig{}, 0)
+
+	stats, err := service.ShadowRuleStats(context.Background(), from, to)
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, stats.TotalTransactions)
+	require.Len(t, stats.Rules, 2)
+	assert.InDelta(t, 0.25, stats.Rules[0].MatchRate, 1e-9)
+	assert.InDelta(t, 0.01, stats.Rules[1].MatchRate, 1e-9)
+}
+
+func Test_Service_ShadowRuleStats_WhenCountFails_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockRepo.EXPECT().CountTransactions(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, errors.New("db error"))
+	service := NewService(mockRepo, nil, nil, nil, nil, DecisionConfig{}, 0)
+
+	_, err := service.ShadowRuleStats(context.Background(), time.Now().Add(-time.Hour), time.Now())
+
+	assert.Error(t, err)
+}
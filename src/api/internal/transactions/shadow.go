@@ -0,0 +1,26 @@
+package transactions
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultShadowStatsWindow is the time range covered when no range is requested
+const DefaultShadowStatsWindow = 24 * time.Hour
+
+// ShadowRuleStat is how often a shadow rule would have fired
+type ShadowRuleStat struct {
+	RuleID    uuid.UUID `json:"rule_id"`
+	RuleName  string    `json:"rule_name"` // Current name, or the last one if the rule was deleted
+	Matches   int       `json:"matches"`
+	MatchRate float64   `json:"match_rate"` // Matches divided by transactions in the range
+}
+
+// ShadowRuleStats summarizes shadow rule matches over a time range
+type ShadowRuleStats struct {
+	From              time.Time        `json:"from"`
+	To                time.Time        `json:"to"`
+	TotalTransactions int              `json:"total_transactions"`
+	Rules             []ShadowRuleStat `json:"rules"`
+}
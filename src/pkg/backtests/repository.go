@@ -0,0 +1,162 @@
+package backtests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// JobStore defines the interface for running backtest jobs (used by worker)
+// This interface follows Interface Segregation Principle - worker only updates job progress
+type JobStore interface {
+	// GetBacktest retrieves a backtest by ID
+	GetBacktest(ctx context.Context, id uuid.UUID) (*models.Backtest, error)
+	// StartBacktest marks a pending backtest as running
+	StartBacktest(ctx context.Context, id uuid.UUID) error
+	// CompleteBacktest stores the result of a finished backtest
+	CompleteBacktest(ctx context.Context, id uuid.UUID, result *models.BacktestResult) error
+	// FailBacktest records why a backtest could not finish
+	FailBacktest(ctx context.Context, id uuid.UUID, reason string) error
+}
+
+// Repository defines the interface for full backtest data access operations (used by API)
+type Repository interface {
+	JobStore
+	// CreateBacktest creates a new pending backtest
+	CreateBacktest(ctx context.Context, backtest *models.Backtest) error
+	// ListBacktests retrieves backtests, most recent first
+	ListBacktests(ctx context.Context, limit, offset int) ([]models.Backtest, error)
+}
+
+// PostgresRepository is the PostgreSQL implementation of Repository
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a new PostgreSQL backtest repository
+func NewPostgresRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const backtestColumns = `id, status, from_time, to_time, rules, replace_rule_set, sample_limit,
+		       result, error, created_at, started_at, completed_at`
+
+// CreateBacktest creates a new pending backtest
+func (r *PostgresRepository) CreateBacktest(ctx context.Context, backtest *models.Backtest) error {
+	rulesJSON, err := json.Marshal(backtest.Rules)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rules: %w", err)
+	}
+
+	query := `
+		INSERT INTO backtests (id, status, from_time, to_time, rules, replace_rule_set, sample_limit, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = r.db.Exec(ctx, query,
+		backtest.ID, backtest.Status, backtest.From, backtest.To, rulesJSON,
+		backtest.ReplaceRuleSet, backtest.SampleLimit, backtest.CreatedAt,
+	)
+	return err
+}
+
+// GetBacktest retrieves a backtest by ID
+func (r *PostgresRepository) GetBacktest(ctx context.Context, id uuid.UUID) (*models.Backtest, error) {
+	query := `SELECT ` + backtestColumns + ` FROM backtests WHERE id = $1`
+
+	return scanBacktest(r.db.QueryRow(ctx, query, id))
+}
+
+// ListBacktests retrieves backtests, most recent first
+func (r *PostgresRepository) ListBacktests(ctx context.Context, limit, offset int) ([]models.Backtest, error) {
+	query := `SELECT ` + backtestColumns + ` FROM backtests ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	backtests := make([]models.Backtest, 0)
+	for rows.Next() {
+		backtest, err := scanBacktest(rows)
+		if err != nil {
+			return nil, err
+		}
+		backtests = append(backtests, *backtest)
+	}
+
+	return backtests, rows.Err()
+}
+
+// StartBacktest marks a pending backtest as running
+// Returns pgx.ErrNoRows if the backtest is not pending, so a job is never run twice
+func (r *PostgresRepository) StartBacktest(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE backtests SET status = $2, started_at = $3 WHERE id = $1 AND status = $4`
+
+	tag, err := r.db.Exec(ctx, query, id, models.BacktestRunning, time.Now(), models.BacktestPending)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// CompleteBacktest stores the result of a finished backtest
+func (r *PostgresRepository) CompleteBacktest(ctx context.Context, id uuid.UUID, result *models.BacktestResult) error {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	query := `UPDATE backtests SET status = $2, result = $3, completed_at = $4 WHERE id = $1`
+
+	_, err = r.db.Exec(ctx, query, id, models.BacktestCompleted, resultJSON, time.Now())
+	return err
+}
+
+// FailBacktest records why a backtest could not finish
+func (r *PostgresRepository) FailBacktest(ctx context.Context, id uuid.UUID, reason string) error {
+	query := `UPDATE backtests SET status = $2, error = $3, completed_at = $4 WHERE id = $1`
+
+	_, err := r.db.Exec(ctx, query, id, models.BacktestFailed, reason, time.Now())
+	return err
+}
+
+// scanBacktest scans a single backtest row
+func scanBacktest(row pgx.Row) (*models.Backtest, error) {
+	var backtest models.Backtest
+	var rulesJSON, resultJSON []byte
+	var errMsg *string
+
+	err := row.Scan(
+		&backtest.ID, &backtest.Status, &backtest.From, &backtest.To, &rulesJSON,
+		&backtest.ReplaceRuleSet, &backtest.SampleLimit, &resultJSON, &errMsg,
+		&backtest.CreatedAt, &backtest.StartedAt, &backtest.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(rulesJSON, &backtest.Rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
+	}
+	if len(resultJSON) > 0 {
+		if err := json.Unmarshal(resultJSON, &backtest.Result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal result: %w", err)
+		}
+	}
+	if errMsg != nil {
+		backtest.Error = *errMsg
+	}
+
+	return &backtest, nil
+}
@@ -8,16 +8,18 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Redis    RedisConfig
-	API      APIConfig
-	Worker   WorkerConfig
-	General  GeneralConfig
-	Auth     AuthConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	API       APIConfig
+	Worker    WorkerConfig
+	General   GeneralConfig
+	Auth      AuthConfig
+	Sanctions SanctionsConfig
 }
 
 type DatabaseConfig struct {
@@ -34,11 +36,19 @@ type RedisConfig struct {
 }
 
 type APIConfig struct {
-	Host      string
-	Port      int
-	TLSEnable bool
-	TLSCert   string // Path to TLS certificate file
-	TLSKey    string // Path to TLS private key file
+	Host           string
+	Port           int
+	TLSEnable      bool
+	TLSCert        string // Path to TLS certificate file
+	TLSKey         string // Path to TLS private key file
+	Decision       DecisionEndpointConfig
+	IdempotencyTTL time.Duration // How long a request's idempotency key is held while its transaction is queued
+}
+
+// DecisionEndpointConfig configures the synchronous pre-transaction decision endpoint
+type DecisionEndpointConfig struct {
+	Timeout  time.Duration // Hard deadline for waiting on the worker's decision
+	Fallback string        // Decision returned when the deadline expires: allow, review or block
 }
 
 type WorkerConfig struct {
@@ -48,11 +58,19 @@ type WorkerConfig struct {
 	Retry       RetryConfig
 	Queue       QueueConfig
 	RulesReload RulesReloadConfig
+	Scoring     ScoringConfig
+	Decision    DecisionConfig
+	RuleHealth  RuleHealthConfig
+	Velocity    VelocityConfig
+	IPIntel     IPIntelConfig
+	Timeline    TimelineConfig
 }
 
 type WorkerTimeouts struct {
 	TransactionProcessing time.Duration
-	RuleEvaluation        time.Duration
+	RuleEvaluation        time.Duration // Default timeout of a single rule, rules may override it
+	Evaluation            time.Duration // Timeout of evaluating all rules for one event, 0 or less disables it
+	Fallback              string        // Decision applied when evaluation times out: allow (fail-open), review or block
 }
 
 type RetryConfig struct {
@@ -63,13 +81,71 @@ type RetryConfig struct {
 }
 
 type QueueConfig struct {
-	PopTimeout time.Duration
+	PopTimeout        time.Duration
+	HeartbeatInterval time.Duration // How often a worker refreshes its heartbeat and recovers stopped workers' events
+	ConsumerTimeout   time.Duration // Missing heartbeat after which a worker's in-flight events are requeued
+	Lanes             LanesConfig
+	Transport         string // redis_list, redis_streams or nats
+	NATS              NATSConfig
+}
+
+// NATSConfig defines the JetStream stream consumed by the nats transport
+type NATSConfig struct {
+	URL    string
+	Stream string // Created with a subject per lane when it does not exist
+}
+
+// LanesConfig defines the order in which workers drain the queue lanes
+type LanesConfig struct {
+	Strict  []models.QueueLane       // Lanes drained in order before any weighted lane
+	Weights map[models.QueueLane]int // Share of pops each other lane gets while several hold events
 }
 
 type RulesReloadConfig struct {
 	Interval time.Duration
 }
 
+// ScoringConfig defines the risk score bands mapped to decisions
+// A threshold of 0 or less disables that band
+type ScoringConfig struct {
+	ReviewThreshold int
+	BlockThreshold  int
+}
+
+// DecisionConfig defines how conflicting rule matches are resolved
+type DecisionConfig struct {
+	ConflictPolicy string // first_match, most_severe or highest_priority
+}
+
+// RuleHealthConfig defines how rule evaluation errors are persisted and acted on
+type RuleHealthConfig struct {
+	FlushInterval    time.Duration // How often per-rule counters are written to the database
+	FailureThreshold int           // Consecutive failures before a rule is flagged, 0 or less disables flagging
+	AutoDisable      bool          // Disable flagged rules instead of only flagging them
+}
+
+// IPIntelConfig defines the local IP database behind the ipCountry and ipASN helpers
+type IPIntelConfig struct {
+	DatabasePath string // CSV of network, country_code, asn and organization; empty disables the helpers
+}
+
+// VelocityConfig defines where velocity helpers read transaction history from
+type VelocityConfig struct {
+	Backend   string        // redis (sliding-window sorted sets, Postgres fallback) or postgres
+	Retention time.Duration // History kept in Redis; longer windows are answered by Postgres
+}
+
+// SanctionsConfig configures sanctions screening, shared by the screening endpoint and the sanctionsHit helper
+type SanctionsConfig struct {
+	MatchThreshold float64 // Minimum name similarity, from 0 to 1, for a screened name to match an entry
+}
+
+// TimelineConfig defines the per-entity event timelines behind the sequence helpers
+type TimelineConfig struct {
+	Retention time.Duration // How long events are kept, bounding sequence windows; 0 or less disables timelines
+	MaxEvents int           // Most recent events kept per entity, 0 or less keeps every retained event
+}
+
 type GeneralConfig struct {
 	Environment string
 	LogLevel    string
@@ -127,6 +203,11 @@ func Load() (*Config, error) {
 			TLSEnable: getEnv("TLS_ENABLE", "") == "true",
 			TLSCert:   getEnv("TLS_CERT_PATH", ""),
 			TLSKey:    getEnv("TLS_KEY_PATH", ""),
+			Decision: DecisionEndpointConfig{
+				Timeout:  getEnvDuration("API_DECISION_TIMEOUT", 250*time.Millisecond),
+				Fallback: getEnv("API_DECISION_FALLBACK", "review"),
+			},
+			IdempotencyTTL: getEnvDuration("API_IDEMPOTENCY_TTL", 24*time.Hour),
 		},
 		Worker: WorkerConfig{
 			Concurrency: getEnvInt("WORKER_CONCURRENCY", 10),
@@ -134,6 +215,8 @@ func Load() (*Config, error) {
 			Timeouts: WorkerTimeouts{
 				TransactionProcessing: getEnvDuration("WORKER_TIMEOUT_TRANSACTION_PROCESSING", 300*time.Millisecond),
 				RuleEvaluation:        getEnvDuration("WORKER_TIMEOUT_RULE_EVALUATION", 300*time.Millisecond),
+				Evaluation:            getEnvDuration("WORKER_TIMEOUT_EVALUATION", 0),
+				Fallback:              getEnv("WORKER_TIMEOUT_FALLBACK", "allow"),
 			},
 			Retry: RetryConfig{
 				MaxAttempts:  getEnvInt("WORKER_RETRY_MAX_ATTEMPTS", 3),
@@ -142,11 +225,41 @@ func Load() (*Config, error) {
 				Multiplier:   getEnvFloat("WORKER_RETRY_MULTIPLIER", 2.0),
 			},
 			Queue: QueueConfig{
-				PopTimeout: getEnvDuration("WORKER_QUEUE_POP_TIMEOUT", 1*time.Second),
+				PopTimeout:        getEnvDuration("WORKER_QUEUE_POP_TIMEOUT", 1*time.Second),
+				HeartbeatInterval: getEnvDuration("WORKER_QUEUE_HEARTBEAT_INTERVAL", 5*time.Second),
+				ConsumerTimeout:   getEnvDuration("WORKER_QUEUE_CONSUMER_TIMEOUT", 30*time.Second),
+				Transport:         getEnv("WORKER_QUEUE_TRANSPORT", "redis_list"),
+				NATS: NATSConfig{
+					URL:    getEnv("WORKER_QUEUE_NATS_URL", "nats://localhost:4222"),
+					Stream: getEnv("WORKER_QUEUE_NATS_STREAM", "ALGO_SHIELD_TRANSACTIONS"),
+				},
 			},
 			RulesReload: RulesReloadConfig{
 				Interval: getEnvDuration("WORKER_RULES_RELOAD_INTERVAL", 10*time.Second),
 			},
+			Scoring: ScoringConfig{
+				ReviewThreshold: getEnvInt("WORKER_SCORE_REVIEW_THRESHOLD", 50),
+				BlockThreshold:  getEnvInt("WORKER_SCORE_BLOCK_THRESHOLD", 80),
+			},
+			Decision: DecisionConfig{
+				ConflictPolicy: getEnv("WORKER_CONFLICT_POLICY", "most_severe"),
+			},
+			Velocity: VelocityConfig{
+				Backend:   getEnv("WORKER_VELOCITY_BACKEND", "redis"),
+				Retention: getEnvDuration("WORKER_VELOCITY_RETENTION", 24*time.Hour),
+			},
+			IPIntel: IPIntelConfig{
+				DatabasePath: getEnv("WORKER_IP_DATABASE_PATH", ""),
+			},
+			Timeline: TimelineConfig{
+				Retention: getEnvDuration("WORKER_TIMELINE_RETENTION", 24*time.Hour),
+				MaxEvents: getEnvInt("WORKER_TIMELINE_MAX_EVENTS", 1000),
+			},
+			RuleHealth: RuleHealthConfig{
+				FlushInterval:    getEnvDuration("WORKER_RULE_HEALTH_FLUSH_INTERVAL", 10*time.Second),
+				FailureThreshold: getEnvInt("WORKER_RULE_HEALTH_FAILURE_THRESHOLD", 100),
+				AutoDisable:      getEnv("WORKER_RULE_HEALTH_AUTO_DISABLE", "") == "true",
+			},
 		},
 		General: GeneralConfig{
 			Environment: environment,
@@ -156,6 +269,49 @@ func Load() (*Config, error) {
 			JWTSecret:          jwtSecret,
 			JWTExpirationHours: getEnvInt("JWT_EXPIRATION_HOURS", 24),
 		},
+		Sanctions: SanctionsConfig{
+			MatchThreshold: getEnvFloat("SANCTIONS_MATCH_THRESHOLD", 0.9),
+		},
+	}
+
+	// Validate decision fallback
+	switch config.API.Decision.Fallback {
+	case "allow", "review", "block":
+	default:
+		return nil, fmt.Errorf("API_DECISION_FALLBACK must be one of allow, review or block")
+	}
+
+	// Validate evaluation timeout fallback
+	switch config.Worker.Timeouts.Fallback {
+	case "allow", "review", "block":
+	default:
+		return nil, fmt.Errorf("WORKER_TIMEOUT_FALLBACK must be one of allow, review or block")
+	}
+
+	// Parse queue lanes
+	lanes, err := parseLanes(getEnv("WORKER_QUEUE_LANES", "realtime=strict,post_transaction=4,backfill=1"))
+	if err != nil {
+		return nil, fmt.Errorf("WORKER_QUEUE_LANES: %w", err)
+	}
+	config.Worker.Queue.Lanes = lanes
+
+	// Validate queue transport
+	switch config.Worker.Queue.Transport {
+	case "redis_list", "redis_streams", "nats":
+	default:
+		return nil, fmt.Errorf("WORKER_QUEUE_TRANSPORT must be one of redis_list, redis_streams or nats")
+	}
+
+	// Validate velocity backend
+	switch config.Worker.Velocity.Backend {
+	case "redis", "postgres":
+	default:
+		return nil, fmt.Errorf("WORKER_VELOCITY_BACKEND must be one of redis or postgres")
+	}
+
+	// Validate sanctions match threshold
+	if config.Sanctions.MatchThreshold <= 0 || config.Sanctions.MatchThreshold > 1 {
+		return nil, fmt.Errorf("SANCTIONS_MATCH_THRESHOLD must be greater than 0 and at most 1")
 	}
 
 	// Validate TLS configuration
@@ -233,6 +389,50 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+// parseLanes parses a comma-separated list of lane=weight pairs, where the weight
+// "strict" drains the lane ahead of the weighted ones, in the order listed
+// Unlisted lanes get a weight of 1
+func parseLanes(value string) (LanesConfig, error) {
+	lanes := LanesConfig{Weights: make(map[models.QueueLane]int)}
+	seen := make(map[models.QueueLane]bool)
+
+	for _, pair := range strings.Split(value, ",") {
+		name, weight, ok := strings.Cut(pair, "=")
+		if !ok {
+			return LanesConfig{}, fmt.Errorf("expected lane=weight, got %q", strings.TrimSpace(pair))
+		}
+
+		lane, err := models.ParseQueueLane(name)
+		if err != nil {
+			return LanesConfig{}, err
+		}
+		if seen[lane] {
+			return LanesConfig{}, fmt.Errorf("lane %s is listed twice", lane)
+		}
+		seen[lane] = true
+
+		weight = strings.TrimSpace(weight)
+		if weight == "strict" {
+			lanes.Strict = append(lanes.Strict, lane)
+			continue
+		}
+
+		w, err := strconv.Atoi(weight)
+		if err != nil || w < 1 {
+			return LanesConfig{}, fmt.Errorf("weight of lane %s must be strict or a positive integer", lane)
+		}
+		lanes.Weights[lane] = w
+	}
+
+	for _, lane := range models.QueueLanes {
+		if !seen[lane] {
+			lanes.Weights[lane] = 1
+		}
+	}
+
+	return lanes, nil
+}
+
 // validateSecretStrength validates the strength of a secret
 // - minLength: minimum required length
 // - isProduction: if true, enforces stricter rules
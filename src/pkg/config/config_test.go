@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
 )
 
 func TestLoad(t *testing.T) {
@@ -31,6 +33,15 @@ func TestLoad(t *testing.T) {
 		t.Errorf("Expected API port 9090, got %d", cfg.API.Port)
 	}
 
+	// The overall evaluation deadline is opt-in and timeouts fail open unless configured otherwise
+	if cfg.Worker.Timeouts.Evaluation != 0 {
+		t.Errorf("Expected evaluation timeout disabled by default, got %v", cfg.Worker.Timeouts.Evaluation)
+	}
+
+	if cfg.Worker.Timeouts.Fallback != "allow" {
+		t.Errorf("Expected timeout fallback 'allow', got '%s'", cfg.Worker.Timeouts.Fallback)
+	}
+
 	// Clean up
 	_ = os.Unsetenv("POSTGRES_HOST")
 	_ = os.Unsetenv("POSTGRES_PORT")
@@ -122,3 +133,111 @@ func TestLoad_ProductionRequiresTLS(t *testing.T) {
 	_ = os.Unsetenv("TLS_CERT_PATH")
 	_ = os.Unsetenv("TLS_KEY_PATH")
 }
+
+func TestLoad_InvalidDecisionFallback(t *testing.T) {
+	_ = os.Setenv("JWT_SECRET", "test-jwt-secret-key-minimum-32-characters-long-for-validation")
+	_ = os.Setenv("POSTGRES_PASSWORD", "test-db-password-minimum-16-chars")
+	_ = os.Setenv("API_DECISION_FALLBACK", "approve")
+
+	_, err := Load()
+	if err == nil {
+		t.Error("Expected error when API_DECISION_FALLBACK is invalid, but got none")
+	}
+
+	// Clean up
+	_ = os.Unsetenv("JWT_SECRET")
+	_ = os.Unsetenv("POSTGRES_PASSWORD")
+	_ = os.Unsetenv("API_DECISION_FALLBACK")
+}
+
+func TestLoad_InvalidTimeoutFallback(t *testing.T) {
+	_ = os.Setenv("JWT_SECRET", "test-jwt-secret-key-minimum-32-characters-long-for-validation")
+	_ = os.Setenv("POSTGRES_PASSWORD", "test-db-password-minimum-16-chars")
+	_ = os.Setenv("WORKER_TIMEOUT_FALLBACK", "closed")
+
+	_, err := Load()
+	if err == nil {
+		t.Error("Expected error when WORKER_TIMEOUT_FALLBACK is invalid, but got none")
+	}
+
+	// Clean up
+	_ = os.Unsetenv("JWT_SECRET")
+	_ = os.Unsetenv("POSTGRES_PASSWORD")
+	_ = os.Unsetenv("WORKER_TIMEOUT_FALLBACK")
+}
+
+func TestLoad_InvalidSanctionsMatchThreshold(t *testing.T) {
+	_ = os.Setenv("JWT_SECRET", "test-jwt-secret-key-minimum-32-characters-long-for-validation")
+	_ = os.Setenv("POSTGRES_PASSWORD", "test-db-password-minimum-16-chars")
+	_ = os.Setenv("SANCTIONS_MATCH_THRESHOLD", "1.5")
+
+	_, err := Load()
+	if err == nil {
+		t.Error("Expected error when SANCTIONS_MATCH_THRESHOLD is above 1, but got none")
+	}
+
+	// Clean up
+	_ = os.Unsetenv("JWT_SECRET")
+	_ = os.Unsetenv("POSTGRES_PASSWORD")
+	_ = os.Unsetenv("SANCTIONS_MATCH_THRESHOLD")
+}
+
+func TestLoad_InvalidQueueLanes(t *testing.T) {
+	_ = os.Setenv("JWT_SECRET", "test-jwt-secret-key-minimum-32-characters-long-for-validation")
+	_ = os.Setenv("POSTGRES_PASSWORD", "test-db-password-minimum-16-chars")
+	_ = os.Setenv("WORKER_QUEUE_LANES", "realtime=strict,bulk=1")
+
+	_, err := Load()
+	if err == nil {
+		t.Error("Expected error when WORKER_QUEUE_LANES names an unknown lane, but got none")
+	}
+
+	// Clean up
+	_ = os.Unsetenv("JWT_SECRET")
+	_ = os.Unsetenv("POSTGRES_PASSWORD")
+	_ = os.Unsetenv("WORKER_QUEUE_LANES")
+}
+
+func TestLoad_InvalidQueueTransport(t *testing.T) {
+	_ = os.Setenv("JWT_SECRET", "test-jwt-secret-key-minimum-32-characters-long-for-validation")
+	_ = os.Setenv("POSTGRES_PASSWORD", "test-db-password-minimum-16-chars")
+	_ = os.Setenv("WORKER_QUEUE_TRANSPORT", "kafka")
+
+	_, err := Load()
+	if err == nil {
+		t.Error("Expected error when WORKER_QUEUE_TRANSPORT is unknown, but got none")
+	}
+
+	// Clean up
+	_ = os.Unsetenv("JWT_SECRET")
+	_ = os.Unsetenv("POSTGRES_PASSWORD")
+	_ = os.Unsetenv("WORKER_QUEUE_TRANSPORT")
+}
+
+func TestParseLanes(t *testing.T) {
+	lanes, err := parseLanes("backfill=strict, realtime=strict,post_transaction=3")
+	if err != nil {
+		t.Fatalf("Failed to parse lanes: %v", err)
+	}
+
+	if len(lanes.Strict) != 2 || lanes.Strict[0] != models.LaneBackfill || lanes.Strict[1] != models.LaneRealtime {
+		t.Errorf("Expected strict lanes [backfill realtime], got %v", lanes.Strict)
+	}
+	if lanes.Weights[models.LanePostTransaction] != 3 || len(lanes.Weights) != 1 {
+		t.Errorf("Expected post_transaction weight 3 only, got %v", lanes.Weights)
+	}
+
+	lanes, err = parseLanes("backfill=2")
+	if err != nil {
+		t.Fatalf("Failed to parse lanes: %v", err)
+	}
+	if lanes.Weights[models.LaneRealtime] != 1 || lanes.Weights[models.LanePostTransaction] != 1 {
+		t.Errorf("Expected unlisted lanes to get weight 1, got %v", lanes.Weights)
+	}
+
+	for _, invalid := range []string{"backfill", "backfill=0", "backfill=fast", "backfill=1,backfill=2"} {
+		if _, err := parseLanes(invalid); err == nil {
+			t.Errorf("Expected error parsing %q, but got none", invalid)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Package deadletters stores the queued events workers gave up on, for inspection and replay.
+package deadletters
+
+import (
+	"context"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Writer defines the interface for recording dead letters (used by worker)
+type Writer interface {
+	AddDeadLetter(ctx context.Context, letter *models.DeadLetter) error
+}
+
+// Repository defines the interface for managing dead letters (used by API)
+type Repository interface {
+	Writer
+	// GetDeadLetter returns pgx.ErrNoRows when the dead letter does not exist
+	GetDeadLetter(ctx context.Context, id uuid.UUID) (*models.DeadLetter, error)
+	// ListDeadLetters retrieves the dead letters matching the filter, most recent first
+	ListDeadLetters(ctx context.Context, filter models.DeadLetterFilter, limit, offset int) ([]models.DeadLetter, error)
+	// DeleteDeadLetter returns pgx.ErrNoRows when the dead letter does not exist
+	DeleteDeadLetter(ctx context.Context, id uuid.UUID) error
+	// DeleteDeadLetters removes dead letters by ID and returns how many existed
+	DeleteDeadLetters(ctx context.Context, ids []uuid.UUID) (int64, error)
+	// PurgeDeadLetters removes the dead letters matching the filter and returns how many
+	PurgeDeadLetters(ctx context.Context, filter models.DeadLetterFilter) (int64, error)
+}
+
+// PostgresRepository is the PostgreSQL implementation of Repository
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresRepository creates a new PostgreSQL dead letter repository
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+const deadLetterColumns = `id, queue, external_id, payload, reason, error, attempts, received_at, failed_at`
+
+// filterCondition matches rows against a filter passed as the parameters $1 to $4
+// Empty values disable their condition
+const filterCondition = `
+	($1 = '' OR reason = $1)
+	AND ($2 = '' OR external_id = $2)
+	AND ($3::timestamptz IS NULL OR failed_at >= $3)
+	AND ($4::timestamptz IS NULL OR failed_at < $4)
+`
+
+// filterArgs returns the parameters of filterCondition
+func filterArgs(filter models.DeadLetterFilter) []any {
+	return []any{string(filter.Reason), filter.ExternalID, filter.From, filter.To}
+}
+
+func (r *PostgresRepository) AddDeadLetter(ctx context.Context, letter *models.DeadLetter) error {
+	if letter.ID == uuid.Nil {
+		letter.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO dead_letters (` + deadLetterColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	var externalID *string
+	if letter.ExternalID != "" {
+		externalID = &letter.ExternalID
+	}
+
+	_, err := r.db.Exec(ctx, query,
+		letter.ID, letter.Queue, externalID, letter.Payload, letter.Reason,
+		letter.Error, letter.Attempts, letter.ReceivedAt, letter.FailedAt,
+	)
+	return err
+}
+
+func (r *PostgresRepository) GetDeadLetter(ctx context.Context, id uuid.UUID) (*models.DeadLetter, error) {
+	query := `SELECT ` + deadLetterColumns + ` FROM dead_letters WHERE id = $1`
+
+	return scanDeadLetter(r.db.QueryRow(ctx, query, id))
+}
+
+func (r *PostgresRepository) ListDeadLetters(ctx context.Context, filter models.DeadLetterFilter, limit, offset int) ([]models.DeadLetter, error) {
+	query := `
+		SELECT ` + deadLetterColumns + `
+		FROM dead_letters
+		WHERE ` + filterCondition + `
+		ORDER BY failed_at DESC, id
+		LIMIT $5 OFFSET $6
+	`
+
+	rows, err := r.db.Query(ctx, query, append(filterArgs(filter), limit, offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	letters := make([]models.DeadLetter, 0)
+	for rows.Next() {
+		letter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, *letter)
+	}
+
+	return letters, rows.Err()
+}
+
+func (r *PostgresRepository) DeleteDeadLetter(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM dead_letters WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *PostgresRepository) DeleteDeadLetters(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM dead_letters WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+func (r *PostgresRepository) PurgeDeadLetters(ctx context.Context, filter models.DeadLetterFilter) (int64, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM dead_letters WHERE `+filterCondition, filterArgs(filter)...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// scanDeadLetter scans a dead_letters row
+func scanDeadLetter(row pgx.Row) (*models.DeadLetter, error) {
+	var letter models.DeadLetter
+	var externalID *string
+
+	err := row.Scan(
+		&letter.ID, &letter.Queue, &externalID, &letter.Payload, &letter.Reason,
+		&letter.Error, &letter.Attempts, &letter.ReceivedAt, &letter.FailedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if externalID != nil {
+		letter.ExternalID = *externalID
+	}
+	return &letter, nil
+}
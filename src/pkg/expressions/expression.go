@@ -0,0 +1,462 @@
+// Package expressions compiles and runs rule expressions against schema-typed
+// environments. It is shared by the API, which validates and dry-runs rules,
+// and the worker, which evaluates them on live traffic.
+package expressions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/file"
+	"github.com/expr-lang/expr/parser"
+	"github.com/expr-lang/expr/vm"
+)
+
+// ErrEmptyExpression is returned when compiling an empty expression
+var ErrEmptyExpression = errors.New("expression is empty")
+
+// amountAggregateHelpers maps the field-keyed amount helpers to their aggregate
+var amountAggregateHelpers = map[string]models.AmountAggregate{
+	"velocitySumBy": models.AggregateSum,
+	"velocityMinBy": models.AggregateMin,
+	"velocityMaxBy": models.AggregateMax,
+	"velocityAvgBy": models.AggregateAvg,
+}
+
+// timeDependentHelpers are the helpers answering from history up to the present, not up to
+// the event being evaluated, so replaying past events through them reflects today's history
+var timeDependentHelpers = map[string]bool{
+	"velocityCount":    true,
+	"velocitySum":      true,
+	"velocityCountBy":  true,
+	"velocityDistinct": true,
+	"velocitySumBy":    true,
+	"velocityMinBy":    true,
+	"velocityMaxBy":    true,
+	"velocityAvgBy":    true,
+	"avgAmount":        true,
+	"stddevAmount":     true,
+	"zscore":           true,
+	"firstSeen":        true,
+	"impossibleTravel": true,
+	"sequence":         true,
+	"followedBy":       true,
+}
+
+// TimeDependentHelpers returns the sorted names of the time-dependent helpers a compiled program calls
+// Macros are inlined at compile time, so helpers called through macros are included
+func TimeDependentHelpers(program *vm.Program) []string {
+	if program == nil {
+		return nil
+	}
+
+	collector := &helperCollector{seen: make(map[string]bool)}
+	node := program.Node()
+	ast.Walk(&node, collector)
+
+	names := make([]string, 0, len(collector.seen))
+	for name := range collector.seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// helperCollector records the time-dependent helpers called in an expression
+type helperCollector struct {
+	seen map[string]bool
+}
+
+func (v *helperCollector) Visit(node *ast.Node) {
+	call, ok := (*node).(*ast.CallNode)
+	if !ok {
+		return
+	}
+	if ident, ok := call.Callee.(*ast.IdentifierNode); ok && timeDependentHelpers[ident.Value] {
+		v.seen[ident.Value] = true
+	}
+}
+
+// HistoryRepository provides the transaction history used by velocity helpers
+type HistoryRepository interface {
+	CountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (int, error)
+	SumAmountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (float64, error)
+	CountByFieldInTimeWindow(ctx context.Context, path string, value any, timeWindowSeconds int) (int, error)
+	AggregateAmountByFieldInTimeWindow(ctx context.Context, path string, value any, aggregate models.AmountAggregate, timeWindowSeconds int) (float64, error)
+	CountDistinctByFieldInTimeWindow(ctx context.Context, distinctPath, path string, value any, timeWindowSeconds int) (int, error)
+	AmountStatsByAccount(ctx context.Context, account string, days int) (models.AmountStats, error)
+	FirstSeenByAccount(ctx context.Context, account string) (*time.Time, error)
+	LastLocationByAccount(ctx context.Context, account string) (*models.AccountLocation, error)
+}
+
+// CountryRiskLookup provides the country risk scores used by the countryRisk helper
+type CountryRiskLookup interface {
+	CountryRiskScore(ctx context.Context, code string) (int, error)
+}
+
+// ListLookup provides the managed lists used by the inList helper
+type ListLookup interface {
+	// Contains reports whether the named list has the value, unknown lists contain nothing
+	Contains(ctx context.Context, listName, value string) (bool, error)
+	// ContainsIP reports whether an IP list has the address or a network including it
+	ContainsIP(ctx context.Context, listName, ip string) (bool, error)
+	// FuzzyContains reports whether the named list has an entry whose fuzzy.NameSimilarity
+	// to the value is at least threshold
+	FuzzyContains(ctx context.Context, listName, value string, threshold float64) (bool, error)
+}
+
+// IPLookup provides the local IP database used by ipCountry and ipASN
+type IPLookup interface {
+	LookupIP(ip string) (models.IPInfo, bool)
+}
+
+// SanctionsLookup provides the sanctions screening used by the sanctionsHit helper
+type SanctionsLookup interface {
+	// SanctionsHit reports whether a name matches a sanctioned entry listing the country or no country
+	SanctionsHit(ctx context.Context, name, country string) (bool, error)
+}
+
+// TimelineLookup provides the per-entity event timelines used by sequence helpers
+type TimelineLookup interface {
+	// Sequence reports whether an entity had events of the given types, in order, within the window ending now
+	Sequence(ctx context.Context, entity string, types []string, within time.Duration) (bool, error)
+}
+
+// Lookups provides the data queried by helper functions.
+// Helpers whose lookup is nil return zero values, as when compiling or dry-running rules.
+type Lookups struct {
+	History     HistoryRepository
+	CountryRisk CountryRiskLookup
+	Lists       ListLookup
+	IPs         IPLookup
+	Sanctions   SanctionsLookup
+	Timeline    TimelineLookup
+}
+
+// CompileError describes where an expression failed to compile
+type CompileError struct {
+	Message string `json:"message"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// DescribeCompileError extracts the position of a compile error.
+// Errors without position information are reported at line 0, column 0.
+func DescribeCompileError(err error) CompileError {
+	var fileErr *file.Error
+	if errors.As(err, &fileErr) {
+		return CompileError{
+			Message: fileErr.Message,
+			Line:    fileErr.Line,
+			Column:  fileErr.Column,
+			Snippet: fileErr.Snippet,
+		}
+	}
+	return CompileError{Message: err.Error()}
+}
+
+// BuildEnv builds a dynamic expression environment from event JSON
+// using the schema's extracted fields as the structure.
+// Nested paths such as "user.country" are placed in nested maps so expressions
+// can use dot notation. Returns a map[string]any that can be used with expr-lang.
+func BuildEnv(ctx context.Context, eventData map[string]any, fields []models.ExtractedField, lookups Lookups) map[string]any {
+	env := make(map[string]any)
+	if eventData == nil {
+		return env
+	}
+
+	// For each field in the schema, extract the value from the event data
+	for _, field := range fields {
+		value := extractValueByPath(eventData, field.Path)
+		setValueByPath(env, field.Path, value)
+	}
+
+	addHelperFunctions(ctx, env, eventData, lookups)
+
+	return env
+}
+
+// BuildCompileEnv builds a typed expression environment from the schema's extracted fields.
+// Every field is set to the zero value of its inferred type so expr can type-check
+// expressions at compile time. Helper functions are registered with their real signatures.
+func BuildCompileEnv(fields []models.ExtractedField) map[string]any {
+	env := make(map[string]any)
+
+	for _, field := range fields {
+		setValueByPath(env, field.Path, zeroValueForType(field.Type))
+	}
+
+	// Helpers are never invoked at compile time, only their signatures matter
+	addHelperFunctions(context.Background(), env, nil, Lookups{})
+
+	return env
+}
+
+// addHelperFunctions registers the helper functions available to expressions
+func addHelperFunctions(ctx context.Context, env map[string]any, eventData map[string]any, lookups Lookups) {
+	addVelocityHelpers(ctx, env, eventData, lookups.History)
+	addGeoHelpers(ctx, env, lookups)
+	addListHelpers(ctx, env, lookups.Lists)
+	addIPHelpers(ctx, env, lookups)
+	addStringHelpers(env)
+	addSanctionsHelpers(ctx, env, lookups.Sanctions)
+	addTimelineHelpers(ctx, env, lookups.Timeline)
+}
+
+// addVelocityHelpers registers the helpers querying transaction history.
+// When historyRepo is nil, they are registered as stubs returning zero.
+func addVelocityHelpers(ctx context.Context, env map[string]any, eventData map[string]any, historyRepo HistoryRepository) {
+	if historyRepo == nil {
+		env["velocityCount"] = func(account string, timeWindowSeconds int) int {
+			return 0
+		}
+		env["velocitySum"] = func(account string, timeWindowSeconds int) float64 {
+			return 0.0
+		}
+		env["velocityCountBy"] = func(path string, value any, timeWindowSeconds int) int {
+			return 0
+		}
+		env["velocityDistinct"] = func(distinctPath, keyPath string, timeWindowSeconds int) int {
+			return 0
+		}
+		for name := range amountAggregateHelpers {
+			env[name] = func(path string, value any, timeWindowSeconds int) float64 {
+				return 0.0
+			}
+		}
+		env["avgAmount"] = func(account string, days int) float64 {
+			return 0.0
+		}
+		env["stddevAmount"] = func(account string, days int) float64 {
+			return 0.0
+		}
+		env["zscore"] = func(account string, amount float64, days int) float64 {
+			return 0.0
+		}
+		// Without history an account's age is unknown, not new
+		env["firstSeen"] = func(account string) time.Time {
+			return time.Time{}
+		}
+		return
+	}
+
+	env["velocityCount"] = func(account string, timeWindowSeconds int) int {
+		count, err := historyRepo.CountByAccountInTimeWindow(ctx, account, timeWindowSeconds)
+		if err != nil {
+			log.Printf("Velocity count error: %v", err)
+			return 0
+		}
+		return count
+	}
+
+	env["velocitySum"] = func(account string, timeWindowSeconds int) float64 {
+		sum, err := historyRepo.SumAmountByAccountInTimeWindow(ctx, account, timeWindowSeconds)
+		if err != nil {
+			log.Printf("Velocity sum error: %v", err)
+			return 0.0
+		}
+		return sum
+	}
+
+	// Field-keyed helpers count nothing when the key is missing from the event
+	env["velocityCountBy"] = func(path string, value any, timeWindowSeconds int) int {
+		if value == nil {
+			return 0
+		}
+		count, err := historyRepo.CountByFieldInTimeWindow(ctx, path, value, timeWindowSeconds)
+		if err != nil {
+			log.Printf("Velocity count by %s error: %v", path, err)
+			return 0
+		}
+		return count
+	}
+
+	// velocityDistinct("device.id", "user.id", 3600) counts the devices seen for the current event's user
+	env["velocityDistinct"] = func(distinctPath, keyPath string, timeWindowSeconds int) int {
+		value := extractValueByPath(eventData, keyPath)
+		if value == nil {
+			return 0
+		}
+		count, err := historyRepo.CountDistinctByFieldInTimeWindow(ctx, distinctPath, keyPath, value, timeWindowSeconds)
+		if err != nil {
+			log.Printf("Velocity distinct %s by %s error: %v", distinctPath, keyPath, err)
+			return 0
+		}
+		return count
+	}
+
+	for name, aggregate := range amountAggregateHelpers {
+		env[name] = func(path string, value any, timeWindowSeconds int) float64 {
+			if value == nil {
+				return 0.0
+			}
+			result, err := historyRepo.AggregateAmountByFieldInTimeWindow(ctx, path, value, aggregate, timeWindowSeconds)
+			if err != nil {
+				log.Printf("Velocity %s by %s error: %v", aggregate, path, err)
+				return 0.0
+			}
+			return result
+		}
+	}
+
+	addBaselineHelpers(ctx, env, historyRepo)
+}
+
+// addBaselineHelpers registers the helpers comparing an event to its account's historical norm
+// Baselines cover the account's previous transactions, not the one being evaluated
+func addBaselineHelpers(ctx context.Context, env map[string]any, historyRepo HistoryRepository) {
+	amountStats := func(account string, days int) models.AmountStats {
+		stats, err := historyRepo.AmountStatsByAccount(ctx, account, days)
+		if err != nil {
+			log.Printf("Amount stats error: %v", err)
+			return models.AmountStats{}
+		}
+		return stats
+	}
+
+	env["avgAmount"] = func(account string, days int) float64 {
+		return amountStats(account, days).Mean
+	}
+
+	env["stddevAmount"] = func(account string, days int) float64 {
+		return amountStats(account, days).StdDev
+	}
+
+	env["zscore"] = func(account string, amount float64, days int) float64 {
+		return amountStats(account, days).ZScore(amount)
+	}
+
+	// An account never seen before is first seen by the event being evaluated
+	// A failed lookup returns the zero time, so an unknown age never reads as a new account
+	env["firstSeen"] = func(account string) time.Time {
+		firstSeen, err := historyRepo.FirstSeenByAccount(ctx, account)
+		if err != nil {
+			log.Printf("First seen error: %v", err)
+			return time.Time{}
+		}
+		if firstSeen == nil {
+			return time.Now()
+		}
+		return *firstSeen
+	}
+}
+
+// zeroValueForType returns the zero value used to type a field in the compile environment
+func zeroValueForType(fieldType models.FieldType) any {
+	switch fieldType {
+	case models.FieldTypeString:
+		return ""
+	case models.FieldTypeNumber:
+		return float64(0)
+	case models.FieldTypeBoolean:
+		return false
+	case models.FieldTypeArray:
+		return []any{}
+	case models.FieldTypeObject:
+		return map[string]any{}
+	default:
+		// Null fields have no known type, expr treats them as any
+		return nil
+	}
+}
+
+// extractValueByPath extracts a value from nested JSON using dot notation
+// e.g., "user.country" extracts data["user"]["country"]
+func extractValueByPath(data map[string]any, path string) any {
+	if data == nil {
+		return nil
+	}
+
+	parts := strings.Split(path, ".")
+	var current any = data
+
+	for _, part := range parts {
+		if current == nil {
+			return nil
+		}
+
+		switch v := current.(type) {
+		case map[string]any:
+			current = v[part]
+		default:
+			return nil
+		}
+	}
+
+	return current
+}
+
+// setValueByPath sets a value in a nested map using dot notation,
+// creating intermediate maps as needed
+// e.g., "user.country" sets env["user"]["country"]
+func setValueByPath(env map[string]any, path string, value any) {
+	parts := strings.Split(path, ".")
+	current := env
+
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			current[part] = next
+		}
+		current = next
+	}
+
+	current[parts[len(parts)-1]] = value
+}
+
+// Compile compiles an expression against the schema's typed environment.
+// The returned program is safe for concurrent use and can be run against any event
+// environment built with BuildEnv for the same fields.
+func Compile(expression string, fields []models.ExtractedField) (*vm.Program, error) {
+	return CompileWithMacros(expression, fields, nil)
+}
+
+// CompileWithMacros compiles an expression like Compile, replacing references to macros
+// with their expressions first so each macro is type-checked against the schema's fields.
+// The program does not depend on macros once compiled: changing a macro requires recompiling.
+func CompileWithMacros(expression string, fields []models.ExtractedField, macros *Macros) (*vm.Program, error) {
+	if expression == "" {
+		return nil, ErrEmptyExpression
+	}
+
+	env := BuildCompileEnv(fields)
+
+	// expr.AsBool() ensures the result must be a boolean
+	options := []expr.Option{expr.Env(env), expr.AsBool()}
+	if macros.Len() > 0 {
+		expander := &macroExpander{macros: macros, env: env}
+		if tree, err := parser.Parse(expression); err == nil {
+			expander.shadowed = letNames(tree.Node)
+		}
+		options = append(options, expr.Patch(expander))
+	}
+
+	return expr.Compile(expression, options...)
+}
+
+// Run runs a precompiled program against an expression environment.
+// Returns an error if the program fails at runtime or does not return a boolean.
+func Run(program *vm.Program, env map[string]any) (bool, error) {
+	result, err := expr.Run(program, env)
+	if err != nil {
+		return false, err
+	}
+
+	// The result should be a boolean due to expr.AsBool() option
+	boolResult, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression did not return boolean: %T", result)
+	}
+
+	return boolResult, nil
+}
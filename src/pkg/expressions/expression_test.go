@@ -0,0 +1,660 @@
+package expressions
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/fuzzy"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFields() []models.ExtractedField {
+	return []models.ExtractedField{
+		{Path: "amount", Type: models.FieldTypeNumber},
+		{Path: "user.country", Type: models.FieldTypeString},
+	}
+}
+
+func Test_Compile_WhenValid_ThenRunsAgainstEvent(t *testing.T) {
+	program, err := Compile(`user.country == "BR" and amount > 100`, testFields())
+	require.NoError(t, err)
+
+	env := BuildEnv(context.Background(), map[string]any{
+		"amount": 150.0,
+		"user":   map[string]any{"country": "BR"},
+	}, testFields(), Lookups{})
+	matched, err := Run(program, env)
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func Test_DescribeCompileError_WhenSyntaxError_ThenReportsPosition(t *testing.T) {
+	_, err := Compile("amount > 100 and\n  unknown == \"x\"", testFields())
+	require.Error(t, err)
+
+	compileErr := DescribeCompileError(err)
+
+	assert.Equal(t, 2, compileErr.Line)
+	assert.Equal(t, 2, compileErr.Column)
+	assert.NotEmpty(t, compileErr.Message)
+	assert.NotEmpty(t, compileErr.Snippet)
+}
+
+func Test_DescribeCompileError_WhenNoPosition_ThenReturnsMessage(t *testing.T) {
+	compileErr := DescribeCompileError(ErrEmptyExpression)
+
+	assert.Equal(t, ErrEmptyExpression.Error(), compileErr.Message)
+	assert.Zero(t, compileErr.Line)
+}
+
+func Test_PointInPolygon_WhenInside_ThenReturnsTrue(t *testing.T) {
+	square := [][]float64{{0, 0}, {0, 10}, {10, 10}, {10, 0}}
+
+	assert.True(t, PointInPolygon(5, 5, square))
+	assert.False(t, PointInPolygon(15, 5, square))
+}
+
+// recordingHistory answers velocity queries with fixed values and records the last field query
+type recordingHistory struct {
+	count     int
+	amount    float64
+	path      string
+	value     any
+	aggregate models.AmountAggregate
+	distinct  string
+	stats     models.AmountStats
+	firstSeen *time.Time
+	seenErr   error
+	location  *models.AccountLocation
+}
+
+func (h *recordingHistory) CountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (int, error) {
+	return h.count, nil
+}
+
+func (h *recordingHistory) SumAmountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (float64, error) {
+	return h.amount, nil
+}
+
+func (h *recordingHistory) CountByFieldInTimeWindow(ctx context.Context, path string, value any, timeWindowSeconds int) (int, error) {
+	h.path, h.value = path, value
+	return h.count, nil
+}
+
+func (h *recordingHistory) AggregateAmountByFieldInTimeWindow(ctx context.Context, path string, value any, aggregate models.AmountAggregate, timeWindowSeconds int) (float64, error) {
+	h.path, h.value, h.aggregate = path, value, aggregate
+	return h.amount, nil
+}
+
+func (h *recordingHistory) CountDistinctByFieldInTimeWindow(ctx context.Context, distinctPath, path string, value any, timeWindowSeconds int) (int, error) {
+	h.distinct, h.path, h.value = distinctPath, path, value
+	return h.count, nil
+}
+
+func (h *recordingHistory) AmountStatsByAccount(ctx context.Context, account string, days int) (models.AmountStats, error) {
+	return h.stats, nil
+}
+
+func (h *recordingHistory) FirstSeenByAccount(ctx context.Context, account string) (*time.Time, error) {
+	return h.firstSeen, h.seenErr
+}
+
+func (h *recordingHistory) LastLocationByAccount(ctx context.Context, account string) (*models.AccountLocation, error) {
+	return h.location, nil
+}
+
+func velocityFields() []models.ExtractedField {
+	return []models.ExtractedField{
+		{Path: "ip", Type: models.FieldTypeString},
+		{Path: "user.id", Type: models.FieldTypeString},
+		{Path: "device.id", Type: models.FieldTypeString},
+	}
+}
+
+func velocityEvent() map[string]any {
+	return map[string]any{
+		"ip":     "10.0.0.1",
+		"user":   map[string]any{"id": "user-1"},
+		"device": map[string]any{"id": "device-1"},
+	}
+}
+
+func Test_BuildEnv_WhenVelocityCountBy_ThenQueriesFieldValue(t *testing.T) {
+	history := &recordingHistory{count: 6}
+	program, err := Compile(`velocityCountBy("ip", ip, 600) > 5`, velocityFields())
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), velocityEvent(), velocityFields(), Lookups{History: history}))
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, "ip", history.path)
+	assert.Equal(t, "10.0.0.1", history.value)
+}
+
+func Test_BuildEnv_WhenVelocityDistinct_ThenKeysByCurrentEventValue(t *testing.T) {
+	history := &recordingHistory{count: 4}
+	program, err := Compile(`velocityDistinct("device.id", "user.id", 3600) > 3`, velocityFields())
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), velocityEvent(), velocityFields(), Lookups{History: history}))
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, "device.id", history.distinct)
+	assert.Equal(t, "user.id", history.path)
+	assert.Equal(t, "user-1", history.value)
+}
+
+func Test_BuildEnv_WhenAmountAggregateHelpers_ThenUseMatchingAggregate(t *testing.T) {
+	cases := map[string]models.AmountAggregate{
+		"velocitySumBy": models.AggregateSum,
+		"velocityMinBy": models.AggregateMin,
+		"velocityMaxBy": models.AggregateMax,
+		"velocityAvgBy": models.AggregateAvg,
+	}
+
+	for helper, aggregate := range cases {
+		t.Run(helper, func(t *testing.T) {
+			history := &recordingHistory{amount: 500}
+			program, err := Compile(helper+`("user.id", user.id, 3600) == 500`, velocityFields())
+			require.NoError(t, err)
+
+			matched, err := Run(program, BuildEnv(context.Background(), velocityEvent(), velocityFields(), Lookups{History: history}))
+
+			require.NoError(t, err)
+			assert.True(t, matched)
+			assert.Equal(t, aggregate, history.aggregate)
+		})
+	}
+}
+
+func Test_BuildEnv_WhenKeyMissingFromEvent_ThenFieldHelpersReturnZero(t *testing.T) {
+	history := &recordingHistory{count: 10, amount: 10}
+	program, err := Compile(`velocityDistinct("device.id", "user.id", 3600) == 0 and velocityMaxBy("ip", ip, 60) == 0`, velocityFields())
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), map[string]any{}, velocityFields(), Lookups{History: history}))
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+	assert.Empty(t, history.path)
+}
+
+func baselineFields() []models.ExtractedField {
+	return []models.ExtractedField{
+		{Path: "amount", Type: models.FieldTypeNumber},
+		{Path: "origin", Type: models.FieldTypeString},
+	}
+}
+
+func Test_BuildEnv_WhenZscore_ThenComparesAmountToAccountBaseline(t *testing.T) {
+	history := &recordingHistory{stats: models.NewAmountStats(4, 400, 40400)}
+	program, err := Compile(`zscore(origin, amount, 30) > 5 and avgAmount(origin, 30) == 100 and stddevAmount(origin, 30) == 10`, baselineFields())
+	require.NoError(t, err)
+
+	env := BuildEnv(context.Background(), map[string]any{"amount": 160.0, "origin": "ACC1"}, baselineFields(), Lookups{History: history})
+	matched, err := Run(program, env)
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func Test_BuildEnv_WhenFirstSeen_ThenSupportsTimeArithmetic(t *testing.T) {
+	seen := time.Now().Add(-48 * time.Hour)
+	program, err := Compile(`now() - firstSeen(origin) < duration("72h")`, baselineFields())
+	require.NoError(t, err)
+
+	env := BuildEnv(context.Background(), map[string]any{"origin": "ACC1"}, baselineFields(), Lookups{History: &recordingHistory{firstSeen: &seen}})
+	matched, err := Run(program, env)
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func Test_BuildEnv_WhenAccountNeverSeen_ThenFirstSeenIsNow(t *testing.T) {
+	program, err := Compile(`now() - firstSeen(origin) < duration("1m")`, baselineFields())
+	require.NoError(t, err)
+
+	env := BuildEnv(context.Background(), map[string]any{"origin": "NEW"}, baselineFields(), Lookups{History: &recordingHistory{}})
+	matched, err := Run(program, env)
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func Test_BuildEnv_WhenFirstSeenLookupFails_ThenAccountIsNotNew(t *testing.T) {
+	program, err := Compile(`now() - firstSeen(origin) < duration("72h")`, baselineFields())
+	require.NoError(t, err)
+
+	history := &recordingHistory{seenErr: errors.New("database unavailable")}
+	env := BuildEnv(context.Background(), map[string]any{"origin": "ACC1"}, baselineFields(), Lookups{History: history})
+	matched, err := Run(program, env)
+
+	require.NoError(t, err)
+	assert.False(t, matched)
+}
+
+// stubCountryRisk scores countries from a fixed table
+type stubCountryRisk map[string]int
+
+func (s stubCountryRisk) CountryRiskScore(ctx context.Context, code string) (int, error) {
+	return s[code], nil
+}
+
+func geoFields() []models.ExtractedField {
+	return []models.ExtractedField{
+		{Path: "origin", Type: models.FieldTypeString},
+		{Path: "country", Type: models.FieldTypeString},
+		{Path: "location.lat", Type: models.FieldTypeNumber},
+		{Path: "location.lon", Type: models.FieldTypeNumber},
+	}
+}
+
+func geoEvent() map[string]any {
+	// Paris
+	return map[string]any{
+		"origin":   "ACC1",
+		"country":  "FR",
+		"location": map[string]any{"lat": 48.8566, "lon": 2.3522},
+	}
+}
+
+func Test_BuildEnv_WhenImpossibleTravel_ThenComparesWithLastLocation(t *testing.T) {
+	// London, 30 minutes ago: about 340 km away
+	history := &recordingHistory{location: &models.AccountLocation{
+		GeoPoint: models.GeoPoint{Latitude: 51.5074, Longitude: -0.1278},
+		SeenAt:   time.Now().Add(-30 * time.Minute),
+	}}
+	program, err := Compile(`impossibleTravel(origin, location.lat, location.lon, 500)`, geoFields())
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), geoEvent(), geoFields(), Lookups{History: history}))
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func Test_BuildEnv_WhenNoLastLocation_ThenTravelIsPossible(t *testing.T) {
+	program, err := Compile(`impossibleTravel(origin, location.lat, location.lon, 900)`, geoFields())
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), geoEvent(), geoFields(), Lookups{History: &recordingHistory{}}))
+
+	require.NoError(t, err)
+	assert.False(t, matched)
+}
+
+func Test_BuildEnv_WhenCountryRisk_ThenLooksUpScore(t *testing.T) {
+	program, err := Compile(`countryRisk(country) >= 70 and countryRisk("BR") == 0`, geoFields())
+	require.NoError(t, err)
+
+	env := BuildEnv(context.Background(), geoEvent(), geoFields(), Lookups{CountryRisk: stubCountryRisk{"FR": 70}})
+	matched, err := Run(program, env)
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func Test_BuildEnv_WhenNoLookups_ThenGeoHelpersReturnZero(t *testing.T) {
+	program, err := Compile(`countryRisk(country) == 0 and not impossibleTravel(origin, location.lat, location.lon, 1)`, geoFields())
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), geoEvent(), geoFields(), Lookups{}))
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func Test_HaversineKm_WhenParisToLondon_ThenReturnsGreatCircleDistance(t *testing.T) {
+	assert.InDelta(t, 343.5, HaversineKm(48.8566, 2.3522, 51.5074, -0.1278), 1)
+	assert.Zero(t, HaversineKm(10, 20, 10, 20))
+}
+
+func Test_ImpossibleTravel_WhenEnoughTimeElapsed_ThenReturnsFalse(t *testing.T) {
+	now := time.Now()
+	last := models.AccountLocation{
+		GeoPoint: models.GeoPoint{Latitude: 51.5074, Longitude: -0.1278},
+		SeenAt:   now.Add(-2 * time.Hour),
+	}
+
+	assert.False(t, ImpossibleTravel(last, models.GeoPoint{Latitude: 48.8566, Longitude: 2.3522}, now, 900))
+	assert.True(t, ImpossibleTravel(last, models.GeoPoint{Latitude: 48.8566, Longitude: 2.3522}, now, 100))
+}
+
+func Test_ImpossibleTravel_WhenSimultaneousNearbyTransactions_ThenReturnsFalse(t *testing.T) {
+	now := time.Now()
+	last := models.AccountLocation{GeoPoint: models.GeoPoint{Latitude: 48.8566, Longitude: 2.3522}, SeenAt: now}
+
+	// About 200 metres away at the same instant
+	assert.False(t, ImpossibleTravel(last, models.GeoPoint{Latitude: 48.8584, Longitude: 2.3522}, now, 900))
+}
+
+// stubLists records the values checked against each list
+type stubLists struct {
+	entries map[string][]string
+	checked []string
+}
+
+func (s *stubLists) Contains(ctx context.Context, listName, value string) (bool, error) {
+	s.checked = append(s.checked, value)
+	for _, entry := range s.entries[listName] {
+		if entry == value {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *stubLists) ContainsIP(ctx context.Context, listName, ip string) (bool, error) {
+	for _, entry := range s.entries[listName] {
+		if IPInCIDR(ip, entry) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *stubLists) FuzzyContains(ctx context.Context, listName, value string, threshold float64) (bool, error) {
+	for _, entry := range s.entries[listName] {
+		if fuzzy.NameSimilarity(value, entry) >= threshold {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func Test_BuildEnv_WhenInList_ThenChecksFormattedValue(t *testing.T) {
+	fields := []models.ExtractedField{
+		{Path: "ip", Type: models.FieldTypeString},
+		{Path: "card.bin", Type: models.FieldTypeNumber},
+	}
+	event := map[string]any{"ip": "10.0.0.1", "card": map[string]any{"bin": float64(41111111)}}
+	lists := &stubLists{entries: map[string][]string{"blocked-bins": {"41111111"}}}
+	program, err := Compile(`inList("blocked-bins", card.bin) and not inList("blocked-ips", ip)`, fields)
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), event, fields, Lookups{Lists: lists}))
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, []string{"41111111", "10.0.0.1"}, lists.checked)
+}
+
+func Test_BuildEnv_WhenNoListLookup_ThenInListIsFalse(t *testing.T) {
+	program, err := Compile(`inList("blocked-ips", ip)`, velocityFields())
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), map[string]any{"ip": "10.0.0.1"}, velocityFields(), Lookups{}))
+
+	require.NoError(t, err)
+	assert.False(t, matched)
+}
+
+// stubIPs is a single-network IP database
+type stubIPs struct{}
+
+func (stubIPs) LookupIP(ip string) (models.IPInfo, bool) {
+	if IPInCIDR(ip, "203.0.113.0/24") {
+		return models.IPInfo{CountryCode: "NL", ASN: 64500}, true
+	}
+	return models.IPInfo{}, false
+}
+
+func Test_BuildEnv_WhenIPHelpers_ThenMatchesRangesAndDatabase(t *testing.T) {
+	fields := []models.ExtractedField{{Path: "ip", Type: models.FieldTypeString}}
+	event := map[string]any{"ip": "203.0.113.9"}
+	lists := &stubLists{entries: map[string][]string{"hosting": {"203.0.113.0/24"}}}
+	program, err := Compile(`ipInCidr(ip, "203.0.0.0/16") and ipInList(ip, "hosting") and not isPrivateIP(ip) and ipCountry(ip) == "NL" and ipASN(ip) == 64500`, fields)
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), event, fields, Lookups{Lists: lists, IPs: stubIPs{}}))
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func Test_BuildEnv_WhenNoIPDatabase_ThenIPCountryIsEmpty(t *testing.T) {
+	fields := []models.ExtractedField{{Path: "ip", Type: models.FieldTypeString}}
+	program, err := Compile(`ipCountry(ip) == "" and ipASN(ip) == 0 and not ipInList(ip, "hosting")`, fields)
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), map[string]any{"ip": "203.0.113.9"}, fields, Lookups{}))
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func Test_IPInCIDR_WhenAddressForms_ThenMatchesNetwork(t *testing.T) {
+	assert.True(t, IPInCIDR("10.1.2.3", "10.0.0.0/8"))
+	assert.True(t, IPInCIDR("::ffff:10.1.2.3", "10.0.0.0/8"))
+	assert.True(t, IPInCIDR("2001:db8::1", "2001:db8::/32"))
+	assert.False(t, IPInCIDR("11.0.0.1", "10.0.0.0/8"))
+	assert.False(t, IPInCIDR("10.1.2.3", "2001:db8::/32"))
+	assert.False(t, IPInCIDR("not-an-ip", "10.0.0.0/8"))
+	assert.False(t, IPInCIDR("10.1.2.3", "10.0.0.0/99"))
+}
+
+func Test_IsPrivateIP_WhenAddressRanges_ThenDetectsNonPublic(t *testing.T) {
+	for _, ip := range []string{"10.0.0.1", "172.16.5.4", "192.168.1.1", "127.0.0.1", "169.254.1.1", "fd00::1", "::1", "fe80::1"} {
+		assert.True(t, IsPrivateIP(ip), ip)
+	}
+	for _, ip := range []string{"8.8.8.8", "203.0.113.9", "2001:4860::8888", "", "garbage"} {
+		assert.False(t, IsPrivateIP(ip), ip)
+	}
+}
+
+func Test_BuildEnv_WhenStringHelpers_ThenComparesNames(t *testing.T) {
+	fields := []models.ExtractedField{{Path: "payee.name", Type: models.FieldTypeString}}
+	event := map[string]any{"payee": map[string]any{"name": "PUTIN, Vladimír"}}
+	lists := &stubLists{entries: map[string][]string{"pep": {"vladimir putin"}}}
+	program, err := Compile(`normalizeName(payee.name) == "putin vladimir" and levenshtein("putin", "puttin") == 1 and jaroWinkler("martha", "marhta") > 0.95 and fuzzyInList("pep", payee.name, 0.9) and not fuzzyInList("pep", "Angela Merkel", 0.9)`, fields)
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), event, fields, Lookups{Lists: lists}))
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+// stubSanctions records the screened names and countries
+type stubSanctions struct {
+	sanctioned map[string]string // Name to country
+	screened   []string
+}
+
+func (s *stubSanctions) SanctionsHit(ctx context.Context, name, country string) (bool, error) {
+	s.screened = append(s.screened, name+"/"+country)
+	listed, ok := s.sanctioned[name]
+	return ok && (country == "" || listed == country), nil
+}
+
+func Test_BuildEnv_WhenSanctionsHit_ThenScreensNameAndCountry(t *testing.T) {
+	fields := []models.ExtractedField{
+		{Path: "payee.name", Type: models.FieldTypeString},
+		{Path: "payee.country", Type: models.FieldTypeString},
+	}
+	event := map[string]any{"payee": map[string]any{"name": "Ivan Petrov", "country": "RU"}}
+	sanctions := &stubSanctions{sanctioned: map[string]string{"Ivan Petrov": "RU"}}
+	program, err := Compile(`sanctionsHit(payee.name, payee.country) and not sanctionsHit("", "") and not sanctionsHit(payee.name, "FR")`, fields)
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), event, fields, Lookups{Sanctions: sanctions}))
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+	// Empty names are not screened
+	assert.Equal(t, []string{"Ivan Petrov/RU", "Ivan Petrov/FR"}, sanctions.screened)
+}
+
+func Test_BuildEnv_WhenNoSanctionsLookup_ThenSanctionsHitIsFalse(t *testing.T) {
+	fields := []models.ExtractedField{{Path: "name", Type: models.FieldTypeString}}
+	program, err := Compile(`sanctionsHit(name, "")`, fields)
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), map[string]any{"name": "Ivan Petrov"}, fields, Lookups{}))
+
+	require.NoError(t, err)
+	assert.False(t, matched)
+}
+
+// stubTimeline records the sequences checked and matches the configured one
+type stubTimeline struct {
+	matching []string
+	checked  [][]string
+}
+
+func (s *stubTimeline) Sequence(ctx context.Context, entity string, types []string, within time.Duration) (bool, error) {
+	s.checked = append(s.checked, types)
+	return entity == "account-1" && within == 30*time.Minute && slices.Equal(types, s.matching), nil
+}
+
+func Test_BuildEnv_WhenSequenceHelpers_ThenChecksEntityTimeline(t *testing.T) {
+	fields := []models.ExtractedField{
+		{Path: "origin", Type: models.FieldTypeString},
+		{Path: "type", Type: models.FieldTypeString},
+	}
+	event := map[string]any{"origin": "account-1", "type": "transfer"}
+	timeline := &stubTimeline{matching: []string{"password_change", "new_device_login", "transfer"}}
+	program, err := Compile(`type == "transfer" and sequence(origin, ["password_change", "new_device_login", "transfer"], 1800) and not followedBy(origin, "login", "transfer", 1800)`, fields)
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), event, fields, Lookups{Timeline: timeline}))
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, [][]string{{"password_change", "new_device_login", "transfer"}, {"login", "transfer"}}, timeline.checked)
+}
+
+func Test_BuildEnv_WhenNoTimelineLookup_ThenSequenceIsFalse(t *testing.T) {
+	fields := []models.ExtractedField{{Path: "origin", Type: models.FieldTypeString}}
+	program, err := Compile(`sequence(origin, ["login", "transfer"], 60) or followedBy(origin, "login", "transfer", 60)`, fields)
+	require.NoError(t, err)
+
+	matched, err := Run(program, BuildEnv(context.Background(), map[string]any{"origin": "account-1"}, fields, Lookups{}))
+
+	require.NoError(t, err)
+	assert.False(t, matched)
+}
+
+func testMacros(t *testing.T, macros map[string]string) *Macros {
+	t.Helper()
+	list := make([]models.Macro, 0, len(macros))
+	for name, expression := range macros {
+		list = append(list, models.Macro{Name: name, Expression: expression})
+	}
+	set, err := NewMacros(list)
+	require.NoError(t, err)
+	return set
+}
+
+func Test_CompileWithMacros_WhenMacrosNested_ThenInlinesThemAgainstSchema(t *testing.T) {
+	macros := testMacros(t, map[string]string{
+		"highAmount":   `amount > 100`,
+		"riskyCountry": `user.country in ["KP", "IR"]`,
+		"highRisk":     `highAmount and riskyCountry`,
+	})
+
+	program, err := CompileWithMacros(`highRisk or amount > 10000`, testFields(), macros)
+	require.NoError(t, err)
+
+	env := BuildEnv(context.Background(), map[string]any{
+		"amount": 150.0,
+		"user":   map[string]any{"country": "IR"},
+	}, testFields(), Lookups{})
+	matched, err := Run(program, env)
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, []string{"highRisk"}, macros.References(`highRisk or amount > 10000`))
+}
+
+func Test_CompileWithMacros_WhenMacroMistyped_ThenFailsAtReference(t *testing.T) {
+	macros := testMacros(t, map[string]string{"highAmount": `amount > "100"`})
+
+	_, err := CompileWithMacros(`user.country == "BR" and highAmount`, testFields(), macros)
+	require.Error(t, err)
+
+	compileErr := DescribeCompileError(err)
+	assert.Equal(t, 1, compileErr.Line)
+	assert.Equal(t, 25, compileErr.Column)
+}
+
+func Test_CompileWithMacros_WhenFieldOrLetHasMacroName_ThenTheyTakePrecedence(t *testing.T) {
+	macros := testMacros(t, map[string]string{"amount": `true`, "limit": `"x"`})
+
+	program, err := CompileWithMacros(`let limit = 100; amount > limit`, testFields(), macros)
+	require.NoError(t, err)
+
+	env := BuildEnv(context.Background(), map[string]any{"amount": 150.0}, testFields(), Lookups{})
+	matched, err := Run(program, env)
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func Test_NewMacros_WhenCycle_ThenReturnsCyclePath(t *testing.T) {
+	_, err := NewMacros([]models.Macro{
+		{Name: "a", Expression: `b or amount > 1`},
+		{Name: "b", Expression: `c`},
+		{Name: "c", Expression: `a`},
+	})
+
+	require.ErrorIs(t, err, ErrMacroCycle)
+	assert.Contains(t, err.Error(), "a -> b -> c -> a")
+}
+
+func Test_NewMacros_WhenInvalid_ThenRejectsMacro(t *testing.T) {
+	tests := []struct {
+		name  string
+		macro models.Macro
+		err   error
+	}{
+		{"not an identifier", models.Macro{Name: "high-risk", Expression: `true`}, ErrInvalidMacroName},
+		{"helper name", models.Macro{Name: "inList", Expression: `true`}, ErrReservedMacroName},
+		{"builtin name", models.Macro{Name: "len", Expression: `true`}, ErrReservedMacroName},
+		{"keyword", models.Macro{Name: "not", Expression: `true`}, ErrReservedMacroName},
+		{"empty expression", models.Macro{Name: "empty", Expression: ` `}, ErrEmptyExpression},
+		{"self reference", models.Macro{Name: "loop", Expression: `loop and true`}, ErrMacroCycle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMacros([]models.Macro{tt.macro})
+
+			var macroErr *MacroError
+			require.ErrorAs(t, err, &macroErr)
+			assert.Equal(t, tt.macro.Name, macroErr.Name)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func Test_TimeDependentHelpers_WhenHelpersCalled_ThenReturnsThemSorted(t *testing.T) {
+	program, err := Compile(`velocityCount("acc", 3600) > 5 or firstSeen("acc") > now() or haversineKm(0, 0, 1, 1) > 10`, testFields())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"firstSeen", "velocityCount"}, TimeDependentHelpers(program))
+}
+
+func Test_TimeDependentHelpers_WhenCalledThroughMacro_ThenIncludesThem(t *testing.T) {
+	macros := testMacros(t, map[string]string{"burst": `velocitySum("acc", 60) > amount`})
+
+	program, err := CompileWithMacros(`burst and user.country == "BR"`, testFields(), macros)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"velocitySum"}, TimeDependentHelpers(program))
+}
+
+func Test_TimeDependentHelpers_WhenOnlyEventFields_ThenReturnsNone(t *testing.T) {
+	program, err := Compile(`amount > 100 and user.country == "BR"`, testFields())
+	require.NoError(t, err)
+
+	assert.Empty(t, TimeDependentHelpers(program))
+}
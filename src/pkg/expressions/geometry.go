@@ -0,0 +1,112 @@
+package expressions
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+)
+
+// earthRadiusKm is the mean Earth radius used by HaversineKm
+const earthRadiusKm = 6371.0
+
+// minTravelTime floors the time between two locations in ImpossibleTravel, so
+// near-simultaneous transactions a few hundred metres apart are not flagged
+const minTravelTime = time.Minute
+
+// addGeoHelpers registers the geo helper functions
+// impossibleTravel and countryRisk return false and 0 when their lookup is nil
+func addGeoHelpers(ctx context.Context, env map[string]any, lookups Lookups) {
+	env["pointInPolygon"] = func(lat, lon float64, polygon [][]float64) bool {
+		return PointInPolygon(lat, lon, polygon)
+	}
+
+	env["haversineKm"] = func(lat1, lon1, lat2, lon2 float64) float64 {
+		return HaversineKm(lat1, lon1, lat2, lon2)
+	}
+
+	// Compares against the account's last known location, before the event being evaluated
+	env["impossibleTravel"] = func(account string, lat, lon, maxKmh float64) bool {
+		if lookups.History == nil {
+			return false
+		}
+		last, err := lookups.History.LastLocationByAccount(ctx, account)
+		if err != nil {
+			log.Printf("Last location error: %v", err)
+			return false
+		}
+		if last == nil {
+			return false
+		}
+		return ImpossibleTravel(*last, models.GeoPoint{Latitude: lat, Longitude: lon}, time.Now(), maxKmh)
+	}
+
+	env["countryRisk"] = func(code string) int {
+		if lookups.CountryRisk == nil {
+			return 0
+		}
+		score, err := lookups.CountryRisk.CountryRiskScore(ctx, code)
+		if err != nil {
+			log.Printf("Country risk error: %v", err)
+			return 0
+		}
+		return score
+	}
+}
+
+// HaversineKm returns the great-circle distance in kilometres between two points given in decimal degrees
+func HaversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// ImpossibleTravel reports whether reaching point at the given time from the last
+// known location requires travelling faster than maxKmh
+func ImpossibleTravel(last models.AccountLocation, point models.GeoPoint, at time.Time, maxKmh float64) bool {
+	elapsed := at.Sub(last.SeenAt)
+	if elapsed < minTravelTime {
+		elapsed = minTravelTime
+	}
+
+	distance := HaversineKm(last.Latitude, last.Longitude, point.Latitude, point.Longitude)
+	return distance/elapsed.Hours() > maxKmh
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// PointInPolygon checks if a point is inside a polygon using the ray casting algorithm.
+// This is a standard algorithm that counts how many times a ray from the point
+// crosses the polygon boundary. If the count is odd, the point is inside.
+func PointInPolygon(lat, lon float64, polygon [][]float64) bool {
+	n := len(polygon)
+	if n < 3 {
+		return false
+	}
+
+	inside := false
+	j := n - 1
+
+	for i := 0; i < n; i++ {
+		// Check if the ray from (lat, lon) going right crosses the edge from polygon[i] to polygon[j]
+		// polygon[i] and polygon[j] are [lat, lon] pairs
+		latI, lonI := polygon[i][0], polygon[i][1]
+		latJ, lonJ := polygon[j][0], polygon[j][1]
+
+		if ((lonI > lon) != (lonJ > lon)) &&
+			(lat < (latJ-latI)*(lon-lonI)/(lonJ-lonI)+latI) {
+			inside = !inside
+		}
+		j = i
+	}
+
+	return inside
+}
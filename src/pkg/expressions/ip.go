@@ -0,0 +1,83 @@
+package expressions
+
+import (
+	"context"
+	"log"
+	"net/netip"
+	"strings"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+)
+
+// addIPHelpers registers the IP range and IP intelligence helpers.
+// Invalid addresses and networks never match. Without an IP database, ipCountry and ipASN return zero values.
+func addIPHelpers(ctx context.Context, env map[string]any, lookups Lookups) {
+	env["ipInCidr"] = func(ip, cidr string) bool {
+		return IPInCIDR(ip, cidr)
+	}
+
+	env["isPrivateIP"] = func(ip string) bool {
+		return IsPrivateIP(ip)
+	}
+
+	env["ipInList"] = func(ip, listName string) bool {
+		if lookups.Lists == nil {
+			return false
+		}
+		contains, err := lookups.Lists.ContainsIP(ctx, listName, ip)
+		if err != nil {
+			log.Printf("IP list lookup error: %v", err)
+			return false
+		}
+		return contains
+	}
+
+	env["ipCountry"] = func(ip string) string {
+		if lookups.IPs == nil {
+			return ""
+		}
+		info, _ := lookups.IPs.LookupIP(ip)
+		return info.CountryCode
+	}
+
+	env["ipASN"] = func(ip string) int {
+		if lookups.IPs == nil {
+			return 0
+		}
+		info, _ := lookups.IPs.LookupIP(ip)
+		return info.ASN
+	}
+}
+
+// IPInCIDR reports whether an address is inside a network in CIDR notation
+// IPv4-mapped IPv6 addresses match IPv4 networks
+func IPInCIDR(ip, cidr string) bool {
+	addr, ok := parseIP(ip)
+	if !ok {
+		return false
+	}
+	prefix, err := models.ParseIPNetwork(cidr)
+	if err != nil {
+		return false
+	}
+	return prefix.Contains(addr)
+}
+
+// IsPrivateIP reports whether an address is not publicly routable:
+// private (RFC 1918, RFC 4193), loopback, link-local or unspecified
+func IsPrivateIP(ip string) bool {
+	addr, ok := parseIP(ip)
+	if !ok {
+		return false
+	}
+	return addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsUnspecified()
+}
+
+// parseIP parses an address from an event, unmapping IPv4-mapped IPv6 addresses
+func parseIP(ip string) (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap().WithZone(""), true
+}
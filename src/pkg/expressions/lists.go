@@ -0,0 +1,53 @@
+package expressions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// addListHelpers registers the helpers checking managed lists.
+// When lists is nil, inList and fuzzyInList never match.
+func addListHelpers(ctx context.Context, env map[string]any, lists ListLookup) {
+	// Values are normalized for the list type, e.g. emails are compared case-insensitively
+	env["inList"] = func(name string, value any) bool {
+		if lists == nil || value == nil {
+			return false
+		}
+		contains, err := lists.Contains(ctx, name, listValueString(value))
+		if err != nil {
+			log.Printf("List lookup error: %v", err)
+			return false
+		}
+		return contains
+	}
+
+	// Threshold is a similarity from 0 to 1; names are normalized and compared in any word order
+	env["fuzzyInList"] = func(name string, value any, threshold float64) bool {
+		if lists == nil || value == nil {
+			return false
+		}
+		contains, err := lists.FuzzyContains(ctx, name, listValueString(value), threshold)
+		if err != nil {
+			log.Printf("Fuzzy list lookup error: %v", err)
+			return false
+		}
+		return contains
+	}
+}
+
+// listValueString formats an event value as a list entry
+// JSON numbers are decoded as float64, so integral BINs and account numbers are printed without exponent
+func listValueString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
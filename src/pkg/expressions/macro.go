@@ -0,0 +1,288 @@
+package expressions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/builtin"
+	"github.com/expr-lang/expr/file"
+	"github.com/expr-lang/expr/parser"
+)
+
+// Macro errors
+var (
+	ErrInvalidMacroName  = errors.New("name must start with a letter or underscore and contain only letters, digits and underscores")
+	ErrReservedMacroName = errors.New("name is reserved by a helper function, builtin or keyword")
+	ErrDuplicateMacro    = errors.New("another macro has the same name")
+	ErrMacroCycle        = errors.New("macro references itself")
+)
+
+// macroNamePattern matches the identifiers usable as macro names
+var macroNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// MacroError reports which macro is invalid and why
+type MacroError struct {
+	Name string
+	Err  error
+}
+
+func (e *MacroError) Error() string {
+	return fmt.Sprintf("macro %s: %v", e.Name, e.Err)
+}
+
+func (e *MacroError) Unwrap() error {
+	return e.Err
+}
+
+// Macros is a validated set of macros, keyed by name
+// A macro is referenced from expressions by its bare name, like a variable, and every
+// reference is replaced by the macro's expression when compiling, so a macro is
+// type-checked against the schema of each rule using it. Schema fields and let
+// variables with the same name take precedence over a macro.
+// Macros are immutable once built; a nil set has no macros.
+type Macros struct {
+	sources map[string]string
+	refs    map[string][]string // Macros referenced directly by each macro
+}
+
+// NewMacros validates macros and builds the set used to compile expressions
+// Names must be identifiers not used by helpers, builtins or keywords, expressions
+// must parse, and macros must not reference themselves directly or through others.
+func NewMacros(macros []models.Macro) (*Macros, error) {
+	reserved := reservedNames()
+
+	set := &Macros{
+		sources: make(map[string]string, len(macros)),
+		refs:    make(map[string][]string, len(macros)),
+	}
+	for _, macro := range macros {
+		if err := validateMacroName(macro.Name, reserved); err != nil {
+			return nil, &MacroError{Name: macro.Name, Err: err}
+		}
+		if _, ok := set.sources[macro.Name]; ok {
+			return nil, &MacroError{Name: macro.Name, Err: ErrDuplicateMacro}
+		}
+		if strings.TrimSpace(macro.Expression) == "" {
+			return nil, &MacroError{Name: macro.Name, Err: ErrEmptyExpression}
+		}
+		if _, err := parser.Parse(macro.Expression); err != nil {
+			return nil, &MacroError{Name: macro.Name, Err: err}
+		}
+		set.sources[macro.Name] = macro.Expression
+	}
+
+	for name, source := range set.sources {
+		set.refs[name] = set.References(source)
+	}
+
+	if err := set.checkCycles(); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// Len returns the number of macros in the set
+func (m *Macros) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.sources)
+}
+
+// References returns the sorted names of the macros an expression uses directly
+// Expressions that do not parse reference nothing
+func (m *Macros) References(expression string) []string {
+	if m.Len() == 0 {
+		return nil
+	}
+
+	tree, err := parser.Parse(expression)
+	if err != nil {
+		return nil
+	}
+
+	collector := &macroCollector{macros: m, shadowed: letNames(tree.Node), seen: make(map[string]bool)}
+	ast.Walk(&tree.Node, collector)
+
+	names := make([]string, 0, len(collector.seen))
+	for name := range collector.seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkCycles rejects macros that reach themselves through their references
+func (m *Macros) checkCycles() error {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make(map[string]int, len(m.sources))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			start := slices.Index(path, name)
+			cycle := append(slices.Clone(path[start:]), name)
+			return &MacroError{Name: name, Err: fmt.Errorf("%w: %s", ErrMacroCycle, strings.Join(cycle, " -> "))}
+		case done:
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, ref := range m.refs[name] {
+			if err := visit(ref); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
+	}
+
+	// Visit in name order so the reported cycle is deterministic
+	names := make([]string, 0, len(m.sources))
+	for name := range m.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expand parses a macro and replaces the macros it references, recursively
+// Every node takes the location of the reference so compile errors point at the macro name
+// Returns nil when the macro does not parse, which NewMacros rules out
+func (m *Macros) expand(name string, env map[string]any, loc file.Location) ast.Node {
+	tree, err := parser.Parse(m.sources[name])
+	if err != nil {
+		return nil
+	}
+
+	ast.Walk(&tree.Node, &macroExpander{macros: m, env: env, shadowed: letNames(tree.Node)})
+	ast.Walk(&tree.Node, locationSetter{loc: loc})
+	return tree.Node
+}
+
+// macroExpander replaces references to macros with their expanded expression
+// Used as an expr patcher, so it runs after parsing and before type checking
+type macroExpander struct {
+	macros   *Macros
+	env      map[string]any
+	shadowed map[string]bool // Let variables declared in the expression
+}
+
+func (v *macroExpander) Visit(node *ast.Node) {
+	ident, ok := (*node).(*ast.IdentifierNode)
+	if !ok || v.shadowed[ident.Value] {
+		return
+	}
+	if _, ok := v.macros.sources[ident.Value]; !ok {
+		return
+	}
+	if _, ok := v.env[ident.Value]; ok {
+		return // A schema field with the macro's name
+	}
+
+	if expanded := v.macros.expand(ident.Value, v.env, ident.Location()); expanded != nil {
+		ast.Patch(node, expanded)
+	}
+}
+
+// macroCollector records the macros referenced by an expression
+type macroCollector struct {
+	macros   *Macros
+	shadowed map[string]bool
+	seen     map[string]bool
+}
+
+func (v *macroCollector) Visit(node *ast.Node) {
+	ident, ok := (*node).(*ast.IdentifierNode)
+	if !ok || v.shadowed[ident.Value] {
+		return
+	}
+	if _, ok := v.macros.sources[ident.Value]; ok {
+		v.seen[ident.Value] = true
+	}
+}
+
+// locationSetter moves every node of a tree to the same location
+type locationSetter struct {
+	loc file.Location
+}
+
+func (v locationSetter) Visit(node *ast.Node) {
+	(*node).SetLocation(v.loc)
+}
+
+// letNames returns the names of the let variables declared in a tree
+func letNames(root ast.Node) map[string]bool {
+	collector := &letCollector{names: make(map[string]bool)}
+	ast.Walk(&root, collector)
+	return collector.names
+}
+
+type letCollector struct {
+	names map[string]bool
+}
+
+func (v *letCollector) Visit(node *ast.Node) {
+	if declarator, ok := (*node).(*ast.VariableDeclaratorNode); ok {
+		v.names[declarator.Name] = true
+	}
+}
+
+// validateMacroName rejects names that are not identifiers or that expressions already use
+func validateMacroName(name string, reserved map[string]bool) error {
+	if !macroNamePattern.MatchString(name) {
+		return ErrInvalidMacroName
+	}
+	if reserved[name] {
+		return ErrReservedMacroName
+	}
+
+	// Keywords such as "not" or "true" do not parse as an identifier
+	tree, err := parser.Parse(name)
+	if err != nil {
+		return ErrReservedMacroName
+	}
+	if ident, ok := tree.Node.(*ast.IdentifierNode); !ok || ident.Value != name {
+		return ErrReservedMacroName
+	}
+
+	return nil
+}
+
+// reservedNames returns the helper and builtin function names macros cannot take
+func reservedNames() map[string]bool {
+	env := make(map[string]any)
+	addHelperFunctions(context.Background(), env, nil, Lookups{})
+
+	reserved := make(map[string]bool, len(env)+len(builtin.Names))
+	for name := range env {
+		reserved[name] = true
+	}
+	for _, name := range builtin.Names {
+		reserved[name] = true
+	}
+	return reserved
+}
@@ -0,0 +1,24 @@
+package expressions
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+// addSanctionsHelpers registers the sanctions screening helper.
+// When sanctions is nil, sanctionsHit never matches.
+func addSanctionsHelpers(ctx context.Context, env map[string]any, sanctions SanctionsLookup) {
+	// Country is an ISO 3166-1 alpha-2 code or an English name, "" screens against every country
+	env["sanctionsHit"] = func(name, country string) bool {
+		if sanctions == nil || strings.TrimSpace(name) == "" {
+			return false
+		}
+		hit, err := sanctions.SanctionsHit(ctx, name, country)
+		if err != nil {
+			log.Printf("Sanctions screening error: %v", err)
+			return false
+		}
+		return hit
+	}
+}
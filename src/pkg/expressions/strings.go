@@ -0,0 +1,18 @@
+package expressions
+
+import "github.com/algo-shield/algo-shield/src/pkg/fuzzy"
+
+// addStringHelpers registers the string similarity helpers used for name screening
+func addStringHelpers(env map[string]any) {
+	env["levenshtein"] = func(a, b string) int {
+		return fuzzy.Levenshtein(a, b)
+	}
+
+	env["jaroWinkler"] = func(a, b string) float64 {
+		return fuzzy.JaroWinkler(a, b)
+	}
+
+	env["normalizeName"] = func(name string) string {
+		return fuzzy.NormalizeName(name)
+	}
+}
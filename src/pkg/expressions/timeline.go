@@ -0,0 +1,42 @@
+package expressions
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// addTimelineHelpers registers the helpers detecting sequences of events of an entity.
+// When timeline is nil, sequence and followedBy never match.
+func addTimelineHelpers(ctx context.Context, env map[string]any, timeline TimelineLookup) {
+	sequence := func(entity string, types []string, withinSeconds int) bool {
+		if timeline == nil {
+			return false
+		}
+		matched, err := timeline.Sequence(ctx, entity, types, time.Duration(withinSeconds)*time.Second)
+		if err != nil {
+			log.Printf("Timeline sequence error: %v", err)
+			return false
+		}
+		return matched
+	}
+
+	// sequence(account, ["password_change", "new_device_login", "transfer"], 1800)
+	// Array literals are []any in expressions; steps that are not strings never match
+	env["sequence"] = func(entity string, steps []any, withinSeconds int) bool {
+		types := make([]string, 0, len(steps))
+		for _, step := range steps {
+			eventType, ok := step.(string)
+			if !ok {
+				return false
+			}
+			types = append(types, eventType)
+		}
+		return sequence(entity, types, withinSeconds)
+	}
+
+	// followedBy(account, "password_change", "transfer", 1800) is sequence with two steps
+	env["followedBy"] = func(entity, first, then string, withinSeconds int) bool {
+		return sequence(entity, []string{first, then}, withinSeconds)
+	}
+}
@@ -0,0 +1,161 @@
+// Package fuzzy compares names for screening: edit distance, Jaro-Winkler similarity,
+// name normalization and an n-gram index for searching large lists.
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// winklerPrefixScale and winklerMaxPrefix are the standard Jaro-Winkler parameters
+const (
+	winklerPrefixScale = 0.1
+	winklerMaxPrefix   = 4
+)
+
+// foldedLetters spells out letters that do not decompose into a base letter and a diacritic
+var foldedLetters = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+}
+
+// Levenshtein returns the number of single-character insertions, deletions and substitutions
+// needed to turn a into b, counting Unicode characters rather than bytes
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	// Two rows of the distance matrix, sized by the shorter string
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+// Jaro returns the Jaro similarity of two strings, from 0 (nothing in common) to 1 (equal)
+func Jaro(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	window = max(window, 0)
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		for j := max(0, i-window); j < min(len(rb), i+window+1); j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// Count matched characters that appear in a different order
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+}
+
+// JaroWinkler returns the Jaro similarity boosted for strings sharing a prefix of up to 4 characters
+// The result ranges from 0 (nothing in common) to 1 (equal)
+func JaroWinkler(a, b string) float64 {
+	jaro := Jaro(a, b)
+
+	prefix := 0
+	ra, rb := []rune(a), []rune(b)
+	for prefix < min(len(ra), len(rb), winklerMaxPrefix) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*winklerPrefixScale*(1-jaro)
+}
+
+// NormalizeName lowercases a name, strips diacritics, turns punctuation into spaces
+// and collapses whitespace, so "  Müller-Lüdenscheidt, J. " becomes "muller ludenscheidt j"
+func NormalizeName(name string) string {
+	var b strings.Builder
+	b.Grow(len(name))
+
+	space := false
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue // Combining diacritic left over from decomposition
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			r = unicode.ToLower(r)
+			if folded, ok := foldedLetters[r]; ok {
+				b.WriteString(folded)
+			} else {
+				b.WriteRune(r)
+			}
+		case r == '\'' || r == '’':
+			continue // Apostrophes join, so "O'Brien" matches "OBrien"
+		default:
+			space = true
+		}
+	}
+
+	return b.String()
+}
+
+// NameSimilarity returns the Jaro-Winkler similarity of two normalized names,
+// also comparing them with their words sorted so "Putin Vladimir" matches "Vladimir Putin"
+func NameSimilarity(a, b string) float64 {
+	a, b = NormalizeName(a), NormalizeName(b)
+	return max(JaroWinkler(a, b), JaroWinkler(sortWords(a), sortWords(b)))
+}
+
+// sortWords returns the words of a normalized name in alphabetical order
+func sortWords(name string) string {
+	words := strings.Fields(name)
+	slices.Sort(words)
+	return strings.Join(words, " ")
+}
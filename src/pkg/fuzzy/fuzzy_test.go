@@ -0,0 +1,104 @@
+package fuzzy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Levenshtein_WhenStringsDiffer_ThenCountsEdits(t *testing.T) {
+	assert.Equal(t, 0, Levenshtein("putin", "putin"))
+	assert.Equal(t, 3, Levenshtein("kitten", "sitting"))
+	assert.Equal(t, 5, Levenshtein("", "hello"))
+	assert.Equal(t, 1, Levenshtein("müller", "muller"))
+}
+
+func Test_JaroWinkler_WhenKnownPairs_ThenReturnsReferenceScores(t *testing.T) {
+	assert.InDelta(t, 0.961, JaroWinkler("martha", "marhta"), 0.001)
+	assert.InDelta(t, 0.840, JaroWinkler("dwayne", "duane"), 0.001)
+	assert.InDelta(t, 0.813, JaroWinkler("dixon", "dicksonx"), 0.001)
+	assert.Equal(t, 1.0, JaroWinkler("same", "same"))
+	assert.Equal(t, 0.0, JaroWinkler("abc", ""))
+}
+
+func Test_NormalizeName_WhenCaseDiacriticsAndPunctuation_ThenReturnsPlainWords(t *testing.T) {
+	assert.Equal(t, "muller ludenscheidt j", NormalizeName("  Müller-Lüdenscheidt, J. "))
+	assert.Equal(t, "obrien sean", NormalizeName("O'Brien, Seán"))
+	assert.Equal(t, "strasse lodz", NormalizeName("Straße Łódź"))
+	assert.Equal(t, "", NormalizeName(" ,.- "))
+}
+
+func Test_NameSimilarity_WhenWordsReordered_ThenScoresAsEqual(t *testing.T) {
+	assert.Equal(t, 1.0, NameSimilarity("PUTIN, Vladimir", "Vladimir Putin"))
+	assert.Greater(t, NameSimilarity("Vladimir Putin", "Vladimr Putin"), 0.95)
+	assert.Less(t, NameSimilarity("Vladimir Putin", "Angela Merkel"), 0.7)
+}
+
+func Test_Index_Search_WhenSimilarNamesIndexed_ThenReturnsBestFirst(t *testing.T) {
+	idx := NewIndex()
+	for _, name := range []string{"Vladimir Putin", "Vladimir Potanin", "Angela Merkel", "Kim Jong Un"} {
+		idx.Add(name)
+	}
+
+	matches := idx.Search("PUTIN Vladimir", 0.85)
+
+	require.NotEmpty(t, matches)
+	assert.Equal(t, "Vladimir Putin", matches[0].Value)
+	assert.Equal(t, 1.0, matches[0].Score)
+	for _, match := range matches {
+		assert.NotEqual(t, "Angela Merkel", match.Value)
+	}
+	assert.Empty(t, idx.Search("Jane Doe", 0.85))
+	assert.Empty(t, idx.Search("", 0.5))
+}
+
+func Test_Index_Search_WhenLargeIndex_ThenFindsMisspelledName(t *testing.T) {
+	idx := NewIndex()
+	for i := range 50000 {
+		idx.Add(fmt.Sprintf("Company %d Holdings", i))
+	}
+	idx.Add("Kim Jong Un")
+
+	matches := idx.Search("Kim Jong-Eun", 0.85)
+
+	require.Len(t, matches, 1)
+	assert.Equal(t, "Kim Jong Un", matches[0].Value)
+	assert.Equal(t, 50001, idx.Len())
+}
+
+func Test_Index_Search_WhenComparedToLinearScan_ThenReturnsEveryAcceptedName(t *testing.T) {
+	names := []string{
+		"Mohamed Ali", "Muhammad Ali", "Mohammed al Hassan", "Muhamad Hasan", "Mahmoud Abbas",
+		"Vladimir Putin", "Wladimir Putin", "Vladimir Potanin", "Putin Vladimir Vladimirovich",
+		"Kim Jong Un", "Kim Jong-Eun", "Kim Jung Un", "Kim Yo Jong",
+		"Osama bin Laden", "Usama bin Ladin", "Oussama ben Laden",
+		"Alexander Lukashenko", "Aleksandr Lukashenka", "Alyaksandr Lukashenka",
+		"Muammar Gaddafi", "Moammar Qadhafi", "Muammar al-Qaddafi",
+		"Jon Smith", "John Smith", "Jonathan Smyth", "Joan Smit",
+		"Li Wei", "Lee Way", "Wei Li", "Ng", "Al",
+		"Sergei Ivanov", "Sergey Ivanov", "Serguei Ivanoff",
+		"Acme Trading LLC", "ACME Trading Limited", "Acme Holdings",
+	}
+	idx := NewIndex()
+	for _, name := range names {
+		idx.Add(name)
+	}
+	queries := append([]string{"Mohamed", "Muhammad", "Gadafi", "Lukashenko A", "Smith J", "Ivanov"}, names...)
+
+	for _, threshold := range []float64{0.6, 0.7, 0.8, 0.85, 0.9, 0.95} {
+		for _, query := range queries {
+			found := make(map[string]bool)
+			for _, match := range idx.Search(query, threshold) {
+				found[match.Value] = true
+			}
+			for _, name := range names {
+				if NameSimilarity(query, name) >= threshold {
+					assert.True(t, found[name], "%q should find %q at %.2f", query, name, threshold)
+				}
+			}
+		}
+	}
+	assert.NotEmpty(t, idx.Search("Mohamed", 0.8), "transliterations sharing a single trigram are still found")
+}
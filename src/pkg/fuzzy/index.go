@@ -0,0 +1,136 @@
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// gramSize is the length of the n-grams indexed, trigrams balance recall and selectivity for names
+const gramSize = 3
+
+// minIndexedThreshold is the lowest threshold searched through the trigrams
+// Below it, names sharing no trigram with the query score high enough to match, such as
+// "al" and "acme trading llc", so every name is scored
+const minIndexedThreshold = 0.8
+
+// Match is an indexed name similar to a query
+type Match struct {
+	Position int     // Position returned by Add for the name
+	Value    string  // The name as added to the index
+	Score    float64 // NameSimilarity between the query and the name
+}
+
+// Index finds names similar to a query without comparing it to every name
+// Names are normalized and split into trigrams; a query only scores the names sharing one
+// of its trigrams whose length can reach the threshold, or every name for low thresholds.
+// An Index is not safe for concurrent writes, but once built it may be searched concurrently.
+type Index struct {
+	values     []string         // Names as added
+	normalized []string         // NormalizeName of each name
+	grams      map[string][]int // Trigram to the positions of the names containing it
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{grams: make(map[string][]int)}
+}
+
+// Add indexes a name and returns its position, numbered from 0 in the order names are added
+func (idx *Index) Add(value string) int {
+	position := len(idx.values)
+	normalized := NormalizeName(value)
+	idx.values = append(idx.values, value)
+	idx.normalized = append(idx.normalized, normalized)
+
+	for _, gram := range nameGrams(normalized) {
+		idx.grams[gram] = append(idx.grams[gram], position)
+	}
+	return position
+}
+
+// Len returns the number of indexed names
+func (idx *Index) Len() int {
+	return len(idx.values)
+}
+
+// Search returns the names whose NameSimilarity to the query is at least threshold, best first
+// From minIndexedThreshold up, only names sharing a trigram with the query are scored, which
+// leaves out scrambled short names only, such as "abc" and "acb"
+func (idx *Index) Search(query string, threshold float64) []Match {
+	normalized := NormalizeName(query)
+	grams := nameGrams(normalized)
+	if len(grams) == 0 {
+		return nil
+	}
+
+	candidates := make(map[int]bool)
+	if threshold < minIndexedThreshold {
+		for position := range idx.values {
+			candidates[position] = true
+		}
+	} else {
+		for _, gram := range grams {
+			for _, position := range idx.grams[gram] {
+				candidates[position] = true
+			}
+		}
+	}
+
+	// Names sharing few trigrams may still score high, such as "mohamed" and "muhammad",
+	// so every name sharing one is a candidate and only the length bound is applied
+	queryLength := utf8.RuneCountInString(normalized)
+	lengthRatio := minLengthRatio(threshold)
+	sortedQuery := sortWords(normalized)
+	matches := make([]Match, 0)
+	for position := range candidates {
+		candidate := idx.normalized[position]
+		candidateLength := utf8.RuneCountInString(candidate)
+		if float64(min(queryLength, candidateLength)) < lengthRatio*float64(max(queryLength, candidateLength)) {
+			continue
+		}
+		score := max(JaroWinkler(normalized, candidate), JaroWinkler(sortedQuery, sortWords(candidate)))
+		if score >= threshold {
+			matches = append(matches, Match{Position: position, Value: idx.values[position], Score: score})
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b Match) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+	return matches
+}
+
+// minLengthRatio returns the shortest length, relative to the longer name, a name may have
+// to score at least threshold with Jaro-Winkler; 0 when any length may
+// The prefix boost adds at most winklerMaxPrefix*winklerPrefixScale of the missing similarity,
+// which bounds the Jaro similarity needed, and Jaro cannot exceed (shorter/longer + 2) / 3
+func minLengthRatio(threshold float64) float64 {
+	maxBoost := winklerMaxPrefix * winklerPrefixScale
+	minJaro := (threshold - maxBoost) / (1 - maxBoost)
+	return max(0, 3*minJaro-2)
+}
+
+// nameGrams returns the distinct trigrams of each word of a normalized name
+// Words are padded with spaces so short words and word boundaries are indexed too
+func nameGrams(normalized string) []string {
+	seen := make(map[string]bool)
+	grams := make([]string, 0)
+	for _, word := range strings.Fields(normalized) {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+gramSize <= len(runes); i++ {
+			gram := string(runes[i : i+gramSize])
+			if !seen[gram] {
+				seen[gram] = true
+				grams = append(grams, gram)
+			}
+		}
+	}
+	return grams
+}
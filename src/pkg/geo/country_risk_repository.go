@@ -0,0 +1,125 @@
+// Package geo stores the reference data behind the geo expression helpers.
+package geo
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CountryRiskReader defines the interface for loading country risk scores (used by worker)
+type CountryRiskReader interface {
+	// ListCountryRisks returns every configured country, ordered by country code
+	ListCountryRisks(ctx context.Context) ([]models.CountryRisk, error)
+}
+
+// CountryRiskRepository defines the interface for managing country risk scores (used by API)
+type CountryRiskRepository interface {
+	CountryRiskReader
+	// GetCountryRisk returns pgx.ErrNoRows when the country has no score
+	GetCountryRisk(ctx context.Context, code string) (*models.CountryRisk, error)
+	// UpsertCountryRisk creates or replaces the score of a country
+	UpsertCountryRisk(ctx context.Context, risk *models.CountryRisk) error
+	// DeleteCountryRisk returns pgx.ErrNoRows when the country has no score
+	DeleteCountryRisk(ctx context.Context, code string) error
+	// CountryRiskScore returns the score of a country, 0 when it has none
+	CountryRiskScore(ctx context.Context, code string) (int, error)
+}
+
+// PostgresCountryRiskRepository is the PostgreSQL implementation of CountryRiskRepository
+type PostgresCountryRiskRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresCountryRiskRepository creates a new PostgreSQL country risk repository
+func NewPostgresCountryRiskRepository(db *pgxpool.Pool) CountryRiskRepository {
+	return &PostgresCountryRiskRepository{db: db}
+}
+
+func (r *PostgresCountryRiskRepository) ListCountryRisks(ctx context.Context) ([]models.CountryRisk, error) {
+	query := `
+		SELECT country_code, score, notes, updated_at
+		FROM country_risk
+		ORDER BY country_code
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	risks := make([]models.CountryRisk, 0)
+	for rows.Next() {
+		var risk models.CountryRisk
+		if err := rows.Scan(&risk.CountryCode, &risk.Score, &risk.Notes, &risk.UpdatedAt); err != nil {
+			return nil, err
+		}
+		risks = append(risks, risk)
+	}
+
+	return risks, rows.Err()
+}
+
+func (r *PostgresCountryRiskRepository) GetCountryRisk(ctx context.Context, code string) (*models.CountryRisk, error) {
+	query := `
+		SELECT country_code, score, notes, updated_at
+		FROM country_risk
+		WHERE country_code = $1
+	`
+
+	var risk models.CountryRisk
+	err := r.db.QueryRow(ctx, query, NormalizeCountryCode(code)).Scan(&risk.CountryCode, &risk.Score, &risk.Notes, &risk.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &risk, nil
+}
+
+func (r *PostgresCountryRiskRepository) UpsertCountryRisk(ctx context.Context, risk *models.CountryRisk) error {
+	query := `
+		INSERT INTO country_risk (country_code, score, notes, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (country_code) DO UPDATE SET
+			score = EXCLUDED.score,
+			notes = EXCLUDED.notes,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(ctx, query, NormalizeCountryCode(risk.CountryCode), risk.Score, risk.Notes, risk.UpdatedAt)
+	return err
+}
+
+func (r *PostgresCountryRiskRepository) DeleteCountryRisk(ctx context.Context, code string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM country_risk WHERE country_code = $1`, NormalizeCountryCode(code))
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PostgresCountryRiskRepository) CountryRiskScore(ctx context.Context, code string) (int, error) {
+	risk, err := r.GetCountryRisk(ctx, code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return risk.Score, nil
+}
+
+// NormalizeCountryCode upper-cases a country code so lookups are case-insensitive
+func NormalizeCountryCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,146 @@
+package geo
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+)
+
+// ipRange is a network of the IP database as its first and last address
+type ipRange struct {
+	first netip.Addr
+	last  netip.Addr
+	info  models.IPInfo
+}
+
+// IPDatabase maps networks to their country and autonomous system, entirely in memory
+// It is read-only once loaded and safe for concurrent use. A nil database knows no address.
+type IPDatabase struct {
+	ranges []ipRange // Sorted by first address, never overlapping
+}
+
+// LoadIPDatabase reads an IP database from a CSV file, see ParseIPDatabase for the format
+func LoadIPDatabase(path string) (*IPDatabase, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	db, err := ParseIPDatabase(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+// ParseIPDatabase reads CSV rows of network, country_code, asn and organization
+// network is in CIDR notation; the other columns are optional and may be empty.
+// A first row whose network is "network" is treated as a header. Networks must not overlap.
+func ParseIPDatabase(r io.Reader) (*IPDatabase, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	ranges := make([]ipRange, 0)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		network := strings.TrimSpace(record[0])
+		if line == 1 && strings.EqualFold(network, "network") {
+			continue
+		}
+
+		prefix, err := models.ParseIPNetwork(network)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		var info models.IPInfo
+		if len(record) > 1 {
+			info.CountryCode = NormalizeCountryCode(record[1])
+		}
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			asn, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(record[2])), "AS"))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid ASN %q", line, record[2])
+			}
+			info.ASN = asn
+		}
+		if len(record) > 3 {
+			info.Organization = strings.TrimSpace(record[3])
+		}
+
+		ranges = append(ranges, ipRange{first: prefix.Addr(), last: lastAddr(prefix), info: info})
+	}
+
+	slices.SortFunc(ranges, func(a, b ipRange) int {
+		return a.first.Compare(b.first)
+	})
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].first.Compare(ranges[i-1].last) <= 0 {
+			return nil, fmt.Errorf("network starting at %s overlaps the one starting at %s", ranges[i].first, ranges[i-1].first)
+		}
+	}
+
+	return &IPDatabase{ranges: ranges}, nil
+}
+
+// Len returns the number of networks in the database
+func (d *IPDatabase) Len() int {
+	if d == nil {
+		return 0
+	}
+	return len(d.ranges)
+}
+
+// LookupIP returns what the database knows about an address
+// Returns false for invalid addresses and addresses outside every network
+func (d *IPDatabase) LookupIP(ip string) (models.IPInfo, bool) {
+	if d == nil {
+		return models.IPInfo{}, false
+	}
+
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return models.IPInfo{}, false
+	}
+	addr = addr.Unmap().WithZone("")
+
+	// The candidate is the last network starting at or before the address
+	i, found := slices.BinarySearchFunc(d.ranges, addr, func(r ipRange, target netip.Addr) int {
+		return r.first.Compare(target)
+	})
+	if !found {
+		i--
+	}
+	if i < 0 || d.ranges[i].last.Compare(addr) < 0 {
+		return models.IPInfo{}, false
+	}
+	return d.ranges[i].info, true
+}
+
+// lastAddr returns the highest address of a network
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	bytes := prefix.Addr().AsSlice()
+	for bit := prefix.Bits(); bit < len(bytes)*8; bit++ {
+		bytes[bit/8] |= 0x80 >> (bit % 8)
+	}
+	addr, _ := netip.AddrFromSlice(bytes)
+	return addr
+}
@@ -0,0 +1,83 @@
+package geo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIPDatabase = `network,country_code,asn,organization
+203.0.113.0/24,nl,AS64500,Example Hosting
+198.51.100.0/25,US,64501,
+2001:db8::/32,DE,64502,Example IPv6
+192.0.2.7,BR,,
+`
+
+func Test_ParseIPDatabase_WhenAddressInNetwork_ThenReturnsInfo(t *testing.T) {
+	db, err := ParseIPDatabase(strings.NewReader(testIPDatabase))
+	require.NoError(t, err)
+	assert.Equal(t, 4, db.Len())
+
+	tests := []struct {
+		ip       string
+		expected models.IPInfo
+	}{
+		{"203.0.113.0", models.IPInfo{CountryCode: "NL", ASN: 64500, Organization: "Example Hosting"}},
+		{"203.0.113.255", models.IPInfo{CountryCode: "NL", ASN: 64500, Organization: "Example Hosting"}},
+		{"::ffff:198.51.100.127", models.IPInfo{CountryCode: "US", ASN: 64501}},
+		{"2001:db8:ffff::1", models.IPInfo{CountryCode: "DE", ASN: 64502, Organization: "Example IPv6"}},
+		{"192.0.2.7", models.IPInfo{CountryCode: "BR"}},
+	}
+	for _, tt := range tests {
+		info, ok := db.LookupIP(tt.ip)
+
+		assert.True(t, ok, tt.ip)
+		assert.Equal(t, tt.expected, info, tt.ip)
+	}
+}
+
+func Test_ParseIPDatabase_WhenAddressOutsideNetworks_ThenReturnsFalse(t *testing.T) {
+	db, err := ParseIPDatabase(strings.NewReader(testIPDatabase))
+	require.NoError(t, err)
+
+	for _, ip := range []string{"198.51.100.128", "192.0.2.8", "10.0.0.1", "2001:db9::1", "0.0.0.0", "not-an-ip"} {
+		_, ok := db.LookupIP(ip)
+
+		assert.False(t, ok, ip)
+	}
+}
+
+func Test_ParseIPDatabase_WhenNetworksOverlap_ThenReturnsError(t *testing.T) {
+	_, err := ParseIPDatabase(strings.NewReader("10.0.0.0/8,US\n10.1.0.0/16,CA\n"))
+
+	assert.ErrorContains(t, err, "overlaps")
+}
+
+func Test_ParseIPDatabase_WhenNetworkInvalid_ThenReturnsLine(t *testing.T) {
+	_, err := ParseIPDatabase(strings.NewReader("10.0.0.0/8,US\n10.0.0/8,CA\n"))
+
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func Test_LoadIPDatabase_WhenFileExists_ThenLoadsNetworks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ip.csv")
+	require.NoError(t, os.WriteFile(path, []byte(testIPDatabase), 0o600))
+
+	db, err := LoadIPDatabase(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, db.Len())
+}
+
+func Test_IPDatabase_LookupIP_WhenNil_ThenReturnsFalse(t *testing.T) {
+	var db *IPDatabase
+
+	_, ok := db.LookupIP("203.0.113.1")
+
+	assert.False(t, ok)
+}
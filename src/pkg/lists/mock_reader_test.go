@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/lists/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/lists/repository.go -destination=src/pkg/lists/mock_reader_test.go -package=lists -exclude_interfaces=Repository
+//
+
+// Package lists is a generated GoMock package.
+package lists
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReader is a mock of Reader interface.
+type MockReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockReaderMockRecorder is the mock recorder for MockReader.
+type MockReaderMockRecorder struct {
+	mock *MockReader
+}
+
+// NewMockReader creates a new mock instance.
+func NewMockReader(ctrl *gomock.Controller) *MockReader {
+	mock := &MockReader{ctrl: ctrl}
+	mock.recorder = &MockReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReader) EXPECT() *MockReaderMockRecorder {
+	return m.recorder
+}
+
+// ActiveEntries mocks base method.
+func (m *MockReader) ActiveEntries(ctx context.Context, listID uuid.UUID) ([]models.ListEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveEntries", ctx, listID)
+	ret0, _ := ret[0].([]models.ListEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveEntries indicates an expected call of ActiveEntries.
+func (mr *MockReaderMockRecorder) ActiveEntries(ctx, listID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveEntries", reflect.TypeOf((*MockReader)(nil).ActiveEntries), ctx, listID)
+}
+
+// GetList mocks base method.
+func (m *MockReader) GetList(ctx context.Context, id uuid.UUID) (*models.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, id)
+	ret0, _ := ret[0].(*models.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockReaderMockRecorder) GetList(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockReader)(nil).GetList), ctx, id)
+}
+
+// ListLists mocks base method.
+func (m *MockReader) ListLists(ctx context.Context) ([]models.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLists", ctx)
+	ret0, _ := ret[0].([]models.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLists indicates an expected call of ListLists.
+func (mr *MockReaderMockRecorder) ListLists(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLists", reflect.TypeOf((*MockReader)(nil).ListLists), ctx)
+}
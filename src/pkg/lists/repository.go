@@ -0,0 +1,263 @@
+// Package lists stores the managed lists checked by the inList expression helper
+// and caches them in memory for evaluating rules.
+package lists
+
+import (
+	"context"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// upsertBatchSize bounds the number of entries written per statement
+const upsertBatchSize = 5000
+
+// Reader defines the interface for loading lists (used by ListService)
+type Reader interface {
+	// ListLists returns every list with its count of unexpired entries, ordered by name
+	ListLists(ctx context.Context) ([]models.List, error)
+	// GetList returns pgx.ErrNoRows when the list does not exist
+	GetList(ctx context.Context, id uuid.UUID) (*models.List, error)
+	// ActiveEntries returns every unexpired entry of a list
+	ActiveEntries(ctx context.Context, listID uuid.UUID) ([]models.ListEntry, error)
+}
+
+// Repository defines the interface for managing lists (used by API)
+// Every change is announced on models.ListInvalidateChannel
+type Repository interface {
+	Reader
+	// GetListByName returns pgx.ErrNoRows when no list has the name
+	GetListByName(ctx context.Context, name string) (*models.List, error)
+	CreateList(ctx context.Context, list *models.List) error
+	// UpdateList renames or redescribes a list, its type cannot change
+	UpdateList(ctx context.Context, list *models.List) error
+	// DeleteList removes a list and its entries, returns pgx.ErrNoRows when it does not exist
+	DeleteList(ctx context.Context, id uuid.UUID) error
+	// ListEntries pages through the unexpired entries of a list, ordered by value
+	ListEntries(ctx context.Context, listID uuid.UUID, limit, offset int) ([]models.ListEntry, error)
+	// UpsertEntries adds entries, replacing the note and expiry of values already in the list
+	// Values must already be normalized for the list type
+	UpsertEntries(ctx context.Context, listID uuid.UUID, entries []models.ListEntry) error
+	// DeleteEntry returns pgx.ErrNoRows when the value is not in the list
+	DeleteEntry(ctx context.Context, listID uuid.UUID, value string) error
+}
+
+// PostgresRepository is the PostgreSQL implementation of Repository
+type PostgresRepository struct {
+	db    *pgxpool.Pool
+	redis *redis.Client
+}
+
+// NewPostgresRepository creates a new PostgreSQL list repository
+// redis may be nil, in which case workers only see changes after a restart
+func NewPostgresRepository(db *pgxpool.Pool, redis *redis.Client) *PostgresRepository {
+	return &PostgresRepository{
+		db:    db,
+		redis: redis,
+	}
+}
+
+// publishInvalidation announces a list change to workers
+func (r *PostgresRepository) publishInvalidation(ctx context.Context, listID uuid.UUID) {
+	if r.redis != nil {
+		r.redis.Publish(ctx, models.ListInvalidateChannel, listID.String())
+	}
+}
+
+const listColumns = `l.id, l.name, l.description, l.type, l.created_at, l.updated_at,
+		       (SELECT COUNT(*) FROM list_entries e WHERE e.list_id = l.id AND (e.expires_at IS NULL OR e.expires_at > NOW()))`
+
+func (r *PostgresRepository) ListLists(ctx context.Context) ([]models.List, error) {
+	query := `SELECT ` + listColumns + ` FROM lists l ORDER BY l.name`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := make([]models.List, 0)
+	for rows.Next() {
+		list, err := scanList(rows)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, *list)
+	}
+
+	return lists, rows.Err()
+}
+
+func (r *PostgresRepository) GetList(ctx context.Context, id uuid.UUID) (*models.List, error) {
+	query := `SELECT ` + listColumns + ` FROM lists l WHERE l.id = $1`
+
+	return scanList(r.db.QueryRow(ctx, query, id))
+}
+
+func (r *PostgresRepository) GetListByName(ctx context.Context, name string) (*models.List, error) {
+	query := `SELECT ` + listColumns + ` FROM lists l WHERE l.name = $1`
+
+	return scanList(r.db.QueryRow(ctx, query, name))
+}
+
+func (r *PostgresRepository) ActiveEntries(ctx context.Context, listID uuid.UUID) ([]models.ListEntry, error) {
+	query := `
+		SELECT value, note, expires_at, created_at
+		FROM list_entries
+		WHERE list_id = $1
+		AND (expires_at IS NULL OR expires_at > NOW())
+	`
+
+	return r.queryEntries(ctx, query, listID)
+}
+
+func (r *PostgresRepository) CreateList(ctx context.Context, list *models.List) error {
+	query := `
+		INSERT INTO lists (id, name, description, type, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Exec(ctx, query, list.ID, list.Name, list.Description, list.Type, list.CreatedAt, list.UpdatedAt)
+	if err == nil {
+		r.publishInvalidation(ctx, list.ID)
+	}
+
+	return err
+}
+
+func (r *PostgresRepository) UpdateList(ctx context.Context, list *models.List) error {
+	query := `
+		UPDATE lists
+		SET name = $2, description = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, list.ID, list.Name, list.Description, list.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	r.publishInvalidation(ctx, list.ID)
+	return nil
+}
+
+func (r *PostgresRepository) DeleteList(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM lists WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	r.publishInvalidation(ctx, id)
+	return nil
+}
+
+func (r *PostgresRepository) ListEntries(ctx context.Context, listID uuid.UUID, limit, offset int) ([]models.ListEntry, error) {
+	query := `
+		SELECT value, note, expires_at, created_at
+		FROM list_entries
+		WHERE list_id = $1
+		AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY value
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryEntries(ctx, query, listID, limit, offset)
+}
+
+func (r *PostgresRepository) UpsertEntries(ctx context.Context, listID uuid.UUID, entries []models.ListEntry) error {
+	query := `
+		INSERT INTO list_entries (list_id, value, note, expires_at, created_at)
+		SELECT $1, value, note, expires_at, $5
+		FROM unnest($2::text[], $3::text[], $4::timestamptz[]) AS t(value, note, expires_at)
+		ON CONFLICT (list_id, value) DO UPDATE SET
+			note = EXCLUDED.note,
+			expires_at = EXCLUDED.expires_at
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	now := time.Now()
+	for start := 0; start < len(entries); start += upsertBatchSize {
+		batch := entries[start:min(start+upsertBatchSize, len(entries))]
+
+		values := make([]string, len(batch))
+		notes := make([]string, len(batch))
+		expiries := make([]*time.Time, len(batch))
+		for i, entry := range batch {
+			values[i], notes[i], expiries[i] = entry.Value, entry.Note, entry.ExpiresAt
+		}
+
+		if _, err := tx.Exec(ctx, query, listID, values, notes, expiries, now); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE lists SET updated_at = $2 WHERE id = $1`, listID, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	r.publishInvalidation(ctx, listID)
+	return nil
+}
+
+func (r *PostgresRepository) DeleteEntry(ctx context.Context, listID uuid.UUID, value string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM list_entries WHERE list_id = $1 AND value = $2`, listID, value)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	r.publishInvalidation(ctx, listID)
+	return nil
+}
+
+// queryEntries runs a query selecting value, note, expires_at and created_at
+func (r *PostgresRepository) queryEntries(ctx context.Context, query string, args ...any) ([]models.ListEntry, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.ListEntry, 0)
+	for rows.Next() {
+		var entry models.ListEntry
+		if err := rows.Scan(&entry.Value, &entry.Note, &entry.ExpiresAt, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// scanList scans a row selected with listColumns
+func scanList(row pgx.Row) (*models.List, error) {
+	var list models.List
+	err := row.Scan(&list.ID, &list.Name, &list.Description, &list.Type, &list.CreatedAt, &list.UpdatedAt, &list.EntryCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
@@ -0,0 +1,319 @@
+package lists
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/fuzzy"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// cachedList is a list with its unexpired entries, keyed by normalized value
+type cachedList struct {
+	id      uuid.UUID
+	name    string
+	typ     models.ListType
+	entries map[string]*time.Time // Expiry of each entry, nil when it never expires
+
+	// Distinct prefix lengths of the networks in an IP list, so an address is
+	// matched with one map lookup per length instead of a scan of every network
+	prefixLengths []int
+
+	// Built on the first fuzzyInList lookup, most lists are never searched fuzzily
+	nameIndexOnce sync.Once
+	nameIndex     *fuzzy.Index
+}
+
+// ListService keeps managed lists in memory so inList never queries the database
+// Workers and the API rule tester share it, so both match lists the same way
+// Lists are loaded at startup or on first use, and reloaded one at a time when a change
+// is announced. Uses sync.RWMutex for thread-safe reads and writes. A nil service contains nothing.
+type ListService struct {
+	repo  Reader // Only needs read access, not full Repository
+	redis *redis.Client
+
+	loadMu sync.Mutex // Serializes loads so concurrent first lookups load the lists once
+	mu     sync.RWMutex
+	loaded bool
+	byID   map[uuid.UUID]*cachedList
+	byName map[string]*cachedList
+}
+
+// NewListService creates a new list cache with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewListService(repo Reader, redisClient *redis.Client) *ListService {
+	return &ListService{
+		repo:   repo,
+		redis:  redisClient,
+		byID:   make(map[uuid.UUID]*cachedList),
+		byName: make(map[string]*cachedList),
+	}
+}
+
+// LoadLists loads every list and its unexpired entries into the cache
+// On error the previously loaded lists are kept
+func (s *ListService) LoadLists(ctx context.Context) error {
+	s.loadMu.Lock()
+	defer s.loadMu.Unlock()
+
+	return s.loadLists(ctx)
+}
+
+// loadLists loads and swaps every list, must be called with loadMu held
+func (s *ListService) loadLists(ctx context.Context) error {
+	all, err := s.repo.ListLists(ctx)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uuid.UUID]*cachedList, len(all))
+	byName := make(map[string]*cachedList, len(all))
+	for i := range all {
+		cached, err := s.load(ctx, &all[i])
+		if err != nil {
+			return err
+		}
+		byID[cached.id] = cached
+		byName[cached.name] = cached
+	}
+
+	s.mu.Lock()
+	s.byID, s.byName, s.loaded = byID, byName, true
+	s.mu.Unlock()
+
+	log.Printf("Loaded %d lists into cache", len(all))
+	return nil
+}
+
+// InvalidateList reloads a list from the repository, removing it from the cache if it no longer exists
+// On other errors the cached copy is kept
+func (s *ListService) InvalidateList(ctx context.Context, id uuid.UUID) {
+	// Reload from database outside the lock to keep readers unblocked
+	list, err := s.repo.GetList(ctx, id)
+	var cached *cachedList
+	if err == nil {
+		cached, err = s.load(ctx, list)
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("Failed to reload list %s, keeping cached entries: %v", id, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop the old name too, the list may have been renamed
+	if previous, ok := s.byID[id]; ok {
+		delete(s.byName, previous.name)
+		delete(s.byID, id)
+	}
+
+	if cached == nil {
+		log.Printf("List %s was deleted", id)
+		return
+	}
+	s.byID[id] = cached
+	s.byName[cached.name] = cached
+	log.Printf("Reloaded list %s (%d entries)", cached.name, len(cached.entries))
+}
+
+// list returns the named list, loading every list on first use
+// Unknown lists are returned as nil
+func (s *ListService) list(ctx context.Context, name string) (*cachedList, error) {
+	s.mu.RLock()
+	loaded := s.loaded
+	cached := s.byName[name]
+	s.mu.RUnlock()
+	if loaded {
+		return cached, nil
+	}
+
+	s.loadMu.Lock()
+	defer s.loadMu.Unlock()
+
+	// Another caller may have loaded them while we waited
+	s.mu.RLock()
+	loaded = s.loaded
+	s.mu.RUnlock()
+	if !loaded {
+		if err := s.loadLists(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.byName[name], nil
+}
+
+// Contains reports whether the named list has an unexpired entry for the value
+// Unknown lists and values invalid for the list type are not contained
+func (s *ListService) Contains(ctx context.Context, listName, value string) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+
+	cached, err := s.list(ctx, listName)
+	if err != nil || cached == nil {
+		return false, err
+	}
+
+	normalized, err := models.NormalizeListValue(cached.typ, value)
+	if err != nil {
+		return false, nil
+	}
+
+	return cached.has(normalized, time.Now()), nil
+}
+
+// ContainsIP reports whether the named IP list has the address or a network including it
+// Unknown lists, lists of other types and invalid addresses are not contained
+func (s *ListService) ContainsIP(ctx context.Context, listName, ip string) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+
+	cached, err := s.list(ctx, listName)
+	if err != nil || cached == nil || cached.typ != models.ListTypeIP {
+		return false, err
+	}
+
+	network, err := models.ParseIPNetwork(ip)
+	if err != nil || !network.IsSingleIP() {
+		return false, nil
+	}
+	addr := network.Addr()
+
+	now := time.Now()
+	if cached.has(addr.String(), now) {
+		return true, nil
+	}
+	for _, bits := range cached.prefixLengths {
+		prefix, err := addr.Prefix(bits)
+		if err != nil {
+			continue // IPv6 length for an IPv4 address
+		}
+		if cached.has(prefix.String(), now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FuzzyContains reports whether the named list has an unexpired entry whose
+// fuzzy.NameSimilarity to the value is at least threshold
+// Unknown lists are not contained
+func (s *ListService) FuzzyContains(ctx context.Context, listName, value string, threshold float64) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+
+	cached, err := s.list(ctx, listName)
+	if err != nil || cached == nil {
+		return false, err
+	}
+
+	now := time.Now()
+	for _, match := range cached.names().Search(value, threshold) {
+		if cached.has(match.Value, now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// names returns the fuzzy index of the list's entries, building it on first use
+func (c *cachedList) names() *fuzzy.Index {
+	c.nameIndexOnce.Do(func() {
+		c.nameIndex = fuzzy.NewIndex()
+		for value := range c.entries {
+			c.nameIndex.Add(value)
+		}
+	})
+	return c.nameIndex
+}
+
+// has reports whether the list has an unexpired entry for a normalized value
+// Entries are never mutated after loading, the map is replaced on reload
+func (c *cachedList) has(value string, now time.Time) bool {
+	expiresAt, ok := c.entries[value]
+	return ok && (expiresAt == nil || expiresAt.After(now))
+}
+
+// SubscribeToInvalidations subscribes to Redis pub/sub for list change events
+// This should be called in a goroutine
+func (s *ListService) SubscribeToInvalidations(ctx context.Context) {
+	if s.redis == nil {
+		log.Println("Redis not available, list invalidation subscription disabled")
+		return
+	}
+
+	pubsub := s.redis.Subscribe(ctx, models.ListInvalidateChannel)
+	defer func() {
+		if err := pubsub.Close(); err != nil {
+			log.Printf("Error closing list invalidation subscription: %v", err)
+		}
+	}()
+
+	log.Println("Subscribed to list invalidation channel")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("List invalidation subscription stopped")
+			return
+		default:
+			msg, err := pubsub.ReceiveMessage(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return // Context cancelled
+				}
+				log.Printf("Error receiving list invalidation message: %v", err)
+				continue
+			}
+
+			listID, err := uuid.Parse(msg.Payload)
+			if err != nil {
+				log.Printf("Invalid list ID in invalidation message: %s", msg.Payload)
+				continue
+			}
+
+			s.InvalidateList(ctx, listID)
+		}
+	}
+}
+
+// load fetches the unexpired entries of a list
+func (s *ListService) load(ctx context.Context, list *models.List) (*cachedList, error) {
+	entries, err := s.repo.ActiveEntries(ctx, list.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := &cachedList{
+		id:      list.ID,
+		name:    list.Name,
+		typ:     list.Type,
+		entries: make(map[string]*time.Time, len(entries)),
+	}
+	lengths := make(map[int]bool)
+	for _, entry := range entries {
+		cached.entries[entry.Value] = entry.ExpiresAt
+		if list.Type == models.ListTypeIP {
+			if prefix, err := netip.ParsePrefix(entry.Value); err == nil {
+				lengths[prefix.Bits()] = true
+			}
+		}
+	}
+	for bits := range lengths {
+		cached.prefixLengths = append(cached.prefixLengths, bits)
+	}
+	return cached, nil
+}
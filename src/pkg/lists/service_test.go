@@ -0,0 +1,220 @@
+package lists
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func loadedService(t *testing.T, repo *MockReader, list models.List, entries []models.ListEntry) *ListService {
+	t.Helper()
+	repo.EXPECT().ListLists(gomock.Any()).Return([]models.List{list}, nil)
+	repo.EXPECT().ActiveEntries(gomock.Any(), list.ID).Return(entries, nil)
+
+	service := NewListService(repo, nil)
+	require.NoError(t, service.LoadLists(context.Background()))
+	return service
+}
+
+func Test_ListService_Contains_WhenValueNeedsNormalizing_ThenMatchesEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	list := models.List{ID: uuid.New(), Name: "blocked-emails", Type: models.ListTypeEmail}
+	service := loadedService(t, NewMockReader(ctrl), list, []models.ListEntry{{Value: "fraud@example.com"}})
+
+	contains, err := service.Contains(context.Background(), "blocked-emails", " Fraud@Example.COM")
+	require.NoError(t, err)
+	assert.True(t, contains)
+
+	contains, err = service.Contains(context.Background(), "unknown", "fraud@example.com")
+	require.NoError(t, err)
+	assert.False(t, contains)
+}
+
+func Test_ListService_Contains_WhenEntryExpiredSinceLoad_ThenReturnsFalse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expired := time.Now().Add(-time.Second)
+	list := models.List{ID: uuid.New(), Name: "watch", Type: models.ListTypeAccount}
+	service := loadedService(t, NewMockReader(ctrl), list, []models.ListEntry{{Value: "acc-1", ExpiresAt: &expired}})
+
+	contains, err := service.Contains(context.Background(), "watch", "acc-1")
+
+	require.NoError(t, err)
+	assert.False(t, contains)
+}
+
+func Test_ListService_InvalidateList_WhenRenamed_ThenOnlyNewNameMatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockReader(ctrl)
+	list := models.List{ID: uuid.New(), Name: "old", Type: models.ListTypeCountry}
+	service := loadedService(t, repo, list, []models.ListEntry{{Value: "KP"}})
+
+	renamed := list
+	renamed.Name = "new"
+	repo.EXPECT().GetList(gomock.Any(), list.ID).Return(&renamed, nil)
+	repo.EXPECT().ActiveEntries(gomock.Any(), list.ID).Return([]models.ListEntry{{Value: "KP"}, {Value: "IR"}}, nil)
+
+	service.InvalidateList(context.Background(), list.ID)
+
+	contains, _ := service.Contains(context.Background(), "old", "KP")
+	assert.False(t, contains)
+	contains, _ = service.Contains(context.Background(), "new", "ir")
+	assert.True(t, contains)
+}
+
+func Test_ListService_InvalidateList_WhenDeleted_ThenRemovesList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockReader(ctrl)
+	list := models.List{ID: uuid.New(), Name: "blocked", Type: models.ListTypeAccount}
+	service := loadedService(t, repo, list, []models.ListEntry{{Value: "acc-1"}})
+
+	repo.EXPECT().GetList(gomock.Any(), list.ID).Return(nil, pgx.ErrNoRows)
+
+	service.InvalidateList(context.Background(), list.ID)
+
+	contains, _ := service.Contains(context.Background(), "blocked", "acc-1")
+	assert.False(t, contains)
+}
+
+func Test_ListService_InvalidateList_WhenReloadFails_ThenKeepsCachedEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockReader(ctrl)
+	list := models.List{ID: uuid.New(), Name: "blocked", Type: models.ListTypeAccount}
+	service := loadedService(t, repo, list, []models.ListEntry{{Value: "acc-1"}})
+
+	repo.EXPECT().GetList(gomock.Any(), list.ID).Return(&list, nil)
+	repo.EXPECT().ActiveEntries(gomock.Any(), list.ID).Return(nil, errors.New("connection reset"))
+
+	service.InvalidateList(context.Background(), list.ID)
+
+	contains, _ := service.Contains(context.Background(), "blocked", "acc-1")
+	assert.True(t, contains)
+}
+
+func Test_ListService_Contains_WhenNil_ThenReturnsFalse(t *testing.T) {
+	var service *ListService
+
+	contains, err := service.Contains(context.Background(), "blocked", "acc-1")
+
+	require.NoError(t, err)
+	assert.False(t, contains)
+}
+
+func Test_ListService_ContainsIP_WhenNetworkEntries_ThenMatchesAddressesInside(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	list := models.List{ID: uuid.New(), Name: "hosting", Type: models.ListTypeIP}
+	service := loadedService(t, NewMockReader(ctrl), list, []models.ListEntry{
+		{Value: "10.0.0.0/8"},
+		{Value: "2001:db8::/32"},
+		{Value: "192.0.2.7"},
+	})
+
+	for _, ip := range []string{"10.200.0.1", "::ffff:10.0.0.1", "2001:db8::abcd", "192.0.2.7"} {
+		contains, err := service.ContainsIP(context.Background(), "hosting", ip)
+
+		require.NoError(t, err)
+		assert.True(t, contains, ip)
+	}
+	for _, ip := range []string{"11.0.0.1", "192.0.2.8", "2001:db9::1", "10.0.0.0/8", "garbage"} {
+		contains, err := service.ContainsIP(context.Background(), "hosting", ip)
+
+		require.NoError(t, err)
+		assert.False(t, contains, ip)
+	}
+}
+
+func Test_ListService_ContainsIP_WhenListNotIPType_ThenReturnsFalse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	list := models.List{ID: uuid.New(), Name: "accounts", Type: models.ListTypeAccount}
+	service := loadedService(t, NewMockReader(ctrl), list, []models.ListEntry{{Value: "10.0.0.1"}})
+
+	contains, err := service.ContainsIP(context.Background(), "accounts", "10.0.0.1")
+
+	require.NoError(t, err)
+	assert.False(t, contains)
+}
+
+func Test_ListService_FuzzyContains_WhenNameMisspelled_ThenMatchesAboveThreshold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expired := time.Now().Add(-time.Second)
+	list := models.List{ID: uuid.New(), Name: "pep", Type: models.ListTypeName}
+	service := loadedService(t, NewMockReader(ctrl), list, []models.ListEntry{
+		{Value: "vladimir putin"},
+		{Value: "angela merkel", ExpiresAt: &expired},
+	})
+
+	contains, err := service.FuzzyContains(context.Background(), "pep", "PUTIN, Vladimr", 0.9)
+	require.NoError(t, err)
+	assert.True(t, contains)
+
+	contains, err = service.FuzzyContains(context.Background(), "pep", "Angela Merkel", 0.9)
+	require.NoError(t, err)
+	assert.False(t, contains)
+
+	contains, err = service.FuzzyContains(context.Background(), "pep", "John Smith", 0.9)
+	require.NoError(t, err)
+	assert.False(t, contains)
+}
+
+func Test_ListService_FuzzyContains_WhenNotLoaded_ThenLoadsListsOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	list := models.List{ID: uuid.New(), Name: "pep", Type: models.ListTypeName}
+	repo := NewMockReader(ctrl)
+	repo.EXPECT().ListLists(gomock.Any()).Return([]models.List{list}, nil).Times(1)
+	repo.EXPECT().ActiveEntries(gomock.Any(), list.ID).Return([]models.ListEntry{{Value: "vladimir putin"}}, nil).Times(1)
+	service := NewListService(repo, nil)
+
+	first, err := service.FuzzyContains(context.Background(), "pep", "Vladimir Putin", 0.9)
+	require.NoError(t, err)
+	second, err := service.FuzzyContains(context.Background(), "pep", "Vladimr Putin", 0.9)
+	require.NoError(t, err)
+
+	assert.True(t, first)
+	assert.True(t, second)
+}
+
+func Test_ListService_Contains_WhenFirstLoadFails_ThenReturnsErrorAndRetries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	list := models.List{ID: uuid.New(), Name: "blocked", Type: models.ListTypeAccount}
+	repo := NewMockReader(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().ListLists(gomock.Any()).Return(nil, errors.New("connection refused")),
+		repo.EXPECT().ListLists(gomock.Any()).Return([]models.List{list}, nil),
+	)
+	repo.EXPECT().ActiveEntries(gomock.Any(), list.ID).Return([]models.ListEntry{{Value: "acc-1"}}, nil)
+	service := NewListService(repo, nil)
+
+	_, err := service.Contains(context.Background(), "blocked", "acc-1")
+	require.Error(t, err)
+
+	contains, err := service.Contains(context.Background(), "blocked", "acc-1")
+	require.NoError(t, err)
+	assert.True(t, contains)
+}
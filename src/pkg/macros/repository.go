@@ -0,0 +1,262 @@
+// Package macros stores the named expression snippets that rule expressions reference by name.
+package macros
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// Reader defines the interface for loading macros (used by worker and rule validation)
+type Reader interface {
+	// ListMacros returns every macro, ordered by name
+	ListMacros(ctx context.Context) ([]models.Macro, error)
+}
+
+// Repository defines the interface for managing macros (used by API)
+// Every change is announced on models.MacroInvalidateChannel
+type Repository interface {
+	Reader
+	// GetMacro returns pgx.ErrNoRows when the macro does not exist
+	GetMacro(ctx context.Context, id uuid.UUID) (*models.Macro, error)
+	// CreateMacro creates a macro together with its first version
+	CreateMacro(ctx context.Context, macro *models.Macro) error
+	// UpdateMacro stores the new content as the next version, returns pgx.ErrNoRows when the macro does not exist
+	UpdateMacro(ctx context.Context, macro *models.Macro) error
+	// DeleteMacro returns pgx.ErrNoRows when the macro does not exist, its versions are kept
+	DeleteMacro(ctx context.Context, id uuid.UUID) error
+	// ListMacroVersions retrieves all versions of a macro, newest first
+	ListMacroVersions(ctx context.Context, macroID uuid.UUID) ([]models.MacroVersion, error)
+	// GetMacroVersion returns pgx.ErrNoRows when the version does not exist
+	GetMacroVersion(ctx context.Context, macroID uuid.UUID, version int) (*models.MacroVersion, error)
+}
+
+// PostgresRepository is the PostgreSQL implementation of Repository
+type PostgresRepository struct {
+	db    *pgxpool.Pool
+	redis *redis.Client
+}
+
+// NewPostgresRepository creates a new PostgreSQL macro repository
+// redis may be nil, in which case workers only see changes after a restart
+func NewPostgresRepository(db *pgxpool.Pool, redis *redis.Client) *PostgresRepository {
+	return &PostgresRepository{
+		db:    db,
+		redis: redis,
+	}
+}
+
+// publishInvalidation announces a macro change to workers
+func (r *PostgresRepository) publishInvalidation(ctx context.Context, macroID uuid.UUID) {
+	if r.redis != nil {
+		r.redis.Publish(ctx, models.MacroInvalidateChannel, macroID.String())
+	}
+}
+
+const macroColumns = `id, name, description, expression, version, version_id, created_at, updated_at`
+
+func (r *PostgresRepository) ListMacros(ctx context.Context) ([]models.Macro, error) {
+	query := `SELECT ` + macroColumns + ` FROM macros ORDER BY name`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	macros := make([]models.Macro, 0)
+	for rows.Next() {
+		macro, err := scanMacro(rows)
+		if err != nil {
+			return nil, err
+		}
+		macros = append(macros, *macro)
+	}
+
+	return macros, rows.Err()
+}
+
+func (r *PostgresRepository) GetMacro(ctx context.Context, id uuid.UUID) (*models.Macro, error) {
+	query := `SELECT ` + macroColumns + ` FROM macros WHERE id = $1`
+
+	return scanMacro(r.db.QueryRow(ctx, query, id))
+}
+
+func (r *PostgresRepository) CreateMacro(ctx context.Context, macro *models.Macro) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	macro.Version = 1
+	macro.VersionID = uuid.New()
+	if err := insertVersion(ctx, tx, macro); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO macros (id, name, description, expression, version, version_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = tx.Exec(ctx, query,
+		macro.ID, macro.Name, macro.Description, macro.Expression,
+		macro.Version, macro.VersionID, macro.CreatedAt, macro.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	r.publishInvalidation(ctx, macro.ID)
+	return nil
+}
+
+func (r *PostgresRepository) UpdateMacro(ctx context.Context, macro *models.Macro) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Lock the macro so concurrent updates get consecutive version numbers
+	var currentVersion int
+	err = tx.QueryRow(ctx, `SELECT version, created_at FROM macros WHERE id = $1 FOR UPDATE`, macro.ID).
+		Scan(&currentVersion, &macro.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	macro.Version = currentVersion + 1
+	macro.VersionID = uuid.New()
+	if err := insertVersion(ctx, tx, macro); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE macros
+		SET name = $2, description = $3, expression = $4, version = $5, version_id = $6, updated_at = $7
+		WHERE id = $1
+	`
+
+	_, err = tx.Exec(ctx, query,
+		macro.ID, macro.Name, macro.Description, macro.Expression,
+		macro.Version, macro.VersionID, macro.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	r.publishInvalidation(ctx, macro.ID)
+	return nil
+}
+
+func (r *PostgresRepository) DeleteMacro(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM macros WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	r.publishInvalidation(ctx, id)
+	return nil
+}
+
+func (r *PostgresRepository) ListMacroVersions(ctx context.Context, macroID uuid.UUID) ([]models.MacroVersion, error) {
+	query := `
+		SELECT id, macro_id, version, snapshot, created_at
+		FROM macro_versions
+		WHERE macro_id = $1
+		ORDER BY version DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, macroID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]models.MacroVersion, 0)
+	for rows.Next() {
+		version, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *version)
+	}
+
+	return versions, rows.Err()
+}
+
+func (r *PostgresRepository) GetMacroVersion(ctx context.Context, macroID uuid.UUID, version int) (*models.MacroVersion, error) {
+	query := `
+		SELECT id, macro_id, version, snapshot, created_at
+		FROM macro_versions
+		WHERE macro_id = $1 AND version = $2
+	`
+
+	return scanVersion(r.db.QueryRow(ctx, query, macroID, version))
+}
+
+// insertVersion stores an immutable snapshot of the macro as its current version
+func insertVersion(ctx context.Context, tx pgx.Tx, macro *models.Macro) error {
+	snapshot, err := json.Marshal(macro)
+	if err != nil {
+		return fmt.Errorf("failed to marshal macro snapshot: %w", err)
+	}
+
+	query := `
+		INSERT INTO macro_versions (id, macro_id, version, snapshot, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err = tx.Exec(ctx, query, macro.VersionID, macro.ID, macro.Version, snapshot, macro.UpdatedAt)
+	return err
+}
+
+// scanMacro scans a macros row
+func scanMacro(row pgx.Row) (*models.Macro, error) {
+	var macro models.Macro
+	err := row.Scan(
+		&macro.ID, &macro.Name, &macro.Description, &macro.Expression,
+		&macro.Version, &macro.VersionID, &macro.CreatedAt, &macro.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &macro, nil
+}
+
+// scanVersion scans a macro_versions row and decodes its snapshot
+func scanVersion(row pgx.Row) (*models.MacroVersion, error) {
+	var version models.MacroVersion
+	var snapshot []byte
+
+	if err := row.Scan(&version.ID, &version.MacroID, &version.Version, &snapshot, &version.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(snapshot, &version.Macro); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal macro snapshot: %w", err)
+	}
+
+	return &version, nil
+}
@@ -0,0 +1,36 @@
+package models
+
+import "math"
+
+// AmountStats summarises an account's transaction amounts over a period
+type AmountStats struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"` // Population standard deviation
+}
+
+// NewAmountStats derives the mean and standard deviation from running sums
+func NewAmountStats(count int, sum, sumSquares float64) AmountStats {
+	if count == 0 {
+		return AmountStats{}
+	}
+
+	mean := sum / float64(count)
+	// Rounding can push the variance of identical amounts slightly below zero
+	variance := math.Max(sumSquares/float64(count)-mean*mean, 0)
+
+	return AmountStats{
+		Count:  count,
+		Mean:   mean,
+		StdDev: math.Sqrt(variance),
+	}
+}
+
+// ZScore returns how many standard deviations amount lies from the mean
+// Returns 0 when the spread is unknown: fewer than two amounts, or all amounts equal
+func (s AmountStats) ZScore(amount float64) float64 {
+	if s.Count < 2 || s.StdDev == 0 {
+		return 0
+	}
+	return (amount - s.Mean) / s.StdDev
+}
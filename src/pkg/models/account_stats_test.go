@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewAmountStats_WhenAmounts_ThenComputesMeanAndStdDev(t *testing.T) {
+	// Amounts 90, 110, 90, 110
+	stats := NewAmountStats(4, 400, 40400)
+
+	assert.Equal(t, 100.0, stats.Mean)
+	assert.InDelta(t, 10.0, stats.StdDev, 1e-9)
+	assert.InDelta(t, 5.0, stats.ZScore(150), 1e-9)
+}
+
+func Test_NewAmountStats_WhenNoAmounts_ThenReturnsZero(t *testing.T) {
+	stats := NewAmountStats(0, 0, 0)
+
+	assert.Equal(t, AmountStats{}, stats)
+	assert.Zero(t, stats.ZScore(100))
+}
+
+func Test_AmountStats_ZScore_WhenAllAmountsEqual_ThenReturnsZero(t *testing.T) {
+	stats := NewAmountStats(3, 300, 30000)
+
+	assert.Zero(t, stats.StdDev)
+	assert.Zero(t, stats.ZScore(1000))
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BacktestStatus string
+
+const (
+	BacktestPending   BacktestStatus = "pending"
+	BacktestRunning   BacktestStatus = "running"
+	BacktestCompleted BacktestStatus = "completed"
+	BacktestFailed    BacktestStatus = "failed"
+)
+
+// BacktestQueueKey is the Redis list holding IDs of backtests waiting for a worker
+const BacktestQueueKey = "backtest:queue"
+
+// Backtest replays candidate rules against stored transactions in a time range
+type Backtest struct {
+	ID             uuid.UUID       `json:"id"`
+	Status         BacktestStatus  `json:"status"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	Rules          []Rule          `json:"rules"`
+	ReplaceRuleSet bool            `json:"replace_rule_set"` // Candidates replace the active rules instead of being added to them
+	SampleLimit    int             `json:"sample_limit"`
+	Result         *BacktestResult `json:"result,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	StartedAt      *time.Time      `json:"started_at,omitempty"`
+	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+}
+
+// BacktestResult summarizes how the candidate rules would have decided past transactions
+type BacktestResult struct {
+	Evaluated      int              `json:"evaluated"`
+	Skipped        int              `json:"skipped"` // Transactions stored without an event payload
+	Changed        int              `json:"changed"` // Decision differs from the stored status
+	RuleMatches    []RuleMatchCount `json:"rule_matches"`
+	DecisionDeltas []DecisionDelta  `json:"decision_deltas"`
+	Samples        []BacktestSample `json:"samples"`
+	// Replayed rules answered from present-day history, whose matches and deltas are approximate
+	TimeDependentRules []TimeDependentRule `json:"time_dependent_rules"`
+}
+
+// TimeDependentRule is a replayed rule calling helpers that answer from history up to the
+// time the backtest runs, not up to the replayed transaction
+type TimeDependentRule struct {
+	RuleID    uuid.UUID `json:"rule_id"`
+	RuleName  string    `json:"rule_name"`
+	Candidate bool      `json:"candidate"`
+	Helpers   []string  `json:"helpers"`
+}
+
+// RuleMatchCount is the number of replayed transactions a candidate rule matched
+type RuleMatchCount struct {
+	RuleID   uuid.UUID `json:"rule_id"`
+	RuleName string    `json:"rule_name"`
+	Matches  int       `json:"matches"`
+}
+
+// DecisionDelta counts transactions whose stored status changed to a new status
+type DecisionDelta struct {
+	From  TransactionStatus `json:"from"`
+	To    TransactionStatus `json:"to"`
+	Count int               `json:"count"`
+}
+
+// BacktestSample is a replayed transaction matched by a candidate rule
+type BacktestSample struct {
+	TransactionID  uuid.UUID         `json:"transaction_id"`
+	ExternalID     string            `json:"external_id"`
+	StoredStatus   TransactionStatus `json:"stored_status"`
+	BacktestStatus TransactionStatus `json:"backtest_status"`
+	RiskScore      int               `json:"risk_score"`
+	MatchedRules   []string          `json:"matched_rules"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeadLetterReason tells why an event was taken out of the queue
+type DeadLetterReason string
+
+const (
+	// DeadLetterProcessingFailed is used when processing still failed after every retry
+	DeadLetterProcessingFailed DeadLetterReason = "processing_failed"
+	// DeadLetterInvalidPayload is used when the queued payload is not an event
+	DeadLetterInvalidPayload DeadLetterReason = "invalid_payload"
+)
+
+// DeadLetter is a queued event the worker gave up on, kept for inspection and replay
+type DeadLetter struct {
+	ID         uuid.UUID        `json:"id"`
+	Queue      string           `json:"queue"`                 // Redis list the event was popped from
+	ExternalID string           `json:"external_id,omitempty"` // Empty when the payload has none
+	Payload    string           `json:"payload"`               // Raw queued payload, replayed as is
+	Reason     DeadLetterReason `json:"reason"`
+	Error      string           `json:"error"`
+	Attempts   int              `json:"attempts"` // Processing attempts made, 0 for invalid payloads
+	ReceivedAt time.Time        `json:"received_at"`
+	FailedAt   time.Time        `json:"failed_at"`
+}
+
+// DeadLetterFilter selects dead letters; zero fields match everything
+type DeadLetterFilter struct {
+	Reason     DeadLetterReason
+	ExternalID string
+	From       *time.Time // Failed at or after
+	To         *time.Time // Failed before
+}
+
+// IsEmpty reports whether the filter matches every dead letter
+func (f DeadLetterFilter) IsEmpty() bool {
+	return f.Reason == "" && f.ExternalID == "" && f.From == nil && f.To == nil
+}
@@ -0,0 +1,39 @@
+package models
+
+// DecisionReplyToField is the reserved event field carrying the correlation ID
+// of a synchronous decision request. The worker publishes its result to
+// DecisionReplyChannel when the field is present.
+const DecisionReplyToField = "_decision_reply_to"
+
+// DecisionReplyChannel is the Redis pub/sub channel used for decision replies
+const DecisionReplyChannel = "decision:reply"
+
+// DecisionReply is published by the worker once a synchronous decision request has been evaluated
+type DecisionReply struct {
+	CorrelationID string            `json:"correlation_id"`
+	Result        TransactionResult `json:"result"`
+}
+
+// DecisionForStatus maps a transaction status to the allow/review/block decision
+func DecisionForStatus(status TransactionStatus) RuleAction {
+	switch status {
+	case StatusRejected:
+		return ActionBlock
+	case StatusInReview:
+		return ActionReview
+	default:
+		return ActionAllow
+	}
+}
+
+// StatusForDecision maps an allow/review/block decision to a transaction status
+func StatusForDecision(decision RuleAction) TransactionStatus {
+	switch decision {
+	case ActionBlock:
+		return StatusRejected
+	case ActionReview:
+		return StatusInReview
+	default:
+		return StatusApproved
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// GeoPoint is a latitude/longitude pair in decimal degrees
+type GeoPoint struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+}
+
+// AccountLocation is the last known location of an account
+type AccountLocation struct {
+	GeoPoint
+	SeenAt time.Time `json:"seen_at"`
+}
+
+// CountryRisk is the risk score assigned to a country, looked up by the countryRisk helper
+type CountryRisk struct {
+	CountryCode string    `json:"country_code" validate:"required,iso3166_1_alpha2"` // ISO 3166-1 alpha-2, upper case
+	Score       int       `json:"score" validate:"gte=0,lte=100"`
+	Notes       string    `json:"notes" validate:"max=500"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// IPInfo is what the local IP database knows about a network, looked up by ipCountry and ipASN
+type IPInfo struct {
+	CountryCode  string `json:"country_code"` // ISO 3166-1 alpha-2, upper case
+	ASN          int    `json:"asn"`
+	Organization string `json:"organization"`
+}
+
+// ParseIPNetwork parses a CIDR network, or a single address as a network of one address
+// The network is masked, and IPv4-mapped IPv6 networks are converted to IPv4
+func ParseIPNetwork(network string) (netip.Prefix, error) {
+	network = strings.TrimSpace(network)
+	if !strings.Contains(network, "/") {
+		addr, err := netip.ParseAddr(network)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		addr = addr.Unmap().WithZone("")
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(network)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	return prefix.Masked(), nil
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/fuzzy"
+	"github.com/google/uuid"
+)
+
+// ListType defines what kind of values a managed list holds
+type ListType string
+
+const (
+	ListTypeAccount ListType = "account"
+	ListTypeIP      ListType = "ip"
+	ListTypeEmail   ListType = "email"
+	ListTypeBIN     ListType = "bin"
+	ListTypeCountry ListType = "country"
+	ListTypeName    ListType = "name"
+)
+
+// ListInvalidateChannel is the Redis pub/sub channel announcing list changes, the payload is the list ID
+const ListInvalidateChannel = "list:invalidate"
+
+// Errors returned when a value does not fit its list type
+var (
+	ErrEmptyListValue   = errors.New("value is empty")
+	ErrInvalidIP        = errors.New("value is not a valid IP address or CIDR network")
+	ErrInvalidEmail     = errors.New("value is not a valid email address")
+	ErrInvalidBIN       = errors.New("value is not a valid BIN: expected 6 to 8 digits")
+	ErrInvalidCountry   = errors.New("value is not a valid country code: expected 2 letters")
+	ErrUnknownListType  = errors.New("unknown list type")
+	ErrListValueTooLong = errors.New("value is longer than 1024 characters")
+)
+
+// List is a named, typed set of values that expressions can check with inList
+type List struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name" validate:"required,min=1,max=100"`
+	Description string    `json:"description" validate:"max=1000"`
+	Type        ListType  `json:"type" validate:"required,oneof=account ip email bin country name"`
+	EntryCount  int       `json:"entry_count"` // Unexpired entries
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ListEntry is a value in a list, optionally expiring
+type ListEntry struct {
+	Value     string     `json:"value"`
+	Note      string     `json:"note,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // nil never expires
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Expired reports whether the entry has expired at the given time
+func (e ListEntry) Expired(at time.Time) bool {
+	return e.ExpiresAt != nil && !e.ExpiresAt.After(at)
+}
+
+// NormalizeListValue validates a value for a list type and returns its canonical form
+// Entries are stored and looked up in canonical form, so "User@Example.com" matches "user@example.com"
+func NormalizeListValue(listType ListType, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", ErrEmptyListValue
+	}
+	if len(value) > 1024 {
+		return "", ErrListValueTooLong
+	}
+
+	switch listType {
+	case ListTypeAccount:
+		return value, nil
+	case ListTypeIP:
+		// Networks of one address are stored as the address, so inList matches them
+		prefix, err := ParseIPNetwork(value)
+		if err != nil {
+			return "", ErrInvalidIP
+		}
+		if prefix.IsSingleIP() {
+			return prefix.Addr().String(), nil
+		}
+		return prefix.String(), nil
+	case ListTypeEmail:
+		at := strings.LastIndex(value, "@")
+		if at <= 0 || at == len(value)-1 {
+			return "", ErrInvalidEmail
+		}
+		return strings.ToLower(value), nil
+	case ListTypeBIN:
+		if len(value) < 6 || len(value) > 8 || strings.Trim(value, "0123456789") != "" {
+			return "", ErrInvalidBIN
+		}
+		return value, nil
+	case ListTypeCountry:
+		if len(value) != 2 || strings.Trim(strings.ToUpper(value), "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+			return "", ErrInvalidCountry
+		}
+		return strings.ToUpper(value), nil
+	case ListTypeName:
+		// Names are compared case, diacritic and punctuation insensitively
+		name := fuzzy.NormalizeName(value)
+		if name == "" {
+			return "", ErrEmptyListValue
+		}
+		return name, nil
+	default:
+		return "", ErrUnknownListType
+	}
+}
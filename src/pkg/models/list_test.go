@@ -0,0 +1,67 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NormalizeListValue_WhenValid_ThenReturnsCanonicalValue(t *testing.T) {
+	tests := []struct {
+		listType ListType
+		value    string
+		expected string
+	}{
+		{ListTypeAccount, "  ACC-1 ", "ACC-1"},
+		{ListTypeIP, "::ffff:10.0.0.1", "10.0.0.1"},
+		{ListTypeIP, "2001:DB8:0:0::1", "2001:db8::1"},
+		{ListTypeIP, "10.1.2.3/8", "10.0.0.0/8"},
+		{ListTypeIP, "10.1.2.3/32", "10.1.2.3"},
+		{ListTypeIP, "::ffff:192.0.2.0/120", "192.0.2.0/24"},
+		{ListTypeEmail, "Fraud@Example.COM", "fraud@example.com"},
+		{ListTypeBIN, "41111111", "41111111"},
+		{ListTypeCountry, "kp", "KP"},
+		{ListTypeName, " Müller-Lüdenscheidt, J. ", "muller ludenscheidt j"},
+	}
+
+	for _, tt := range tests {
+		normalized, err := NormalizeListValue(tt.listType, tt.value)
+
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.expected, normalized)
+	}
+}
+
+func Test_NormalizeListValue_WhenInvalid_ThenReturnsError(t *testing.T) {
+	tests := []struct {
+		listType ListType
+		value    string
+		expected error
+	}{
+		{ListTypeAccount, "   ", ErrEmptyListValue},
+		{ListTypeIP, "10.0.0.256", ErrInvalidIP},
+		{ListTypeIP, "10.0.0.0/33", ErrInvalidIP},
+		{ListTypeEmail, "not-an-email", ErrInvalidEmail},
+		{ListTypeBIN, "4111", ErrInvalidBIN},
+		{ListTypeCountry, "KPR", ErrInvalidCountry},
+		{ListTypeName, "--", ErrEmptyListValue},
+		{ListType("phone"), "555", ErrUnknownListType},
+	}
+
+	for _, tt := range tests {
+		_, err := NormalizeListValue(tt.listType, tt.value)
+
+		assert.ErrorIs(t, err, tt.expected, tt.value)
+	}
+}
+
+func Test_ListEntry_Expired_WhenExpiryPassed_ThenReturnsTrue(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+
+	assert.True(t, ListEntry{ExpiresAt: &past}.Expired(now))
+	assert.False(t, ListEntry{ExpiresAt: &future}.Expired(now))
+	assert.False(t, ListEntry{}.Expired(now))
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MacroInvalidateChannel is the Redis pub/sub channel announcing macro changes, the payload is the macro ID
+const MacroInvalidateChannel = "macro:invalidate"
+
+// Macro is a named expression snippet that rule expressions reference by name
+// Each use is replaced by the macro's expression when a rule is compiled
+type Macro struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Expression  string    `json:"expression"`
+	Version     int       `json:"version"`    // Current version number, set by the repository
+	VersionID   uuid.UUID `json:"version_id"` // ID of the immutable snapshot of the current version
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// MacroVersion is an immutable snapshot of a macro taken on create and update
+type MacroVersion struct {
+	ID        uuid.UUID `json:"id"`
+	MacroID   uuid.UUID `json:"macro_id"`
+	Version   int       `json:"version"`
+	Macro     Macro     `json:"macro"`
+	CreatedAt time.Time `json:"created_at"`
+}
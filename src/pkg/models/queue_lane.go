@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// QueueLane names a transaction queue
+// Workers drain strict lanes first and share the rest between weighted lanes,
+// so bulk traffic cannot starve real-time decisions
+type QueueLane string
+
+const (
+	// LaneRealtime carries pre-authorisation decision requests
+	LaneRealtime QueueLane = "realtime"
+	// LanePostTransaction carries transactions reported once completed, the default lane
+	LanePostTransaction QueueLane = "post_transaction"
+	// LaneBackfill carries bulk imports and re-screening
+	LaneBackfill QueueLane = "backfill"
+)
+
+// ErrUnknownQueueLane is returned for lane names other than realtime, post_transaction and backfill
+var ErrUnknownQueueLane = errors.New("unknown queue lane: expected realtime, post_transaction or backfill")
+
+// QueueLanes lists every lane
+var QueueLanes = []QueueLane{LaneRealtime, LanePostTransaction, LaneBackfill}
+
+// queueLaneKeys maps lanes to their Redis lists
+// The realtime and post_transaction lanes keep the keys of the decision and transaction
+// queues they replace, so events queued before an upgrade are still consumed
+var queueLaneKeys = map[QueueLane]string{
+	LaneRealtime:        "transaction:decision:queue",
+	LanePostTransaction: "transaction:queue",
+	LaneBackfill:        "transaction:queue:backfill",
+}
+
+// StreamPayloadField is the Redis stream entry field holding the JSON event
+const StreamPayloadField = "payload"
+
+// QueueKey returns the Redis list holding the lane's events
+func (l QueueLane) QueueKey() string {
+	return queueLaneKeys[l]
+}
+
+// StreamKey returns the Redis stream holding the lane's events with the redis_streams transport
+func (l QueueLane) StreamKey() string {
+	return "transaction:stream:" + string(l)
+}
+
+// Subject returns the NATS subject the lane's events are published on with the nats transport
+func (l QueueLane) Subject() string {
+	return "transactions." + string(l)
+}
+
+// ParseQueueLane returns the lane with the given name
+func ParseQueueLane(name string) (QueueLane, error) {
+	lane := QueueLane(strings.TrimSpace(name))
+	if _, ok := queueLaneKeys[lane]; !ok {
+		return "", ErrUnknownQueueLane
+	}
+	return lane, nil
+}
+
+// QueueLaneForKey returns the lane whose events are held in a Redis list or stream, or a NATS subject
+func QueueLaneForKey(key string) (QueueLane, bool) {
+	for _, lane := range QueueLanes {
+		if key == lane.QueueKey() || key == lane.StreamKey() || key == lane.Subject() {
+			return lane, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_QueueLaneForKey_WhenKeyOfAnyTransport_ThenReturnsLane(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected QueueLane
+	}{
+		{"transaction:decision:queue", LaneRealtime},
+		{"transaction:queue", LanePostTransaction},
+		{"transaction:stream:backfill", LaneBackfill},
+		{"transactions.realtime", LaneRealtime},
+	}
+
+	for _, tt := range tests {
+		lane, ok := QueueLaneForKey(tt.key)
+
+		assert.True(t, ok, tt.key)
+		assert.Equal(t, tt.expected, lane, tt.key)
+	}
+}
+
+func Test_QueueLaneForKey_WhenUnknownKey_ThenReturnsFalse(t *testing.T) {
+	_, ok := QueueLaneForKey("backtest:queue")
+
+	assert.False(t, ok)
+}
@@ -14,15 +14,42 @@ const (
 	ActionReview RuleAction = "review"
 )
 
+type RuleMode string
+
+const (
+	RuleModeActive RuleMode = "active" // Matches take part in the decision
+	RuleModeShadow RuleMode = "shadow" // Matches are recorded but never affect the decision
+)
+
 type Rule struct {
-	ID          uuid.UUID      `json:"id"`
-	Name        string         `json:"name" validate:"required,min=1,max=255"`
-	Description string         `json:"description" validate:"max=1000"`
-	Action      RuleAction     `json:"action" validate:"required,oneof=allow block review"`
-	Priority    int            `json:"priority" validate:"gte=0,lte=100"`
-	Enabled     bool           `json:"enabled"`
-	Conditions  map[string]any `json:"conditions" validate:"required"`
-	SchemaID    *uuid.UUID     `json:"schema_id,omitempty"` // Reference to event schema
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
+	ID             uuid.UUID      `json:"id"`
+	Name           string         `json:"name" validate:"required,min=1,max=255"`
+	Description    string         `json:"description" validate:"max=1000"`
+	Action         RuleAction     `json:"action" validate:"required,oneof=allow block review"`
+	Priority       int            `json:"priority" validate:"gte=0,lte=100"`
+	Score          int            `json:"score" validate:"gte=0,lte=100"` // Risk score contributed when the rule matches
+	Enabled        bool           `json:"enabled"`
+	Mode           RuleMode       `json:"mode" validate:"omitempty,oneof=active shadow"` // Empty is treated as active
+	StopProcessing bool           `json:"stop_processing"`                               // Terminal rule: a match skips the remaining active rules
+	TimeoutMs      int            `json:"timeout_ms" validate:"gte=0,lte=60000"`         // Evaluation timeout override, 0 uses the worker default
+	Conditions     map[string]any `json:"conditions" validate:"required"`
+	SchemaID       *uuid.UUID     `json:"schema_id,omitempty"` // Reference to event schema
+	Version        int            `json:"version"`             // Current version number, set by the repository
+	VersionID      uuid.UUID      `json:"version_id"`          // ID of the immutable snapshot of the current version
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// RuleVersion is an immutable snapshot of a rule taken on create, update and rollback
+type RuleVersion struct {
+	ID        uuid.UUID `json:"id"`
+	RuleID    uuid.UUID `json:"rule_id"`
+	Version   int       `json:"version"`
+	Rule      Rule      `json:"rule"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsShadow reports whether the rule only records matches without affecting decisions
+func (r *Rule) IsShadow() bool {
+	return r.Mode == RuleModeShadow
 }
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RuleErrorKind string
+
+const (
+	RuleErrorCompile       RuleErrorKind = "compile"        // Expression missing or failed to compile
+	RuleErrorSchemaMissing RuleErrorKind = "schema_missing" // Referenced schema is not loaded
+	RuleErrorRuntime       RuleErrorKind = "runtime"        // Expression failed while running against an event
+	RuleErrorTimeout       RuleErrorKind = "timeout"        // Expression did not finish within the rule's timeout
+)
+
+// RuleHealth summarizes how a rule has behaved on live traffic
+type RuleHealth struct {
+	RuleID              uuid.UUID     `json:"rule_id"`
+	Evaluations         int64         `json:"evaluations"`
+	Errors              int64         `json:"errors"`
+	ErrorRate           float64       `json:"error_rate"` // Errors / evaluations, 0 when never evaluated
+	ConsecutiveFailures int           `json:"consecutive_failures"`
+	LastError           string        `json:"last_error,omitempty"`
+	LastErrorKind       RuleErrorKind `json:"last_error_kind,omitempty"`
+	LastErrorAt         *time.Time    `json:"last_error_at,omitempty"`
+	LastMatchAt         *time.Time    `json:"last_match_at,omitempty"`
+	Flagged             bool          `json:"flagged"`       // Consecutive failures reached the configured threshold
+	AutoDisabled        bool          `json:"auto_disabled"` // The worker disabled the rule when flagging it
+	FlaggedAt           *time.Time    `json:"flagged_at,omitempty"`
+	UpdatedAt           *time.Time    `json:"updated_at,omitempty"`
+}
+
+// RuleHealthDelta holds the evaluation outcomes a worker observed for one rule since its last flush
+type RuleHealthDelta struct {
+	RuleID              uuid.UUID
+	Evaluations         int64
+	Errors              int64
+	ConsecutiveFailures int  // Failures since the last success, or since the last flush when Recovered is false
+	Recovered           bool // A successful evaluation reset the failure streak during this window
+	LastError           string
+	LastErrorKind       RuleErrorKind
+	LastErrorAt         *time.Time
+	LastMatchAt         *time.Time
+}
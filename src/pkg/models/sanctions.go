@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SanctionsSource identifies a published sanctions list
+type SanctionsSource string
+
+const (
+	SanctionsSourceOFAC SanctionsSource = "ofac" // US Treasury OFAC Specially Designated Nationals (SDN) list
+	SanctionsSourceEU   SanctionsSource = "eu"   // EU consolidated financial sanctions list
+	SanctionsSourceUN   SanctionsSource = "un"   // UN Security Council consolidated list
+)
+
+// SanctionsEntityType is what a sanctions entry designates
+type SanctionsEntityType string
+
+const (
+	SanctionsEntityIndividual SanctionsEntityType = "individual"
+	SanctionsEntityEntity     SanctionsEntityType = "entity"
+	SanctionsEntityVessel     SanctionsEntityType = "vessel"
+	SanctionsEntityAircraft   SanctionsEntityType = "aircraft"
+	SanctionsEntityUnknown    SanctionsEntityType = "unknown"
+)
+
+// SanctionsInvalidateChannel is the Redis pub/sub channel announcing a sanctions import, the payload is the source
+const SanctionsInvalidateChannel = "sanctions:invalidate"
+
+// SanctionsEntry is a designated person, organisation, vessel or aircraft of a sanctions list
+type SanctionsEntry struct {
+	ID         uuid.UUID           `json:"id"`
+	Source     SanctionsSource     `json:"source"`
+	SourceID   string              `json:"source_id"` // Identifier in the published list, e.g. the OFAC UID
+	Type       SanctionsEntityType `json:"type"`
+	Name       string              `json:"name"`
+	Aliases    []string            `json:"aliases"`   // Other names, including weak aliases; never includes Name
+	Countries  []string            `json:"countries"` // ISO 3166-1 alpha-2 codes of nationalities and addresses
+	Programs   []string            `json:"programs"`  // Sanctions programmes or regimes
+	Remarks    string              `json:"remarks,omitempty"`
+	ImportedAt time.Time           `json:"imported_at"`
+}
+
+// SanctionsImport records the last import of a source
+type SanctionsImport struct {
+	Source     SanctionsSource `json:"source"`
+	FileName   string          `json:"file_name"`
+	EntryCount int             `json:"entry_count"`
+	ImportedAt time.Time       `json:"imported_at"`
+}
+
+// ScreeningRequest is a name to screen against the sanctions lists
+type ScreeningRequest struct {
+	Name      string            `json:"name" validate:"required,min=1,max=500"`
+	Country   string            `json:"country,omitempty" validate:"max=100"`               // ISO 3166-1 alpha-2 code or English name; restricts matches to entries with this country or none
+	Threshold float64           `json:"threshold,omitempty" validate:"gte=0,lte=1"`         // Minimum score, 0 uses the configured default
+	Sources   []SanctionsSource `json:"sources,omitempty" validate:"dive,oneof=ofac eu un"` // Lists to screen against, empty for all
+	Limit     int               `json:"limit,omitempty" validate:"gte=0,lte=100"`           // Maximum matches returned, 0 for 10
+}
+
+// ScreeningMatch is a sanctions entry whose name or alias is similar to the screened name
+type ScreeningMatch struct {
+	Entry       SanctionsEntry `json:"entry"`
+	MatchedName string         `json:"matched_name"` // The name or alias of the entry that matched
+	Score       float64        `json:"score"`        // Name similarity from 0 to 1
+}
@@ -0,0 +1,21 @@
+package models
+
+// FieldType represents the inferred type of a JSON field
+type FieldType string
+
+const (
+	FieldTypeString  FieldType = "string"
+	FieldTypeNumber  FieldType = "number"
+	FieldTypeBoolean FieldType = "boolean"
+	FieldTypeArray   FieldType = "array"
+	FieldTypeObject  FieldType = "object"
+	FieldTypeNull    FieldType = "null"
+)
+
+// ExtractedField represents a field extracted from sample JSON
+type ExtractedField struct {
+	Path        string    `json:"path"`
+	Type        FieldType `json:"type"`
+	Nullable    bool      `json:"nullable"`
+	SampleValue any       `json:"sample_value,omitempty"`
+}
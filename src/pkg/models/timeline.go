@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// TimelineEvent is an event in an entity's timeline, used to detect sequences of events
+// across schemas, such as a password change followed by a large transfer
+type TimelineEvent struct {
+	ID   string    `json:"id"`   // External ID of the event, or the transaction ID when it has none
+	Type string    `json:"type"` // Event type, e.g. "login" or "transfer"
+	At   time.Time `json:"at"`   // When the worker processed the event
+}
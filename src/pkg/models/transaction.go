@@ -16,19 +16,28 @@ const (
 )
 
 type Transaction struct {
-	ID             uuid.UUID         `json:"id"`
-	ExternalID     string            `json:"external_id"`
-	Amount         float64           `json:"amount"`
-	Currency       string            `json:"currency"`
-	Origin         string            `json:"origin"`
-	Destination    string            `json:"destination"`
-	Type           string            `json:"type"`
-	Status         TransactionStatus `json:"status"`
-	ProcessingTime int64             `json:"processing_time_ms"`
-	MatchedRules   []string          `json:"matched_rules"`
-	Metadata       map[string]any    `json:"metadata"`
-	CreatedAt      time.Time         `json:"created_at"`
-	ProcessedAt    *time.Time        `json:"processed_at"`
+	ID                 uuid.UUID         `json:"id"`
+	ExternalID         string            `json:"external_id"`
+	Amount             float64           `json:"amount"`
+	Currency           string            `json:"currency"`
+	Origin             string            `json:"origin"`
+	Destination        string            `json:"destination"`
+	Type               string            `json:"type"`
+	Status             TransactionStatus `json:"status"`
+	RiskScore          int               `json:"risk_score"`
+	ProcessingTime     int64             `json:"processing_time_ms"`
+	MatchedRules       []string          `json:"matched_rules"`
+	MatchedVersions    []uuid.UUID       `json:"matched_rule_versions"`        // Versions of the matched rules at evaluation time
+	ShadowMatchedRules []string          `json:"shadow_matched_rules"`         // Shadow rules that matched, excluded from the decision
+	ShadowVersions     []uuid.UUID       `json:"shadow_matched_rule_versions"` // Versions of the matched shadow rules at evaluation time
+	DecidedBy          *uuid.UUID        `json:"decided_by_rule_id,omitempty"`
+	TimedOutRules      []string          `json:"timed_out_rules"`  // Rules that timed out or were skipped by the evaluation deadline
+	TimeoutFallback    bool              `json:"timeout_fallback"` // The timeout fallback decision was applied
+	Metadata           map[string]any    `json:"metadata"`
+	Event              Event             `json:"event,omitempty"`    // Original event payload, replayed by backtests
+	Location           *GeoPoint         `json:"location,omitempty"` // Event coordinates, kept as the account's last known location
+	CreatedAt          time.Time         `json:"created_at"`
+	ProcessedAt        *time.Time        `json:"processed_at"`
 }
 
 // Event represents a generic JSON event for rule evaluation
@@ -36,9 +45,26 @@ type Transaction struct {
 type Event map[string]any
 
 type TransactionResult struct {
-	TransactionID  uuid.UUID         `json:"transaction_id"`
-	Status         TransactionStatus `json:"status"`
-	MatchedRules   []string          `json:"matched_rules"`
-	ProcessingTime int64             `json:"processing_time_ms"`
-	Message        string            `json:"message"`
+	TransactionID      uuid.UUID         `json:"transaction_id"`
+	Status             TransactionStatus `json:"status"`
+	RiskScore          int               `json:"risk_score"`
+	MatchedRules       []string          `json:"matched_rules"`
+	MatchedVersions    []uuid.UUID       `json:"matched_rule_versions"`                  // Versions of the matched rules at evaluation time
+	ShadowMatchedRules []string          `json:"shadow_matched_rules,omitempty"`         // Shadow rules that matched, excluded from the decision
+	ShadowVersions     []uuid.UUID       `json:"shadow_matched_rule_versions,omitempty"` // Versions of the matched shadow rules at evaluation time
+	DecidedBy          *uuid.UUID        `json:"decided_by_rule_id,omitempty"`           // Rule whose action set the status, nil if none
+	TimedOutRules      []string          `json:"timed_out_rules,omitempty"`              // Rules that timed out or were skipped by the evaluation deadline
+	TimeoutFallback    bool              `json:"timeout_fallback,omitempty"`             // The timeout fallback decision was applied
+	ProcessingTime     int64             `json:"processing_time_ms"`
+	Message            string            `json:"message"`
 }
+
+// AmountAggregate selects how velocity helpers aggregate transaction amounts
+type AmountAggregate string
+
+const (
+	AggregateSum AmountAggregate = "sum"
+	AggregateMin AmountAggregate = "min"
+	AggregateMax AmountAggregate = "max"
+	AggregateAvg AmountAggregate = "avg"
+)
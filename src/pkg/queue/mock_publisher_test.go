@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/queue/publisher.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/queue/publisher.go -destination=src/pkg/queue/mock_publisher_test.go -package=queue -exclude_interfaces=Publisher
+//
+
+// Package queue is a generated GoMock package.
+package queue
+
+import (
+	context "context"
+	reflect "reflect"
+
+	jetstream "github.com/nats-io/nats.go/jetstream"
+	redis "github.com/redis/go-redis/v9"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRedisList is a mock of RedisList interface.
+type MockRedisList struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedisListMockRecorder
+	isgomock struct{}
+}
+
+// MockRedisListMockRecorder is the mock recorder for MockRedisList.
+type MockRedisListMockRecorder struct {
+	mock *MockRedisList
+}
+
+// NewMockRedisList creates a new mock instance.
+func NewMockRedisList(ctrl *gomock.Controller) *MockRedisList {
+	mock := &MockRedisList{ctrl: ctrl}
+	mock.recorder = &MockRedisListMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedisList) EXPECT() *MockRedisListMockRecorder {
+	return m.recorder
+}
+
+// LPush mocks base method.
+func (m *MockRedisList) LPush(ctx context.Context, key string, values ...any) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LPush", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// LPush indicates an expected call of LPush.
+func (mr *MockRedisListMockRecorder) LPush(ctx, key any, values ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPush", reflect.TypeOf((*MockRedisList)(nil).LPush), varargs...)
+}
+
+// MockRedisStream is a mock of RedisStream interface.
+type MockRedisStream struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedisStreamMockRecorder
+	isgomock struct{}
+}
+
+// MockRedisStreamMockRecorder is the mock recorder for MockRedisStream.
+type MockRedisStreamMockRecorder struct {
+	mock *MockRedisStream
+}
+
+// NewMockRedisStream creates a new mock instance.
+func NewMockRedisStream(ctrl *gomock.Controller) *MockRedisStream {
+	mock := &MockRedisStream{ctrl: ctrl}
+	mock.recorder = &MockRedisStreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedisStream) EXPECT() *MockRedisStreamMockRecorder {
+	return m.recorder
+}
+
+// XAdd mocks base method.
+func (m *MockRedisStream) XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XAdd", ctx, a)
+	ret0, _ := ret[0].(*redis.StringCmd)
+	return ret0
+}
+
+// XAdd indicates an expected call of XAdd.
+func (mr *MockRedisStreamMockRecorder) XAdd(ctx, a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XAdd", reflect.TypeOf((*MockRedisStream)(nil).XAdd), ctx, a)
+}
+
+// MockJetStream is a mock of JetStream interface.
+type MockJetStream struct {
+	ctrl     *gomock.Controller
+	recorder *MockJetStreamMockRecorder
+	isgomock struct{}
+}
+
+// MockJetStreamMockRecorder is the mock recorder for MockJetStream.
+type MockJetStreamMockRecorder struct {
+	mock *MockJetStream
+}
+
+// NewMockJetStream creates a new mock instance.
+func NewMockJetStream(ctrl *gomock.Controller) *MockJetStream {
+	mock := &MockJetStream{ctrl: ctrl}
+	mock.recorder = &MockJetStreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJetStream) EXPECT() *MockJetStreamMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockJetStream) Publish(ctx context.Context, subject string, data []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, subject, data}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Publish", varargs...)
+	ret0, _ := ret[0].(*jetstream.PubAck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockJetStreamMockRecorder) Publish(ctx, subject, data any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, subject, data}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockJetStream)(nil).Publish), varargs...)
+}
@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/redis/go-redis/v9"
+)
+
+// Transports transaction events are queued on, selected by WORKER_QUEUE_TRANSPORT
+const (
+	TransportRedisList    = "redis_list"
+	TransportRedisStreams = "redis_streams"
+	TransportNATS         = "nats"
+)
+
+// Publisher queues events on a lane over the transport the workers consume
+type Publisher interface {
+	// Publish queues the payloads on the lane, in order
+	// Payloads published before a failure stay queued; the worker skips an event saved twice
+	Publish(ctx context.Context, lane models.QueueLane, payloads ...string) error
+}
+
+// RedisList defines the Redis list operation used by ListPublisher
+type RedisList interface {
+	LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+}
+
+// RedisStream defines the Redis stream operation used by StreamPublisher
+type RedisStream interface {
+	XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd
+}
+
+// JetStream defines the JetStream operation used by JetStreamPublisher
+type JetStream interface {
+	Publish(ctx context.Context, subject string, data []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+}
+
+// ListPublisher queues events on the lane's Redis list, for the redis_list transport
+type ListPublisher struct {
+	redis RedisList
+}
+
+// NewListPublisher creates a publisher to the lanes' Redis lists
+func NewListPublisher(redis RedisList) *ListPublisher {
+	return &ListPublisher{redis: redis}
+}
+
+// Publish pushes the payloads to the lane's list in a single command
+func (p *ListPublisher) Publish(ctx context.Context, lane models.QueueLane, payloads ...string) error {
+	values := make([]interface{}, len(payloads))
+	for i, payload := range payloads {
+		values[i] = payload
+	}
+	return p.redis.LPush(ctx, lane.QueueKey(), values...).Err()
+}
+
+// StreamPublisher queues events on the lane's Redis stream, for the redis_streams transport
+type StreamPublisher struct {
+	redis RedisStream
+}
+
+// NewStreamPublisher creates a publisher to the lanes' Redis streams
+func NewStreamPublisher(redis RedisStream) *StreamPublisher {
+	return &StreamPublisher{redis: redis}
+}
+
+// Publish adds one stream entry per payload
+func (p *StreamPublisher) Publish(ctx context.Context, lane models.QueueLane, payloads ...string) error {
+	for _, payload := range payloads {
+		err := p.redis.XAdd(ctx, &redis.XAddArgs{
+			Stream: lane.StreamKey(),
+			Values: map[string]any{models.StreamPayloadField: payload},
+		}).Err()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JetStreamPublisher queues events on the lane's NATS subject, for the nats transport
+// The stream capturing the subjects is created by the workers
+type JetStreamPublisher struct {
+	js JetStream
+}
+
+// NewJetStreamPublisher creates a publisher to the lanes' JetStream subjects
+func NewJetStreamPublisher(js JetStream) *JetStreamPublisher {
+	return &JetStreamPublisher{js: js}
+}
+
+// Publish publishes one message per payload, each acknowledged by JetStream
+func (p *JetStreamPublisher) Publish(ctx context.Context, lane models.QueueLane, payloads ...string) error {
+	for _, payload := range payloads {
+		if _, err := p.js.Publish(ctx, lane.Subject(), []byte(payload)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
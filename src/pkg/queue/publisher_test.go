@@ -0,0 +1,90 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_ListPublisher_Publish_WhenCalled_ThenPushesPayloadsToLaneList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisList(ctrl)
+	mockRedis.EXPECT().LPush(gomock.Any(), "transaction:queue:backfill", `{"id":1}`, `{"id":2}`).Return(redis.NewIntResult(2, nil))
+	publisher := NewListPublisher(mockRedis)
+
+	err := publisher.Publish(context.Background(), models.LaneBackfill, `{"id":1}`, `{"id":2}`)
+
+	assert.NoError(t, err)
+}
+
+func Test_StreamPublisher_Publish_WhenCalled_ThenAddsOneEntryPerPayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisStream(ctrl)
+	gomock.InOrder(
+		mockRedis.EXPECT().XAdd(gomock.Any(), &redis.XAddArgs{
+			Stream: "transaction:stream:realtime",
+			Values: map[string]any{"payload": `{"id":1}`},
+		}).Return(redis.NewStringResult("1-0", nil)),
+		mockRedis.EXPECT().XAdd(gomock.Any(), &redis.XAddArgs{
+			Stream: "transaction:stream:realtime",
+			Values: map[string]any{"payload": `{"id":2}`},
+		}).Return(redis.NewStringResult("2-0", nil)),
+	)
+	publisher := NewStreamPublisher(mockRedis)
+
+	err := publisher.Publish(context.Background(), models.LaneRealtime, `{"id":1}`, `{"id":2}`)
+
+	assert.NoError(t, err)
+}
+
+func Test_StreamPublisher_Publish_WhenAddFails_ThenStopsAndReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisStream(ctrl)
+	mockRedis.EXPECT().XAdd(gomock.Any(), gomock.Any()).Return(redis.NewStringResult("", errors.New("connection refused")))
+	publisher := NewStreamPublisher(mockRedis)
+
+	err := publisher.Publish(context.Background(), models.LaneRealtime, `{"id":1}`, `{"id":2}`)
+
+	assert.Error(t, err)
+}
+
+func Test_JetStreamPublisher_Publish_WhenCalled_ThenPublishesToLaneSubject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	gomock.InOrder(
+		mockJS.EXPECT().Publish(gomock.Any(), "transactions.post_transaction", []byte(`{"id":1}`)).Return(&jetstream.PubAck{Sequence: 1}, nil),
+		mockJS.EXPECT().Publish(gomock.Any(), "transactions.post_transaction", []byte(`{"id":2}`)).Return(&jetstream.PubAck{Sequence: 2}, nil),
+	)
+	publisher := NewJetStreamPublisher(mockJS)
+
+	err := publisher.Publish(context.Background(), models.LanePostTransaction, `{"id":1}`, `{"id":2}`)
+
+	assert.NoError(t, err)
+}
+
+func Test_JetStreamPublisher_Publish_WhenNoStream_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJS := NewMockJetStream(ctrl)
+	mockJS.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, jetstream.ErrNoStreamResponse)
+	publisher := NewJetStreamPublisher(mockJS)
+
+	err := publisher.Publish(context.Background(), models.LanePostTransaction, `{"id":1}`)
+
+	assert.ErrorIs(t, err, jetstream.ErrNoStreamResponse)
+}
@@ -0,0 +1,124 @@
+package rules
+
+import (
+	"context"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HealthReader defines the interface for reading rule health (used by API)
+type HealthReader interface {
+	// GetRuleHealth retrieves the health summary of a rule
+	// Returns pgx.ErrNoRows when the rule was never evaluated
+	GetRuleHealth(ctx context.Context, ruleID uuid.UUID) (*models.RuleHealth, error)
+}
+
+// HealthRepository defines the interface for recording rule health (used by worker)
+type HealthRepository interface {
+	HealthReader
+	// RecordHealth merges a worker's evaluation outcomes into the stored summary
+	// Returns the updated summary so the caller can act on the failure streak
+	RecordHealth(ctx context.Context, delta models.RuleHealthDelta) (*models.RuleHealth, error)
+	// FlagRule marks a rule as failing, optionally recording that it was auto-disabled
+	FlagRule(ctx context.Context, ruleID uuid.UUID, autoDisabled bool) error
+}
+
+// PostgresHealthRepository is the PostgreSQL implementation of HealthRepository
+type PostgresHealthRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresHealthRepository creates a new PostgreSQL rule health repository
+func NewPostgresHealthRepository(db *pgxpool.Pool) HealthRepository {
+	return &PostgresHealthRepository{db: db}
+}
+
+const healthColumns = `rule_id, evaluations, errors, consecutive_failures, last_error, last_error_kind,
+		       last_error_at, last_match_at, flagged, auto_disabled, flagged_at, updated_at`
+
+// GetRuleHealth retrieves the health summary of a rule
+func (r *PostgresHealthRepository) GetRuleHealth(ctx context.Context, ruleID uuid.UUID) (*models.RuleHealth, error) {
+	query := `SELECT ` + healthColumns + ` FROM rule_health WHERE rule_id = $1`
+
+	return scanHealth(r.db.QueryRow(ctx, query, ruleID))
+}
+
+// RecordHealth merges a worker's evaluation outcomes into the stored summary
+// Several workers flush independently: counters are added, and a failure streak
+// is only replaced when this worker saw the rule recover
+func (r *PostgresHealthRepository) RecordHealth(ctx context.Context, delta models.RuleHealthDelta) (*models.RuleHealth, error) {
+	var lastError, lastErrorKind *string
+	if delta.LastErrorAt != nil {
+		kind := string(delta.LastErrorKind)
+		lastError, lastErrorKind = &delta.LastError, &kind
+	}
+
+	query := `
+		INSERT INTO rule_health (rule_id, evaluations, errors, consecutive_failures, last_error, last_error_kind, last_error_at, last_match_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		ON CONFLICT (rule_id) DO UPDATE SET
+			evaluations = rule_health.evaluations + EXCLUDED.evaluations,
+			errors = rule_health.errors + EXCLUDED.errors,
+			consecutive_failures = CASE WHEN $9 THEN EXCLUDED.consecutive_failures
+			                            ELSE rule_health.consecutive_failures + EXCLUDED.consecutive_failures END,
+			last_error = COALESCE(EXCLUDED.last_error, rule_health.last_error),
+			last_error_kind = COALESCE(EXCLUDED.last_error_kind, rule_health.last_error_kind),
+			last_error_at = GREATEST(EXCLUDED.last_error_at, rule_health.last_error_at),
+			last_match_at = GREATEST(EXCLUDED.last_match_at, rule_health.last_match_at),
+			flagged = rule_health.flagged AND NOT $9,
+			updated_at = NOW()
+		RETURNING ` + healthColumns
+
+	return scanHealth(r.db.QueryRow(ctx, query,
+		delta.RuleID, delta.Evaluations, delta.Errors, delta.ConsecutiveFailures,
+		lastError, lastErrorKind, delta.LastErrorAt, delta.LastMatchAt, delta.Recovered,
+	))
+}
+
+// FlagRule marks a rule as failing, optionally recording that it was auto-disabled
+func (r *PostgresHealthRepository) FlagRule(ctx context.Context, ruleID uuid.UUID, autoDisabled bool) error {
+	query := `
+		UPDATE rule_health
+		SET flagged = true, auto_disabled = $2, flagged_at = NOW(), updated_at = NOW()
+		WHERE rule_id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, ruleID, autoDisabled)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// scanHealth scans a rule_health row and derives the error rate
+func scanHealth(row pgx.Row) (*models.RuleHealth, error) {
+	var health models.RuleHealth
+	var lastError, lastErrorKind *string
+
+	err := row.Scan(
+		&health.RuleID, &health.Evaluations, &health.Errors, &health.ConsecutiveFailures,
+		&lastError, &lastErrorKind, &health.LastErrorAt, &health.LastMatchAt,
+		&health.Flagged, &health.AutoDisabled, &health.FlaggedAt, &health.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastError != nil {
+		health.LastError = *lastError
+	}
+	if lastErrorKind != nil {
+		health.LastErrorKind = models.RuleErrorKind(*lastErrorKind)
+	}
+	if health.Evaluations > 0 {
+		health.ErrorRate = float64(health.Errors) / float64(health.Evaluations)
+	}
+
+	return &health, nil
+}
@@ -32,10 +32,14 @@ type Repository interface {
 	GetRule(ctx context.Context, id uuid.UUID) (*models.Rule, error)
 	// ListRules retrieves all rules
 	ListRules(ctx context.Context) ([]models.Rule, error)
-	// UpdateRule updates an existing rule
+	// UpdateRule updates an existing rule, storing the result as a new version
 	UpdateRule(ctx context.Context, rule *models.Rule) error
 	// DeleteRule deletes a rule by ID
 	DeleteRule(ctx context.Context, id uuid.UUID) error
+	// ListRuleVersions retrieves all versions of a rule, newest first
+	ListRuleVersions(ctx context.Context, ruleID uuid.UUID) ([]models.RuleVersion, error)
+	// GetRuleVersion retrieves a single version of a rule
+	GetRuleVersion(ctx context.Context, ruleID uuid.UUID, version int) (*models.RuleVersion, error)
 }
 
 // PostgresRepository is the PostgreSQL implementation of Repository
@@ -64,10 +68,10 @@ func (r *PostgresRepository) LoadRules(ctx context.Context) ([]models.Rule, erro
 
 	// Load from database
 	query := `
-		SELECT id, name, description, action, priority, enabled, conditions, schema_id, created_at, updated_at
+		SELECT id, name, description, action, priority, score, enabled, mode, stop_processing, timeout_ms, conditions, schema_id, version, version_id, created_at, updated_at
 		FROM rules
 		WHERE enabled = true
-		ORDER BY priority ASC
+		ORDER BY priority ASC, created_at ASC, id ASC
 	`
 
 	rows, err := r.db.Query(ctx, query)
@@ -83,8 +87,8 @@ func (r *PostgresRepository) LoadRules(ctx context.Context) ([]models.Rule, erro
 
 		err := rows.Scan(
 			&rule.ID, &rule.Name, &rule.Description, &rule.Action,
-			&rule.Priority, &rule.Enabled, &conditionsJSON,
-			&rule.SchemaID, &rule.CreatedAt, &rule.UpdatedAt,
+			&rule.Priority, &rule.Score, &rule.Enabled, &rule.Mode, &rule.StopProcessing, &rule.TimeoutMs, &conditionsJSON,
+			&rule.SchemaID, &rule.Version, &rule.VersionID, &rule.CreatedAt, &rule.UpdatedAt,
 		)
 		if err != nil {
 			continue
@@ -110,30 +114,49 @@ func (r *PostgresRepository) LoadRules(ctx context.Context) ([]models.Rule, erro
 	return rules, nil
 }
 
-// CreateRule creates a new rule
+// CreateRule creates a new rule together with its first version
 func (r *PostgresRepository) CreateRule(ctx context.Context, rule *models.Rule) error {
 	conditionsJSON, err := json.Marshal(rule.Conditions)
 	if err != nil {
 		return fmt.Errorf("failed to marshal conditions: %w", err)
 	}
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rule.Version = 1
+	rule.VersionID = uuid.New()
+	if err := insertVersion(ctx, tx, rule); err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO rules (id, name, description, action, priority, enabled, conditions, schema_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO rules (id, name, description, action, priority, score, enabled, mode, stop_processing, timeout_ms, conditions, schema_id, version, version_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
 
-	_, err = r.db.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		rule.ID, rule.Name, rule.Description, rule.Action,
-		rule.Priority, rule.Enabled, conditionsJSON,
-		rule.SchemaID, rule.CreatedAt, rule.UpdatedAt,
+		rule.Priority, rule.Score, rule.Enabled, rule.Mode, rule.StopProcessing, rule.TimeoutMs, conditionsJSON,
+		rule.SchemaID, rule.Version, rule.VersionID, rule.CreatedAt, rule.UpdatedAt,
 	)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
 
 	// Invalidate cache on create
-	if err == nil && r.redis != nil {
+	if r.redis != nil {
 		r.redis.Del(ctx, "rules:cache")
 	}
 
-	return err
+	return nil
 }
 
 // GetRule retrieves a rule by ID
@@ -142,15 +165,15 @@ func (r *PostgresRepository) GetRule(ctx context.Context, id uuid.UUID) (*models
 	var conditionsJSON []byte
 
 	query := `
-		SELECT id, name, description, action, priority, enabled, conditions, schema_id, created_at, updated_at
+		SELECT id, name, description, action, priority, score, enabled, mode, stop_processing, timeout_ms, conditions, schema_id, version, version_id, created_at, updated_at
 		FROM rules
 		WHERE id = $1
 	`
 
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&rule.ID, &rule.Name, &rule.Description, &rule.Action,
-		&rule.Priority, &rule.Enabled, &conditionsJSON,
-		&rule.SchemaID, &rule.CreatedAt, &rule.UpdatedAt,
+		&rule.Priority, &rule.Score, &rule.Enabled, &rule.Mode, &rule.StopProcessing, &rule.TimeoutMs, &conditionsJSON,
+		&rule.SchemaID, &rule.Version, &rule.VersionID, &rule.CreatedAt, &rule.UpdatedAt,
 	)
 
 	if err != nil {
@@ -167,12 +190,12 @@ func (r *PostgresRepository) GetRule(ctx context.Context, id uuid.UUID) (*models
 	return &rule, nil
 }
 
-// ListRules retrieves all rules
+// ListRules retrieves all rules in evaluation order, the same order LoadRules uses
 func (r *PostgresRepository) ListRules(ctx context.Context) ([]models.Rule, error) {
 	query := `
-		SELECT id, name, description, action, priority, enabled, conditions, schema_id, created_at, updated_at
+		SELECT id, name, description, action, priority, score, enabled, mode, stop_processing, timeout_ms, conditions, schema_id, version, version_id, created_at, updated_at
 		FROM rules
-		ORDER BY priority ASC
+		ORDER BY priority ASC, created_at ASC, id ASC
 	`
 
 	rows, err := r.db.Query(ctx, query)
@@ -188,8 +211,8 @@ func (r *PostgresRepository) ListRules(ctx context.Context) ([]models.Rule, erro
 
 		err := rows.Scan(
 			&rule.ID, &rule.Name, &rule.Description, &rule.Action,
-			&rule.Priority, &rule.Enabled, &conditionsJSON,
-			&rule.SchemaID, &rule.CreatedAt, &rule.UpdatedAt,
+			&rule.Priority, &rule.Score, &rule.Enabled, &rule.Mode, &rule.StopProcessing, &rule.TimeoutMs, &conditionsJSON,
+			&rule.SchemaID, &rule.Version, &rule.VersionID, &rule.CreatedAt, &rule.UpdatedAt,
 		)
 		if err != nil {
 			continue
@@ -205,30 +228,52 @@ func (r *PostgresRepository) ListRules(ctx context.Context) ([]models.Rule, erro
 }
 
 // UpdateRule updates an existing rule
+// Previous versions are kept: the new content is stored as the next version
 func (r *PostgresRepository) UpdateRule(ctx context.Context, rule *models.Rule) error {
 	conditionsJSON, err := json.Marshal(rule.Conditions)
 	if err != nil {
 		return fmt.Errorf("failed to marshal conditions: %w", err)
 	}
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Lock the rule so concurrent updates get consecutive version numbers
+	var currentVersion int
+	err = tx.QueryRow(ctx, `SELECT version, created_at FROM rules WHERE id = $1 FOR UPDATE`, rule.ID).
+		Scan(&currentVersion, &rule.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	rule.Version = currentVersion + 1
+	rule.VersionID = uuid.New()
+	if err := insertVersion(ctx, tx, rule); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE rules
 		SET name = $2, description = $3, action = $4, 
-		    priority = $5, enabled = $6, conditions = $7, schema_id = $8, updated_at = $9
+		    priority = $5, score = $6, enabled = $7, mode = $8, stop_processing = $9, timeout_ms = $10, conditions = $11, schema_id = $12,
+		    version = $13, version_id = $14, updated_at = $15
 		WHERE id = $1
 	`
 
-	result, err := r.db.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		rule.ID, rule.Name, rule.Description, rule.Action,
-		rule.Priority, rule.Enabled, conditionsJSON, rule.SchemaID, rule.UpdatedAt,
+		rule.Priority, rule.Score, rule.Enabled, rule.Mode, rule.StopProcessing, rule.TimeoutMs, conditionsJSON, rule.SchemaID,
+		rule.Version, rule.VersionID, rule.UpdatedAt,
 	)
-
 	if err != nil {
 		return err
 	}
 
-	if result.RowsAffected() == 0 {
-		return pgx.ErrNoRows
+	if err := tx.Commit(ctx); err != nil {
+		return err
 	}
 
 	// Invalidate cache on update
@@ -258,3 +303,74 @@ func (r *PostgresRepository) DeleteRule(ctx context.Context, id uuid.UUID) error
 
 	return nil
 }
+
+// ListRuleVersions retrieves all versions of a rule, newest first
+// Versions outlive the rule, so the history of a deleted rule is still available
+func (r *PostgresRepository) ListRuleVersions(ctx context.Context, ruleID uuid.UUID) ([]models.RuleVersion, error) {
+	query := `
+		SELECT id, rule_id, version, snapshot, created_at
+		FROM rule_versions
+		WHERE rule_id = $1
+		ORDER BY version DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]models.RuleVersion, 0)
+	for rows.Next() {
+		version, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *version)
+	}
+
+	return versions, rows.Err()
+}
+
+// GetRuleVersion retrieves a single version of a rule
+func (r *PostgresRepository) GetRuleVersion(ctx context.Context, ruleID uuid.UUID, version int) (*models.RuleVersion, error) {
+	query := `
+		SELECT id, rule_id, version, snapshot, created_at
+		FROM rule_versions
+		WHERE rule_id = $1 AND version = $2
+	`
+
+	return scanVersion(r.db.QueryRow(ctx, query, ruleID, version))
+}
+
+// insertVersion stores an immutable snapshot of the rule as its current version
+func insertVersion(ctx context.Context, tx pgx.Tx, rule *models.Rule) error {
+	snapshot, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule snapshot: %w", err)
+	}
+
+	query := `
+		INSERT INTO rule_versions (id, rule_id, version, snapshot, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err = tx.Exec(ctx, query, rule.VersionID, rule.ID, rule.Version, snapshot, rule.UpdatedAt)
+	return err
+}
+
+// scanVersion scans a rule_versions row and decodes its snapshot
+func scanVersion(row pgx.Row) (*models.RuleVersion, error) {
+	var version models.RuleVersion
+	var snapshot []byte
+
+	if err := row.Scan(&version.ID, &version.RuleID, &version.Version, &snapshot, &version.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(snapshot, &version.Rule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rule snapshot: %w", err)
+	}
+
+	return &version, nil
+}
@@ -0,0 +1,118 @@
+package sanctions
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/algo-shield/algo-shield/src/pkg/fuzzy"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// countryNameVariants covers names used by sanctions lists that differ from the CLDR English names
+var countryNameVariants = map[string]string{
+	"Burma":                                  "MM",
+	"Korea, North":                           "KP",
+	"Korea, Democratic People's Republic of": "KP",
+	"Democratic People's Republic of Korea":  "KP",
+	"Korea, South":                           "KR",
+	"Korea, Republic of":                     "KR",
+	"Iran, Islamic Republic of":              "IR",
+	"Syrian Arab Republic":                   "SY",
+	"Russian Federation":                     "RU",
+	"Congo, Democratic Republic of the":      "CD",
+	"Democratic Republic of the Congo":       "CD",
+	"Congo, Republic of the":                 "CG",
+	"Republic of the Congo":                  "CG",
+	"Lao People's Democratic Republic":       "LA",
+	"Venezuela, Bolivarian Republic of":      "VE",
+	"Bolivia, Plurinational State of":        "BO",
+	"Tanzania, United Republic of":           "TZ",
+	"Moldova, Republic of":                   "MD",
+	"Viet Nam":                               "VN",
+	"Ivory Coast":                            "CI",
+	"Turkey":                                 "TR",
+	"Türkiye":                                "TR",
+	"West Bank":                              "PS",
+	"Gaza":                                   "PS",
+	"Palestinian Territories":                "PS",
+	"United States of America":               "US",
+	"United Kingdom of Great Britain and Northern Ireland": "GB",
+	"Gambia, The":                     "GM",
+	"Bahamas, The":                    "BS",
+	"Macedonia":                       "MK",
+	"Czech Republic":                  "CZ",
+	"Swaziland":                       "SZ",
+	"Cabo Verde":                      "CV",
+	"Brunei Darussalam":               "BN",
+	"Micronesia, Federated States of": "FM",
+	"Macau":                           "MO",
+}
+
+// countryKeyIgnoredWords are dropped when comparing country names, so "Bosnia & Herzegovina"
+// matches "Bosnia and Herzegovina" and "Gambia, The" matches "The Gambia"
+var countryKeyIgnoredWords = map[string]bool{"and": true, "the": true, "of": true}
+
+var (
+	countryNamesOnce sync.Once
+	countryNames     map[string]string // Normalized English country name to ISO 3166-1 alpha-2 code
+)
+
+// CountryCode returns the ISO 3166-1 alpha-2 code of a country named in a sanctions list
+// Two-letter codes are returned upper-cased. Returns "" for names it does not recognise.
+func CountryCode(name string) string {
+	countryNamesOnce.Do(loadCountryNames)
+
+	name = strings.TrimSpace(name)
+	if len(name) == 2 {
+		if region, err := language.ParseRegion(name); err == nil && region.IsCountry() {
+			return region.Canonicalize().String()
+		}
+	}
+
+	return countryNames[countryKey(name)]
+}
+
+// loadCountryNames indexes the English names of every ISO 3166-1 alpha-2 country
+// Display names such as "Myanmar (Burma)" and "Hong Kong SAR China" are also indexed
+// without their qualifier
+func loadCountryNames() {
+	names := display.English.Regions()
+	countryNames = make(map[string]string)
+	for first := 'A'; first <= 'Z'; first++ {
+		for second := 'A'; second <= 'Z'; second++ {
+			region, err := language.ParseRegion(string([]rune{first, second}))
+			if err != nil || !region.IsCountry() || region.Canonicalize() != region {
+				continue // Not a country, or a deprecated code such as UK for GB
+			}
+			name := names.Name(region)
+			if name == "" {
+				continue
+			}
+			countryNames[countryKey(name)] = region.String()
+			if i := strings.Index(name, " ("); i > 0 {
+				countryNames[countryKey(name[:i])] = region.String()
+			}
+			if short, ok := strings.CutSuffix(name, " SAR China"); ok {
+				countryNames[countryKey(short)] = region.String()
+			}
+		}
+	}
+	for name, code := range countryNameVariants {
+		countryNames[countryKey(name)] = code
+	}
+}
+
+// countryKey normalizes a country name for lookup
+func countryKey(name string) string {
+	words := make([]string, 0)
+	for _, word := range strings.Fields(fuzzy.NormalizeName(name)) {
+		if word == "st" {
+			word = "saint"
+		}
+		if !countryKeyIgnoredWords[word] {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
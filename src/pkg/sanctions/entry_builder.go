@@ -0,0 +1,95 @@
+package sanctions
+
+import (
+	"strings"
+
+	"github.com/algo-shield/algo-shield/src/pkg/fuzzy"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+)
+
+// entryBuilder accumulates the names, countries and programmes of a parsed entry,
+// dropping blanks and duplicates
+type entryBuilder struct {
+	entry     models.SanctionsEntry
+	names     map[string]bool // Normalized names already added
+	countries map[string]bool
+	programs  map[string]bool
+}
+
+func newEntryBuilder(source models.SanctionsSource, sourceID string, entityType models.SanctionsEntityType) *entryBuilder {
+	return &entryBuilder{
+		entry: models.SanctionsEntry{
+			Source:    source,
+			SourceID:  strings.TrimSpace(sourceID),
+			Type:      entityType,
+			Aliases:   make([]string, 0),
+			Countries: make([]string, 0),
+			Programs:  make([]string, 0),
+		},
+		names:     make(map[string]bool),
+		countries: make(map[string]bool),
+		programs:  make(map[string]bool),
+	}
+}
+
+// addName adds a name, the first one added becomes the entry's primary name
+func (b *entryBuilder) addName(name string) {
+	name = strings.Join(strings.Fields(name), " ")
+	normalized := fuzzy.NormalizeName(name)
+	if normalized == "" || b.names[normalized] {
+		return
+	}
+	b.names[normalized] = true
+
+	if b.entry.Name == "" {
+		b.entry.Name = name
+	} else {
+		b.entry.Aliases = append(b.entry.Aliases, name)
+	}
+}
+
+// addCountry adds a country given by name or ISO code, ignoring names it does not recognise
+func (b *entryBuilder) addCountry(country string) {
+	code := CountryCode(country)
+	if code == "" || b.countries[code] {
+		return
+	}
+	b.countries[code] = true
+	b.entry.Countries = append(b.entry.Countries, code)
+}
+
+func (b *entryBuilder) addProgram(program string) {
+	program = strings.TrimSpace(program)
+	if program == "" || b.programs[program] {
+		return
+	}
+	b.programs[program] = true
+	b.entry.Programs = append(b.entry.Programs, program)
+}
+
+func (b *entryBuilder) addRemark(remark string) {
+	remark = strings.TrimSpace(remark)
+	if remark == "" {
+		return
+	}
+	if b.entry.Remarks != "" {
+		b.entry.Remarks += " "
+	}
+	b.entry.Remarks += remark
+}
+
+// build returns the entry, or false when it has no identifier or name to screen against
+func (b *entryBuilder) build() (models.SanctionsEntry, bool) {
+	return b.entry, b.entry.SourceID != "" && b.entry.Name != ""
+}
+
+// joinName joins name parts, skipping empty ones
+func joinName(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
@@ -0,0 +1,90 @@
+package sanctions
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+)
+
+// euCountry is an element of the EU list carrying an ISO country code
+type euCountry struct {
+	Code string `xml:"countryIso2Code,attr"`
+}
+
+// euSanctionEntity is a sanctionEntity element of the EU consolidated list XML (version 1.1)
+type euSanctionEntity struct {
+	LogicalID  string   `xml:"logicalId,attr"`
+	Remarks    []string `xml:"remark"`
+	Regulation []struct {
+		Programme string `xml:"programme,attr"`
+	} `xml:"regulation"`
+	SubjectType struct {
+		Code string `xml:"code,attr"`
+	} `xml:"subjectType"`
+	NameAliases []struct {
+		WholeName  string `xml:"wholeName,attr"`
+		FirstName  string `xml:"firstName,attr"`
+		MiddleName string `xml:"middleName,attr"`
+		LastName   string `xml:"lastName,attr"`
+	} `xml:"nameAlias"`
+	Citizenships []euCountry `xml:"citizenship"`
+	Addresses    []euCountry `xml:"address"`
+	Birthdates   []euCountry `xml:"birthdate"`
+}
+
+// ParseEUXML reads the EU consolidated financial sanctions list in its XML format
+// The file is streamed one entry at a time
+func ParseEUXML(r io.Reader) ([]models.SanctionsEntry, error) {
+	entries := make([]models.SanctionsEntry, 0)
+	err := decodeElements(r, func(decoder *xml.Decoder, start xml.StartElement) error {
+		var entity euSanctionEntity
+		if err := decoder.DecodeElement(&entity, &start); err != nil {
+			return err
+		}
+
+		builder := newEntryBuilder(models.SanctionsSourceEU, entity.LogicalID, euEntityType(entity.SubjectType.Code))
+		for _, alias := range entity.NameAliases {
+			name := alias.WholeName
+			if strings.TrimSpace(name) == "" {
+				name = joinName(alias.FirstName, alias.MiddleName, alias.LastName)
+			}
+			builder.addName(name)
+		}
+		for _, group := range [][]euCountry{entity.Citizenships, entity.Addresses, entity.Birthdates} {
+			for _, country := range group {
+				builder.addCountry(country.Code) // "00" marks an unknown country and is ignored
+			}
+		}
+		for _, regulation := range entity.Regulation {
+			builder.addProgram(regulation.Programme)
+		}
+		for _, remark := range entity.Remarks {
+			builder.addRemark(remark)
+		}
+
+		if entry, ok := builder.build(); ok {
+			entries = append(entries, entry)
+		}
+		return nil
+	}, "sanctionEntity")
+	if err != nil {
+		return nil, fmt.Errorf("invalid EU sanctions XML: %w", err)
+	}
+
+	return entries, nil
+}
+
+// euEntityType maps the EU subject type code
+func euEntityType(code string) models.SanctionsEntityType {
+	switch code {
+	case "person":
+		return models.SanctionsEntityIndividual
+	case "enterprise":
+		return models.SanctionsEntityEntity
+	default:
+		return models.SanctionsEntityUnknown
+	}
+}
@@ -0,0 +1,212 @@
+package sanctions
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+)
+
+// ofacNull is the placeholder OFAC CSV files use for empty fields
+const ofacNull = "-0-"
+
+// ofacSDNEntry is an sdnEntry element of the OFAC SDN XML file (sdn.xml)
+type ofacSDNEntry struct {
+	UID       string   `xml:"uid"`
+	FirstName string   `xml:"firstName"`
+	LastName  string   `xml:"lastName"`
+	Type      string   `xml:"sdnType"`
+	Programs  []string `xml:"programList>program"`
+	Remarks   string   `xml:"remarks"`
+	Akas      []struct {
+		FirstName string `xml:"firstName"`
+		LastName  string `xml:"lastName"`
+	} `xml:"akaList>aka"`
+	Addresses     []string `xml:"addressList>address>country"`
+	Nationalities []string `xml:"nationalityList>nationality>country"`
+	Citizenships  []string `xml:"citizenshipList>citizenship>country"`
+}
+
+// ParseOFACXML reads the OFAC SDN list in its XML format (sdn.xml)
+// The file is streamed one entry at a time
+func ParseOFACXML(r io.Reader) ([]models.SanctionsEntry, error) {
+	entries := make([]models.SanctionsEntry, 0)
+	err := decodeElements(r, func(decoder *xml.Decoder, start xml.StartElement) error {
+		var sdn ofacSDNEntry
+		if err := decoder.DecodeElement(&sdn, &start); err != nil {
+			return err
+		}
+
+		builder := newEntryBuilder(models.SanctionsSourceOFAC, sdn.UID, ofacEntityType(sdn.Type))
+		builder.addName(joinName(sdn.FirstName, sdn.LastName))
+		for _, aka := range sdn.Akas {
+			builder.addName(joinName(aka.FirstName, aka.LastName))
+		}
+		for _, country := range slices.Concat(sdn.Nationalities, sdn.Citizenships, sdn.Addresses) {
+			builder.addCountry(country)
+		}
+		for _, program := range sdn.Programs {
+			builder.addProgram(program)
+		}
+		builder.addRemark(sdn.Remarks)
+
+		if entry, ok := builder.build(); ok {
+			entries = append(entries, entry)
+		}
+		return nil
+	}, "sdnEntry")
+	if err != nil {
+		return nil, fmt.Errorf("invalid OFAC SDN XML: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ParseOFACCSV reads the OFAC SDN list in its CSV format
+// sdn is sdn.csv; alt (alt.csv, aliases) and add (add.csv, addresses) are optional and may be nil
+func ParseOFACCSV(sdn, alt, add io.Reader) ([]models.SanctionsEntry, error) {
+	builders := make(map[string]*entryBuilder)
+	order := make([]string, 0)
+
+	// sdn.csv: ent_num, SDN_Name, SDN_Type, Program, Title, Call_Sign, Vess_type, Tonnage, GRT, Vess_flag, Vess_owner, Remarks
+	err := readOFACCSV(sdn, 3, func(record []string) {
+		id := record[0]
+		builder := newEntryBuilder(models.SanctionsSourceOFAC, id, ofacEntityType(record[2]))
+		builder.addName(ofacCSVName(record[1]))
+		// Programmes are written as "SDGT] [IRGC"
+		for _, program := range strings.FieldsFunc(record[3], func(r rune) bool { return r == '[' || r == ']' }) {
+			builder.addProgram(program)
+		}
+		if len(record) > 11 {
+			builder.addRemark(ofacField(record[11]))
+		}
+		if _, exists := builders[id]; !exists {
+			order = append(order, id)
+		}
+		builders[id] = builder
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid OFAC sdn.csv: %w", err)
+	}
+
+	// alt.csv: ent_num, alt_num, alt_type, alt_name, alt_remarks
+	if alt != nil {
+		err := readOFACCSV(alt, 4, func(record []string) {
+			if builder, ok := builders[record[0]]; ok {
+				builder.addName(ofacCSVName(record[3]))
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid OFAC alt.csv: %w", err)
+		}
+	}
+
+	// add.csv: ent_num, add_num, address, city_state_zip, country, add_remarks
+	if add != nil {
+		err := readOFACCSV(add, 5, func(record []string) {
+			if builder, ok := builders[record[0]]; ok {
+				builder.addCountry(ofacField(record[4]))
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid OFAC add.csv: %w", err)
+		}
+	}
+
+	entries := make([]models.SanctionsEntry, 0, len(order))
+	for _, id := range order {
+		if entry, ok := builders[id].build(); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// readOFACCSV calls fn with the trimmed fields of each record that has the field at index lastField
+// OFAC files have no header and end with a stray end-of-file character, records without
+// a numeric entity number are skipped
+func readOFACCSV(r io.Reader, lastField int, fn func(record []string)) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) <= lastField {
+			continue
+		}
+		for i := range record {
+			record[i] = ofacField(record[i])
+		}
+		if record[0] == "" || strings.Trim(record[0], "0123456789") != "" {
+			continue
+		}
+		fn(record)
+	}
+}
+
+// ofacField trims a CSV field, turning the OFAC null placeholder into ""
+func ofacField(field string) string {
+	field = strings.TrimSpace(field)
+	if field == ofacNull {
+		return ""
+	}
+	return field
+}
+
+// ofacCSVName turns "LAST, First" into "First LAST"
+// Names with several commas, usually organisations, are kept as written
+func ofacCSVName(name string) string {
+	last, first, found := strings.Cut(name, ", ")
+	if !found || strings.Contains(first, ",") {
+		return name
+	}
+	return joinName(first, last)
+}
+
+// ofacEntityType maps the SDN type, empty for organisations in the CSV format
+func ofacEntityType(sdnType string) models.SanctionsEntityType {
+	switch strings.ToLower(strings.TrimSpace(sdnType)) {
+	case "individual":
+		return models.SanctionsEntityIndividual
+	case "vessel":
+		return models.SanctionsEntityVessel
+	case "aircraft":
+		return models.SanctionsEntityAircraft
+	case "entity", "", ofacNull:
+		return models.SanctionsEntityEntity
+	default:
+		return models.SanctionsEntityUnknown
+	}
+}
+
+// decodeElements streams an XML document, calling fn for every element with one of the given local names
+// Namespaces are ignored, as the published lists change them between versions
+func decodeElements(r io.Reader, fn func(decoder *xml.Decoder, start xml.StartElement) error, names ...string) error {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok && slices.Contains(names, start.Name.Local) {
+			if err := fn(decoder, start); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package sanctions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseOFACXML_WhenSDNEntries_ThenNormalizesNamesAndCountries(t *testing.T) {
+	doc := `<?xml version="1.0" standalone="yes"?>
+<sdnList xmlns="https://sanctionslistservice.ofac.treas.gov/api/PublicationPreview/exports/XML">
+  <publshInformation><Publish_Date>01/02/2024</Publish_Date></publshInformation>
+  <sdnEntry>
+    <uid>36</uid>
+    <lastName>AEROCARIBBEAN AIRLINES</lastName>
+    <sdnType>Entity</sdnType>
+    <programList><program>CUBA</program></programList>
+    <akaList><aka><uid>12</uid><type>a.k.a.</type><lastName>AERO-CARIBBEAN</lastName></aka></akaList>
+    <addressList><address><city>Havana</city><country>Cuba</country></address></addressList>
+  </sdnEntry>
+  <sdnEntry>
+    <uid>7157</uid>
+    <firstName>Ivan</firstName>
+    <lastName>PETROV</lastName>
+    <sdnType>Individual</sdnType>
+    <programList><program>UKRAINE-EO13660</program><program>RUSSIA-EO14024</program></programList>
+    <akaList>
+      <aka><firstName>Ivan</firstName><lastName>Petrov</lastName></aka>
+      <aka><firstName>Ioann</firstName><lastName>PETROFF</lastName></aka>
+    </akaList>
+    <nationalityList><nationality><country>Russia</country></nationality></nationalityList>
+    <remarks>DOB 01 Jan 1970.</remarks>
+  </sdnEntry>
+</sdnList>`
+
+	entries, err := ParseOFACXML(strings.NewReader(doc))
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "36", entries[0].SourceID)
+	assert.Equal(t, models.SanctionsEntityEntity, entries[0].Type)
+	assert.Equal(t, []string{"AERO-CARIBBEAN"}, entries[0].Aliases)
+	assert.Equal(t, []string{"CU"}, entries[0].Countries)
+
+	ivan := entries[1]
+	assert.Equal(t, models.SanctionsSourceOFAC, ivan.Source)
+	assert.Equal(t, models.SanctionsEntityIndividual, ivan.Type)
+	assert.Equal(t, "Ivan PETROV", ivan.Name)
+	// The alias differing only by case is dropped
+	assert.Equal(t, []string{"Ioann PETROFF"}, ivan.Aliases)
+	assert.Equal(t, []string{"RU"}, ivan.Countries)
+	assert.Equal(t, []string{"UKRAINE-EO13660", "RUSSIA-EO14024"}, ivan.Programs)
+	assert.Equal(t, "DOB 01 Jan 1970.", ivan.Remarks)
+}
+
+func Test_ParseOFACXML_WhenMalformed_ThenReturnsError(t *testing.T) {
+	_, err := ParseOFACXML(strings.NewReader(`<sdnList><sdnEntry><uid>1</uid>`))
+
+	assert.Error(t, err)
+}
+
+func Test_ParseOFACCSV_WhenAliasesAndAddresses_ThenMergesByEntityNumber(t *testing.T) {
+	sdn := `36,"AEROCARIBBEAN AIRLINES",-0- ,"CUBA",-0- ,-0- ,-0- ,-0- ,-0- ,-0- ,-0- ,-0- 
+7157,"PETROV, Ivan","individual","UKRAINE-EO13660] [RUSSIA-EO14024",-0- ,-0- ,-0- ,-0- ,-0- ,-0- ,-0- ,"DOB 01 Jan 1970."
+` + "\x1a"
+	alt := `7157,101,"aka","PETROFF, Ioann",-0- 
+9999,102,"aka","UNKNOWN, Entity",-0- 
+`
+	add := `36,25,-0- ,"Havana","Cuba",-0- 
+7157,26,-0- ,"Moscow","Russia",-0- 
+`
+
+	entries, err := ParseOFACCSV(strings.NewReader(sdn), strings.NewReader(alt), strings.NewReader(add))
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "AEROCARIBBEAN AIRLINES", entries[0].Name)
+	assert.Equal(t, models.SanctionsEntityEntity, entries[0].Type)
+	assert.Equal(t, []string{"CU"}, entries[0].Countries)
+	assert.Empty(t, entries[0].Remarks)
+
+	ivan := entries[1]
+	assert.Equal(t, "Ivan PETROV", ivan.Name)
+	assert.Equal(t, models.SanctionsEntityIndividual, ivan.Type)
+	assert.Equal(t, []string{"Ioann PETROFF"}, ivan.Aliases)
+	assert.Equal(t, []string{"RU"}, ivan.Countries)
+	assert.Equal(t, []string{"UKRAINE-EO13660", "RUSSIA-EO14024"}, ivan.Programs)
+	assert.Equal(t, "DOB 01 Jan 1970.", ivan.Remarks)
+}
+
+func Test_ParseOFACCSV_WhenOnlySDNFile_ThenParsesWithoutAliases(t *testing.T) {
+	entries, err := ParseOFACCSV(strings.NewReader(`7157,"PETROV, Ivan","individual","RUSSIA-EO14024"`), nil, nil)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Empty(t, entries[0].Aliases)
+	assert.Empty(t, entries[0].Countries)
+}
+
+func Test_ParseEUXML_WhenSanctionEntities_ThenReadsAliasesAndCountries(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<export xmlns="http://eu.europa.ec/fpi/fsd/export" generationDate="2024-01-02T10:00:00.000+01:00">
+  <sanctionEntity designationDate="2022-02-23" logicalId="13">
+    <remark>Member of the State Duma.</remark>
+    <regulation programme="UKR" regulationType="amendment"/>
+    <subjectType code="person" classificationCode="P"/>
+    <nameAlias firstName="Ivan" lastName="Petrov" wholeName="Ivan Petrov" strong="true"/>
+    <nameAlias wholeName="Иван Петров" strong="true"/>
+    <nameAlias firstName="Ioann" lastName="Petroff" strong="false"/>
+    <citizenship countryIso2Code="RU" countryDescription="RUSSIAN FEDERATION"/>
+    <birthdate birthdate="1970-01-01" countryIso2Code="UA"/>
+  </sanctionEntity>
+  <sanctionEntity logicalId="14">
+    <regulation programme="IRN"/>
+    <subjectType code="enterprise" classificationCode="E"/>
+    <nameAlias wholeName="Example Shipping Company"/>
+    <address countryIso2Code="IR" countryDescription="IRAN"/>
+  </sanctionEntity>
+</export>`
+
+	entries, err := ParseEUXML(strings.NewReader(doc))
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	ivan := entries[0]
+	assert.Equal(t, models.SanctionsSourceEU, ivan.Source)
+	assert.Equal(t, "13", ivan.SourceID)
+	assert.Equal(t, models.SanctionsEntityIndividual, ivan.Type)
+	assert.Equal(t, "Ivan Petrov", ivan.Name)
+	assert.Equal(t, []string{"Иван Петров", "Ioann Petroff"}, ivan.Aliases)
+	assert.Equal(t, []string{"RU", "UA"}, ivan.Countries)
+	assert.Equal(t, []string{"UKR"}, ivan.Programs)
+	assert.Equal(t, "Member of the State Duma.", ivan.Remarks)
+
+	assert.Equal(t, models.SanctionsEntityEntity, entries[1].Type)
+	assert.Equal(t, []string{"IR"}, entries[1].Countries)
+}
+
+func Test_ParseUNXML_WhenIndividualsAndEntities_ThenReadsBoth(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<CONSOLIDATED_LIST dateGenerated="2024-01-02T10:00:00">
+  <INDIVIDUALS>
+    <INDIVIDUAL>
+      <DATAID>6908555</DATAID>
+      <FIRST_NAME>IVAN</FIRST_NAME>
+      <SECOND_NAME>PETROV</SECOND_NAME>
+      <UN_LIST_TYPE>DPRK</UN_LIST_TYPE>
+      <REFERENCE_NUMBER>KPi.001</REFERENCE_NUMBER>
+      <COMMENTS1>Involved in procurement.</COMMENTS1>
+      <NATIONALITY><VALUE>Democratic People's Republic of Korea</VALUE></NATIONALITY>
+      <INDIVIDUAL_ALIAS><QUALITY>Good</QUALITY><ALIAS_NAME>Ioann Petroff</ALIAS_NAME></INDIVIDUAL_ALIAS>
+      <INDIVIDUAL_ALIAS><QUALITY>Low</QUALITY><ALIAS_NAME></ALIAS_NAME></INDIVIDUAL_ALIAS>
+      <INDIVIDUAL_ADDRESS><COUNTRY>China</COUNTRY></INDIVIDUAL_ADDRESS>
+    </INDIVIDUAL>
+  </INDIVIDUALS>
+  <ENTITIES>
+    <ENTITY>
+      <DATAID>110403</DATAID>
+      <FIRST_NAME>EXAMPLE TRADING CORPORATION</FIRST_NAME>
+      <UN_LIST_TYPE>DPRK</UN_LIST_TYPE>
+      <ENTITY_ALIAS><ALIAS_NAME>EXTRACO</ALIAS_NAME></ENTITY_ALIAS>
+      <ENTITY_ADDRESS><COUNTRY>Democratic People's Republic of Korea</COUNTRY></ENTITY_ADDRESS>
+    </ENTITY>
+  </ENTITIES>
+</CONSOLIDATED_LIST>`
+
+	entries, err := ParseUNXML(strings.NewReader(doc))
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	ivan := entries[0]
+	assert.Equal(t, models.SanctionsSourceUN, ivan.Source)
+	assert.Equal(t, "KPi.001", ivan.SourceID)
+	assert.Equal(t, models.SanctionsEntityIndividual, ivan.Type)
+	assert.Equal(t, "IVAN PETROV", ivan.Name)
+	assert.Equal(t, []string{"Ioann Petroff"}, ivan.Aliases)
+	assert.Equal(t, []string{"KP", "CN"}, ivan.Countries)
+	assert.Equal(t, []string{"DPRK"}, ivan.Programs)
+	assert.Equal(t, "Involved in procurement.", ivan.Remarks)
+
+	entity := entries[1]
+	// Entities without a reference number fall back to the data ID
+	assert.Equal(t, "110403", entity.SourceID)
+	assert.Equal(t, models.SanctionsEntityEntity, entity.Type)
+	assert.Equal(t, []string{"EXTRACO"}, entity.Aliases)
+	assert.Equal(t, []string{"KP"}, entity.Countries)
+}
+
+func Test_CountryCode_WhenNamesAndCodes_ThenReturnsISOCode(t *testing.T) {
+	cases := map[string]string{
+		"RU":                                    "RU",
+		"gb":                                    "GB",
+		"UK":                                    "GB",
+		"Russia":                                "RU",
+		"RUSSIAN FEDERATION":                    "RU",
+		"United Kingdom":                        "GB",
+		"Burma":                                 "MM",
+		"Myanmar":                               "MM",
+		"Bosnia & Herzegovina":                  "BA",
+		"Türkiye":                               "TR",
+		"St. Kitts & Nevis":                     "KN",
+		"Democratic People's Republic of Korea": "KP",
+		"Atlantis":                              "",
+		"":                                      "",
+	}
+	for name, code := range cases {
+		assert.Equal(t, code, CountryCode(name), name)
+	}
+}
@@ -0,0 +1,78 @@
+package sanctions
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+)
+
+// unEntry is an INDIVIDUAL or ENTITY element of the UN Security Council consolidated list XML
+// Entities only use FIRST_NAME
+type unEntry struct {
+	DataID          string   `xml:"DATAID"`
+	ReferenceNumber string   `xml:"REFERENCE_NUMBER"`
+	FirstName       string   `xml:"FIRST_NAME"`
+	SecondName      string   `xml:"SECOND_NAME"`
+	ThirdName       string   `xml:"THIRD_NAME"`
+	FourthName      string   `xml:"FOURTH_NAME"`
+	ListType        string   `xml:"UN_LIST_TYPE"`
+	Comments        string   `xml:"COMMENTS1"`
+	Nationalities   []string `xml:"NATIONALITY>VALUE"`
+	Aliases         []string `xml:"INDIVIDUAL_ALIAS>ALIAS_NAME"`
+	EntityAliases   []string `xml:"ENTITY_ALIAS>ALIAS_NAME"`
+	Addresses       []string `xml:"INDIVIDUAL_ADDRESS>COUNTRY"`
+	EntityAddresses []string `xml:"ENTITY_ADDRESS>COUNTRY"`
+}
+
+// ParseUNXML reads the UN Security Council consolidated sanctions list in its XML format
+// The file is streamed one entry at a time
+func ParseUNXML(r io.Reader) ([]models.SanctionsEntry, error) {
+	entries := make([]models.SanctionsEntry, 0)
+	parse := func(entityType models.SanctionsEntityType) func(*xml.Decoder, xml.StartElement) error {
+		return func(decoder *xml.Decoder, start xml.StartElement) error {
+			var un unEntry
+			if err := decoder.DecodeElement(&un, &start); err != nil {
+				return err
+			}
+
+			// The reference number, e.g. "KPi.033", is the identifier published with designations
+			id := un.ReferenceNumber
+			if id == "" {
+				id = un.DataID
+			}
+
+			builder := newEntryBuilder(models.SanctionsSourceUN, id, entityType)
+			builder.addName(joinName(un.FirstName, un.SecondName, un.ThirdName, un.FourthName))
+			for _, alias := range slices.Concat(un.Aliases, un.EntityAliases) {
+				builder.addName(alias)
+			}
+			for _, country := range slices.Concat(un.Nationalities, un.Addresses, un.EntityAddresses) {
+				builder.addCountry(country)
+			}
+			builder.addProgram(un.ListType)
+			builder.addRemark(un.Comments)
+
+			if entry, ok := builder.build(); ok {
+				entries = append(entries, entry)
+			}
+			return nil
+		}
+	}
+
+	individuals := parse(models.SanctionsEntityIndividual)
+	entities := parse(models.SanctionsEntityEntity)
+	err := decodeElements(r, func(decoder *xml.Decoder, start xml.StartElement) error {
+		if start.Name.Local == "INDIVIDUAL" {
+			return individuals(decoder, start)
+		}
+		return entities(decoder, start)
+	}, "INDIVIDUAL", "ENTITY")
+	if err != nil {
+		return nil, fmt.Errorf("invalid UN sanctions XML: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,176 @@
+// Package sanctions imports published sanctions lists and screens names against them.
+package sanctions
+
+import (
+	"context"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// Reader defines the interface for loading sanctions entries (used by the screener)
+type Reader interface {
+	// ListEntries returns every entry of every source with its aliases
+	ListEntries(ctx context.Context) ([]models.SanctionsEntry, error)
+}
+
+// Repository defines the interface for importing sanctions lists
+// Every import is announced on models.SanctionsInvalidateChannel
+type Repository interface {
+	Reader
+	// ReplaceSource replaces every entry of a source with the entries of a new import, atomically
+	ReplaceSource(ctx context.Context, source models.SanctionsSource, fileName string, entries []models.SanctionsEntry) error
+	// ListImports returns the last import of each source, ordered by source
+	ListImports(ctx context.Context) ([]models.SanctionsImport, error)
+}
+
+// PostgresRepository is the PostgreSQL implementation of Repository
+type PostgresRepository struct {
+	db    *pgxpool.Pool
+	redis *redis.Client
+}
+
+// NewPostgresRepository creates a new PostgreSQL sanctions repository
+// redis may be nil, in which case screeners only see imports after a restart
+func NewPostgresRepository(db *pgxpool.Pool, redis *redis.Client) *PostgresRepository {
+	return &PostgresRepository{
+		db:    db,
+		redis: redis,
+	}
+}
+
+func (r *PostgresRepository) ListEntries(ctx context.Context) ([]models.SanctionsEntry, error) {
+	query := `
+		SELECT e.id, e.source, e.source_id, e.entity_type, e.name, e.countries, e.programs, e.remarks, e.imported_at,
+		       COALESCE(array_agg(a.name ORDER BY a.position) FILTER (WHERE a.name IS NOT NULL), '{}')
+		FROM sanctions_entries e
+		LEFT JOIN sanctions_aliases a ON a.entry_id = e.id
+		GROUP BY e.id
+		ORDER BY e.source, e.source_id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.SanctionsEntry, 0)
+	for rows.Next() {
+		var entry models.SanctionsEntry
+		err := rows.Scan(
+			&entry.ID, &entry.Source, &entry.SourceID, &entry.Type, &entry.Name,
+			&entry.Countries, &entry.Programs, &entry.Remarks, &entry.ImportedAt, &entry.Aliases,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *PostgresRepository) ReplaceSource(ctx context.Context, source models.SanctionsSource, fileName string, entries []models.SanctionsEntry) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Aliases are removed by the cascade
+	if _, err := tx.Exec(ctx, `DELETE FROM sanctions_entries WHERE source = $1`, source); err != nil {
+		return err
+	}
+
+	// A list publishing the same identifier twice keeps its first entry
+	now := time.Now()
+	seen := make(map[string]bool, len(entries))
+	entryRows := make([][]any, 0, len(entries))
+	aliasRows := make([][]any, 0, len(entries))
+	for _, entry := range entries {
+		if seen[entry.SourceID] {
+			continue
+		}
+		seen[entry.SourceID] = true
+
+		id := uuid.New()
+		entryRows = append(entryRows, []any{
+			id, string(source), entry.SourceID, string(entry.Type), entry.Name,
+			nonNil(entry.Countries), nonNil(entry.Programs), entry.Remarks, now,
+		})
+		for position, alias := range entry.Aliases {
+			aliasRows = append(aliasRows, []any{id, position, alias})
+		}
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"sanctions_entries"},
+		[]string{"id", "source", "source_id", "entity_type", "name", "countries", "programs", "remarks", "imported_at"},
+		pgx.CopyFromRows(entryRows),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"sanctions_aliases"},
+		[]string{"entry_id", "position", "name"},
+		pgx.CopyFromRows(aliasRows),
+	)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO sanctions_imports (source, file_name, entry_count, imported_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (source) DO UPDATE SET
+			file_name = EXCLUDED.file_name,
+			entry_count = EXCLUDED.entry_count,
+			imported_at = EXCLUDED.imported_at
+	`
+	if _, err := tx.Exec(ctx, query, source, fileName, len(entryRows), now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	if r.redis != nil {
+		r.redis.Publish(ctx, models.SanctionsInvalidateChannel, string(source))
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListImports(ctx context.Context) ([]models.SanctionsImport, error) {
+	rows, err := r.db.Query(ctx, `SELECT source, file_name, entry_count, imported_at FROM sanctions_imports ORDER BY source`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	imports := make([]models.SanctionsImport, 0)
+	for rows.Next() {
+		var imported models.SanctionsImport
+		if err := rows.Scan(&imported.Source, &imported.FileName, &imported.EntryCount, &imported.ImportedAt); err != nil {
+			return nil, err
+		}
+		imports = append(imports, imported)
+	}
+
+	return imports, rows.Err()
+}
+
+// nonNil returns an empty slice for nil, as the array columns are NOT NULL
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,210 @@
+package sanctions
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+
+	"github.com/algo-shield/algo-shield/src/pkg/fuzzy"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultScreeningLimit is the number of matches returned when a request sets no limit
+const defaultScreeningLimit = 10
+
+// screeningIndex is the fuzzy index of every name and alias of every entry
+type screeningIndex struct {
+	names   *fuzzy.Index
+	entries []*models.SanctionsEntry // Entry of each indexed name, by index position
+}
+
+// Screener screens names against the sanctions entries, kept in memory in a fuzzy index
+// Entries are loaded on first use and reloaded when an import is announced
+// Safe for concurrent use. A nil screener never matches.
+type Screener struct {
+	repo             Reader // Only needs read access, not full Repository
+	redis            *redis.Client
+	defaultThreshold float64
+
+	loadMu sync.Mutex // Serializes loads so concurrent first requests build the index once
+	mu     sync.RWMutex
+	index  *screeningIndex // nil until loaded
+}
+
+// NewScreener creates a new sanctions screener with dependency injection
+// defaultThreshold is the minimum score of a match when a request sets none
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewScreener(repo Reader, redisClient *redis.Client, defaultThreshold float64) *Screener {
+	return &Screener{
+		repo:             repo,
+		redis:            redisClient,
+		defaultThreshold: defaultThreshold,
+	}
+}
+
+// Load replaces the index with the repository's entries
+// On error the previous index is kept
+func (s *Screener) Load(ctx context.Context) error {
+	s.loadMu.Lock()
+	defer s.loadMu.Unlock()
+
+	return s.load(ctx)
+}
+
+// load builds and swaps the index, must be called with loadMu held
+func (s *Screener) load(ctx context.Context) error {
+	entries, err := s.repo.ListEntries(ctx)
+	if err != nil {
+		return err
+	}
+
+	index := &screeningIndex{names: fuzzy.NewIndex()}
+	for i := range entries {
+		entry := &entries[i]
+		for _, name := range append([]string{entry.Name}, entry.Aliases...) {
+			index.names.Add(name)
+			index.entries = append(index.entries, entry)
+		}
+	}
+
+	s.mu.Lock()
+	s.index = index
+	s.mu.Unlock()
+
+	log.Printf("Loaded %d sanctions entries with %d names into screening index", len(entries), index.names.Len())
+	return nil
+}
+
+// current returns the index, loading it on first use
+func (s *Screener) current(ctx context.Context) (*screeningIndex, error) {
+	s.mu.RLock()
+	index := s.index
+	s.mu.RUnlock()
+	if index != nil {
+		return index, nil
+	}
+
+	s.loadMu.Lock()
+	defer s.loadMu.Unlock()
+
+	// Another caller may have loaded it while we waited
+	s.mu.RLock()
+	index = s.index
+	s.mu.RUnlock()
+	if index != nil {
+		return index, nil
+	}
+
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index, nil
+}
+
+// Screen returns the entries with a name or alias similar to the requested name, best first
+// Each entry is returned once, with its best matching name
+func (s *Screener) Screen(ctx context.Context, req models.ScreeningRequest) ([]models.ScreeningMatch, error) {
+	if s == nil {
+		return []models.ScreeningMatch{}, nil
+	}
+
+	index, err := s.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := req.Threshold
+	if threshold <= 0 {
+		threshold = s.defaultThreshold
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultScreeningLimit
+	}
+	country := CountryCode(req.Country)
+
+	// Search returns the best names first, so the first match of an entry is its best
+	matches := make([]models.ScreeningMatch, 0)
+	seen := make(map[*models.SanctionsEntry]bool)
+	for _, match := range index.names.Search(req.Name, threshold) {
+		entry := index.entries[match.Position]
+		if seen[entry] || !screenedSource(req.Sources, entry.Source) || !screenedCountry(country, entry.Countries) {
+			continue
+		}
+		seen[entry] = true
+
+		matches = append(matches, models.ScreeningMatch{
+			Entry:       *entry,
+			MatchedName: match.Value,
+			Score:       match.Score,
+		})
+		if len(matches) == limit {
+			break
+		}
+	}
+
+	return matches, nil
+}
+
+// SanctionsHit reports whether a name matches any entry at the default threshold
+// country restricts matches to entries with that country or none; "" and unrecognised
+// countries screen against every entry, so a bad country never hides a hit
+func (s *Screener) SanctionsHit(ctx context.Context, name, country string) (bool, error) {
+	matches, err := s.Screen(ctx, models.ScreeningRequest{Name: name, Country: country, Limit: 1})
+	return len(matches) > 0, err
+}
+
+// SubscribeToInvalidations reloads the index whenever an import is announced
+// This is a blocking function that should be called in a goroutine
+func (s *Screener) SubscribeToInvalidations(ctx context.Context) {
+	if s.redis == nil {
+		log.Println("Redis not available, sanctions invalidation subscription disabled")
+		return
+	}
+
+	pubsub := s.redis.Subscribe(ctx, models.SanctionsInvalidateChannel)
+	defer func() {
+		if err := pubsub.Close(); err != nil {
+			log.Printf("Error closing sanctions invalidation subscription: %v", err)
+		}
+	}()
+
+	log.Println("Subscribed to sanctions invalidation channel")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Sanctions invalidation subscription stopped")
+			return
+		default:
+			msg, err := pubsub.ReceiveMessage(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return // Context cancelled
+				}
+				log.Printf("Error receiving sanctions invalidation message: %v", err)
+				continue
+			}
+
+			log.Printf("Sanctions source %s was imported, reloading", msg.Payload)
+			if err := s.Load(ctx); err != nil {
+				log.Printf("Failed to reload sanctions, keeping previous entries: %v", err)
+			}
+		}
+	}
+}
+
+// screenedSource reports whether a source was requested, no sources means all of them
+func screenedSource(sources []models.SanctionsSource, source models.SanctionsSource) bool {
+	return len(sources) == 0 || slices.Contains(sources, source)
+}
+
+// screenedCountry reports whether an entry is relevant to a country
+// Entries without known countries are always relevant
+func screenedCountry(country string, countries []string) bool {
+	return country == "" || len(countries) == 0 || slices.Contains(countries, country)
+}
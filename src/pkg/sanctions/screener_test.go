@@ -0,0 +1,130 @@
+package sanctions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubReader serves fixed entries and counts the loads
+type stubReader struct {
+	entries []models.SanctionsEntry
+	err     error
+	loads   int
+}
+
+func (r *stubReader) ListEntries(ctx context.Context) ([]models.SanctionsEntry, error) {
+	r.loads++
+	return r.entries, r.err
+}
+
+func screeningEntries() []models.SanctionsEntry {
+	return []models.SanctionsEntry{
+		{Source: models.SanctionsSourceOFAC, SourceID: "7157", Name: "Ivan PETROV", Aliases: []string{"Ioann PETROFF"}, Countries: []string{"RU"}},
+		{Source: models.SanctionsSourceUN, SourceID: "KPi.001", Name: "IVAN PETROV", Countries: []string{"KP"}},
+		{Source: models.SanctionsSourceEU, SourceID: "14", Name: "Example Shipping Company"},
+	}
+}
+
+func Test_Screener_Screen_WhenNameOrAliasMatches_ThenReturnsEachEntryOnceBestFirst(t *testing.T) {
+	reader := &stubReader{entries: screeningEntries()}
+	screener := NewScreener(reader, nil, 0.9)
+
+	matches, err := screener.Screen(context.Background(), models.ScreeningRequest{Name: "Petroff, Ioann"})
+
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "7157", matches[0].Entry.SourceID)
+	assert.Equal(t, "Ioann PETROFF", matches[0].MatchedName)
+	assert.InDelta(t, 1.0, matches[0].Score, 0.001)
+
+	matches, err = screener.Screen(context.Background(), models.ScreeningRequest{Name: "Ivan Petrov"})
+
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.ElementsMatch(t, []string{"7157", "KPi.001"}, []string{matches[0].Entry.SourceID, matches[1].Entry.SourceID})
+	// The index is loaded once
+	assert.Equal(t, 1, reader.loads)
+}
+
+func Test_Screener_Screen_WhenCountrySourcesAndLimit_ThenFiltersMatches(t *testing.T) {
+	screener := NewScreener(&stubReader{entries: screeningEntries()}, nil, 0.9)
+	ctx := context.Background()
+
+	matches, err := screener.Screen(ctx, models.ScreeningRequest{Name: "Ivan Petrov", Country: "North Korea"})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "KPi.001", matches[0].Entry.SourceID)
+
+	matches, err = screener.Screen(ctx, models.ScreeningRequest{Name: "Ivan Petrov", Sources: []models.SanctionsSource{models.SanctionsSourceOFAC}})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "7157", matches[0].Entry.SourceID)
+
+	matches, err = screener.Screen(ctx, models.ScreeningRequest{Name: "Ivan Petrov", Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, matches, 1)
+
+	// Entries without countries are screened for every country
+	matches, err = screener.Screen(ctx, models.ScreeningRequest{Name: "Example Shipping Co", Country: "FR", Threshold: 0.8})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "14", matches[0].Entry.SourceID)
+}
+
+func Test_Screener_Screen_WhenBelowThreshold_ThenReturnsNoMatches(t *testing.T) {
+	screener := NewScreener(&stubReader{entries: screeningEntries()}, nil, 0.9)
+
+	matches, err := screener.Screen(context.Background(), models.ScreeningRequest{Name: "Ivan Pavlov"})
+
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	// A lower requested threshold overrides the default
+	matches, err = screener.Screen(context.Background(), models.ScreeningRequest{Name: "Ivan Pavlov", Threshold: 0.8})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, matches)
+}
+
+func Test_Screener_SanctionsHit_WhenCountryGiven_ThenRestrictsToCountry(t *testing.T) {
+	screener := NewScreener(&stubReader{entries: screeningEntries()}, nil, 0.9)
+	ctx := context.Background()
+
+	hit, err := screener.SanctionsHit(ctx, "Ioann Petroff", "RU")
+	require.NoError(t, err)
+	assert.True(t, hit)
+
+	hit, err = screener.SanctionsHit(ctx, "Ioann Petroff", "FR")
+	require.NoError(t, err)
+	assert.False(t, hit)
+
+	hit, err = screener.SanctionsHit(ctx, "Jane Doe", "")
+	require.NoError(t, err)
+	assert.False(t, hit)
+}
+
+func Test_Screener_Load_WhenRepositoryFails_ThenKeepsPreviousEntries(t *testing.T) {
+	reader := &stubReader{entries: screeningEntries()}
+	screener := NewScreener(reader, nil, 0.9)
+	require.NoError(t, screener.Load(context.Background()))
+
+	reader.entries, reader.err = nil, errors.New("database unavailable")
+	require.Error(t, screener.Load(context.Background()))
+
+	hit, err := screener.SanctionsHit(context.Background(), "Ivan Petrov", "")
+	require.NoError(t, err)
+	assert.True(t, hit)
+}
+
+func Test_Screener_Screen_WhenFirstLoadFails_ThenReturnsError(t *testing.T) {
+	screener := NewScreener(&stubReader{err: errors.New("database unavailable")}, nil, 0.9)
+
+	_, err := screener.Screen(context.Background(), models.ScreeningRequest{Name: "Ivan Petrov"})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/timeline/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/timeline/repository.go -destination=src/pkg/timeline/mock_redis_sorted_set_test.go -package=timeline -exclude_interfaces=Recorder
+//
+
+// Package timeline is a generated GoMock package.
+package timeline
+
+import (
+	context "context"
+	reflect "reflect"
+
+	redis "github.com/redis/go-redis/v9"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRedisSortedSet is a mock of RedisSortedSet interface.
+type MockRedisSortedSet struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedisSortedSetMockRecorder
+	isgomock struct{}
+}
+
+// MockRedisSortedSetMockRecorder is the mock recorder for MockRedisSortedSet.
+type MockRedisSortedSetMockRecorder struct {
+	mock *MockRedisSortedSet
+}
+
+// NewMockRedisSortedSet creates a new mock instance.
+func NewMockRedisSortedSet(ctrl *gomock.Controller) *MockRedisSortedSet {
+	mock := &MockRedisSortedSet{ctrl: ctrl}
+	mock.recorder = &MockRedisSortedSetMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedisSortedSet) EXPECT() *MockRedisSortedSetMockRecorder {
+	return m.recorder
+}
+
+// Pipelined mocks base method.
+func (m *MockRedisSortedSet) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pipelined", ctx, fn)
+	ret0, _ := ret[0].([]redis.Cmder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pipelined indicates an expected call of Pipelined.
+func (mr *MockRedisSortedSetMockRecorder) Pipelined(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pipelined", reflect.TypeOf((*MockRedisSortedSet)(nil).Pipelined), ctx, fn)
+}
+
+// ZRangeByScoreWithScores mocks base method.
+func (m *MockRedisSortedSet) ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.ZSliceCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScoreWithScores", ctx, key, opt)
+	ret0, _ := ret[0].(*redis.ZSliceCmd)
+	return ret0
+}
+
+// ZRangeByScoreWithScores indicates an expected call of ZRangeByScoreWithScores.
+func (mr *MockRedisSortedSetMockRecorder) ZRangeByScoreWithScores(ctx, key, opt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScoreWithScores", reflect.TypeOf((*MockRedisSortedSet)(nil).ZRangeByScoreWithScores), ctx, key, opt)
+}
@@ -0,0 +1,160 @@
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix prefixes the per-entity sorted sets of recent events
+const keyPrefix = "timeline:"
+
+// Recorder defines the interface for adding processed events to entity timelines
+type Recorder interface {
+	// RecordEvent adds an event to an entity's timeline
+	// Recording the same event ID and type again only moves it, so retries are safe
+	RecordEvent(ctx context.Context, entity string, event models.TimelineEvent) error
+}
+
+// RedisSortedSet defines the Redis operations used by the Redis timeline
+type RedisSortedSet interface {
+	ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.ZSliceCmd
+	Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+}
+
+// RedisTimeline keeps each entity's recent events in a Redis sorted set scored by time
+// Events older than the retention are trimmed, and only the most recent maxEvents are kept
+// so a noisy entity cannot grow without bound. A nil timeline records and matches nothing.
+type RedisTimeline struct {
+	redis     RedisSortedSet
+	retention time.Duration
+	maxEvents int
+}
+
+// NewRedisTimeline creates a new Redis timeline
+// retention and maxEvents only apply when recording; maxEvents of 0 or less keeps every retained event
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewRedisTimeline(redis RedisSortedSet, retention time.Duration, maxEvents int) *RedisTimeline {
+	return &RedisTimeline{
+		redis:     redis,
+		retention: retention,
+		maxEvents: maxEvents,
+	}
+}
+
+// RecordEvent adds an event to an entity's timeline and trims events past the retention and cap
+func (t *RedisTimeline) RecordEvent(ctx context.Context, entity string, event models.TimelineEvent) error {
+	if t == nil {
+		return nil
+	}
+	if strings.Contains(event.Type, "|") {
+		return fmt.Errorf("timeline event type %q cannot contain '|'", event.Type)
+	}
+
+	key := timelineKey(entity)
+	cutoff := event.At.Add(-t.retention).UnixMilli()
+
+	_, err := t.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(event.At.UnixMilli()), Member: timelineMember(event)})
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(cutoff, 10))
+		if t.maxEvents > 0 {
+			pipe.ZRemRangeByRank(ctx, key, 0, int64(-t.maxEvents-1))
+		}
+		pipe.Expire(ctx, key, t.retention)
+		return nil
+	})
+	return err
+}
+
+// EventsSince returns an entity's events at or after since, oldest first
+func (t *RedisTimeline) EventsSince(ctx context.Context, entity string, since time.Time) ([]models.TimelineEvent, error) {
+	if t == nil {
+		return []models.TimelineEvent{}, nil
+	}
+
+	members, err := t.redis.ZRangeByScoreWithScores(ctx, timelineKey(entity), &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.TimelineEvent, 0, len(members))
+	for _, member := range members {
+		event, err := parseTimelineMember(member)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// Sequence reports whether an entity had events of the given types, in that order, within the
+// window ending now. Other events may occur between the steps. Windows longer than the
+// retention only see the retained events.
+func (t *RedisTimeline) Sequence(ctx context.Context, entity string, types []string, within time.Duration) (bool, error) {
+	if t == nil || entity == "" || len(types) == 0 || within <= 0 {
+		return false, nil
+	}
+
+	events, err := t.EventsSince(ctx, entity, time.Now().Add(-within))
+	if err != nil {
+		return false, err
+	}
+	return MatchSequence(events, types), nil
+}
+
+// MatchSequence reports whether events, oldest first, contain the given types in order
+// Types are compared case-insensitively and other events may occur between the steps
+func MatchSequence(events []models.TimelineEvent, types []string) bool {
+	if len(types) == 0 {
+		return false
+	}
+
+	// Matching each step with its earliest occurrence leaves the most room for the next steps
+	step := 0
+	for _, event := range events {
+		if strings.EqualFold(event.Type, types[step]) {
+			step++
+			if step == len(types) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// timelineKey returns the sorted set key of an entity
+func timelineKey(entity string) string {
+	return keyPrefix + entity
+}
+
+// timelineMember encodes an event as a sorted set member: "<type>|<id>"
+// The type is first because IDs are free-form, while types cannot contain '|'
+func timelineMember(event models.TimelineEvent) string {
+	return event.Type + "|" + event.ID
+}
+
+// parseTimelineMember decodes a sorted set member and its score
+func parseTimelineMember(member redis.Z) (models.TimelineEvent, error) {
+	encoded, ok := member.Member.(string)
+	if !ok {
+		return models.TimelineEvent{}, fmt.Errorf("invalid timeline member %v", member.Member)
+	}
+	eventType, id, found := strings.Cut(encoded, "|")
+	if !found {
+		return models.TimelineEvent{}, fmt.Errorf("invalid timeline member %q", encoded)
+	}
+	return models.TimelineEvent{
+		ID:   id,
+		Type: eventType,
+		At:   time.UnixMilli(int64(member.Score)),
+	}, nil
+}
@@ -0,0 +1,139 @@
+package timeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_RedisTimeline_RecordEvent_WhenCalled_ThenAddsTrimsCapsAndExpires(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	timeline := NewRedisTimeline(mockRedis, time.Hour, 100)
+
+	mockRedis.EXPECT().Pipelined(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+			pipe := redis.NewClient(&redis.Options{}).Pipeline()
+			require.NoError(t, fn(pipe))
+			assert.Equal(t, 4, pipe.Len())
+			return nil, nil
+		},
+	)
+
+	err := timeline.RecordEvent(context.Background(), "ACC1", models.TimelineEvent{ID: "evt-1", Type: "login", At: time.Now()})
+
+	require.NoError(t, err)
+}
+
+func Test_RedisTimeline_RecordEvent_WhenTypeContainsSeparator_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeline := NewRedisTimeline(NewMockRedisSortedSet(ctrl), time.Hour, 100)
+
+	err := timeline.RecordEvent(context.Background(), "ACC1", models.TimelineEvent{ID: "evt-1", Type: "login|web", At: time.Now()})
+
+	assert.Error(t, err)
+}
+
+func Test_RedisTimeline_Sequence_WhenStepsInOrder_ThenMatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	timeline := NewRedisTimeline(mockRedis, time.Hour, 100)
+	now := float64(time.Now().UnixMilli())
+
+	mockRedis.EXPECT().ZRangeByScoreWithScores(gomock.Any(), "timeline:ACC1", gomock.Any()).
+		Return(redis.NewZSliceCmdResult([]redis.Z{
+			{Member: "password_change|evt-1", Score: now - 1200000},
+			{Member: "login|evt-2", Score: now - 600000},
+			{Member: "new_device_login|evt-3", Score: now - 300000},
+			{Member: "transfer|tx|4", Score: now},
+		}, nil)).Times(2)
+
+	matched, err := timeline.Sequence(context.Background(), "ACC1", []string{"password_change", "new_device_login", "transfer"}, 30*time.Minute)
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	matched, err = timeline.Sequence(context.Background(), "ACC1", []string{"new_device_login", "password_change"}, 30*time.Minute)
+	require.NoError(t, err)
+	assert.False(t, matched)
+}
+
+func Test_RedisTimeline_Sequence_WhenRedisFails_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	timeline := NewRedisTimeline(mockRedis, time.Hour, 100)
+
+	mockRedis.EXPECT().ZRangeByScoreWithScores(gomock.Any(), "timeline:ACC1", gomock.Any()).
+		Return(redis.NewZSliceCmdResult(nil, errors.New("redis down")))
+
+	_, err := timeline.Sequence(context.Background(), "ACC1", []string{"login", "transfer"}, time.Minute)
+
+	assert.Error(t, err)
+}
+
+func Test_RedisTimeline_EventsSince_WhenMembersStored_ThenDecodesTypeIDAndTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	timeline := NewRedisTimeline(mockRedis, time.Hour, 100)
+	at := time.UnixMilli(1700000000000)
+
+	mockRedis.EXPECT().ZRangeByScoreWithScores(gomock.Any(), "timeline:ACC1", &redis.ZRangeBy{Min: "1699999999000", Max: "+inf"}).
+		Return(redis.NewZSliceCmdResult([]redis.Z{{Member: "transfer|tx|4", Score: float64(at.UnixMilli())}}, nil))
+
+	events, err := timeline.EventsSince(context.Background(), "ACC1", at.Add(-time.Second))
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.TimelineEvent{{ID: "tx|4", Type: "transfer", At: at}}, events)
+}
+
+func Test_RedisTimeline_Sequence_WhenNilOrEmptyArguments_ThenNeverMatches(t *testing.T) {
+	var nilTimeline *RedisTimeline
+	matched, err := nilTimeline.Sequence(context.Background(), "ACC1", []string{"login"}, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, matched)
+	require.NoError(t, nilTimeline.RecordEvent(context.Background(), "ACC1", models.TimelineEvent{Type: "login"}))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	timeline := NewRedisTimeline(NewMockRedisSortedSet(ctrl), time.Hour, 100)
+
+	for _, args := range []struct {
+		entity string
+		types  []string
+		within time.Duration
+	}{
+		{"", []string{"login"}, time.Minute},
+		{"ACC1", nil, time.Minute},
+		{"ACC1", []string{"login"}, 0},
+	} {
+		matched, err := timeline.Sequence(context.Background(), args.entity, args.types, args.within)
+		require.NoError(t, err)
+		assert.False(t, matched)
+	}
+}
+
+func Test_MatchSequence_WhenStepsRepeatOrInterleave_ThenUsesEarliestOccurrences(t *testing.T) {
+	events := []models.TimelineEvent{{Type: "login"}, {Type: "LOGIN"}, {Type: "transfer"}, {Type: "login"}}
+
+	assert.True(t, MatchSequence(events, []string{"login", "login", "transfer"}))
+	assert.True(t, MatchSequence(events, []string{"Login", "transfer", "login"}))
+	assert.False(t, MatchSequence(events, []string{"transfer", "transfer"}))
+	assert.False(t, MatchSequence(events, nil))
+	assert.False(t, MatchSequence(nil, []string{"login"}))
+}
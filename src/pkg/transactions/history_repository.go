@@ -0,0 +1,230 @@
+package transactions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// amountAggregateSQL maps amount aggregates to their SQL functions
+var amountAggregateSQL = map[models.AmountAggregate]string{
+	models.AggregateSum: "SUM",
+	models.AggregateMin: "MIN",
+	models.AggregateMax: "MAX",
+	models.AggregateAvg: "AVG",
+}
+
+// TransactionHistoryRepository defines the interface for transaction history queries
+type TransactionHistoryRepository interface {
+	// CountByAccountInTimeWindow counts transactions for an account within a time window
+	CountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (int, error)
+	// SumAmountByAccountInTimeWindow sums transaction amounts for an account within a time window
+	SumAmountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (float64, error)
+	// CountByFieldInTimeWindow counts transactions whose event field at path equals value within a time window
+	CountByFieldInTimeWindow(ctx context.Context, path string, value any, timeWindowSeconds int) (int, error)
+	// AggregateAmountByFieldInTimeWindow aggregates the amounts of transactions whose event field at path
+	// equals value within a time window
+	AggregateAmountByFieldInTimeWindow(ctx context.Context, path string, value any, aggregate models.AmountAggregate, timeWindowSeconds int) (float64, error)
+	// CountDistinctByFieldInTimeWindow counts the distinct values at distinctPath among transactions
+	// whose event field at path equals value within a time window
+	CountDistinctByFieldInTimeWindow(ctx context.Context, distinctPath, path string, value any, timeWindowSeconds int) (int, error)
+	// AmountStatsByAccount returns the mean and spread of an account's amounts over the last days, today included
+	AmountStatsByAccount(ctx context.Context, account string, days int) (models.AmountStats, error)
+	// FirstSeenByAccount returns when an account first transacted, or nil if it never did
+	FirstSeenByAccount(ctx context.Context, account string) (*time.Time, error)
+	// LastLocationByAccount returns where an account last transacted, or nil if no location is known
+	LastLocationByAccount(ctx context.Context, account string) (*models.AccountLocation, error)
+}
+
+// PostgresHistoryRepository is the PostgreSQL implementation
+type PostgresHistoryRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresHistoryRepository creates a new PostgreSQL history repository
+func NewPostgresHistoryRepository(db *pgxpool.Pool) TransactionHistoryRepository {
+	return &PostgresHistoryRepository{db: db}
+}
+
+func (r *PostgresHistoryRepository) CountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (int, error) {
+	query := `
+		SELECT COUNT(*) 
+		FROM transactions 
+		WHERE origin = $1 
+		AND created_at > NOW() - INTERVAL '1 second' * $2
+	`
+
+	var count int
+	err := r.db.QueryRow(ctx, query, account, timeWindowSeconds).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *PostgresHistoryRepository) SumAmountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0) 
+		FROM transactions 
+		WHERE origin = $1 
+		AND created_at > NOW() - INTERVAL '1 second' * $2
+	`
+
+	var sum float64
+	err := r.db.QueryRow(ctx, query, account, timeWindowSeconds).Scan(&sum)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return sum, nil
+}
+
+func (r *PostgresHistoryRepository) CountByFieldInTimeWindow(ctx context.Context, path string, value any, timeWindowSeconds int) (int, error) {
+	filter, err := fieldFilter(path, value)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		SELECT COUNT(*)
+		FROM transactions
+		WHERE event_payload @> $1
+		AND created_at > NOW() - INTERVAL '1 second' * $2
+	`
+
+	var count int
+	err = r.db.QueryRow(ctx, query, filter, timeWindowSeconds).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *PostgresHistoryRepository) AggregateAmountByFieldInTimeWindow(ctx context.Context, path string, value any, aggregate models.AmountAggregate, timeWindowSeconds int) (float64, error) {
+	function, ok := amountAggregateSQL[aggregate]
+	if !ok {
+		return 0.0, fmt.Errorf("unknown amount aggregate %q", aggregate)
+	}
+
+	filter, err := fieldFilter(path, value)
+	if err != nil {
+		return 0.0, err
+	}
+
+	// function comes from amountAggregateSQL, never from user input
+	query := fmt.Sprintf(`
+		SELECT COALESCE(%s(amount), 0)
+		FROM transactions
+		WHERE event_payload @> $1
+		AND created_at > NOW() - INTERVAL '1 second' * $2
+	`, function)
+
+	var result float64
+	err = r.db.QueryRow(ctx, query, filter, timeWindowSeconds).Scan(&result)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return result, nil
+}
+
+func (r *PostgresHistoryRepository) CountDistinctByFieldInTimeWindow(ctx context.Context, distinctPath, path string, value any, timeWindowSeconds int) (int, error) {
+	filter, err := fieldFilter(path, value)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		SELECT COUNT(DISTINCT event_payload #>> $3)
+		FROM transactions
+		WHERE event_payload @> $1
+		AND created_at > NOW() - INTERVAL '1 second' * $2
+	`
+
+	var count int
+	err = r.db.QueryRow(ctx, query, filter, timeWindowSeconds, strings.Split(distinctPath, ".")).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *PostgresHistoryRepository) AmountStatsByAccount(ctx context.Context, account string, days int) (models.AmountStats, error) {
+	query := `
+		SELECT COALESCE(SUM(transaction_count), 0), COALESCE(SUM(amount_sum), 0), COALESCE(SUM(amount_sum_squares), 0)
+		FROM account_daily_stats
+		WHERE account = $1
+		AND day > CURRENT_DATE - $2::int
+	`
+
+	var count int
+	var sum, sumSquares float64
+	err := r.db.QueryRow(ctx, query, account, days).Scan(&count, &sum, &sumSquares)
+	if err != nil {
+		return models.AmountStats{}, err
+	}
+
+	return models.NewAmountStats(count, sum, sumSquares), nil
+}
+
+func (r *PostgresHistoryRepository) FirstSeenByAccount(ctx context.Context, account string) (*time.Time, error) {
+	query := `
+		SELECT first_seen_at
+		FROM account_profiles
+		WHERE account = $1
+	`
+
+	var firstSeen time.Time
+	err := r.db.QueryRow(ctx, query, account).Scan(&firstSeen)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &firstSeen, nil
+}
+
+func (r *PostgresHistoryRepository) LastLocationByAccount(ctx context.Context, account string) (*models.AccountLocation, error) {
+	query := `
+		SELECT last_latitude, last_longitude, last_location_at
+		FROM account_profiles
+		WHERE account = $1
+		AND last_location_at IS NOT NULL
+	`
+
+	var location models.AccountLocation
+	err := r.db.QueryRow(ctx, query, account).Scan(&location.Latitude, &location.Longitude, &location.SeenAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+// fieldFilter builds the JSON document matched by event_payload containment,
+// e.g. path "device.id" and value "abc" give {"device":{"id":"abc"}}
+// Containment keeps the value's JSON type and can use the event_payload GIN index
+func fieldFilter(path string, value any) ([]byte, error) {
+	parts := strings.Split(path, ".")
+	filter := value
+	for i := len(parts) - 1; i >= 0; i-- {
+		filter = map[string]any{parts[i]: filter}
+	}
+
+	return json.Marshal(filter)
+}
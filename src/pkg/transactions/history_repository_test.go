@@ -0,0 +1,22 @@
+package transactions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fieldFilter_WhenNestedPath_ThenBuildsNestedDocument(t *testing.T) {
+	filter, err := fieldFilter("device.id", "abc")
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"device":{"id":"abc"}}`, string(filter))
+}
+
+func Test_fieldFilter_WhenNumericValue_ThenKeepsJSONType(t *testing.T) {
+	filter, err := fieldFilter("merchant_id", 42.0)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"merchant_id":42}`, string(filter))
+}
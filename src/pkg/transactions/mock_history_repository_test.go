@@ -0,0 +1,163 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/transactions/history_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/transactions/history_repository.go -destination=src/pkg/transactions/mock_history_repository_test.go -package=transactions
+//
+
+// Package transactions is a generated GoMock package.
+package transactions
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransactionHistoryRepository is a mock of TransactionHistoryRepository interface.
+type MockTransactionHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionHistoryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionHistoryRepositoryMockRecorder is the mock recorder for MockTransactionHistoryRepository.
+type MockTransactionHistoryRepositoryMockRecorder struct {
+	mock *MockTransactionHistoryRepository
+}
+
+// NewMockTransactionHistoryRepository creates a new mock instance.
+func NewMockTransactionHistoryRepository(ctrl *gomock.Controller) *MockTransactionHistoryRepository {
+	mock := &MockTransactionHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockTransactionHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionHistoryRepository) EXPECT() *MockTransactionHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AggregateAmountByFieldInTimeWindow mocks base method.
+func (m *MockTransactionHistoryRepository) AggregateAmountByFieldInTimeWindow(ctx context.Context, path string, value any, aggregate models.AmountAggregate, timeWindowSeconds int) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregateAmountByFieldInTimeWindow", ctx, path, value, aggregate, timeWindowSeconds)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AggregateAmountByFieldInTimeWindow indicates an expected call of AggregateAmountByFieldInTimeWindow.
+func (mr *MockTransactionHistoryRepositoryMockRecorder) AggregateAmountByFieldInTimeWindow(ctx, path, value, aggregate, timeWindowSeconds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateAmountByFieldInTimeWindow", reflect.TypeOf((*MockTransactionHistoryRepository)(nil).AggregateAmountByFieldInTimeWindow), ctx, path, value, aggregate, timeWindowSeconds)
+}
+
+// AmountStatsByAccount mocks base method.
+func (m *MockTransactionHistoryRepository) AmountStatsByAccount(ctx context.Context, account string, days int) (models.AmountStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AmountStatsByAccount", ctx, account, days)
+	ret0, _ := ret[0].(models.AmountStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AmountStatsByAccount indicates an expected call of AmountStatsByAccount.
+func (mr *MockTransactionHistoryRepositoryMockRecorder) AmountStatsByAccount(ctx, account, days any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AmountStatsByAccount", reflect.TypeOf((*MockTransactionHistoryRepository)(nil).AmountStatsByAccount), ctx, account, days)
+}
+
+// CountByAccountInTimeWindow mocks base method.
+func (m *MockTransactionHistoryRepository) CountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByAccountInTimeWindow", ctx, account, timeWindowSeconds)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByAccountInTimeWindow indicates an expected call of CountByAccountInTimeWindow.
+func (mr *MockTransactionHistoryRepositoryMockRecorder) CountByAccountInTimeWindow(ctx, account, timeWindowSeconds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByAccountInTimeWindow", reflect.TypeOf((*MockTransactionHistoryRepository)(nil).CountByAccountInTimeWindow), ctx, account, timeWindowSeconds)
+}
+
+// CountByFieldInTimeWindow mocks base method.
+func (m *MockTransactionHistoryRepository) CountByFieldInTimeWindow(ctx context.Context, path string, value any, timeWindowSeconds int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByFieldInTimeWindow", ctx, path, value, timeWindowSeconds)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByFieldInTimeWindow indicates an expected call of CountByFieldInTimeWindow.
+func (mr *MockTransactionHistoryRepositoryMockRecorder) CountByFieldInTimeWindow(ctx, path, value, timeWindowSeconds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByFieldInTimeWindow", reflect.TypeOf((*MockTransactionHistoryRepository)(nil).CountByFieldInTimeWindow), ctx, path, value, timeWindowSeconds)
+}
+
+// CountDistinctByFieldInTimeWindow mocks base method.
+func (m *MockTransactionHistoryRepository) CountDistinctByFieldInTimeWindow(ctx context.Context, distinctPath, path string, value any, timeWindowSeconds int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDistinctByFieldInTimeWindow", ctx, distinctPath, path, value, timeWindowSeconds)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDistinctByFieldInTimeWindow indicates an expected call of CountDistinctByFieldInTimeWindow.
+func (mr *MockTransactionHistoryRepositoryMockRecorder) CountDistinctByFieldInTimeWindow(ctx, distinctPath, path, value, timeWindowSeconds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDistinctByFieldInTimeWindow", reflect.TypeOf((*MockTransactionHistoryRepository)(nil).CountDistinctByFieldInTimeWindow), ctx, distinctPath, path, value, timeWindowSeconds)
+}
+
+// FirstSeenByAccount mocks base method.
+func (m *MockTransactionHistoryRepository) FirstSeenByAccount(ctx context.Context, account string) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FirstSeenByAccount", ctx, account)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FirstSeenByAccount indicates an expected call of FirstSeenByAccount.
+func (mr *MockTransactionHistoryRepositoryMockRecorder) FirstSeenByAccount(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FirstSeenByAccount", reflect.TypeOf((*MockTransactionHistoryRepository)(nil).FirstSeenByAccount), ctx, account)
+}
+
+// LastLocationByAccount mocks base method.
+func (m *MockTransactionHistoryRepository) LastLocationByAccount(ctx context.Context, account string) (*models.AccountLocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastLocationByAccount", ctx, account)
+	ret0, _ := ret[0].(*models.AccountLocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastLocationByAccount indicates an expected call of LastLocationByAccount.
+func (mr *MockTransactionHistoryRepositoryMockRecorder) LastLocationByAccount(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastLocationByAccount", reflect.TypeOf((*MockTransactionHistoryRepository)(nil).LastLocationByAccount), ctx, account)
+}
+
+// SumAmountByAccountInTimeWindow mocks base method.
+func (m *MockTransactionHistoryRepository) SumAmountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumAmountByAccountInTimeWindow", ctx, account, timeWindowSeconds)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumAmountByAccountInTimeWindow indicates an expected call of SumAmountByAccountInTimeWindow.
+func (mr *MockTransactionHistoryRepositoryMockRecorder) SumAmountByAccountInTimeWindow(ctx, account, timeWindowSeconds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumAmountByAccountInTimeWindow", reflect.TypeOf((*MockTransactionHistoryRepository)(nil).SumAmountByAccountInTimeWindow), ctx, account, timeWindowSeconds)
+}
@@ -0,0 +1,137 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/transactions/redis_history_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/transactions/redis_history_repository.go -destination=src/pkg/transactions/mock_redis_sorted_set_test.go -package=transactions -exclude_interfaces=HistoryRecorder
+//
+
+// Package transactions is a generated GoMock package.
+package transactions
+
+import (
+	context "context"
+	reflect "reflect"
+
+	redis "github.com/redis/go-redis/v9"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRedisSortedSet is a mock of RedisSortedSet interface.
+type MockRedisSortedSet struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedisSortedSetMockRecorder
+	isgomock struct{}
+}
+
+// MockRedisSortedSetMockRecorder is the mock recorder for MockRedisSortedSet.
+type MockRedisSortedSetMockRecorder struct {
+	mock *MockRedisSortedSet
+}
+
+// NewMockRedisSortedSet creates a new mock instance.
+func NewMockRedisSortedSet(ctrl *gomock.Controller) *MockRedisSortedSet {
+	mock := &MockRedisSortedSet{ctrl: ctrl}
+	mock.recorder = &MockRedisSortedSetMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedisSortedSet) EXPECT() *MockRedisSortedSetMockRecorder {
+	return m.recorder
+}
+
+// Exists mocks base method.
+func (m *MockRedisSortedSet) Exists(ctx context.Context, keys ...string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Exists", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockRedisSortedSetMockRecorder) Exists(ctx any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockRedisSortedSet)(nil).Exists), varargs...)
+}
+
+// Get mocks base method.
+func (m *MockRedisSortedSet) Get(ctx context.Context, key string) *redis.StringCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*redis.StringCmd)
+	return ret0
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRedisSortedSetMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRedisSortedSet)(nil).Get), ctx, key)
+}
+
+// Pipelined mocks base method.
+func (m *MockRedisSortedSet) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pipelined", ctx, fn)
+	ret0, _ := ret[0].([]redis.Cmder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pipelined indicates an expected call of Pipelined.
+func (mr *MockRedisSortedSetMockRecorder) Pipelined(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pipelined", reflect.TypeOf((*MockRedisSortedSet)(nil).Pipelined), ctx, fn)
+}
+
+// ZCount mocks base method.
+func (m *MockRedisSortedSet) ZCount(ctx context.Context, key, min, max string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZCount", ctx, key, min, max)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// ZCount indicates an expected call of ZCount.
+func (mr *MockRedisSortedSetMockRecorder) ZCount(ctx, key, min, max any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZCount", reflect.TypeOf((*MockRedisSortedSet)(nil).ZCount), ctx, key, min, max)
+}
+
+// ZRangeByScore mocks base method.
+func (m *MockRedisSortedSet) ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.StringSliceCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScore", ctx, key, opt)
+	ret0, _ := ret[0].(*redis.StringSliceCmd)
+	return ret0
+}
+
+// ZRangeByScore indicates an expected call of ZRangeByScore.
+func (mr *MockRedisSortedSetMockRecorder) ZRangeByScore(ctx, key, opt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScore", reflect.TypeOf((*MockRedisSortedSet)(nil).ZRangeByScore), ctx, key, opt)
+}
+
+// ZRem mocks base method.
+func (m *MockRedisSortedSet) ZRem(ctx context.Context, key string, members ...any) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZRem", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// ZRem indicates an expected call of ZRem.
+func (mr *MockRedisSortedSetMockRecorder) ZRem(ctx, key any, members ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockRedisSortedSet)(nil).ZRem), varargs...)
+}
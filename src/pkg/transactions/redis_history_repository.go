@@ -0,0 +1,214 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// historyKeyPrefix prefixes the per-account sorted sets of recent transactions
+const historyKeyPrefix = "velocity:"
+
+// historyStartKey holds when recording started, in Unix milliseconds
+// It is set by the first recorded transaction and expires like the sorted sets, so it is gone
+// whenever they all are: after a flush, or once nothing was recorded for the retention
+const historyStartKey = "velocity-history:started_at"
+
+// HistoryRecorder defines the interface for recording processed transactions for velocity queries
+type HistoryRecorder interface {
+	// RecordTransaction adds a transaction to its account's history
+	// Recording the same transaction ID again only moves it, so retries are safe
+	RecordTransaction(ctx context.Context, account, transactionID string, amount float64, at time.Time) error
+	// RemoveTransaction removes a transaction recorded with the same ID and amount, if any
+	RemoveTransaction(ctx context.Context, account, transactionID string, amount float64) error
+}
+
+// RedisSortedSet defines the Redis operations used by the Redis history repository
+type RedisSortedSet interface {
+	ZCount(ctx context.Context, key, min, max string) *redis.IntCmd
+	ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.StringSliceCmd
+	ZRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Exists(ctx context.Context, keys ...string) *redis.IntCmd
+	Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+}
+
+// RedisHistoryRepository keeps each account's recent transactions in a Redis sorted set
+// scored by time, so counts are O(log n) and sums O(log n + m) for m transactions in the window.
+// Windows longer than the retention or reaching back before recording started, such as right after
+// a deploy or a flush, and Redis failures, are answered by the fallback repository.
+type RedisHistoryRepository struct {
+	redis     RedisSortedSet
+	retention time.Duration
+	fallback  TransactionHistoryRepository
+}
+
+// NewRedisHistoryRepository creates a new Redis history repository
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewRedisHistoryRepository(redis RedisSortedSet, retention time.Duration, fallback TransactionHistoryRepository) *RedisHistoryRepository {
+	return &RedisHistoryRepository{
+		redis:     redis,
+		retention: retention,
+		fallback:  fallback,
+	}
+}
+
+// RecordTransaction adds a transaction to its account's history and trims entries past the retention
+func (r *RedisHistoryRepository) RecordTransaction(ctx context.Context, account, transactionID string, amount float64, at time.Time) error {
+	key := historyKey(account)
+	cutoff := at.Add(-r.retention).UnixMilli()
+
+	_, err := r.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(at.UnixMilli()), Member: historyMember(transactionID, amount)})
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(cutoff, 10))
+		pipe.Expire(ctx, key, r.retention)
+		pipe.SetNX(ctx, historyStartKey, time.Now().UnixMilli(), 0)
+		pipe.Expire(ctx, historyStartKey, r.retention)
+		return nil
+	})
+	return err
+}
+
+// RemoveTransaction removes a transaction from its account's history
+func (r *RedisHistoryRepository) RemoveTransaction(ctx context.Context, account, transactionID string, amount float64) error {
+	return r.redis.ZRem(ctx, historyKey(account), historyMember(transactionID, amount)).Err()
+}
+
+// CountByAccountInTimeWindow counts transactions for an account within a time window
+func (r *RedisHistoryRepository) CountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (int, error) {
+	if !r.covers(ctx, account, timeWindowSeconds) {
+		return r.fallback.CountByAccountInTimeWindow(ctx, account, timeWindowSeconds)
+	}
+
+	count, err := r.redis.ZCount(ctx, historyKey(account), windowStart(timeWindowSeconds), "+inf").Result()
+	if err != nil {
+		log.Printf("Redis velocity count failed, falling back: %v", err)
+		return r.fallback.CountByAccountInTimeWindow(ctx, account, timeWindowSeconds)
+	}
+
+	return int(count), nil
+}
+
+// SumAmountByAccountInTimeWindow sums transaction amounts for an account within a time window
+func (r *RedisHistoryRepository) SumAmountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (float64, error) {
+	if !r.covers(ctx, account, timeWindowSeconds) {
+		return r.fallback.SumAmountByAccountInTimeWindow(ctx, account, timeWindowSeconds)
+	}
+
+	members, err := r.redis.ZRangeByScore(ctx, historyKey(account), &redis.ZRangeBy{
+		Min: windowStart(timeWindowSeconds),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		log.Printf("Redis velocity sum failed, falling back: %v", err)
+		return r.fallback.SumAmountByAccountInTimeWindow(ctx, account, timeWindowSeconds)
+	}
+
+	sum := 0.0
+	for _, member := range members {
+		amount, err := memberAmount(member)
+		if err != nil {
+			return 0.0, err
+		}
+		sum += amount
+	}
+
+	return sum, nil
+}
+
+// CountByFieldInTimeWindow counts transactions whose event field at path equals value within a time window
+// Only per-account history is kept in Redis, so field-keyed queries are answered by the fallback
+func (r *RedisHistoryRepository) CountByFieldInTimeWindow(ctx context.Context, path string, value any, timeWindowSeconds int) (int, error) {
+	return r.fallback.CountByFieldInTimeWindow(ctx, path, value, timeWindowSeconds)
+}
+
+// AggregateAmountByFieldInTimeWindow aggregates amounts of transactions whose event field at path equals value
+// Answered by the fallback, like all field-keyed queries
+func (r *RedisHistoryRepository) AggregateAmountByFieldInTimeWindow(ctx context.Context, path string, value any, aggregate models.AmountAggregate, timeWindowSeconds int) (float64, error) {
+	return r.fallback.AggregateAmountByFieldInTimeWindow(ctx, path, value, aggregate, timeWindowSeconds)
+}
+
+// CountDistinctByFieldInTimeWindow counts distinct values at distinctPath among transactions keyed by a field
+// Answered by the fallback, like all field-keyed queries
+func (r *RedisHistoryRepository) CountDistinctByFieldInTimeWindow(ctx context.Context, distinctPath, path string, value any, timeWindowSeconds int) (int, error) {
+	return r.fallback.CountDistinctByFieldInTimeWindow(ctx, distinctPath, path, value, timeWindowSeconds)
+}
+
+// AmountStatsByAccount returns the mean and spread of an account's amounts over the last days
+// Baselines are maintained in the database, so they are answered by the fallback
+func (r *RedisHistoryRepository) AmountStatsByAccount(ctx context.Context, account string, days int) (models.AmountStats, error) {
+	return r.fallback.AmountStatsByAccount(ctx, account, days)
+}
+
+// FirstSeenByAccount returns when an account first transacted
+// Baselines are maintained in the database, so they are answered by the fallback
+func (r *RedisHistoryRepository) FirstSeenByAccount(ctx context.Context, account string) (*time.Time, error) {
+	return r.fallback.FirstSeenByAccount(ctx, account)
+}
+
+// LastLocationByAccount returns where an account last transacted
+// Locations are maintained in the database, so they are answered by the fallback
+func (r *RedisHistoryRepository) LastLocationByAccount(ctx context.Context, account string) (*models.AccountLocation, error) {
+	return r.fallback.LastLocationByAccount(ctx, account)
+}
+
+// covers reports whether the account's retained history spans the whole window
+// History is only complete since recording started, so sorted sets that are empty or
+// partial after a deploy or a flush never undercount. A missing sorted set may have been
+// evicted rather than expired with the account idle, so it is never trusted either.
+func (r *RedisHistoryRepository) covers(ctx context.Context, account string, timeWindowSeconds int) bool {
+	window := time.Duration(timeWindowSeconds) * time.Second
+	if window > r.retention {
+		return false
+	}
+
+	startedAt, err := r.redis.Get(ctx, historyStartKey).Int64()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("Redis velocity history start lookup failed, falling back: %v", err)
+		}
+		return false
+	}
+	if time.UnixMilli(startedAt).After(time.Now().Add(-window)) {
+		return false
+	}
+
+	exists, err := r.redis.Exists(ctx, historyKey(account)).Result()
+	if err != nil {
+		log.Printf("Redis velocity history lookup failed, falling back: %v", err)
+		return false
+	}
+	return exists == 1
+}
+
+// historyKey returns the sorted set key of an account
+func historyKey(account string) string {
+	return historyKeyPrefix + account
+}
+
+// windowStart returns the exclusive lower score bound of a window ending now
+func windowStart(timeWindowSeconds int) string {
+	start := time.Now().Add(-time.Duration(timeWindowSeconds) * time.Second).UnixMilli()
+	return "(" + strconv.FormatInt(start, 10)
+}
+
+// historyMember encodes a transaction as a sorted set member: "<id>|<amount>"
+func historyMember(transactionID string, amount float64) string {
+	return transactionID + "|" + strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+// memberAmount decodes the amount of a sorted set member
+func memberAmount(member string) (float64, error) {
+	i := strings.LastIndex(member, "|")
+	if i < 0 {
+		return 0, fmt.Errorf("invalid velocity history member %q", member)
+	}
+	return strconv.ParseFloat(member[i+1:], 64)
+}
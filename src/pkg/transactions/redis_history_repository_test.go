@@ -0,0 +1,233 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// startedAt answers the history start lookup with a recording start time
+func startedAt(at time.Time) *redis.StringCmd {
+	return redis.NewStringResult(strconv.FormatInt(at.UnixMilli(), 10), nil)
+}
+
+func Test_RedisHistoryRepository_RecordTransaction_WhenCalled_ThenAddsTrimsAndExpires(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, nil)
+
+	mockRedis.EXPECT().Pipelined(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+			pipe := redis.NewClient(&redis.Options{}).Pipeline()
+			require.NoError(t, fn(pipe))
+			assert.Equal(t, 5, pipe.Len(), "add, trim and expire the set, mark the recording start and expire it alike")
+			return nil, nil
+		},
+	)
+
+	err := repo.RecordTransaction(context.Background(), "ACC1", "tx-1", 100.5, time.Now())
+
+	require.NoError(t, err)
+}
+
+func Test_RedisHistoryRepository_RemoveTransaction_WhenCalled_ThenRemovesRecordedMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, nil)
+
+	mockRedis.EXPECT().ZRem(gomock.Any(), "velocity:ACC1", []interface{}{"tx-1|100.5"}).Return(redis.NewIntResult(1, nil))
+
+	err := repo.RemoveTransaction(context.Background(), "ACC1", "tx-1", 100.5)
+
+	require.NoError(t, err)
+}
+
+func Test_RedisHistoryRepository_CountByAccountInTimeWindow_WhenWithinRetention_ThenCountsSortedSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, nil)
+
+	mockRedis.EXPECT().Get(gomock.Any(), historyStartKey).Return(startedAt(time.Now().Add(-2 * time.Hour)))
+	mockRedis.EXPECT().Exists(gomock.Any(), "velocity:ACC1").Return(redis.NewIntResult(1, nil))
+	mockRedis.EXPECT().ZCount(gomock.Any(), "velocity:ACC1", gomock.Any(), "+inf").
+		Return(redis.NewIntResult(4, nil))
+
+	count, err := repo.CountByAccountInTimeWindow(context.Background(), "ACC1", 600)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+}
+
+func Test_RedisHistoryRepository_CountByAccountInTimeWindow_WhenRecordingNeverStarted_ThenUsesFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	fallback := NewMockTransactionHistoryRepository(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, fallback)
+
+	mockRedis.EXPECT().Get(gomock.Any(), historyStartKey).Return(redis.NewStringResult("", redis.Nil))
+	fallback.EXPECT().CountByAccountInTimeWindow(gomock.Any(), "ACC1", 600).Return(7, nil)
+
+	count, err := repo.CountByAccountInTimeWindow(context.Background(), "ACC1", 600)
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, count)
+}
+
+func Test_RedisHistoryRepository_CountByAccountInTimeWindow_WhenRecordingStartedWithinWindow_ThenUsesFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	fallback := NewMockTransactionHistoryRepository(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, fallback)
+
+	mockRedis.EXPECT().Get(gomock.Any(), historyStartKey).Return(startedAt(time.Now().Add(-5 * time.Minute)))
+	fallback.EXPECT().CountByAccountInTimeWindow(gomock.Any(), "ACC1", 600).Return(7, nil)
+
+	count, err := repo.CountByAccountInTimeWindow(context.Background(), "ACC1", 600)
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, count)
+}
+
+func Test_RedisHistoryRepository_CountByAccountInTimeWindow_WhenSortedSetEvicted_ThenUsesFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	fallback := NewMockTransactionHistoryRepository(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, fallback)
+
+	// The recording start survived, but the account's sorted set is gone
+	mockRedis.EXPECT().Get(gomock.Any(), historyStartKey).Return(startedAt(time.Now().Add(-2 * time.Hour)))
+	mockRedis.EXPECT().Exists(gomock.Any(), "velocity:ACC1").Return(redis.NewIntResult(0, nil))
+	fallback.EXPECT().CountByAccountInTimeWindow(gomock.Any(), "ACC1", 600).Return(7, nil)
+
+	count, err := repo.CountByAccountInTimeWindow(context.Background(), "ACC1", 600)
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, count)
+}
+
+func Test_RedisHistoryRepository_SumAmountByAccountInTimeWindow_WhenSortedSetEvicted_ThenUsesFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	fallback := NewMockTransactionHistoryRepository(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, fallback)
+
+	mockRedis.EXPECT().Get(gomock.Any(), historyStartKey).Return(startedAt(time.Now().Add(-2 * time.Hour)))
+	mockRedis.EXPECT().Exists(gomock.Any(), "velocity:ACC1").Return(redis.NewIntResult(0, nil))
+	fallback.EXPECT().SumAmountByAccountInTimeWindow(gomock.Any(), "ACC1", 600).Return(350.0, nil)
+
+	sum, err := repo.SumAmountByAccountInTimeWindow(context.Background(), "ACC1", 600)
+
+	require.NoError(t, err)
+	assert.Equal(t, 350.0, sum)
+}
+
+func Test_RedisHistoryRepository_CountByAccountInTimeWindow_WhenWindowExceedsRetention_ThenUsesFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	fallback := NewMockTransactionHistoryRepository(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, fallback)
+
+	fallback.EXPECT().CountByAccountInTimeWindow(gomock.Any(), "ACC1", 7200).Return(12, nil)
+
+	count, err := repo.CountByAccountInTimeWindow(context.Background(), "ACC1", 7200)
+
+	require.NoError(t, err)
+	assert.Equal(t, 12, count)
+}
+
+func Test_RedisHistoryRepository_CountByAccountInTimeWindow_WhenRedisFails_ThenUsesFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	fallback := NewMockTransactionHistoryRepository(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, fallback)
+
+	mockRedis.EXPECT().Get(gomock.Any(), historyStartKey).Return(startedAt(time.Now().Add(-2 * time.Hour)))
+	mockRedis.EXPECT().Exists(gomock.Any(), "velocity:ACC1").Return(redis.NewIntResult(1, nil))
+	mockRedis.EXPECT().ZCount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(redis.NewIntResult(0, errors.New("connection refused")))
+	fallback.EXPECT().CountByAccountInTimeWindow(gomock.Any(), "ACC1", 60).Return(2, nil)
+
+	count, err := repo.CountByAccountInTimeWindow(context.Background(), "ACC1", 60)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func Test_RedisHistoryRepository_SumAmountByAccountInTimeWindow_WhenWithinRetention_ThenSumsMemberAmounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, nil)
+
+	mockRedis.EXPECT().Get(gomock.Any(), historyStartKey).Return(startedAt(time.Now().Add(-2 * time.Hour)))
+	mockRedis.EXPECT().Exists(gomock.Any(), "velocity:ACC1").Return(redis.NewIntResult(1, nil))
+	mockRedis.EXPECT().ZRangeByScore(gomock.Any(), "velocity:ACC1", gomock.Any()).
+		Return(redis.NewStringSliceResult([]string{"tx-1|100.5", "tx-2|49.5", "tx|with|pipes|10"}, nil))
+
+	sum, err := repo.SumAmountByAccountInTimeWindow(context.Background(), "ACC1", 600)
+
+	require.NoError(t, err)
+	assert.Equal(t, 160.0, sum)
+}
+
+func Test_RedisHistoryRepository_SumAmountByAccountInTimeWindow_WhenRedisFails_ThenUsesFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	fallback := NewMockTransactionHistoryRepository(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, fallback)
+
+	mockRedis.EXPECT().Get(gomock.Any(), historyStartKey).Return(startedAt(time.Now().Add(-2 * time.Hour)))
+	mockRedis.EXPECT().Exists(gomock.Any(), "velocity:ACC1").Return(redis.NewIntResult(1, nil))
+	mockRedis.EXPECT().ZRangeByScore(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(redis.NewStringSliceResult(nil, errors.New("connection refused")))
+	fallback.EXPECT().SumAmountByAccountInTimeWindow(gomock.Any(), "ACC1", 60).Return(75.0, nil)
+
+	sum, err := repo.SumAmountByAccountInTimeWindow(context.Background(), "ACC1", 60)
+
+	require.NoError(t, err)
+	assert.Equal(t, 75.0, sum)
+}
+
+func Test_RedisHistoryRepository_CountByFieldInTimeWindow_WhenCalled_ThenUsesFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisSortedSet(ctrl)
+	fallback := NewMockTransactionHistoryRepository(ctrl)
+	repo := NewRedisHistoryRepository(mockRedis, time.Hour, fallback)
+
+	fallback.EXPECT().CountByFieldInTimeWindow(gomock.Any(), "ip", "10.0.0.1", 600).Return(3, nil)
+
+	count, err := repo.CountByFieldInTimeWindow(context.Background(), "ip", "10.0.0.1", 600)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
@@ -9,7 +9,14 @@ import (
 
 	"github.com/algo-shield/algo-shield/src/pkg/config"
 	"github.com/algo-shield/algo-shield/src/pkg/database"
+	"github.com/algo-shield/algo-shield/src/pkg/deadletters"
+	"github.com/algo-shield/algo-shield/src/pkg/geo"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/algo-shield/algo-shield/src/workers/internal/processor"
+	"github.com/algo-shield/algo-shield/src/workers/internal/queue"
+	"github.com/algo-shield/algo-shield/src/workers/internal/rules"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
 func main() {
@@ -45,17 +52,93 @@ func main() {
 		Multiplier:   cfg.Worker.Retry.Multiplier,
 	}
 
+	conflictPolicy, err := rules.ParseConflictPolicy(cfg.Worker.Decision.ConflictPolicy)
+	if err != nil {
+		log.Fatalf("Invalid worker configuration: %v", err)
+	}
+
+	// Load the optional local IP database once; it is only read afterwards
+	var ipDatabase *geo.IPDatabase
+	if path := cfg.Worker.IPIntel.DatabasePath; path != "" {
+		ipDatabase, err = geo.LoadIPDatabase(path)
+		if err != nil {
+			log.Fatalf("Failed to load IP database: %v", err)
+		}
+		log.Printf("Loaded %d networks from IP database %s", ipDatabase.Len(), path)
+	}
+
+	// Build rule engine configuration from worker config
+	engineCfg := rules.EngineConfig{
+		RuleEvaluationTimeout: cfg.Worker.Timeouts.RuleEvaluation,
+		EvaluationTimeout:     cfg.Worker.Timeouts.Evaluation,
+		TimeoutFallback:       models.RuleAction(cfg.Worker.Timeouts.Fallback),
+		ScoreBands: rules.ScoreBands{
+			ReviewThreshold: cfg.Worker.Scoring.ReviewThreshold,
+			BlockThreshold:  cfg.Worker.Scoring.BlockThreshold,
+		},
+		ConflictPolicy: conflictPolicy,
+		Velocity: rules.VelocityConfig{
+			Redis:     cfg.Worker.Velocity.Backend == "redis",
+			Retention: cfg.Worker.Velocity.Retention,
+		},
+		Timeline: rules.TimelineConfig{
+			Retention: cfg.Worker.Timeline.Retention,
+			MaxEvents: cfg.Worker.Timeline.MaxEvents,
+		},
+		Health: rules.HealthConfig{
+			FlushInterval:    cfg.Worker.RuleHealth.FlushInterval,
+			FailureThreshold: cfg.Worker.RuleHealth.FailureThreshold,
+			AutoDisable:      cfg.Worker.RuleHealth.AutoDisable,
+		},
+		IPDatabase:         ipDatabase,
+		SanctionsThreshold: cfg.Sanctions.MatchThreshold,
+	}
+
+	queueCfg := queue.Config{
+		PopTimeout:        cfg.Worker.Queue.PopTimeout,
+		HeartbeatInterval: cfg.Worker.Queue.HeartbeatInterval,
+		ConsumerTimeout:   cfg.Worker.Queue.ConsumerTimeout,
+		StrictLanes:       cfg.Worker.Queue.Lanes.Strict,
+		LaneWeights:       cfg.Worker.Queue.Lanes.Weights,
+	}
+
+	// Select the transport transaction events are consumed from
+	// Redis lists are consumed by the processor's own queue service, which also carries backtest jobs
+	var consumer queue.Consumer
+	switch cfg.Worker.Queue.Transport {
+	case queue.TransportRedisStreams:
+		consumer = queue.NewStreamConsumer(redis.Client, deadletters.NewPostgresRepository(db.Pool), queueCfg)
+	case queue.TransportNATS:
+		nc, err := nats.Connect(cfg.Worker.Queue.NATS.URL)
+		if err != nil {
+			log.Fatalf("Failed to connect to NATS: %v", err)
+		}
+		defer func() {
+			if err := nc.Drain(); err != nil {
+				log.Printf("Error draining NATS connection: %v", err)
+			}
+		}()
+
+		js, err := jetstream.New(nc)
+		if err != nil {
+			log.Fatalf("Failed to create JetStream context: %v", err)
+		}
+		consumer = queue.NewJetStreamConsumer(js, deadletters.NewPostgresRepository(db.Pool), cfg.Worker.Queue.NATS.Stream, queueCfg)
+	}
+	log.Printf("Consuming transactions from %s", cfg.Worker.Queue.Transport)
+
 	// Create processor with all configurations
 	proc := processor.NewProcessor(
 		db.Pool,
 		redis.Client,
+		consumer,
 		cfg.Worker.Concurrency,
 		cfg.Worker.BatchSize,
 		cfg.Worker.Timeouts.TransactionProcessing,
-		cfg.Worker.Timeouts.RuleEvaluation,
-		cfg.Worker.Queue.PopTimeout,
 		cfg.Worker.RulesReload.Interval,
+		queueCfg,
 		retryCfg,
+		engineCfg,
 	)
 
 	// Setup context with cancellation
@@ -0,0 +1,118 @@
+package backtest
+
+import (
+	"sort"
+
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	engine "github.com/algo-shield/algo-shield/src/workers/internal/rules"
+	"github.com/google/uuid"
+)
+
+// statusChange is a stored status and the status the backtest reached
+type statusChange struct {
+	from models.TransactionStatus
+	to   models.TransactionStatus
+}
+
+// aggregator accumulates the result of a backtest as transactions are replayed
+type aggregator struct {
+	sampleLimit int
+	matchIndex  map[uuid.UUID]int // Candidate rule ID to its position in result.RuleMatches
+	deltas      map[statusChange]int
+	summary     models.BacktestResult
+}
+
+func newAggregator(job *models.Backtest) *aggregator {
+	agg := &aggregator{
+		sampleLimit: job.SampleLimit,
+		matchIndex:  make(map[uuid.UUID]int, len(job.Rules)),
+		deltas:      make(map[statusChange]int),
+		summary: models.BacktestResult{
+			RuleMatches:    make([]models.RuleMatchCount, 0, len(job.Rules)),
+			DecisionDeltas: make([]models.DecisionDelta, 0),
+			Samples:        make([]models.BacktestSample, 0),
+
+			TimeDependentRules: make([]models.TimeDependentRule, 0),
+		},
+	}
+
+	for _, rule := range job.Rules {
+		agg.matchIndex[rule.ID] = len(agg.summary.RuleMatches)
+		agg.summary.RuleMatches = append(agg.summary.RuleMatches, models.RuleMatchCount{
+			RuleID:   rule.ID,
+			RuleName: rule.Name,
+		})
+	}
+
+	return agg
+}
+
+// flagTimeDependent records the replayed rules whose helpers answer from present-day history
+func (a *aggregator) flagTimeDependent(compiled []engine.CompiledRule) {
+	for i := range compiled {
+		helpers := expressions.TimeDependentHelpers(compiled[i].Program)
+		if len(helpers) == 0 {
+			continue
+		}
+		_, candidate := a.matchIndex[compiled[i].Rule.ID]
+		a.summary.TimeDependentRules = append(a.summary.TimeDependentRules, models.TimeDependentRule{
+			RuleID:    compiled[i].Rule.ID,
+			RuleName:  compiled[i].Rule.Name,
+			Candidate: candidate,
+			Helpers:   helpers,
+		})
+	}
+}
+
+// skip records a transaction that could not be replayed
+func (a *aggregator) skip() {
+	a.summary.Skipped++
+}
+
+// add records the backtest decision for a replayed transaction
+func (a *aggregator) add(transaction *models.Transaction, result *models.TransactionResult, matched []*engine.CompiledRule) {
+	a.summary.Evaluated++
+
+	if result.Status != transaction.Status {
+		a.summary.Changed++
+		a.deltas[statusChange{from: transaction.Status, to: result.Status}]++
+	}
+
+	candidateMatched := false
+	for _, rule := range matched {
+		if i, ok := a.matchIndex[rule.Rule.ID]; ok {
+			a.summary.RuleMatches[i].Matches++
+			candidateMatched = true
+		}
+	}
+
+	if candidateMatched && len(a.summary.Samples) < a.sampleLimit {
+		a.summary.Samples = append(a.summary.Samples, models.BacktestSample{
+			TransactionID:  transaction.ID,
+			ExternalID:     transaction.ExternalID,
+			StoredStatus:   transaction.Status,
+			BacktestStatus: result.Status,
+			RiskScore:      result.RiskScore,
+			MatchedRules:   result.MatchedRules,
+		})
+	}
+}
+
+// result returns the accumulated result with decision deltas in a stable order
+func (a *aggregator) result() *models.BacktestResult {
+	deltas := make([]models.DecisionDelta, 0, len(a.deltas))
+	for change, count := range a.deltas {
+		deltas = append(deltas, models.DecisionDelta{From: change.from, To: change.to, Count: count})
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].From != deltas[j].From {
+			return deltas[i].From < deltas[j].From
+		}
+		return deltas[i].To < deltas[j].To
+	})
+
+	result := a.summary
+	result.DecisionDeltas = deltas
+	return &result
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/workers/internal/backtest/runner.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/workers/internal/backtest/runner.go -destination=src/workers/internal/backtest/mock_evaluator_test.go -package=backtest
+//
+
+// Package backtest is a generated GoMock package.
+package backtest
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	rules "github.com/algo-shield/algo-shield/src/workers/internal/rules"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEvaluator is a mock of Evaluator interface.
+type MockEvaluator struct {
+	ctrl     *gomock.Controller
+	recorder *MockEvaluatorMockRecorder
+	isgomock struct{}
+}
+
+// MockEvaluatorMockRecorder is the mock recorder for MockEvaluator.
+type MockEvaluatorMockRecorder struct {
+	mock *MockEvaluator
+}
+
+// NewMockEvaluator creates a new mock instance.
+func NewMockEvaluator(ctrl *gomock.Controller) *MockEvaluator {
+	mock := &MockEvaluator{ctrl: ctrl}
+	mock.recorder = &MockEvaluatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEvaluator) EXPECT() *MockEvaluatorMockRecorder {
+	return m.recorder
+}
+
+// ActiveRules mocks base method.
+func (m *MockEvaluator) ActiveRules() []models.Rule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveRules")
+	ret0, _ := ret[0].([]models.Rule)
+	return ret0
+}
+
+// ActiveRules indicates an expected call of ActiveRules.
+func (mr *MockEvaluatorMockRecorder) ActiveRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveRules", reflect.TypeOf((*MockEvaluator)(nil).ActiveRules))
+}
+
+// CompileRules mocks base method.
+func (m *MockEvaluator) CompileRules(arg0 []models.Rule) []rules.CompiledRule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompileRules", arg0)
+	ret0, _ := ret[0].([]rules.CompiledRule)
+	return ret0
+}
+
+// CompileRules indicates an expected call of CompileRules.
+func (mr *MockEvaluatorMockRecorder) CompileRules(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompileRules", reflect.TypeOf((*MockEvaluator)(nil).CompileRules), arg0)
+}
+
+// EvaluateRules mocks base method.
+func (m *MockEvaluator) EvaluateRules(ctx context.Context, event models.Event, compiledRules []rules.CompiledRule) (*models.TransactionResult, []*rules.CompiledRule) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateRules", ctx, event, compiledRules)
+	ret0, _ := ret[0].(*models.TransactionResult)
+	ret1, _ := ret[1].([]*rules.CompiledRule)
+	return ret0, ret1
+}
+
+// EvaluateRules indicates an expected call of EvaluateRules.
+func (mr *MockEvaluatorMockRecorder) EvaluateRules(ctx, event, compiledRules any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateRules", reflect.TypeOf((*MockEvaluator)(nil).EvaluateRules), ctx, event, compiledRules)
+}
+
+// MockJobPopper is a mock of JobPopper interface.
+type MockJobPopper struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobPopperMockRecorder
+	isgomock struct{}
+}
+
+// MockJobPopperMockRecorder is the mock recorder for MockJobPopper.
+type MockJobPopperMockRecorder struct {
+	mock *MockJobPopper
+}
+
+// NewMockJobPopper creates a new mock instance.
+func NewMockJobPopper(ctrl *gomock.Controller) *MockJobPopper {
+	mock := &MockJobPopper{ctrl: ctrl}
+	mock.recorder = &MockJobPopperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobPopper) EXPECT() *MockJobPopperMockRecorder {
+	return m.recorder
+}
+
+// PopBacktest mocks base method.
+func (m *MockJobPopper) PopBacktest(ctx context.Context) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PopBacktest", ctx)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PopBacktest indicates an expected call of PopBacktest.
+func (mr *MockJobPopperMockRecorder) PopBacktest(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PopBacktest", reflect.TypeOf((*MockJobPopper)(nil).PopBacktest), ctx)
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/backtests/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/backtests/repository.go -destination=src/workers/internal/backtest/mock_job_store_test.go -package=backtest -exclude_interfaces=Repository
+//
+
+// Package backtest is a generated GoMock package.
+package backtest
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobStore is a mock of JobStore interface.
+type MockJobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockJobStoreMockRecorder is the mock recorder for MockJobStore.
+type MockJobStoreMockRecorder struct {
+	mock *MockJobStore
+}
+
+// NewMockJobStore creates a new mock instance.
+func NewMockJobStore(ctrl *gomock.Controller) *MockJobStore {
+	mock := &MockJobStore{ctrl: ctrl}
+	mock.recorder = &MockJobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobStore) EXPECT() *MockJobStoreMockRecorder {
+	return m.recorder
+}
+
+// CompleteBacktest mocks base method.
+func (m *MockJobStore) CompleteBacktest(ctx context.Context, id uuid.UUID, result *models.BacktestResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteBacktest", ctx, id, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteBacktest indicates an expected call of CompleteBacktest.
+func (mr *MockJobStoreMockRecorder) CompleteBacktest(ctx, id, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteBacktest", reflect.TypeOf((*MockJobStore)(nil).CompleteBacktest), ctx, id, result)
+}
+
+// FailBacktest mocks base method.
+func (m *MockJobStore) FailBacktest(ctx context.Context, id uuid.UUID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailBacktest", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailBacktest indicates an expected call of FailBacktest.
+func (mr *MockJobStoreMockRecorder) FailBacktest(ctx, id, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailBacktest", reflect.TypeOf((*MockJobStore)(nil).FailBacktest), ctx, id, reason)
+}
+
+// GetBacktest mocks base method.
+func (m *MockJobStore) GetBacktest(ctx context.Context, id uuid.UUID) (*models.Backtest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBacktest", ctx, id)
+	ret0, _ := ret[0].(*models.Backtest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBacktest indicates an expected call of GetBacktest.
+func (mr *MockJobStoreMockRecorder) GetBacktest(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBacktest", reflect.TypeOf((*MockJobStore)(nil).GetBacktest), ctx, id)
+}
+
+// StartBacktest mocks base method.
+func (m *MockJobStore) StartBacktest(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartBacktest", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartBacktest indicates an expected call of StartBacktest.
+func (mr *MockJobStoreMockRecorder) StartBacktest(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartBacktest", reflect.TypeOf((*MockJobStore)(nil).StartBacktest), ctx, id)
+}
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/workers/internal/backtest/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/workers/internal/backtest/repository.go -destination=src/workers/internal/backtest/mock_transaction_reader_test.go -package=backtest
+//
+
+// Package backtest is a generated GoMock package.
+package backtest
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransactionReader is a mock of TransactionReader interface.
+type MockTransactionReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionReaderMockRecorder is the mock recorder for MockTransactionReader.
+type MockTransactionReaderMockRecorder struct {
+	mock *MockTransactionReader
+}
+
+// NewMockTransactionReader creates a new mock instance.
+func NewMockTransactionReader(ctrl *gomock.Controller) *MockTransactionReader {
+	mock := &MockTransactionReader{ctrl: ctrl}
+	mock.recorder = &MockTransactionReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionReader) EXPECT() *MockTransactionReaderMockRecorder {
+	return m.recorder
+}
+
+// ListForReplay mocks base method.
+func (m *MockTransactionReader) ListForReplay(ctx context.Context, from, to time.Time, after ReplayCursor, limit int) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListForReplay", ctx, from, to, after, limit)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListForReplay indicates an expected call of ListForReplay.
+func (mr *MockTransactionReaderMockRecorder) ListForReplay(ctx, from, to, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListForReplay", reflect.TypeOf((*MockTransactionReader)(nil).ListForReplay), ctx, from, to, after, limit)
+}
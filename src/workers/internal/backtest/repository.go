@@ -0,0 +1,67 @@
+package backtest
+
+import (
+	"context"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ReplayCursor marks the last transaction read so replay pages never overlap
+type ReplayCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// TransactionReader defines the interface for reading stored transactions to replay
+type TransactionReader interface {
+	// ListForReplay returns transactions created in [from, to) after the cursor, oldest first
+	ListForReplay(ctx context.Context, from, to time.Time, after ReplayCursor, limit int) ([]models.Transaction, error)
+}
+
+// PostgresTransactionReader is the PostgreSQL implementation of TransactionReader
+type PostgresTransactionReader struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresTransactionReader creates a new PostgreSQL transaction reader
+func NewPostgresTransactionReader(db *pgxpool.Pool) TransactionReader {
+	return &PostgresTransactionReader{db: db}
+}
+
+func (r *PostgresTransactionReader) ListForReplay(ctx context.Context, from, to time.Time, after ReplayCursor, limit int) ([]models.Transaction, error) {
+	query := `
+		SELECT id, external_id, status, event_payload, created_at
+		FROM transactions
+		WHERE created_at >= $1 AND created_at < $2
+		AND (created_at, id) > ($3, $4)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $5
+	`
+
+	rows, err := r.db.Query(ctx, query, from, to, after.CreatedAt, after.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := make([]models.Transaction, 0, limit)
+	for rows.Next() {
+		var transaction models.Transaction
+		err := rows.Scan(
+			&transaction.ID,
+			&transaction.ExternalID,
+			&transaction.Status,
+			&transaction.Event,
+			&transaction.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
@@ -0,0 +1,185 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/backtests"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/algo-shield/algo-shield/src/workers/internal/queue"
+	engine "github.com/algo-shield/algo-shield/src/workers/internal/rules"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// replayPageSize is the number of stored transactions read per query
+const replayPageSize = 500
+
+// failureTimeout bounds how long recording a failed job may take after shutdown
+const failureTimeout = 5 * time.Second
+
+// Evaluator defines the rule engine operations used to replay transactions
+// Backtests go through the same compile and evaluation path as live traffic
+type Evaluator interface {
+	ActiveRules() []models.Rule
+	CompileRules(rules []models.Rule) []engine.CompiledRule
+	EvaluateRules(ctx context.Context, event models.Event, compiledRules []engine.CompiledRule) (*models.TransactionResult, []*engine.CompiledRule)
+}
+
+// JobPopper defines the interface for receiving queued backtest jobs
+type JobPopper interface {
+	PopBacktest(ctx context.Context) (uuid.UUID, error)
+}
+
+// Runner executes queued backtests against stored transactions
+type Runner struct {
+	jobs         backtests.JobStore
+	transactions TransactionReader
+	evaluator    Evaluator
+	queue        JobPopper
+}
+
+// NewRunner creates a new backtest runner with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewRunner(jobs backtests.JobStore, transactions TransactionReader, evaluator Evaluator, queue JobPopper) *Runner {
+	return &Runner{
+		jobs:         jobs,
+		transactions: transactions,
+		evaluator:    evaluator,
+		queue:        queue,
+	}
+}
+
+// Start runs queued backtests one at a time until the context is cancelled
+// This is a blocking function that should be called in a goroutine managed by errgroup
+func (r *Runner) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		id, err := r.queue.PopBacktest(ctx)
+		if err != nil {
+			if !errors.Is(err, queue.ErrTimeout) && ctx.Err() == nil {
+				log.Printf("Backtest queue error: %v", err)
+			}
+			continue
+		}
+
+		if err := r.Run(ctx, id); err != nil {
+			log.Printf("Backtest %s failed: %v", id, err)
+		}
+	}
+}
+
+// Run executes a single backtest and stores its result
+// A backtest that is no longer pending, for example one already picked up by another worker, is skipped
+func (r *Runner) Run(ctx context.Context, id uuid.UUID) error {
+	job, err := r.jobs.GetBacktest(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to load backtest: %w", err)
+	}
+
+	if err := r.jobs.StartBacktest(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Backtest %s is not pending, skipping", id)
+			return nil
+		}
+		return fmt.Errorf("failed to start backtest: %w", err)
+	}
+
+	log.Printf("Running backtest %s over %s - %s", id, job.From.Format(time.RFC3339), job.To.Format(time.RFC3339))
+
+	result, err := r.replay(ctx, job)
+	if err != nil {
+		// Record the failure even when the run was interrupted by shutdown
+		failCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), failureTimeout)
+		defer cancel()
+		if failErr := r.jobs.FailBacktest(failCtx, id, err.Error()); failErr != nil {
+			log.Printf("Failed to record backtest %s failure: %v", id, failErr)
+		}
+		return err
+	}
+
+	if err := r.jobs.CompleteBacktest(ctx, id, result); err != nil {
+		return fmt.Errorf("failed to store backtest result: %w", err)
+	}
+
+	log.Printf("Backtest %s completed: evaluated=%d, changed=%d", id, result.Evaluated, result.Changed)
+	return nil
+}
+
+// replay evaluates every stored transaction in the job's time range against the candidate rule set
+func (r *Runner) replay(ctx context.Context, job *models.Backtest) (*models.BacktestResult, error) {
+	compiled := r.evaluator.CompileRules(candidateRuleSet(job, r.evaluator.ActiveRules()))
+
+	// Candidates were compile-checked by the API, but a schema may have changed since
+	candidates := make(map[uuid.UUID]bool, len(job.Rules))
+	for _, rule := range job.Rules {
+		candidates[rule.ID] = true
+	}
+	for i := range compiled {
+		if candidates[compiled[i].Rule.ID] && compiled[i].Err != nil {
+			return nil, fmt.Errorf("candidate rule %s: %w", compiled[i].Rule.Name, compiled[i].Err)
+		}
+	}
+
+	// Helpers such as velocity read history up to now, so rules calling them are flagged as approximate
+	agg := newAggregator(job)
+	agg.flagTimeDependent(compiled)
+	cursor := ReplayCursor{CreatedAt: job.From}
+	for {
+		page, err := r.transactions.ListForReplay(ctx, job.From, job.To, cursor, replayPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transactions: %w", err)
+		}
+
+		for i := range page {
+			transaction := &page[i]
+			if transaction.Event == nil {
+				agg.skip()
+				continue
+			}
+			result, matched := r.evaluator.EvaluateRules(ctx, transaction.Event, compiled)
+			agg.add(transaction, result, matched)
+		}
+
+		if len(page) < replayPageSize {
+			break
+		}
+		last := page[len(page)-1]
+		cursor = ReplayCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return agg.result(), nil
+}
+
+// candidateRuleSet builds the rule set to replay
+// Candidates are evaluated as enabled active rules; unless the job replaces the rule set,
+// they are added to the active rules, replacing active rules with the same ID
+func candidateRuleSet(job *models.Backtest, active []models.Rule) []models.Rule {
+	ruleSet := make([]models.Rule, 0, len(active)+len(job.Rules))
+	candidates := make(map[uuid.UUID]bool, len(job.Rules))
+	for _, rule := range job.Rules {
+		rule.Enabled = true
+		rule.Mode = models.RuleModeActive
+		ruleSet = append(ruleSet, rule)
+		candidates[rule.ID] = true
+	}
+
+	if job.ReplaceRuleSet {
+		return ruleSet
+	}
+
+	for _, rule := range active {
+		if !candidates[rule.ID] {
+			ruleSet = append(ruleSet, rule)
+		}
+	}
+	return ruleSet
+}
@@ -0,0 +1,253 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	engine "github.com/algo-shield/algo-shield/src/workers/internal/rules"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func testJob(candidates ...models.Rule) *models.Backtest {
+	return &models.Backtest{
+		ID:          uuid.New(),
+		Status:      models.BacktestPending,
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		Rules:       candidates,
+		SampleLimit: 10,
+	}
+}
+
+func storedTransaction(status models.TransactionStatus, event models.Event) models.Transaction {
+	return models.Transaction{
+		ID:         uuid.New(),
+		ExternalID: "tx-" + uuid.NewString()[:8],
+		Status:     status,
+		Event:      event,
+		CreatedAt:  time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// compileAll compiles rules as-is, keeping the order the runner passed in
+func compileAll(rules []models.Rule) []engine.CompiledRule {
+	compiled := make([]engine.CompiledRule, len(rules))
+	for i, rule := range rules {
+		compiled[i] = engine.CompiledRule{Rule: rule}
+	}
+	return compiled
+}
+
+func Test_Runner_Run_WhenCandidateMatches_ThenStoresResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	candidate := models.Rule{ID: uuid.New(), Name: "Large Amount", Action: models.ActionBlock}
+	job := testJob(candidate)
+	flagged := storedTransaction(models.StatusApproved, models.Event{"amount": 5000.0})
+	unchanged := storedTransaction(models.StatusApproved, models.Event{"amount": 10.0})
+	legacy := storedTransaction(models.StatusApproved, nil)
+
+	jobs := NewMockJobStore(ctrl)
+	reader := NewMockTransactionReader(ctrl)
+	evaluator := NewMockEvaluator(ctrl)
+	runner := NewRunner(jobs, reader, evaluator, nil)
+
+	jobs.EXPECT().GetBacktest(gomock.Any(), job.ID).Return(job, nil)
+	jobs.EXPECT().StartBacktest(gomock.Any(), job.ID).Return(nil)
+	evaluator.EXPECT().ActiveRules().Return(nil)
+	evaluator.EXPECT().CompileRules(gomock.Any()).DoAndReturn(compileAll)
+	reader.EXPECT().ListForReplay(gomock.Any(), job.From, job.To, ReplayCursor{CreatedAt: job.From}, replayPageSize).
+		Return([]models.Transaction{flagged, unchanged, legacy}, nil)
+	evaluator.EXPECT().EvaluateRules(gomock.Any(), flagged.Event, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, event models.Event, compiled []engine.CompiledRule) (*models.TransactionResult, []*engine.CompiledRule) {
+			return &models.TransactionResult{Status: models.StatusRejected, RiskScore: 80, MatchedRules: []string{"Large Amount"}},
+				[]*engine.CompiledRule{&compiled[0]}
+		})
+	evaluator.EXPECT().EvaluateRules(gomock.Any(), unchanged.Event, gomock.Any()).
+		Return(&models.TransactionResult{Status: models.StatusApproved, MatchedRules: []string{}}, nil)
+	jobs.EXPECT().CompleteBacktest(gomock.Any(), job.ID, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, id uuid.UUID, result *models.BacktestResult) error {
+			assert.Equal(t, 2, result.Evaluated)
+			assert.Equal(t, 1, result.Skipped)
+			assert.Equal(t, 1, result.Changed)
+			require.Len(t, result.RuleMatches, 1)
+			assert.Equal(t, candidate.ID, result.RuleMatches[0].RuleID)
+			assert.Equal(t, 1, result.RuleMatches[0].Matches)
+			assert.Equal(t, []models.DecisionDelta{
+				{From: models.StatusApproved, To: models.StatusRejected, Count: 1},
+			}, result.DecisionDeltas)
+			require.Len(t, result.Samples, 1)
+			assert.Equal(t, flagged.ID, result.Samples[0].TransactionID)
+			assert.Equal(t, models.StatusRejected, result.Samples[0].BacktestStatus)
+			return nil
+		})
+
+	err := runner.Run(context.Background(), job.ID)
+
+	require.NoError(t, err)
+}
+
+func Test_Runner_Run_WhenPageIsFull_ThenReadsNextPageAfterCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	job := testJob(models.Rule{ID: uuid.New(), Name: "Candidate"})
+	firstPage := make([]models.Transaction, replayPageSize)
+	for i := range firstPage {
+		firstPage[i] = storedTransaction(models.StatusApproved, nil)
+	}
+	last := firstPage[len(firstPage)-1]
+
+	jobs := NewMockJobStore(ctrl)
+	reader := NewMockTransactionReader(ctrl)
+	evaluator := NewMockEvaluator(ctrl)
+	runner := NewRunner(jobs, reader, evaluator, nil)
+
+	jobs.EXPECT().GetBacktest(gomock.Any(), job.ID).Return(job, nil)
+	jobs.EXPECT().StartBacktest(gomock.Any(), job.ID).Return(nil)
+	evaluator.EXPECT().ActiveRules().Return(nil)
+	evaluator.EXPECT().CompileRules(gomock.Any()).DoAndReturn(compileAll)
+	gomock.InOrder(
+		reader.EXPECT().ListForReplay(gomock.Any(), job.From, job.To, ReplayCursor{CreatedAt: job.From}, replayPageSize).
+			Return(firstPage, nil),
+		reader.EXPECT().ListForReplay(gomock.Any(), job.From, job.To, ReplayCursor{CreatedAt: last.CreatedAt, ID: last.ID}, replayPageSize).
+			Return([]models.Transaction{}, nil),
+	)
+	jobs.EXPECT().CompleteBacktest(gomock.Any(), job.ID, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, id uuid.UUID, result *models.BacktestResult) error {
+			assert.Equal(t, replayPageSize, result.Skipped)
+			return nil
+		})
+
+	err := runner.Run(context.Background(), job.ID)
+
+	require.NoError(t, err)
+}
+
+func Test_Runner_Run_WhenCandidateFailsToCompile_ThenFailsJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	candidate := models.Rule{ID: uuid.New(), Name: "Broken"}
+	job := testJob(candidate)
+
+	jobs := NewMockJobStore(ctrl)
+	evaluator := NewMockEvaluator(ctrl)
+	runner := NewRunner(jobs, NewMockTransactionReader(ctrl), evaluator, nil)
+
+	jobs.EXPECT().GetBacktest(gomock.Any(), job.ID).Return(job, nil)
+	jobs.EXPECT().StartBacktest(gomock.Any(), job.ID).Return(nil)
+	evaluator.EXPECT().ActiveRules().Return(nil)
+	evaluator.EXPECT().CompileRules(gomock.Any()).Return([]engine.CompiledRule{
+		{Rule: candidate, Err: errors.New("unknown name amout")},
+	})
+	jobs.EXPECT().FailBacktest(gomock.Any(), job.ID, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, id uuid.UUID, reason string) error {
+			assert.Contains(t, reason, "Broken")
+			return nil
+		})
+
+	err := runner.Run(context.Background(), job.ID)
+
+	assert.Error(t, err)
+}
+
+func Test_Runner_Run_WhenJobNotPending_ThenSkips(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	job := testJob()
+	jobs := NewMockJobStore(ctrl)
+	runner := NewRunner(jobs, NewMockTransactionReader(ctrl), NewMockEvaluator(ctrl), nil)
+
+	jobs.EXPECT().GetBacktest(gomock.Any(), job.ID).Return(job, nil)
+	jobs.EXPECT().StartBacktest(gomock.Any(), job.ID).Return(pgx.ErrNoRows)
+
+	err := runner.Run(context.Background(), job.ID)
+
+	assert.NoError(t, err)
+}
+
+func Test_CandidateRuleSet_WhenAddingCandidates_ThenReplacesActiveRuleWithSameID(t *testing.T) {
+	shared := uuid.New()
+	active := []models.Rule{
+		{ID: shared, Name: "Old Version", Enabled: true},
+		{ID: uuid.New(), Name: "Other", Enabled: true},
+	}
+	job := testJob(models.Rule{ID: shared, Name: "New Version", Enabled: false})
+
+	ruleSet := candidateRuleSet(job, active)
+
+	require.Len(t, ruleSet, 2)
+	assert.Equal(t, "New Version", ruleSet[0].Name)
+	assert.True(t, ruleSet[0].Enabled, "candidates are evaluated as enabled")
+	assert.Equal(t, models.RuleModeActive, ruleSet[0].Mode, "shadow candidates are evaluated as active")
+	assert.Equal(t, "Other", ruleSet[1].Name)
+}
+
+func Test_CandidateRuleSet_WhenReplacingRuleSet_ThenIgnoresActiveRules(t *testing.T) {
+	active := []models.Rule{{ID: uuid.New(), Name: "Active"}}
+	job := testJob(models.Rule{ID: uuid.New(), Name: "Candidate"})
+	job.ReplaceRuleSet = true
+
+	ruleSet := candidateRuleSet(job, active)
+
+	require.Len(t, ruleSet, 1)
+	assert.Equal(t, "Candidate", ruleSet[0].Name)
+}
+
+func Test_Runner_Run_WhenRulesCallTimeDependentHelpers_ThenFlagsThem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	candidate := models.Rule{ID: uuid.New(), Name: "Burst", Action: models.ActionBlock}
+	active := models.Rule{ID: uuid.New(), Name: "New Account", Action: models.ActionReview}
+	plain := models.Rule{ID: uuid.New(), Name: "Large Amount", Action: models.ActionReview}
+	job := testJob(candidate)
+	fields := []models.ExtractedField{{Path: "origin", Type: models.FieldTypeString}}
+	programs := map[uuid.UUID]string{
+		candidate.ID: `velocityCount(origin, 60) > 5`,
+		active.ID:    `firstSeen(origin) > now() - duration("24h")`,
+		plain.ID:     `origin == "ACC1"`,
+	}
+
+	jobs := NewMockJobStore(ctrl)
+	reader := NewMockTransactionReader(ctrl)
+	evaluator := NewMockEvaluator(ctrl)
+	runner := NewRunner(jobs, reader, evaluator, nil)
+
+	jobs.EXPECT().GetBacktest(gomock.Any(), job.ID).Return(job, nil)
+	jobs.EXPECT().StartBacktest(gomock.Any(), job.ID).Return(nil)
+	evaluator.EXPECT().ActiveRules().Return([]models.Rule{active, plain})
+	evaluator.EXPECT().CompileRules(gomock.Any()).DoAndReturn(func(rules []models.Rule) []engine.CompiledRule {
+		compiled := compileAll(rules)
+		for i := range compiled {
+			program, err := expressions.Compile(programs[compiled[i].Rule.ID], fields)
+			require.NoError(t, err)
+			compiled[i].Program = program
+		}
+		return compiled
+	})
+	reader.EXPECT().ListForReplay(gomock.Any(), job.From, job.To, gomock.Any(), replayPageSize).Return(nil, nil)
+	jobs.EXPECT().CompleteBacktest(gomock.Any(), job.ID, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, id uuid.UUID, result *models.BacktestResult) error {
+			assert.Equal(t, []models.TimeDependentRule{
+				{RuleID: candidate.ID, RuleName: "Burst", Candidate: true, Helpers: []string{"velocityCount"}},
+				{RuleID: active.ID, RuleName: "New Account", Candidate: false, Helpers: []string{"firstSeen"}},
+			}, result.TimeDependentRules)
+			return nil
+		})
+
+	err := runner.Run(context.Background(), job.ID)
+
+	require.NoError(t, err)
+}
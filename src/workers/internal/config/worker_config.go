@@ -36,6 +36,16 @@ func (wc *WorkerConfig) RuleEvaluationTimeout() time.Duration {
 	return wc.cfg.Worker.Timeouts.RuleEvaluation
 }
 
+// EvaluationTimeout returns the timeout for evaluating all rules for one event
+func (wc *WorkerConfig) EvaluationTimeout() time.Duration {
+	return wc.cfg.Worker.Timeouts.Evaluation
+}
+
+// TimeoutFallback returns the decision applied when rule evaluation times out
+func (wc *WorkerConfig) TimeoutFallback() string {
+	return wc.cfg.Worker.Timeouts.Fallback
+}
+
 // RetryConfig returns the retry configuration
 func (wc *WorkerConfig) RetryConfig() config.RetryConfig {
 	return wc.cfg.Worker.Retry
@@ -46,7 +56,47 @@ func (wc *WorkerConfig) QueuePopTimeout() time.Duration {
 	return wc.cfg.Worker.Queue.PopTimeout
 }
 
+// QueueConfig returns the queue consumption and recovery configuration
+func (wc *WorkerConfig) QueueConfig() config.QueueConfig {
+	return wc.cfg.Worker.Queue
+}
+
+// QueueTransport returns the transport transaction events are consumed from
+func (wc *WorkerConfig) QueueTransport() string {
+	return wc.cfg.Worker.Queue.Transport
+}
+
 // RulesReloadInterval returns the interval for reloading rules
 func (wc *WorkerConfig) RulesReloadInterval() time.Duration {
 	return wc.cfg.Worker.RulesReload.Interval
 }
+
+// ScoringConfig returns the risk score bands used for decisions
+func (wc *WorkerConfig) ScoringConfig() config.ScoringConfig {
+	return wc.cfg.Worker.Scoring
+}
+
+// DecisionConfig returns the rule conflict resolution configuration
+func (wc *WorkerConfig) DecisionConfig() config.DecisionConfig {
+	return wc.cfg.Worker.Decision
+}
+
+// RuleHealthConfig returns the rule health tracking configuration
+func (wc *WorkerConfig) RuleHealthConfig() config.RuleHealthConfig {
+	return wc.cfg.Worker.RuleHealth
+}
+
+// VelocityConfig returns the velocity history configuration
+func (wc *WorkerConfig) VelocityConfig() config.VelocityConfig {
+	return wc.cfg.Worker.Velocity
+}
+
+// IPIntelConfig returns the local IP database configuration
+func (wc *WorkerConfig) IPIntelConfig() config.IPIntelConfig {
+	return wc.cfg.Worker.IPIntel
+}
+
+// TimelineConfig returns the per-entity event timeline configuration
+func (wc *WorkerConfig) TimelineConfig() config.TimelineConfig {
+	return wc.cfg.Worker.Timeline
+}
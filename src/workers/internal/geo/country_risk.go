@@ -0,0 +1,48 @@
+package geo
+
+import (
+	"context"
+	"sync/atomic"
+
+	geopkg "github.com/algo-shield/algo-shield/src/pkg/geo"
+)
+
+// CountryRiskCache keeps country risk scores in memory for the countryRisk helper
+// Uses atomic.Value for lock-free reads; scores are reloaded together with the rules
+// A nil cache scores every country 0
+type CountryRiskCache struct {
+	repo   geopkg.CountryRiskReader // Only needs read access, not full Repository
+	scores atomic.Value             // stores map[string]int
+}
+
+// NewCountryRiskCache creates a new country risk cache with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewCountryRiskCache(repo geopkg.CountryRiskReader) *CountryRiskCache {
+	c := &CountryRiskCache{repo: repo}
+	c.scores.Store(make(map[string]int))
+	return c
+}
+
+// Load replaces the cached scores with the repository's
+// On error the previously loaded scores are kept
+func (c *CountryRiskCache) Load(ctx context.Context) error {
+	risks, err := c.repo.ListCountryRisks(ctx)
+	if err != nil {
+		return err
+	}
+
+	scores := make(map[string]int, len(risks))
+	for _, risk := range risks {
+		scores[geopkg.NormalizeCountryCode(risk.CountryCode)] = risk.Score
+	}
+	c.scores.Store(scores)
+	return nil
+}
+
+// CountryRiskScore returns the cached score of a country, 0 when it has none
+func (c *CountryRiskCache) CountryRiskScore(ctx context.Context, code string) (int, error) {
+	if c == nil {
+		return 0, nil
+	}
+	return c.scores.Load().(map[string]int)[geopkg.NormalizeCountryCode(code)], nil
+}
@@ -0,0 +1,59 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_CountryRiskCache_CountryRiskScore_WhenLoaded_ThenLooksUpCaseInsensitively(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockCountryRiskReader(ctrl)
+	cache := NewCountryRiskCache(repo)
+
+	repo.EXPECT().ListCountryRisks(gomock.Any()).Return([]models.CountryRisk{{CountryCode: "KP", Score: 100}}, nil)
+	require.NoError(t, cache.Load(context.Background()))
+
+	score, err := cache.CountryRiskScore(context.Background(), "kp")
+	require.NoError(t, err)
+	assert.Equal(t, 100, score)
+
+	score, err = cache.CountryRiskScore(context.Background(), "BR")
+	require.NoError(t, err)
+	assert.Zero(t, score)
+}
+
+func Test_CountryRiskCache_Load_WhenRepositoryFails_ThenKeepsPreviousScores(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockCountryRiskReader(ctrl)
+	cache := NewCountryRiskCache(repo)
+
+	gomock.InOrder(
+		repo.EXPECT().ListCountryRisks(gomock.Any()).Return([]models.CountryRisk{{CountryCode: "IR", Score: 90}}, nil),
+		repo.EXPECT().ListCountryRisks(gomock.Any()).Return(nil, errors.New("db down")),
+	)
+	require.NoError(t, cache.Load(context.Background()))
+	require.Error(t, cache.Load(context.Background()))
+
+	score, err := cache.CountryRiskScore(context.Background(), "IR")
+	require.NoError(t, err)
+	assert.Equal(t, 90, score)
+}
+
+func Test_CountryRiskCache_CountryRiskScore_WhenNil_ThenReturnsZero(t *testing.T) {
+	var cache *CountryRiskCache
+
+	score, err := cache.CountryRiskScore(context.Background(), "KP")
+
+	require.NoError(t, err)
+	assert.Zero(t, score)
+}
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/geo/country_risk_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/geo/country_risk_repository.go -destination=src/workers/internal/geo/mock_country_risk_reader_test.go -package=geo -exclude_interfaces=CountryRiskRepository
+//
+
+// Package geo is a generated GoMock package.
+package geo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCountryRiskReader is a mock of CountryRiskReader interface.
+type MockCountryRiskReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockCountryRiskReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockCountryRiskReaderMockRecorder is the mock recorder for MockCountryRiskReader.
+type MockCountryRiskReaderMockRecorder struct {
+	mock *MockCountryRiskReader
+}
+
+// NewMockCountryRiskReader creates a new mock instance.
+func NewMockCountryRiskReader(ctrl *gomock.Controller) *MockCountryRiskReader {
+	mock := &MockCountryRiskReader{ctrl: ctrl}
+	mock.recorder = &MockCountryRiskReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCountryRiskReader) EXPECT() *MockCountryRiskReaderMockRecorder {
+	return m.recorder
+}
+
+// ListCountryRisks mocks base method.
+func (m *MockCountryRiskReader) ListCountryRisks(ctx context.Context) ([]models.CountryRisk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCountryRisks", ctx)
+	ret0, _ := ret[0].([]models.CountryRisk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCountryRisks indicates an expected call of ListCountryRisks.
+func (mr *MockCountryRiskReaderMockRecorder) ListCountryRisks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCountryRisks", reflect.TypeOf((*MockCountryRiskReader)(nil).ListCountryRisks), ctx)
+}
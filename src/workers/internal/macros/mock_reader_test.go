@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/macros/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/macros/repository.go -destination=src/workers/internal/macros/mock_reader_test.go -package=macros -exclude_interfaces=Repository
+//
+
+// Package macros is a generated GoMock package.
+package macros
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReader is a mock of Reader interface.
+type MockReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockReaderMockRecorder is the mock recorder for MockReader.
+type MockReaderMockRecorder struct {
+	mock *MockReader
+}
+
+// NewMockReader creates a new mock instance.
+func NewMockReader(ctrl *gomock.Controller) *MockReader {
+	mock := &MockReader{ctrl: ctrl}
+	mock.recorder = &MockReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReader) EXPECT() *MockReaderMockRecorder {
+	return m.recorder
+}
+
+// ListMacros mocks base method.
+func (m *MockReader) ListMacros(ctx context.Context) ([]models.Macro, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMacros", ctx)
+	ret0, _ := ret[0].([]models.Macro)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMacros indicates an expected call of ListMacros.
+func (mr *MockReaderMockRecorder) ListMacros(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMacros", reflect.TypeOf((*MockReader)(nil).ListMacros), ctx)
+}
@@ -0,0 +1,127 @@
+// Package macros caches expression macros in the worker so rules are compiled with them.
+package macros
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/algo-shield/algo-shield/src/pkg/macros"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// MacroService keeps the validated macro set used to compile rules
+// The whole set is reloaded on every change since macros are validated together
+// Uses sync.RWMutex for thread-safe reads and writes. A nil service has no macros.
+type MacroService struct {
+	repo  macros.Reader // Only needs read access, not full Repository
+	redis *redis.Client
+
+	mu     sync.RWMutex
+	macros *expressions.Macros
+
+	onInvalidate []func()
+}
+
+// NewMacroService creates a new macro cache with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewMacroService(repo macros.Reader, redisClient *redis.Client) *MacroService {
+	return &MacroService{
+		repo:  repo,
+		redis: redisClient,
+	}
+}
+
+// Load loads and validates every macro
+// An invalid set, such as a cycle introduced by concurrent updates, is logged and the
+// previous set is kept so rules that compiled keep matching
+func (s *MacroService) Load(ctx context.Context) error {
+	all, err := s.repo.ListMacros(ctx)
+	if err != nil {
+		return err
+	}
+
+	set, err := expressions.NewMacros(all)
+	if err != nil {
+		log.Printf("Ignoring invalid macros, keeping the previous set: %v", err)
+		return nil
+	}
+
+	s.mu.Lock()
+	s.macros = set
+	s.mu.Unlock()
+
+	log.Printf("Loaded %d macros", set.Len())
+	return nil
+}
+
+// Macros returns the current macro set
+func (s *MacroService) Macros() *expressions.Macros {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.macros
+}
+
+// OnInvalidate registers a callback invoked after the macros have been reloaded
+// Callbacks run outside the cache lock, so they may safely call Macros
+// Must be called before SubscribeToInvalidations starts
+func (s *MacroService) OnInvalidate(fn func()) {
+	s.onInvalidate = append(s.onInvalidate, fn)
+}
+
+// Invalidate reloads the macros and notifies the registered callbacks
+// Failing to reload keeps the previous macros and skips the callbacks
+func (s *MacroService) Invalidate(ctx context.Context) {
+	if err := s.Load(ctx); err != nil {
+		log.Printf("Failed to reload macros: %v", err)
+		return
+	}
+
+	for _, fn := range s.onInvalidate {
+		fn()
+	}
+}
+
+// SubscribeToInvalidations subscribes to Redis pub/sub for macro change events
+// This should be called in a goroutine
+func (s *MacroService) SubscribeToInvalidations(ctx context.Context) {
+	if s.redis == nil {
+		log.Println("Redis not available, macro invalidation subscription disabled")
+		return
+	}
+
+	pubsub := s.redis.Subscribe(ctx, models.MacroInvalidateChannel)
+	defer func() {
+		if err := pubsub.Close(); err != nil {
+			log.Printf("Error closing macro invalidation subscription: %v", err)
+		}
+	}()
+
+	log.Println("Subscribed to macro invalidation channel")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Macro invalidation subscription stopped")
+			return
+		default:
+			msg, err := pubsub.ReceiveMessage(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return // Context cancelled
+				}
+				log.Printf("Error receiving macro invalidation message: %v", err)
+				continue
+			}
+
+			log.Printf("Macro %s changed, reloading macros", msg.Payload)
+			s.Invalidate(ctx)
+		}
+	}
+}
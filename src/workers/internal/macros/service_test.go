@@ -0,0 +1,77 @@
+package macros
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_MacroService_Load_WhenValid_ThenExposesMacros(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockReader(ctrl)
+	repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{{Name: "highAmount", Expression: "amount > 100"}}, nil)
+
+	service := NewMacroService(repo, nil)
+	require.NoError(t, service.Load(context.Background()))
+
+	assert.Equal(t, 1, service.Macros().Len())
+	assert.Equal(t, []string{"highAmount"}, service.Macros().References("highAmount or false"))
+}
+
+func Test_MacroService_Load_WhenCycle_ThenKeepsPreviousMacros(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockReader(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{{Name: "a", Expression: "true"}}, nil),
+		repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{
+			{Name: "a", Expression: "b"},
+			{Name: "b", Expression: "a"},
+		}, nil),
+	)
+
+	service := NewMacroService(repo, nil)
+	require.NoError(t, service.Load(context.Background()))
+	previous := service.Macros()
+
+	require.NoError(t, service.Load(context.Background()))
+	assert.Same(t, previous, service.Macros())
+}
+
+func Test_MacroService_Invalidate_WhenReloaded_ThenNotifiesCallbacks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockReader(ctrl)
+	repo.EXPECT().ListMacros(gomock.Any()).Return([]models.Macro{}, nil)
+
+	service := NewMacroService(repo, nil)
+	calls := 0
+	service.OnInvalidate(func() { calls++ })
+
+	service.Invalidate(context.Background())
+	assert.Equal(t, 1, calls)
+}
+
+func Test_MacroService_Invalidate_WhenReloadFails_ThenSkipsCallbacks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockReader(ctrl)
+	repo.EXPECT().ListMacros(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	service := NewMacroService(repo, nil)
+	calls := 0
+	service.OnInvalidate(func() { calls++ })
+
+	service.Invalidate(context.Background())
+	assert.Equal(t, 0, calls)
+}
@@ -5,7 +5,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
@@ -87,6 +89,34 @@ func (mc *MetricsCollector) RecordProcessing(duration time.Duration, success boo
 	mc.lastProcessedTime.Store(time.Now().UnixNano())
 }
 
+// LaneDepthFunc returns the number of events waiting in each queue lane
+type LaneDepthFunc func(ctx context.Context) (map[models.QueueLane]int64, error)
+
+// ObserveLaneDepths reports the depth of each queue lane as the processor_queue_depth gauge
+// Depths are only read when metrics are collected
+func (mc *MetricsCollector) ObserveLaneDepths(depths LaneDepthFunc) error {
+	_, err := meter.Int64ObservableGauge(
+		"processor_queue_depth",
+		metric.WithDescription("Number of events waiting in each queue lane"),
+		metric.WithInt64Callback(laneDepthCallback(depths)),
+	)
+	return err
+}
+
+// laneDepthCallback observes one value per lane, labelled with the lane name
+func laneDepthCallback(depths LaneDepthFunc) metric.Int64Callback {
+	return func(ctx context.Context, observer metric.Int64Observer) error {
+		laneDepths, err := depths(ctx)
+		if err != nil {
+			return err
+		}
+		for lane, depth := range laneDepths {
+			observer.Observe(depth, metric.WithAttributes(attribute.String("lane", string(lane))))
+		}
+		return nil
+	}
+}
+
 // GetMetrics returns current metrics snapshot
 // Uses atomic operations for thread-safe reads
 func (mc *MetricsCollector) GetMetrics() Metrics {
@@ -6,8 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
 )
 
 func Test_NewMetricsCollector_CreatesCollector(t *testing.T) {
@@ -175,3 +178,39 @@ func Test_MetricsCollector_GetMetrics_CalculatesTotalDuration(t *testing.T) {
 
 	assert.Equal(t, 300*time.Millisecond, metrics.TotalDuration)
 }
+
+// recordingObserver keeps the values observed by a gauge callback, by lane
+type recordingObserver struct {
+	embedded.Int64Observer
+	values map[string]int64
+}
+
+func (o *recordingObserver) Observe(value int64, options ...metric.ObserveOption) {
+	attributes := metric.NewObserveConfig(options).Attributes()
+	lane, _ := attributes.Value("lane")
+	o.values[lane.AsString()] = value
+}
+
+func Test_laneDepthCallback_WhenDepthsRead_ThenObservesEachLane(t *testing.T) {
+	observer := &recordingObserver{values: make(map[string]int64)}
+	callback := laneDepthCallback(func(ctx context.Context) (map[models.QueueLane]int64, error) {
+		return map[models.QueueLane]int64{models.LaneRealtime: 3, models.LaneBackfill: 1200}, nil
+	})
+
+	err := callback(context.Background(), observer)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"realtime": 3, "backfill": 1200}, observer.values)
+}
+
+func Test_laneDepthCallback_WhenDepthsUnavailable_ThenReturnsError(t *testing.T) {
+	observer := &recordingObserver{values: make(map[string]int64)}
+	callback := laneDepthCallback(func(ctx context.Context) (map[models.QueueLane]int64, error) {
+		return nil, errors.New("redis down")
+	})
+
+	err := callback(context.Background(), observer)
+
+	assert.Error(t, err)
+	assert.Empty(t, observer.values)
+}
@@ -2,10 +2,14 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 
+	"github.com/algo-shield/algo-shield/src/pkg/backtests"
+	"github.com/algo-shield/algo-shield/src/pkg/deadletters"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/algo-shield/algo-shield/src/workers/internal/backtest"
 	"github.com/algo-shield/algo-shield/src/workers/internal/queue"
 	engine "github.com/algo-shield/algo-shield/src/workers/internal/rules"
 	"github.com/algo-shield/algo-shield/src/workers/internal/transactions"
@@ -15,10 +19,21 @@ import (
 	"golang.org/x/sync/semaphore"
 )
 
+// settleTimeout bounds acknowledging or requeueing an event, including after shutdown
+const settleTimeout = 5 * time.Second
+
+// TransactionProcessor defines the interface for processing a queued transaction event
+type TransactionProcessor interface {
+	ProcessTransaction(ctx context.Context, event models.Event) error
+	// DiscardTransaction undoes what processing recorded for an event that was dead-lettered
+	DiscardTransaction(ctx context.Context, event models.Event)
+}
+
 type Processor struct {
-	transactionService  *transactions.Service
-	queueService        *queue.QueueService
+	transactionService  TransactionProcessor
+	consumer            queue.Consumer
 	ruleEngine          *engine.Engine
+	backtestRunner      *backtest.Runner
 	metricsCollector    *MetricsCollector
 	retryConfig         RetryConfig
 	concurrency         int
@@ -27,23 +42,54 @@ type Processor struct {
 	rulesReloadInterval time.Duration
 }
 
-func NewProcessor(db *pgxpool.Pool, redis *redis.Client, concurrency, batchSize int, transactionTimeout, ruleEvaluationTimeout, queuePopTimeout, rulesReloadInterval time.Duration, retryConfig RetryConfig) *Processor {
-	// Create single instance of rule engine with timeout
-	ruleEngine := engine.NewEngine(db, redis, ruleEvaluationTimeout)
+// NewProcessor creates a processor consuming transaction events from consumer
+// A nil consumer consumes the Redis lists, which also carry the backtest jobs
+func NewProcessor(db *pgxpool.Pool, redis *redis.Client, consumer queue.Consumer, concurrency, batchSize int, transactionTimeout, rulesReloadInterval time.Duration, queueConfig queue.Config, retryConfig RetryConfig, engineConfig engine.EngineConfig) *Processor {
+	// Create single instance of rule engine with timeout and decision settings
+	ruleEngine := engine.NewEngine(db, redis, engineConfig)
 
 	// Create transaction repository and service with dependency injection
-	transactionService := transactions.NewService(transactions.NewPostgresRepository(db), ruleEngine)
+	// Decisions for synchronous requests are published back to the API over Redis
+	// Evaluated transactions feed the velocity history used by later evaluations
+	// Events join their entity's timeline before evaluation, so sequences can end with them
+	transactionService := transactions.NewService(
+		transactions.NewPostgresRepository(db),
+		ruleEngine,
+		queue.NewDecisionPublisher(redis),
+		ruleEngine.HistoryRecorder(),
+		ruleEngine.TimelineRecorder(),
+	)
+
+	// Events stay in flight until processed, so none are lost on a crash
+	// Events that keep failing are moved to the dead letters
+	// Backtests replay stored transactions through the same rule engine
+	queueService := queue.NewQueueService(redis, deadletters.NewPostgresRepository(db), queueConfig)
+	if consumer == nil {
+		consumer = queueService
+	}
+	backtestRunner := backtest.NewRunner(
+		backtests.NewPostgresRepository(db),
+		backtest.NewPostgresTransactionReader(db),
+		ruleEngine,
+		queueService,
+	)
 
 	// Default batch size to 50 if not provided
 	if batchSize <= 0 {
 		batchSize = 50
 	}
 
+	metricsCollector := NewMetricsCollector()
+	if err := metricsCollector.ObserveLaneDepths(consumer.LaneDepths); err != nil {
+		log.Printf("Failed to register queue depth metrics: %v", err)
+	}
+
 	return &Processor{
 		transactionService:  transactionService,
-		queueService:        queue.NewQueueService(redis, queuePopTimeout),
+		consumer:            consumer,
 		ruleEngine:          ruleEngine,
-		metricsCollector:    NewMetricsCollector(),
+		backtestRunner:      backtestRunner,
+		metricsCollector:    metricsCollector,
 		retryConfig:         retryConfig,
 		concurrency:         concurrency,
 		batchSize:           batchSize,
@@ -60,21 +106,72 @@ func (p *Processor) Start(ctx context.Context) error {
 		return err
 	}
 
+	// Load managed lists before evaluating, so blocklists never start empty
+	if err := p.ruleEngine.LoadLists(ctx); err != nil {
+		return err
+	}
+
+	// Load sanctions entries so sanctionsHit screens against the latest import from the start
+	if err := p.ruleEngine.LoadSanctions(ctx); err != nil {
+		return err
+	}
+
+	// Register before popping so other workers recover this worker's events if it crashes
+	if err := p.consumer.Register(ctx); err != nil {
+		return err
+	}
+
 	// Create errgroup for managing all goroutines with proper error handling
 	g, gCtx := errgroup.WithContext(ctx)
 
+	// Keep the queue heartbeat alive and recover events of stopped workers
+	g.Go(func() error {
+		p.consumer.Start(gCtx)
+		return nil // Heartbeat stops on context cancellation
+	})
+
 	// Start schema invalidation subscription (managed by errgroup)
 	g.Go(func() error {
 		p.ruleEngine.StartSchemaInvalidationSubscription(gCtx)
 		return nil // Subscription runs until context cancellation
 	})
 
+	// Start macro invalidation subscription (managed by errgroup)
+	g.Go(func() error {
+		p.ruleEngine.StartMacroInvalidationSubscription(gCtx)
+		return nil // Subscription runs until context cancellation
+	})
+
+	// Start list invalidation subscription (managed by errgroup)
+	g.Go(func() error {
+		p.ruleEngine.StartListInvalidationSubscription(gCtx)
+		return nil // Subscription runs until context cancellation
+	})
+
+	// Start sanctions invalidation subscription (managed by errgroup)
+	g.Go(func() error {
+		p.ruleEngine.StartSanctionsInvalidationSubscription(gCtx)
+		return nil // Subscription runs until context cancellation
+	})
+
 	// Reload rules periodically for hot-reload
 	g.Go(func() error {
 		p.reloadRulesPeriodically(gCtx)
 		return nil // Periodic reload doesn't return errors that should stop the processor
 	})
 
+	// Persist per-rule health counters periodically
+	g.Go(func() error {
+		p.ruleEngine.StartHealthFlush(gCtx)
+		return nil // Flushing stops on context cancellation
+	})
+
+	// Run queued backtests one at a time alongside live traffic
+	g.Go(func() error {
+		p.backtestRunner.Start(gCtx)
+		return nil // Runner stops on context cancellation
+	})
+
 	// Start worker goroutines using errgroup
 	for i := 0; i < p.concurrency; i++ {
 		workerID := i // Capture loop variable
@@ -88,7 +185,16 @@ func (p *Processor) Start(ctx context.Context) error {
 	log.Println("Processor started, waiting for shutdown signal...")
 
 	// Wait for all goroutines to finish (they'll stop when context is cancelled)
-	if err := g.Wait(); err != nil {
+	err := g.Wait()
+
+	// Hand back events still in flight now that no worker can acknowledge them
+	releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), settleTimeout)
+	defer cancel()
+	if releaseErr := p.consumer.Release(releaseCtx); releaseErr != nil {
+		log.Printf("Failed to release in-flight events: %v", releaseErr)
+	}
+
+	if err != nil {
 		log.Printf("Processor stopped with error: %v", err)
 		return err
 	}
@@ -147,7 +253,7 @@ func (p *Processor) worker(ctx context.Context, id int) {
 
 func (p *Processor) processNextTransaction(ctx context.Context) {
 	// Pop transaction from queue
-	event, err := p.queueService.PopTransaction(ctx)
+	delivery, err := p.consumer.PopTransaction(ctx)
 	if err != nil {
 		// Check if it's a timeout (expected) vs actual error
 		if err == queue.ErrTimeout {
@@ -159,34 +265,34 @@ func (p *Processor) processNextTransaction(ctx context.Context) {
 		return
 	}
 
-	if event == nil {
+	if delivery == nil {
 		return
 	}
+	event := delivery.Event
+	transactions.AssignEventID(event)
 
 	// Process with metrics and retry
+	attempts := 0
 	duration, err := MeasureExecution(ctx, func() error {
 		return Retry(ctx, p.retryConfig, func() error {
+			attempts++
+
 			// Add timeout to context
 			processCtx, cancel := context.WithTimeout(ctx, p.transactionTimeout)
 			defer cancel()
 
-			return p.transactionService.ProcessTransaction(processCtx, *event)
+			return p.processEvent(processCtx, event)
 		})
 	})
 
 	success := err == nil
 	p.metricsCollector.RecordProcessing(duration, success)
+	p.settle(ctx, delivery, err, attempts)
 
-	// Extract external_id for logging
-	externalID := "unknown"
-	if id, ok := (*event)["external_id"].(string); ok {
-		externalID = id
-	} else if id, ok := (*event)["id"].(string); ok {
-		externalID = id
-	}
+	externalID := eventID(event)
 
 	if err != nil {
-		log.Printf("Failed to process transaction %s after retries: %v (duration: %v)", externalID, err, duration)
+		log.Printf("Failed to process transaction %s after %d attempts: %v (duration: %v)", externalID, attempts, err, duration)
 	} else {
 		log.Printf("Processed transaction %s successfully (duration: %v)", externalID, duration)
 	}
@@ -195,11 +301,11 @@ func (p *Processor) processNextTransaction(ctx context.Context) {
 // processBatch processes multiple transactions in a batch using parallel processing
 // Uses worker pool pattern with controlled concurrency to avoid overwhelming the system
 func (p *Processor) processBatch(ctx context.Context) {
-	events := make([]*models.Event, 0, p.batchSize)
+	events := make([]*queue.Delivery, 0, p.batchSize)
 
 	// Collect batch
 	for i := 0; i < p.batchSize; i++ {
-		event, err := p.queueService.PopTransaction(ctx)
+		delivery, err := p.consumer.PopTransaction(ctx)
 		if err != nil {
 			if err == queue.ErrTimeout {
 				break // No more items available
@@ -207,8 +313,9 @@ func (p *Processor) processBatch(ctx context.Context) {
 			log.Printf("Queue error while collecting batch: %v", err)
 			continue
 		}
-		if event != nil {
-			events = append(events, event)
+		if delivery != nil {
+			transactions.AssignEventID(delivery.Event)
+			events = append(events, delivery)
 		}
 	}
 
@@ -255,7 +362,8 @@ type BatchResult struct {
 
 // processBatchParallel processes events in parallel with controlled concurrency
 // Uses golang.org/x/sync/semaphore for robust concurrency control and errgroup for error handling
-func (p *Processor) processBatchParallel(ctx context.Context, events []*models.Event) []BatchResult {
+// Each event is acknowledged once processed and dead-lettered when it keeps failing
+func (p *Processor) processBatchParallel(ctx context.Context, events []*queue.Delivery) []BatchResult {
 	// Use concurrency limit to prevent overwhelming the system
 	// Use the processor's concurrency setting, but cap at batch size
 	concurrencyLimit := int64(p.concurrency)
@@ -277,22 +385,15 @@ func (p *Processor) processBatchParallel(ctx context.Context, events []*models.E
 
 	// Process each event in parallel with controlled concurrency
 	for _, event := range events {
-		evt := event // Capture loop variable
+		delivery := event // Capture loop variable
 
 		g.Go(func() error {
 			// Acquire semaphore (blocks if limit reached, respects context cancellation)
 			if err := sem.Acquire(gCtx, 1); err != nil {
 				// Context cancelled or semaphore acquisition failed
-				// Extract external_id for logging
-				externalID := "unknown"
-				if id, ok := (*evt)["external_id"].(string); ok {
-					externalID = id
-				} else if id, ok := (*evt)["id"].(string); ok {
-					externalID = id
-				}
-
+				p.settle(ctx, delivery, err, 0)
 				results <- BatchResult{
-					ExternalID: externalID,
+					ExternalID: eventID(delivery.Event),
 					Success:    false,
 					Error:      err,
 					Duration:   0,
@@ -305,22 +406,17 @@ func (p *Processor) processBatchParallel(ctx context.Context, events []*models.E
 			processCtx, cancel := context.WithTimeout(gCtx, p.transactionTimeout)
 			defer cancel()
 
+			attempts := 0
 			duration, err := MeasureExecution(processCtx, func() error {
 				return Retry(processCtx, p.retryConfig, func() error {
-					return p.transactionService.ProcessTransaction(processCtx, *evt)
+					attempts++
+					return p.processEvent(processCtx, delivery.Event)
 				})
 			})
-
-			// Extract external_id for logging
-			externalID := "unknown"
-			if id, ok := (*evt)["external_id"].(string); ok {
-				externalID = id
-			} else if id, ok := (*evt)["id"].(string); ok {
-				externalID = id
-			}
+			p.settle(ctx, delivery, err, attempts)
 
 			results <- BatchResult{
-				ExternalID: externalID,
+				ExternalID: eventID(delivery.Event),
 				Success:    err == nil,
 				Error:      err,
 				Duration:   duration,
@@ -344,3 +440,52 @@ func (p *Processor) processBatchParallel(ctx context.Context, events []*models.E
 
 	return batchResults
 }
+
+// processEvent processes an event once, marking failures that retrying cannot fix as permanent
+// so they are dead-lettered without waiting for the remaining attempts
+func (p *Processor) processEvent(ctx context.Context, event models.Event) error {
+	err := p.transactionService.ProcessTransaction(ctx, event)
+	if errors.Is(err, transactions.ErrRejectedTransaction) {
+		return Permanent(err)
+	}
+	return err
+}
+
+// settle acknowledges a processed event or dead-letters one that failed every attempt,
+// discarding what its attempts recorded
+// Events interrupted by shutdown go back to their queue instead, they did nothing wrong
+// It outlives shutdown so an event saved just before stopping is not processed again
+func (p *Processor) settle(ctx context.Context, delivery *queue.Delivery, processErr error, attempts int) {
+	settleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), settleTimeout)
+	defer cancel()
+
+	var err error
+	switch {
+	case processErr == nil:
+		err = p.consumer.Ack(settleCtx, delivery)
+	case ctx.Err() != nil:
+		err = p.consumer.Requeue(settleCtx, delivery)
+	default:
+		err = p.consumer.DeadLetter(settleCtx, delivery, models.DeadLetterProcessingFailed, processErr, attempts)
+		if err == nil {
+			// Dead letters are only processed again when replayed, which records them anew
+			p.transactionService.DiscardTransaction(settleCtx, delivery.Event)
+		}
+	}
+
+	// Whatever is left in flight is requeued on shutdown or by another worker
+	if err != nil {
+		log.Printf("Failed to settle transaction %s: %v", eventID(delivery.Event), err)
+	}
+}
+
+// eventID extracts the external_id, or id, of an event for logging
+func eventID(event models.Event) string {
+	if id, ok := event["external_id"].(string); ok {
+		return id
+	}
+	if id, ok := event["id"].(string); ok {
+		return id
+	}
+	return "unknown"
+}
@@ -0,0 +1,228 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/algo-shield/algo-shield/src/workers/internal/queue"
+	"github.com/algo-shield/algo-shield/src/workers/internal/transactions"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTransactionService fails the events whose external_id has an error set, or every event
+// with an error set for anyEvent, counting attempts
+type fakeTransactionService struct {
+	mu        sync.Mutex
+	errs      map[string]error
+	attempts  map[string]int
+	discarded []string
+}
+
+func (f *fakeTransactionService) ProcessTransaction(ctx context.Context, event models.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := eventID(event)
+	f.attempts[id]++
+	if err, ok := f.errs[id]; ok {
+		return err
+	}
+	return f.errs[anyEvent]
+}
+
+// anyEvent keys the error fakeTransactionService returns for events without their own
+const anyEvent = "*"
+
+func (f *fakeTransactionService) DiscardTransaction(ctx context.Context, event models.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.discarded = append(f.discarded, eventID(event))
+}
+
+// fakeConsumer hands out published events in order and records how each delivery was settled
+type fakeConsumer struct {
+	mu          sync.Mutex
+	pending     []string
+	inFlight    map[*queue.Delivery]bool
+	deadLetters []models.DeadLetter
+}
+
+func newFakeConsumer() *fakeConsumer {
+	return &fakeConsumer{inFlight: make(map[*queue.Delivery]bool)}
+}
+
+func (f *fakeConsumer) Publish(payload string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending = append(f.pending, payload)
+}
+
+func (f *fakeConsumer) InFlight() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.inFlight)
+}
+
+func (f *fakeConsumer) DeadLetters() []models.DeadLetter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]models.DeadLetter(nil), f.deadLetters...)
+}
+
+func (f *fakeConsumer) Register(ctx context.Context) error { return nil }
+
+func (f *fakeConsumer) Start(ctx context.Context) {}
+
+func (f *fakeConsumer) Release(ctx context.Context) error { return nil }
+
+func (f *fakeConsumer) PopTransaction(ctx context.Context) (*queue.Delivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.pending) == 0 {
+		return nil, queue.ErrTimeout
+	}
+	delivery := &queue.Delivery{Payload: f.pending[0], ReceivedAt: time.Now()}
+	f.pending = f.pending[1:]
+	if err := json.Unmarshal([]byte(delivery.Payload), &delivery.Event); err != nil {
+		return nil, queue.ErrInvalidData
+	}
+	f.inFlight[delivery] = true
+	return delivery, nil
+}
+
+func (f *fakeConsumer) Ack(ctx context.Context, delivery *queue.Delivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.inFlight, delivery)
+	return nil
+}
+
+func (f *fakeConsumer) Requeue(ctx context.Context, delivery *queue.Delivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending = append(f.pending, delivery.Payload)
+	delete(f.inFlight, delivery)
+	return nil
+}
+
+func (f *fakeConsumer) DeadLetter(ctx context.Context, delivery *queue.Delivery, reason models.DeadLetterReason, cause error, attempts int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deadLetters = append(f.deadLetters, models.DeadLetter{
+		ExternalID: eventID(delivery.Event),
+		Payload:    delivery.Payload,
+		Reason:     reason,
+		Error:      cause.Error(),
+		Attempts:   attempts,
+	})
+	delete(f.inFlight, delivery)
+	return nil
+}
+
+func (f *fakeConsumer) LaneDepths(ctx context.Context) (map[models.QueueLane]int64, error) {
+	return map[models.QueueLane]int64{}, nil
+}
+
+// newTestProcessor wires a processor to a fake consumer, whatever the production transport
+func newTestProcessor(batchSize int, errs map[string]error) (*Processor, *fakeConsumer, *fakeTransactionService) {
+	consumer := newFakeConsumer()
+	service := &fakeTransactionService{errs: errs, attempts: make(map[string]int)}
+	return &Processor{
+		transactionService: service,
+		consumer:           consumer,
+		metricsCollector:   NewMetricsCollector(),
+		retryConfig:        RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1},
+		concurrency:        2,
+		batchSize:          batchSize,
+		transactionTimeout: time.Second,
+	}, consumer, service
+}
+
+func Test_Processor_ProcessBatch_WhenEventsSucceed_ThenAcksThemAndRecordsMetrics(t *testing.T) {
+	processor, consumer, service := newTestProcessor(10, nil)
+	consumer.Publish(`{"external_id":"ext-1"}`)
+	consumer.Publish(`{"external_id":"ext-2"}`)
+	consumer.Publish(`{"external_id":"ext-3"}`)
+
+	processor.processBatch(context.Background())
+
+	assert.Equal(t, 0, consumer.InFlight())
+	assert.Empty(t, consumer.DeadLetters())
+	assert.Equal(t, map[string]int{"ext-1": 1, "ext-2": 1, "ext-3": 1}, service.attempts)
+	assert.Empty(t, service.discarded)
+	metrics := processor.GetMetrics()
+	assert.Equal(t, int64(3), metrics.TotalProcessed)
+	assert.Equal(t, int64(0), metrics.TotalFailed)
+}
+
+func Test_Processor_ProcessBatch_WhenEventKeepsFailing_ThenDeadLettersItAfterRetries(t *testing.T) {
+	processor, consumer, service := newTestProcessor(10, map[string]error{"ext-1": errors.New("database unavailable")})
+	consumer.Publish(`{"external_id":"ext-1"}`)
+	consumer.Publish(`{"external_id":"ext-2"}`)
+
+	processor.processBatch(context.Background())
+
+	require.Len(t, consumer.DeadLetters(), 1)
+	letter := consumer.DeadLetters()[0]
+	assert.Equal(t, "ext-1", letter.ExternalID)
+	assert.Equal(t, models.DeadLetterProcessingFailed, letter.Reason)
+	assert.Equal(t, 3, letter.Attempts)
+	assert.Equal(t, 3, service.attempts["ext-1"])
+	assert.Equal(t, []string{"ext-1"}, service.discarded)
+	assert.Equal(t, 0, consumer.InFlight())
+	assert.Equal(t, int64(1), processor.GetMetrics().TotalFailed)
+}
+
+func Test_Processor_ProcessNextTransaction_WhenRejected_ThenDeadLettersItWithoutRetrying(t *testing.T) {
+	processor, consumer, service := newTestProcessor(1, map[string]error{"ext-1": transactions.ErrRejectedTransaction})
+	consumer.Publish(`{"external_id":"ext-1"}`)
+
+	processor.processNextTransaction(context.Background())
+
+	require.Len(t, consumer.DeadLetters(), 1)
+	assert.Equal(t, 1, consumer.DeadLetters()[0].Attempts)
+	assert.Equal(t, 1, service.attempts["ext-1"])
+	assert.Equal(t, []string{"ext-1"}, service.discarded)
+}
+
+func Test_Processor_ProcessNextTransaction_WhenEventHasNoID_ThenRetriesItUnderOneAssignedID(t *testing.T) {
+	processor, consumer, service := newTestProcessor(1, map[string]error{anyEvent: errors.New("database unavailable")})
+	consumer.Publish(`{"amount":100}`)
+
+	processor.processNextTransaction(context.Background())
+
+	require.Len(t, service.attempts, 1)
+	for id, attempts := range service.attempts {
+		assert.NoError(t, uuid.Validate(id))
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, []string{id}, service.discarded)
+	}
+}
+
+func Test_Processor_ProcessBatch_WhenEventsHaveNoID_ThenAssignsEachItsOwn(t *testing.T) {
+	processor, consumer, service := newTestProcessor(10, nil)
+	consumer.Publish(`{"amount":100}`)
+	consumer.Publish(`{"amount":200}`)
+
+	processor.processBatch(context.Background())
+
+	assert.Len(t, service.attempts, 2)
+	assert.NotContains(t, service.attempts, "unknown")
+}
+
+func Test_Processor_ProcessNextTransaction_WhenQueueEmpty_ThenRecordsNothing(t *testing.T) {
+	processor, _, _ := newTestProcessor(1, nil)
+
+	processor.processNextTransaction(context.Background())
+
+	assert.Equal(t, int64(0), processor.GetMetrics().TotalProcessed)
+}
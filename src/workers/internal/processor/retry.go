@@ -17,6 +17,27 @@ func (e *RetryableError) Error() string {
 	return e.Err.Error()
 }
 
+// PermanentError indicates an error that retrying cannot fix, such as data the database rejects
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent marks an error as not worth retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
 // RetryConfig configures retry behavior
 type RetryConfig struct {
 	MaxAttempts  int
@@ -36,6 +57,7 @@ func DefaultRetryConfig() RetryConfig {
 }
 
 // Retry executes a function with exponential backoff retry logic
+// Permanent errors are returned at once without further attempts
 func Retry(ctx context.Context, config RetryConfig, fn func() error) error {
 	var lastErr error
 	delay := config.InitialDelay
@@ -55,6 +77,11 @@ func Retry(ctx context.Context, config RetryConfig, fn func() error) error {
 
 		lastErr = err
 
+		var permanentErr *PermanentError
+		if errors.As(err, &permanentErr) {
+			return err
+		}
+
 		// Check if error is retryable
 		var retryableErr *RetryableError
 		if errors.As(err, &retryableErr) {
@@ -3,6 +3,7 @@ package processor
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -178,6 +179,41 @@ func Test_RetryableError_Error_ReturnsWrappedError(t *testing.T) {
 	assert.Equal(t, "inner error", retryErr.Error())
 }
 
+func Test_Retry_WhenErrorIsPermanent_ThenStopsRetrying(t *testing.T) {
+	ctx := context.Background()
+	config := RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+		Multiplier:   2.0,
+	}
+
+	innerErr := errors.New("rejected")
+	callCount := 0
+	fn := func() error {
+		callCount++
+		return fmt.Errorf("saving: %w", Permanent(innerErr))
+	}
+
+	err := Retry(ctx, config, fn)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, innerErr)
+	assert.Equal(t, 1, callCount)
+}
+
+func Test_Permanent_WhenErrorIsNil_ThenReturnsNil(t *testing.T) {
+	assert.NoError(t, Permanent(nil))
+}
+
+func Test_PermanentError_Error_ReturnsWrappedError(t *testing.T) {
+	innerErr := errors.New("inner error")
+	err := Permanent(innerErr)
+
+	assert.Equal(t, "inner error", err.Error())
+	assert.ErrorIs(t, err, innerErr)
+}
+
 func Test_Retry_ExponentialBackoff_ThenDelaysIncrease(t *testing.T) {
 	ctx := context.Background()
 	config := RetryConfig{
@@ -0,0 +1,106 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/deadletters"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	queuepkg "github.com/algo-shield/algo-shield/src/pkg/queue"
+)
+
+// Transports a worker can consume transaction events from, the ones producers publish to
+const (
+	TransportRedisList    = queuepkg.TransportRedisList
+	TransportRedisStreams = queuepkg.TransportRedisStreams
+	TransportNATS         = queuepkg.TransportNATS
+)
+
+// Consumer delivers queued transaction events to the processor
+// Every transport follows the same lifecycle: a popped event stays in flight until it is
+// acknowledged, requeued or dead-lettered, and events left in flight by a stopped worker
+// are delivered again. Batching, retries and metrics are left to the processor.
+type Consumer interface {
+	// Register prepares the transport; must be called before the first PopTransaction
+	Register(ctx context.Context) error
+	// Start runs background upkeep, such as heartbeats and recovery, until the context is cancelled
+	Start(ctx context.Context)
+	// Release hands back the events still in flight, called on shutdown
+	Release(ctx context.Context) error
+	// PopTransaction returns the next event, taking lanes in the configured order
+	// Returns ErrTimeout if no event is available (expected)
+	// Returns ErrInvalidData for payloads that are not events, which are dead-lettered
+	PopTransaction(ctx context.Context) (*Delivery, error)
+	// Ack settles a processed delivery
+	Ack(ctx context.Context, delivery *Delivery) error
+	// Requeue puts a delivery that could not be processed yet back on its lane
+	Requeue(ctx context.Context, delivery *Delivery) error
+	// DeadLetter stores a delivery the worker gave up on and settles it
+	// When it cannot be stored it is requeued instead, so it is never lost
+	DeadLetter(ctx context.Context, delivery *Delivery, reason models.DeadLetterReason, cause error, attempts int) error
+	// LaneDepths returns the number of events waiting in each lane, in flight ones excluded
+	LaneDepths(ctx context.Context) (map[models.QueueLane]int64, error)
+}
+
+// Delivery is an event popped from a queue and held in flight until acknowledged
+// The raw payload is kept so the exact queued entry can be acknowledged, requeued or dead-lettered
+type Delivery struct {
+	Event      models.Event
+	Payload    string
+	ReceivedAt time.Time
+	queue      string // List, stream or subject the event was taken from
+	ref        any    // Transport handle on the queued entry, such as a stream entry ID
+}
+
+// decode unmarshals a popped payload, dead-lettering it through the consumer when it is not an event
+func decode(ctx context.Context, consumer Consumer, delivery *Delivery) (*Delivery, error) {
+	err := json.Unmarshal([]byte(delivery.Payload), &delivery.Event)
+	if err == nil && delivery.Event == nil {
+		err = errors.New("payload is null")
+	}
+	if err != nil {
+		if dlErr := consumer.DeadLetter(ctx, delivery, models.DeadLetterInvalidPayload, err, 0); dlErr != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, dlErr)
+		}
+		return nil, ErrInvalidData
+	}
+
+	return delivery, nil
+}
+
+// deadLetter stores a delivery then acknowledges it, or requeues it when it cannot be stored
+func deadLetter(ctx context.Context, consumer Consumer, writer deadletters.Writer, delivery *Delivery, reason models.DeadLetterReason, cause error, attempts int) error {
+	if err := writer.AddDeadLetter(ctx, newDeadLetter(delivery, reason, cause, attempts)); err != nil {
+		if requeueErr := consumer.Requeue(ctx, delivery); requeueErr != nil {
+			return errors.Join(err, requeueErr)
+		}
+		return err
+	}
+
+	return consumer.Ack(ctx, delivery)
+}
+
+// newDeadLetter records why a delivery was given up on
+func newDeadLetter(delivery *Delivery, reason models.DeadLetterReason, cause error, attempts int) *models.DeadLetter {
+	letter := &models.DeadLetter{
+		Queue:      delivery.queue,
+		Payload:    delivery.Payload,
+		Reason:     reason,
+		Error:      cause.Error(),
+		Attempts:   attempts,
+		ReceivedAt: delivery.ReceivedAt,
+		FailedAt:   time.Now(),
+	}
+	if id, ok := delivery.Event["external_id"].(string); ok {
+		letter.ExternalID = id
+	}
+	if reason == models.DeadLetterInvalidPayload {
+		// Text columns reject NUL bytes and invalid UTF-8, which broken payloads may contain
+		letter.Payload = strings.ReplaceAll(strings.ToValidUTF8(letter.Payload, "\uFFFD"), "\x00", "")
+	}
+	return letter
+}
@@ -0,0 +1,37 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisPublisher defines interface for Redis PUBLISH operation
+type RedisPublisher interface {
+	Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd
+}
+
+// DecisionPublisher publishes synchronous decision replies back to the API
+type DecisionPublisher struct {
+	redis RedisPublisher
+}
+
+// NewDecisionPublisher creates a new decision publisher
+func NewDecisionPublisher(redis RedisPublisher) *DecisionPublisher {
+	return &DecisionPublisher{redis: redis}
+}
+
+// PublishDecision publishes the evaluation result for a correlation ID
+func (p *DecisionPublisher) PublishDecision(ctx context.Context, correlationID string, result *models.TransactionResult) error {
+	payload, err := json.Marshal(models.DecisionReply{
+		CorrelationID: correlationID,
+		Result:        *result,
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.redis.Publish(ctx, models.DecisionReplyChannel, payload).Err()
+}
@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_DecisionPublisher_PublishDecision_WhenSuccess_ThenPublishesReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transactionID := uuid.New()
+	result := &models.TransactionResult{
+		TransactionID: transactionID,
+		Status:        models.StatusRejected,
+		MatchedRules:  []string{"rule-1"},
+	}
+	mockRedis := NewMockRedisPublisher(ctrl)
+	cmd := redis.NewIntCmd(context.Background())
+	cmd.SetVal(1)
+	mockRedis.EXPECT().Publish(gomock.Any(), models.DecisionReplyChannel, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, channel string, message interface{}) *redis.IntCmd {
+			var reply models.DecisionReply
+			require.NoError(t, json.Unmarshal(message.([]byte), &reply))
+			assert.Equal(t, "corr-1", reply.CorrelationID)
+			assert.Equal(t, transactionID, reply.Result.TransactionID)
+			assert.Equal(t, models.StatusRejected, reply.Result.Status)
+			return cmd
+		})
+	publisher := NewDecisionPublisher(mockRedis)
+
+	err := publisher.PublishDecision(context.Background(), "corr-1", result)
+
+	require.NoError(t, err)
+}
+
+func Test_DecisionPublisher_PublishDecision_WhenRedisFails_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisPublisher(ctrl)
+	cmd := redis.NewIntCmd(context.Background())
+	cmd.SetErr(errors.New("connection refused"))
+	mockRedis.EXPECT().Publish(gomock.Any(), models.DecisionReplyChannel, gomock.Any()).Return(cmd)
+	publisher := NewDecisionPublisher(mockRedis)
+
+	err := publisher.PublishDecision(context.Background(), "corr-1", &models.TransactionResult{})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,227 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/deadletters"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// natsConsumerPrefix prefixes the durable consumer of each lane, shared by every worker
+	natsConsumerPrefix = "algo-shield-workers-"
+)
+
+// JetStreamConsumer is the Consumer of events published to a NATS JetStream stream
+// The stream captures one subject per lane, each read by a durable pull consumer that every
+// worker shares. Messages not acknowledged within the consumer timeout, such as those of a
+// crashed worker, are redelivered by JetStream itself.
+type JetStreamConsumer struct {
+	js          jetstream.JetStream
+	deadLetters deadletters.Writer
+	stream      string
+	cfg         Config
+	lanes       *laneScheduler
+
+	mu        sync.Mutex
+	consumers map[string]jetstream.Consumer // Lane consumers by subject, set by Register
+	inFlight  map[*Delivery]bool
+}
+
+// NewJetStreamConsumer creates a consumer of the named stream
+func NewJetStreamConsumer(js jetstream.JetStream, deadLetters deadletters.Writer, stream string, cfg Config) *JetStreamConsumer {
+	return &JetStreamConsumer{
+		js:          js,
+		deadLetters: deadLetters,
+		stream:      stream,
+		cfg:         cfg,
+		lanes:       newLaneScheduler(cfg.StrictLanes, cfg.LaneWeights, models.QueueLane.Subject),
+		consumers:   make(map[string]jetstream.Consumer),
+		inFlight:    make(map[*Delivery]bool),
+	}
+}
+
+// Register creates the stream when it does not exist yet, and the durable consumer of each lane
+// An existing stream is used as it is, so it may be managed outside the worker
+func (j *JetStreamConsumer) Register(ctx context.Context) error {
+	_, err := j.js.Stream(ctx, j.stream)
+	if errors.Is(err, jetstream.ErrStreamNotFound) {
+		subjects := make([]string, len(models.QueueLanes))
+		for i, lane := range models.QueueLanes {
+			subjects[i] = lane.Subject()
+		}
+		_, err = j.js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:      j.stream,
+			Subjects:  subjects,
+			Retention: jetstream.WorkQueuePolicy,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, lane := range models.QueueLanes {
+		consumer, err := j.js.CreateOrUpdateConsumer(ctx, j.stream, jetstream.ConsumerConfig{
+			Durable:       natsConsumerPrefix + string(lane),
+			FilterSubject: lane.Subject(),
+			AckPolicy:     jetstream.AckExplicitPolicy,
+			AckWait:       j.cfg.ConsumerTimeout,
+			MaxDeliver:    -1,
+		})
+		if err != nil {
+			return err
+		}
+		j.consumers[lane.Subject()] = consumer
+	}
+	return nil
+}
+
+// Start does nothing until the context is cancelled: JetStream redelivers unacknowledged messages
+func (j *JetStreamConsumer) Start(ctx context.Context) {
+	<-ctx.Done()
+}
+
+// Release asks JetStream to redeliver the messages still in flight at once
+// Called on shutdown once every worker goroutine has stopped
+func (j *JetStreamConsumer) Release(ctx context.Context) error {
+	j.mu.Lock()
+	inFlight := make([]*Delivery, 0, len(j.inFlight))
+	for delivery := range j.inFlight {
+		inFlight = append(inFlight, delivery)
+	}
+	j.mu.Unlock()
+
+	var errs []error
+	for _, delivery := range inFlight {
+		errs = append(errs, j.Requeue(ctx, delivery))
+	}
+	return errors.Join(errs...)
+}
+
+// PopTransaction fetches the next message for this worker
+// Strict lanes are fetched first, then the weighted lanes share the fetches by weight.
+// When every lane is empty it waits on the first strict lane, or the heaviest weighted one.
+func (j *JetStreamConsumer) PopTransaction(ctx context.Context) (*Delivery, error) {
+	var empty []string
+	for _, subject := range j.lanes.order() {
+		delivery, err := j.fetch(subject, 0)
+		if err != nil {
+			return nil, err
+		}
+		if delivery != nil {
+			j.lanes.served(subject, empty)
+			return decode(ctx, j, delivery)
+		}
+		empty = append(empty, subject)
+	}
+
+	delivery, err := j.fetch(j.lanes.blockingQueue(), j.cfg.PopTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, ErrTimeout
+	}
+
+	return decode(ctx, j, delivery)
+}
+
+// fetch takes one message of a lane, waiting up to wait for it, or not at all when wait is 0
+// Returns nil when no message arrived
+func (j *JetStreamConsumer) fetch(subject string, wait time.Duration) (*Delivery, error) {
+	j.mu.Lock()
+	consumer, ok := j.consumers[subject]
+	j.mu.Unlock()
+	if !ok {
+		return nil, errors.New("jetstream consumer not registered for " + subject)
+	}
+
+	var batch jetstream.MessageBatch
+	var err error
+	if wait > 0 {
+		batch, err = consumer.Fetch(1, jetstream.FetchMaxWait(wait))
+	} else {
+		batch, err = consumer.FetchNoWait(1)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for msg := range batch.Messages() {
+		delivery := &Delivery{Payload: string(msg.Data()), ReceivedAt: time.Now(), queue: msg.Subject(), ref: msg}
+
+		j.mu.Lock()
+		j.inFlight[delivery] = true
+		j.mu.Unlock()
+
+		return delivery, nil
+	}
+
+	if err := batch.Error(); err != nil && !errors.Is(err, jetstream.ErrNoMessages) {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// settle acknowledges or naks a delivery's message and forgets it
+func (j *JetStreamConsumer) settle(delivery *Delivery, settle func(jetstream.Msg) error) error {
+	msg, ok := delivery.ref.(jetstream.Msg)
+	if !ok {
+		return errors.New("delivery is not a jetstream message")
+	}
+	if err := settle(msg); err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	delete(j.inFlight, delivery)
+	j.mu.Unlock()
+	return nil
+}
+
+// Ack acknowledges a message and waits for JetStream to confirm, so it is not redelivered
+func (j *JetStreamConsumer) Ack(ctx context.Context, delivery *Delivery) error {
+	return j.settle(delivery, func(msg jetstream.Msg) error {
+		return msg.DoubleAck(ctx)
+	})
+}
+
+// Requeue asks JetStream to redeliver a message
+func (j *JetStreamConsumer) Requeue(ctx context.Context, delivery *Delivery) error {
+	return j.settle(delivery, func(msg jetstream.Msg) error {
+		return msg.Nak()
+	})
+}
+
+// DeadLetter stores a delivery the worker gave up on, then acknowledges it
+// When it cannot be stored it is requeued instead, so it is never lost
+func (j *JetStreamConsumer) DeadLetter(ctx context.Context, delivery *Delivery, reason models.DeadLetterReason, cause error, attempts int) error {
+	return deadLetter(ctx, j, j.deadLetters, delivery, reason, cause, attempts)
+}
+
+// LaneDepths returns the number of messages not yet delivered on each lane
+func (j *JetStreamConsumer) LaneDepths(ctx context.Context) (map[models.QueueLane]int64, error) {
+	depths := make(map[models.QueueLane]int64, len(models.QueueLanes))
+	for _, lane := range models.QueueLanes {
+		j.mu.Lock()
+		consumer, ok := j.consumers[lane.Subject()]
+		j.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		info, err := consumer.Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		depths[lane] = int64(info.NumPending)
+	}
+	return depths, nil
+}
@@ -0,0 +1,184 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newTestJetStream starts an in-process NATS server with JetStream, stopped with the test
+func newTestJetStream(t *testing.T) jetstream.JetStream {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	require.NoError(t, err)
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+	require.True(t, srv.ReadyForConnections(5*time.Second), "NATS server not ready")
+
+	nc, err := nats.Connect(srv.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+
+	js, err := jetstream.New(nc)
+	require.NoError(t, err)
+	return js
+}
+
+func newTestJetStreamConsumer(t *testing.T, deadLetters *MockWriter) (*JetStreamConsumer, jetstream.JetStream) {
+	t.Helper()
+
+	js := newTestJetStream(t)
+	consumer := NewJetStreamConsumer(js, deadLetters, "TEST_TRANSACTIONS", Config{
+		PopTimeout:      100 * time.Millisecond,
+		ConsumerTimeout: 30 * time.Second,
+		StrictLanes:     []models.QueueLane{models.LaneRealtime},
+	})
+	require.NoError(t, consumer.Register(context.Background()))
+	return consumer, js
+}
+
+func publish(t *testing.T, js jetstream.JetStream, lane models.QueueLane, payload string) {
+	t.Helper()
+
+	_, err := js.Publish(context.Background(), lane.Subject(), []byte(payload))
+	require.NoError(t, err)
+}
+
+func Test_JetStreamConsumer_Register_WhenStreamExists_ThenKeepsIt(t *testing.T) {
+	js := newTestJetStream(t)
+	_, err := js.CreateStream(context.Background(), jetstream.StreamConfig{
+		Name:      "TEST_TRANSACTIONS",
+		Subjects:  []string{"transactions.>"},
+		Retention: jetstream.WorkQueuePolicy,
+	})
+	require.NoError(t, err)
+	consumer := NewJetStreamConsumer(js, nil, "TEST_TRANSACTIONS", Config{ConsumerTimeout: 30 * time.Second})
+
+	require.NoError(t, consumer.Register(context.Background()))
+
+	stream, err := js.Stream(context.Background(), "TEST_TRANSACTIONS")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"transactions.>"}, stream.CachedInfo().Config.Subjects)
+}
+
+func Test_JetStreamConsumer_PopTransaction_WhenLanesHoldEvents_ThenTakesStrictLaneFirst(t *testing.T) {
+	consumer, js := newTestJetStreamConsumer(t, nil)
+	publish(t, js, models.LanePostTransaction, `{"external_id":"ext-1"}`)
+	publish(t, js, models.LaneRealtime, `{"external_id":"ext-2"}`)
+
+	first, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+	second, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "ext-2", first.Event["external_id"])
+	assert.Equal(t, "transactions.realtime", first.queue)
+	assert.Equal(t, "ext-1", second.Event["external_id"])
+}
+
+func Test_JetStreamConsumer_PopTransaction_WhenEmpty_ThenReturnsErrTimeout(t *testing.T) {
+	consumer, _ := newTestJetStreamConsumer(t, nil)
+
+	_, err := consumer.PopTransaction(context.Background())
+
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func Test_JetStreamConsumer_Ack_WhenCalled_ThenRemovesMessage(t *testing.T) {
+	consumer, js := newTestJetStreamConsumer(t, nil)
+	publish(t, js, models.LaneBackfill, `{"external_id":"ext-1"}`)
+	delivery, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, consumer.Ack(context.Background(), delivery))
+
+	_, err = consumer.PopTransaction(context.Background())
+	assert.ErrorIs(t, err, ErrTimeout)
+	stream, err := js.Stream(context.Background(), "TEST_TRANSACTIONS")
+	require.NoError(t, err)
+	info, err := stream.Info(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), info.State.Msgs)
+}
+
+func Test_JetStreamConsumer_Release_WhenEventsInFlight_ThenRedeliversThem(t *testing.T) {
+	consumer, js := newTestJetStreamConsumer(t, nil)
+	publish(t, js, models.LanePostTransaction, `{"external_id":"ext-1"}`)
+	_, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, consumer.Release(context.Background()))
+
+	result, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ext-1", result.Event["external_id"])
+}
+
+func Test_JetStreamConsumer_PopTransaction_WhenInvalidJSON_ThenDeadLettersAndAcksIt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeadLetters := NewMockWriter(ctrl)
+	mockDeadLetters.EXPECT().AddDeadLetter(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, letter *models.DeadLetter) error {
+		assert.Equal(t, "transactions.post_transaction", letter.Queue)
+		assert.Equal(t, models.DeadLetterInvalidPayload, letter.Reason)
+		return nil
+	})
+	consumer, js := newTestJetStreamConsumer(t, mockDeadLetters)
+	publish(t, js, models.LanePostTransaction, "not json")
+
+	_, err := consumer.PopTransaction(context.Background())
+	assert.ErrorIs(t, err, ErrInvalidData)
+
+	_, err = consumer.PopTransaction(context.Background())
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func Test_JetStreamConsumer_DeadLetter_WhenStoreFails_ThenRedeliversIt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeadLetters := NewMockWriter(ctrl)
+	mockDeadLetters.EXPECT().AddDeadLetter(gomock.Any(), gomock.Any()).Return(errors.New("database unavailable"))
+	consumer, js := newTestJetStreamConsumer(t, mockDeadLetters)
+	publish(t, js, models.LanePostTransaction, `{"external_id":"ext-1"}`)
+	delivery, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+
+	err = consumer.DeadLetter(context.Background(), delivery, models.DeadLetterProcessingFailed, errors.New("timeout"), 3)
+	assert.Error(t, err)
+
+	result, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ext-1", result.Event["external_id"])
+}
+
+func Test_JetStreamConsumer_LaneDepths_WhenEventsWaiting_ThenExcludesInFlight(t *testing.T) {
+	consumer, js := newTestJetStreamConsumer(t, nil)
+	publish(t, js, models.LaneBackfill, `{"external_id":"ext-1"}`)
+	publish(t, js, models.LaneBackfill, `{"external_id":"ext-2"}`)
+	_, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+
+	depths, err := consumer.LaneDepths(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), depths[models.LaneBackfill])
+	assert.Equal(t, int64(0), depths[models.LaneRealtime])
+}
@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+)
+
+// laneQueues returns the queues of every lane, whether or not this worker drains them
+func laneQueues() []string {
+	queues := make([]string, len(models.QueueLanes))
+	for i, lane := range models.QueueLanes {
+		queues[i] = lane.QueueKey()
+	}
+	return queues
+}
+
+// weightedLane is a lane sharing pops with the other weighted lanes
+type weightedLane struct {
+	queue   string
+	weight  int
+	current int // Credit accumulated while waiting for its turn
+}
+
+// laneScheduler orders the queues tried by each pop
+// Strict lanes come first, in order. Weighted lanes follow by smooth weighted round-robin:
+// while several hold events, each serves pops in proportion to its weight, interleaved
+// rather than in bursts. A lane found empty does not bank credit for later.
+type laneScheduler struct {
+	mu          sync.Mutex
+	strict      []string
+	weighted    []*weightedLane
+	totalWeight int
+}
+
+// newLaneScheduler creates a scheduler over the queues key returns for each lane
+// Lanes neither strict nor weighted get a weight of 1
+func newLaneScheduler(strict []models.QueueLane, weights map[models.QueueLane]int, key func(models.QueueLane) string) *laneScheduler {
+	s := &laneScheduler{}
+
+	isStrict := make(map[models.QueueLane]bool, len(strict))
+	for _, lane := range strict {
+		isStrict[lane] = true
+		s.strict = append(s.strict, key(lane))
+	}
+
+	for _, lane := range models.QueueLanes {
+		if isStrict[lane] {
+			continue
+		}
+		weight, ok := weights[lane]
+		if !ok || weight < 1 {
+			weight = 1
+		}
+		s.weighted = append(s.weighted, &weightedLane{queue: key(lane), weight: weight})
+		s.totalWeight += weight
+	}
+
+	return s
+}
+
+// order returns the queues to try for the next pop, most due first
+func (s *laneScheduler) order() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	weighted := make([]*weightedLane, len(s.weighted))
+	copy(weighted, s.weighted)
+	sort.SliceStable(weighted, func(i, j int) bool {
+		return weighted[i].current+weighted[i].weight > weighted[j].current+weighted[j].weight
+	})
+
+	queues := make([]string, 0, len(s.strict)+len(weighted))
+	queues = append(queues, s.strict...)
+	for _, lane := range weighted {
+		queues = append(queues, lane.queue)
+	}
+	return queues
+}
+
+// served charges the weighted lane whose queue served a pop
+// The lanes found empty first lose their credit and are left out of the round.
+// Pops served by strict lanes leave the weighted lanes as they are.
+func (s *laneScheduler) served(queue string, empty []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var servedLane *weightedLane
+	for _, lane := range s.weighted {
+		if lane.queue == queue {
+			servedLane = lane
+		}
+	}
+	if servedLane == nil {
+		return
+	}
+
+	isEmpty := make(map[string]bool, len(empty))
+	for _, q := range empty {
+		isEmpty[q] = true
+	}
+
+	total := 0
+	for _, lane := range s.weighted {
+		if isEmpty[lane.queue] {
+			lane.current = 0
+			continue
+		}
+		lane.current += lane.weight
+		total += lane.weight
+	}
+	servedLane.current -= total
+
+	// Lanes assumed busy without being tried may drift; bound every credit to one round
+	for _, lane := range s.weighted {
+		lane.current = max(-s.totalWeight, min(lane.current, s.totalWeight))
+	}
+}
+
+// blockingQueue returns the queue an idle pop waits on: the first strict lane,
+// or else the heaviest weighted lane
+func (s *laneScheduler) blockingQueue() string {
+	if len(s.strict) > 0 {
+		return s.strict[0]
+	}
+
+	heaviest := s.weighted[0]
+	for _, lane := range s.weighted[1:] {
+		if lane.weight > heaviest.weight {
+			heaviest = lane
+		}
+	}
+	return heaviest.queue
+}
@@ -0,0 +1,111 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	realtimeQueue        = models.LaneRealtime.QueueKey()
+	postTransactionQueue = models.LanePostTransaction.QueueKey()
+	backfillQueue        = models.LaneBackfill.QueueKey()
+)
+
+func Test_laneScheduler_order_WhenStrictLanes_ThenTriesThemFirst(t *testing.T) {
+	scheduler := newLaneScheduler(
+		[]models.QueueLane{models.LaneRealtime},
+		map[models.QueueLane]int{models.LanePostTransaction: 4, models.LaneBackfill: 1},
+		models.QueueLane.QueueKey,
+	)
+
+	assert.Equal(t, []string{realtimeQueue, postTransactionQueue, backfillQueue}, scheduler.order())
+}
+
+// pop simulates n pops from lanes where only the given queues hold events
+// Like PopTransaction, the queues are tried in order and the empty ones passed on
+func pop(scheduler *laneScheduler, busy map[string]bool, n int) []string {
+	var popped []string
+	for i := 0; i < n; i++ {
+		var empty []string
+		for _, queue := range scheduler.order() {
+			if busy[queue] {
+				scheduler.served(queue, empty)
+				popped = append(popped, queue)
+				break
+			}
+			empty = append(empty, queue)
+		}
+	}
+	return popped
+}
+
+func count(popped []string, queue string) int {
+	n := 0
+	for _, q := range popped {
+		if q == queue {
+			n++
+		}
+	}
+	return n
+}
+
+func Test_laneScheduler_served_WhenLanesBusy_ThenSharesPopsByWeight(t *testing.T) {
+	scheduler := newLaneScheduler(
+		[]models.QueueLane{models.LaneRealtime},
+		map[models.QueueLane]int{models.LanePostTransaction: 4, models.LaneBackfill: 1},
+		models.QueueLane.QueueKey,
+	)
+
+	popped := pop(scheduler, map[string]bool{postTransactionQueue: true, backfillQueue: true}, 10)
+
+	assert.Equal(t, 8, count(popped, postTransactionQueue))
+	assert.Equal(t, 2, count(popped, backfillQueue))
+	assert.Equal(t, 1, count(popped[:5], backfillQueue), "pops are interleaved rather than served in bursts")
+}
+
+func Test_laneScheduler_served_WhenStrictLaneBusy_ThenWeightedLanesWait(t *testing.T) {
+	scheduler := newLaneScheduler([]models.QueueLane{models.LaneRealtime}, nil, models.QueueLane.QueueKey)
+
+	popped := pop(scheduler, map[string]bool{realtimeQueue: true, postTransactionQueue: true, backfillQueue: true}, 5)
+
+	assert.Equal(t, 5, count(popped, realtimeQueue))
+}
+
+func Test_laneScheduler_served_WhenLaneWasEmpty_ThenItDoesNotCatchUpInABurst(t *testing.T) {
+	scheduler := newLaneScheduler(
+		[]models.QueueLane{models.LaneRealtime},
+		map[models.QueueLane]int{models.LanePostTransaction: 1, models.LaneBackfill: 1},
+		models.QueueLane.QueueKey,
+	)
+
+	// Only the post_transaction lane holds events for a while, then both do
+	pop(scheduler, map[string]bool{postTransactionQueue: true}, 20)
+	popped := pop(scheduler, map[string]bool{postTransactionQueue: true, backfillQueue: true}, 6)
+
+	assert.Equal(t, 3, count(popped, backfillQueue))
+	assert.Equal(t, 1, count(popped[:2], backfillQueue))
+}
+
+func Test_laneScheduler_served_WhenStrictLaneServes_ThenWeightedLanesUnchanged(t *testing.T) {
+	scheduler := newLaneScheduler(
+		[]models.QueueLane{models.LaneRealtime},
+		map[models.QueueLane]int{models.LanePostTransaction: 1, models.LaneBackfill: 3},
+		models.QueueLane.QueueKey,
+	)
+
+	scheduler.served(realtimeQueue, nil)
+
+	for _, lane := range scheduler.weighted {
+		assert.Zero(t, lane.current)
+	}
+}
+
+func Test_laneScheduler_blockingQueue_WhenNoStrictLane_ThenReturnsHeaviestLane(t *testing.T) {
+	strict := newLaneScheduler([]models.QueueLane{models.LaneBackfill}, nil, models.QueueLane.QueueKey)
+	weighted := newLaneScheduler(nil, map[models.QueueLane]int{models.LaneBackfill: 5}, models.QueueLane.QueueKey)
+
+	assert.Equal(t, backfillQueue, strict.blockingQueue())
+	assert.Equal(t, backfillQueue, weighted.blockingQueue())
+}
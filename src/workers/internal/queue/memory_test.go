@@ -0,0 +1,262 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MemoryConsumer is an in-process broker holding queued events in memory
+// It follows the lane order and delivery lifecycle of the other transports, so the shared
+// consumer helpers can be exercised without Redis or NATS
+type MemoryConsumer struct {
+	mu          sync.Mutex
+	cfg         Config
+	lanes       *laneScheduler
+	queues      map[string][]string
+	inFlight    map[*Delivery]bool
+	deadLetters []models.DeadLetter
+	published   chan struct{} // Closed and replaced whenever an event is published
+}
+
+// NewMemoryConsumer creates an empty in-process broker
+func NewMemoryConsumer(cfg Config) *MemoryConsumer {
+	return &MemoryConsumer{
+		cfg:       cfg,
+		lanes:     newLaneScheduler(cfg.StrictLanes, cfg.LaneWeights, models.QueueLane.QueueKey),
+		queues:    make(map[string][]string),
+		inFlight:  make(map[*Delivery]bool),
+		published: make(chan struct{}),
+	}
+}
+
+// Publish queues a raw payload on a lane, waking any pop waiting for events
+func (m *MemoryConsumer) Publish(lane models.QueueLane, payload string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queues[lane.QueueKey()] = append(m.queues[lane.QueueKey()], payload)
+	close(m.published)
+	m.published = make(chan struct{})
+}
+
+// InFlight returns the number of deliveries popped but not settled yet
+func (m *MemoryConsumer) InFlight() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.inFlight)
+}
+
+// DeadLetters returns the dead letters stored so far
+func (m *MemoryConsumer) DeadLetters() []models.DeadLetter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]models.DeadLetter(nil), m.deadLetters...)
+}
+
+// Register does nothing: the broker lives and dies with the worker
+func (m *MemoryConsumer) Register(ctx context.Context) error {
+	return nil
+}
+
+// Start does nothing until the context is cancelled
+func (m *MemoryConsumer) Start(ctx context.Context) {
+	<-ctx.Done()
+}
+
+// Release puts the events still in flight back at the front of their lanes
+func (m *MemoryConsumer) Release(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for delivery := range m.inFlight {
+		m.queues[delivery.queue] = append([]string{delivery.Payload}, m.queues[delivery.queue]...)
+		delete(m.inFlight, delivery)
+	}
+	return nil
+}
+
+// PopTransaction takes the next event, waiting up to the pop timeout for one to be published
+func (m *MemoryConsumer) PopTransaction(ctx context.Context) (*Delivery, error) {
+	timer := time.NewTimer(m.cfg.PopTimeout)
+	defer timer.Stop()
+
+	for {
+		delivery, published := m.pop()
+		if delivery != nil {
+			return decode(ctx, m, delivery)
+		}
+
+		select {
+		case <-published:
+		case <-timer.C:
+			return nil, ErrTimeout
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// pop takes the next event in lane order, or returns the channel closed by the next publish
+func (m *MemoryConsumer) pop() (*Delivery, <-chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var empty []string
+	for _, source := range m.lanes.order() {
+		if len(m.queues[source]) == 0 {
+			empty = append(empty, source)
+			continue
+		}
+
+		payload := m.queues[source][0]
+		m.queues[source] = m.queues[source][1:]
+		m.lanes.served(source, empty)
+
+		delivery := &Delivery{Payload: payload, ReceivedAt: time.Now(), queue: source}
+		m.inFlight[delivery] = true
+		return delivery, nil
+	}
+
+	return nil, m.published
+}
+
+// Ack forgets a processed delivery
+func (m *MemoryConsumer) Ack(ctx context.Context, delivery *Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.inFlight, delivery)
+	return nil
+}
+
+// Requeue puts a delivery back at the end of its lane
+func (m *MemoryConsumer) Requeue(ctx context.Context, delivery *Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queues[delivery.queue] = append(m.queues[delivery.queue], delivery.Payload)
+	delete(m.inFlight, delivery)
+	return nil
+}
+
+// DeadLetter keeps a delivery the worker gave up on, see DeadLetters
+func (m *MemoryConsumer) DeadLetter(ctx context.Context, delivery *Delivery, reason models.DeadLetterReason, cause error, attempts int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.deadLetters = append(m.deadLetters, *newDeadLetter(delivery, reason, cause, attempts))
+	delete(m.inFlight, delivery)
+	return nil
+}
+
+// LaneDepths returns the number of events waiting in each lane
+func (m *MemoryConsumer) LaneDepths(ctx context.Context) (map[models.QueueLane]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	depths := make(map[models.QueueLane]int64, len(models.QueueLanes))
+	for _, lane := range models.QueueLanes {
+		depths[lane] = int64(len(m.queues[lane.QueueKey()]))
+	}
+	return depths, nil
+}
+
+func newTestMemoryConsumer() *MemoryConsumer {
+	return NewMemoryConsumer(Config{
+		PopTimeout:  50 * time.Millisecond,
+		StrictLanes: []models.QueueLane{models.LaneRealtime},
+	})
+}
+
+func Test_MemoryConsumer_PopTransaction_WhenLanesHoldEvents_ThenTakesStrictLaneFirst(t *testing.T) {
+	consumer := newTestMemoryConsumer()
+	consumer.Publish(models.LanePostTransaction, `{"external_id":"ext-1"}`)
+	consumer.Publish(models.LaneRealtime, `{"external_id":"ext-2"}`)
+
+	first, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+	second, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "ext-2", first.Event["external_id"])
+	assert.Equal(t, "ext-1", second.Event["external_id"])
+	assert.Equal(t, 2, consumer.InFlight())
+}
+
+func Test_MemoryConsumer_PopTransaction_WhenPublishedWhileWaiting_ThenReturnsEvent(t *testing.T) {
+	consumer := NewMemoryConsumer(Config{PopTimeout: time.Second})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		consumer.Publish(models.LaneBackfill, `{"external_id":"ext-1"}`)
+	}()
+	result, err := consumer.PopTransaction(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "ext-1", result.Event["external_id"])
+}
+
+func Test_MemoryConsumer_PopTransaction_WhenEmpty_ThenReturnsErrTimeout(t *testing.T) {
+	consumer := newTestMemoryConsumer()
+
+	_, err := consumer.PopTransaction(context.Background())
+
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func Test_MemoryConsumer_PopTransaction_WhenInvalidJSON_ThenDeadLettersIt(t *testing.T) {
+	consumer := newTestMemoryConsumer()
+	consumer.Publish(models.LanePostTransaction, "not json")
+
+	_, err := consumer.PopTransaction(context.Background())
+
+	assert.ErrorIs(t, err, ErrInvalidData)
+	require.Len(t, consumer.DeadLetters(), 1)
+	assert.Equal(t, models.DeadLetterInvalidPayload, consumer.DeadLetters()[0].Reason)
+	assert.Equal(t, 0, consumer.InFlight())
+}
+
+func Test_MemoryConsumer_Release_WhenEventsInFlight_ThenPutsThemBackFirst(t *testing.T) {
+	consumer := newTestMemoryConsumer()
+	consumer.Publish(models.LanePostTransaction, `{"external_id":"ext-1"}`)
+	consumer.Publish(models.LanePostTransaction, `{"external_id":"ext-2"}`)
+	_, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, consumer.Release(context.Background()))
+
+	result, err := consumer.PopTransaction(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ext-1", result.Event["external_id"])
+}
+
+func Test_MemoryConsumer_Settle_WhenAckedRequeuedOrDeadLettered_ThenLeavesFlight(t *testing.T) {
+	consumer := newTestMemoryConsumer()
+	for range 3 {
+		consumer.Publish(models.LanePostTransaction, `{"external_id":"ext-1"}`)
+	}
+	deliveries := make([]*Delivery, 3)
+	for i := range deliveries {
+		delivery, err := consumer.PopTransaction(context.Background())
+		require.NoError(t, err)
+		deliveries[i] = delivery
+	}
+
+	require.NoError(t, consumer.Ack(context.Background(), deliveries[0]))
+	require.NoError(t, consumer.Requeue(context.Background(), deliveries[1]))
+	require.NoError(t, consumer.DeadLetter(context.Background(), deliveries[2], models.DeadLetterProcessingFailed, errors.New("timeout"), 3))
+
+	depths, err := consumer.LaneDepths(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), depths[models.LanePostTransaction])
+	assert.Equal(t, 0, consumer.InFlight())
+	require.Len(t, consumer.DeadLetters(), 1)
+	assert.Equal(t, 3, consumer.DeadLetters()[0].Attempts)
+}
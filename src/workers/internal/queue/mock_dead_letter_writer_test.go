@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/pkg/deadletters/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/pkg/deadletters/repository.go -destination=src/workers/internal/queue/mock_dead_letter_writer_test.go -package=queue -exclude_interfaces=Repository
+//
+
+// Package queue is a generated GoMock package.
+package queue
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWriter is a mock of Writer interface.
+type MockWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriterMockRecorder
+	isgomock struct{}
+}
+
+// MockWriterMockRecorder is the mock recorder for MockWriter.
+type MockWriterMockRecorder struct {
+	mock *MockWriter
+}
+
+// NewMockWriter creates a new mock instance.
+func NewMockWriter(ctrl *gomock.Controller) *MockWriter {
+	mock := &MockWriter{ctrl: ctrl}
+	mock.recorder = &MockWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
+	return m.recorder
+}
+
+// AddDeadLetter mocks base method.
+func (m *MockWriter) AddDeadLetter(ctx context.Context, letter *models.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeadLetter", ctx, letter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDeadLetter indicates an expected call of AddDeadLetter.
+func (mr *MockWriterMockRecorder) AddDeadLetter(ctx, letter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeadLetter", reflect.TypeOf((*MockWriter)(nil).AddDeadLetter), ctx, letter)
+}
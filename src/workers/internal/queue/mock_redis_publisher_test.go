@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: decision_publisher.go
+//
+// Generated by this command:
+//
+//	mockgen -source=decision_publisher.go -destination=mock_redis_publisher_test.go -package=queue
+//
+
+// Package queue is a generated GoMock package.
+package queue
+
+import (
+	context "context"
+	reflect "reflect"
+
+	redis "github.com/redis/go-redis/v9"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRedisPublisher is a mock of RedisPublisher interface.
+type MockRedisPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedisPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockRedisPublisherMockRecorder is the mock recorder for MockRedisPublisher.
+type MockRedisPublisherMockRecorder struct {
+	mock *MockRedisPublisher
+}
+
+// NewMockRedisPublisher creates a new mock instance.
+func NewMockRedisPublisher(ctrl *gomock.Controller) *MockRedisPublisher {
+	mock := &MockRedisPublisher{ctrl: ctrl}
+	mock.recorder = &MockRedisPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedisPublisher) EXPECT() *MockRedisPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockRedisPublisher) Publish(ctx context.Context, channel string, message any) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, channel, message)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockRedisPublisherMockRecorder) Publish(ctx, channel, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockRedisPublisher)(nil).Publish), ctx, channel, message)
+}
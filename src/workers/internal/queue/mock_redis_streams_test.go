@@ -0,0 +1,164 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: streams.go
+//
+// Generated by this command:
+//
+//	mockgen -source=streams.go -destination=mock_redis_streams_test.go -package=queue
+//
+
+// Package queue is a generated GoMock package.
+package queue
+
+import (
+	context "context"
+	reflect "reflect"
+
+	redis "github.com/redis/go-redis/v9"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRedisStreams is a mock of RedisStreams interface.
+type MockRedisStreams struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedisStreamsMockRecorder
+	isgomock struct{}
+}
+
+// MockRedisStreamsMockRecorder is the mock recorder for MockRedisStreams.
+type MockRedisStreamsMockRecorder struct {
+	mock *MockRedisStreams
+}
+
+// NewMockRedisStreams creates a new mock instance.
+func NewMockRedisStreams(ctrl *gomock.Controller) *MockRedisStreams {
+	mock := &MockRedisStreams{ctrl: ctrl}
+	mock.recorder = &MockRedisStreamsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedisStreams) EXPECT() *MockRedisStreamsMockRecorder {
+	return m.recorder
+}
+
+// XAck mocks base method.
+func (m *MockRedisStreams) XAck(ctx context.Context, stream, group string, ids ...string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, stream, group}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "XAck", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// XAck indicates an expected call of XAck.
+func (mr *MockRedisStreamsMockRecorder) XAck(ctx, stream, group any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, stream, group}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XAck", reflect.TypeOf((*MockRedisStreams)(nil).XAck), varargs...)
+}
+
+// XAdd mocks base method.
+func (m *MockRedisStreams) XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XAdd", ctx, a)
+	ret0, _ := ret[0].(*redis.StringCmd)
+	return ret0
+}
+
+// XAdd indicates an expected call of XAdd.
+func (mr *MockRedisStreamsMockRecorder) XAdd(ctx, a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XAdd", reflect.TypeOf((*MockRedisStreams)(nil).XAdd), ctx, a)
+}
+
+// XAutoClaim mocks base method.
+func (m *MockRedisStreams) XAutoClaim(ctx context.Context, a *redis.XAutoClaimArgs) *redis.XAutoClaimCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XAutoClaim", ctx, a)
+	ret0, _ := ret[0].(*redis.XAutoClaimCmd)
+	return ret0
+}
+
+// XAutoClaim indicates an expected call of XAutoClaim.
+func (mr *MockRedisStreamsMockRecorder) XAutoClaim(ctx, a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XAutoClaim", reflect.TypeOf((*MockRedisStreams)(nil).XAutoClaim), ctx, a)
+}
+
+// XDel mocks base method.
+func (m *MockRedisStreams) XDel(ctx context.Context, stream string, ids ...string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, stream}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "XDel", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// XDel indicates an expected call of XDel.
+func (mr *MockRedisStreamsMockRecorder) XDel(ctx, stream any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, stream}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XDel", reflect.TypeOf((*MockRedisStreams)(nil).XDel), varargs...)
+}
+
+// XGroupCreateMkStream mocks base method.
+func (m *MockRedisStreams) XGroupCreateMkStream(ctx context.Context, stream, group, start string) *redis.StatusCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XGroupCreateMkStream", ctx, stream, group, start)
+	ret0, _ := ret[0].(*redis.StatusCmd)
+	return ret0
+}
+
+// XGroupCreateMkStream indicates an expected call of XGroupCreateMkStream.
+func (mr *MockRedisStreamsMockRecorder) XGroupCreateMkStream(ctx, stream, group, start any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XGroupCreateMkStream", reflect.TypeOf((*MockRedisStreams)(nil).XGroupCreateMkStream), ctx, stream, group, start)
+}
+
+// XLen mocks base method.
+func (m *MockRedisStreams) XLen(ctx context.Context, stream string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XLen", ctx, stream)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// XLen indicates an expected call of XLen.
+func (mr *MockRedisStreamsMockRecorder) XLen(ctx, stream any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XLen", reflect.TypeOf((*MockRedisStreams)(nil).XLen), ctx, stream)
+}
+
+// XPending mocks base method.
+func (m *MockRedisStreams) XPending(ctx context.Context, stream, group string) *redis.XPendingCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XPending", ctx, stream, group)
+	ret0, _ := ret[0].(*redis.XPendingCmd)
+	return ret0
+}
+
+// XPending indicates an expected call of XPending.
+func (mr *MockRedisStreamsMockRecorder) XPending(ctx, stream, group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XPending", reflect.TypeOf((*MockRedisStreams)(nil).XPending), ctx, stream, group)
+}
+
+// XReadGroup mocks base method.
+func (m *MockRedisStreams) XReadGroup(ctx context.Context, a *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XReadGroup", ctx, a)
+	ret0, _ := ret[0].(*redis.XStreamSliceCmd)
+	return ret0
+}
+
+// XReadGroup indicates an expected call of XReadGroup.
+func (mr *MockRedisStreamsMockRecorder) XReadGroup(ctx, a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XReadGroup", reflect.TypeOf((*MockRedisStreams)(nil).XReadGroup), ctx, a)
+}
@@ -1,6 +1,12 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: queue_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=queue_service.go -destination=mock_redis_test.go -package=queue
+//
 
+// Package queue is a generated GoMock package.
 package queue
 
 import (
@@ -12,33 +18,48 @@ import (
 	gomock "go.uber.org/mock/gomock"
 )
 
-// MockRedisPopper is a mock of RedisPopper interface
-type MockRedisPopper struct {
+// MockRedisQueue is a mock of RedisQueue interface.
+type MockRedisQueue struct {
 	ctrl     *gomock.Controller
-	recorder *MockRedisPopperMockRecorder
+	recorder *MockRedisQueueMockRecorder
+	isgomock struct{}
 }
 
-// MockRedisPopperMockRecorder is the mock recorder for MockRedisPopper
-type MockRedisPopperMockRecorder struct {
-	mock *MockRedisPopper
+// MockRedisQueueMockRecorder is the mock recorder for MockRedisQueue.
+type MockRedisQueueMockRecorder struct {
+	mock *MockRedisQueue
 }
 
-// NewMockRedisPopper creates a new mock instance
-func NewMockRedisPopper(ctrl *gomock.Controller) *MockRedisPopper {
-	mock := &MockRedisPopper{ctrl: ctrl}
-	mock.recorder = &MockRedisPopperMockRecorder{mock}
+// NewMockRedisQueue creates a new mock instance.
+func NewMockRedisQueue(ctrl *gomock.Controller) *MockRedisQueue {
+	mock := &MockRedisQueue{ctrl: ctrl}
+	mock.recorder = &MockRedisQueueMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockRedisPopper) EXPECT() *MockRedisPopperMockRecorder {
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedisQueue) EXPECT() *MockRedisQueueMockRecorder {
 	return m.recorder
 }
 
-// BRPop mocks base method
-func (m *MockRedisPopper) BRPop(ctx context.Context, timeout time.Duration, keys ...string) *redis.StringSliceCmd {
+// BLMove mocks base method.
+func (m *MockRedisQueue) BLMove(ctx context.Context, source, destination, srcpos, destpos string, timeout time.Duration) *redis.StringCmd {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx, timeout}
+	ret := m.ctrl.Call(m, "BLMove", ctx, source, destination, srcpos, destpos, timeout)
+	ret0, _ := ret[0].(*redis.StringCmd)
+	return ret0
+}
+
+// BLMove indicates an expected call of BLMove.
+func (mr *MockRedisQueueMockRecorder) BLMove(ctx, source, destination, srcpos, destpos, timeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BLMove", reflect.TypeOf((*MockRedisQueue)(nil).BLMove), ctx, source, destination, srcpos, destpos, timeout)
+}
+
+// BRPop mocks base method.
+func (m *MockRedisQueue) BRPop(ctx context.Context, timeout time.Duration, keys ...string) *redis.StringSliceCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, timeout}
 	for _, a := range keys {
 		varargs = append(varargs, a)
 	}
@@ -47,9 +68,174 @@ func (m *MockRedisPopper) BRPop(ctx context.Context, timeout time.Duration, keys
 	return ret0
 }
 
-// BRPop indicates an expected call of BRPop
-func (mr *MockRedisPopperMockRecorder) BRPop(ctx, timeout interface{}, keys ...interface{}) *gomock.Call {
+// BRPop indicates an expected call of BRPop.
+func (mr *MockRedisQueueMockRecorder) BRPop(ctx, timeout any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, timeout}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BRPop", reflect.TypeOf((*MockRedisQueue)(nil).BRPop), varargs...)
+}
+
+// Del mocks base method.
+func (m *MockRedisQueue) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Del", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockRedisQueueMockRecorder) Del(ctx any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockRedisQueue)(nil).Del), varargs...)
+}
+
+// Exists mocks base method.
+func (m *MockRedisQueue) Exists(ctx context.Context, keys ...string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Exists", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockRedisQueueMockRecorder) Exists(ctx any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockRedisQueue)(nil).Exists), varargs...)
+}
+
+// LLen mocks base method.
+func (m *MockRedisQueue) LLen(ctx context.Context, key string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LLen", ctx, key)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// LLen indicates an expected call of LLen.
+func (mr *MockRedisQueueMockRecorder) LLen(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LLen", reflect.TypeOf((*MockRedisQueue)(nil).LLen), ctx, key)
+}
+
+// LMove mocks base method.
+func (m *MockRedisQueue) LMove(ctx context.Context, source, destination, srcpos, destpos string) *redis.StringCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LMove", ctx, source, destination, srcpos, destpos)
+	ret0, _ := ret[0].(*redis.StringCmd)
+	return ret0
+}
+
+// LMove indicates an expected call of LMove.
+func (mr *MockRedisQueueMockRecorder) LMove(ctx, source, destination, srcpos, destpos any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LMove", reflect.TypeOf((*MockRedisQueue)(nil).LMove), ctx, source, destination, srcpos, destpos)
+}
+
+// LPush mocks base method.
+func (m *MockRedisQueue) LPush(ctx context.Context, key string, values ...any) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LPush", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// LPush indicates an expected call of LPush.
+func (mr *MockRedisQueueMockRecorder) LPush(ctx, key any, values ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPush", reflect.TypeOf((*MockRedisQueue)(nil).LPush), varargs...)
+}
+
+// LRem mocks base method.
+func (m *MockRedisQueue) LRem(ctx context.Context, key string, count int64, value any) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LRem", ctx, key, count, value)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// LRem indicates an expected call of LRem.
+func (mr *MockRedisQueueMockRecorder) LRem(ctx, key, count, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LRem", reflect.TypeOf((*MockRedisQueue)(nil).LRem), ctx, key, count, value)
+}
+
+// SAdd mocks base method.
+func (m *MockRedisQueue) SAdd(ctx context.Context, key string, members ...any) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SAdd", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// SAdd indicates an expected call of SAdd.
+func (mr *MockRedisQueueMockRecorder) SAdd(ctx, key any, members ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockRedisQueue)(nil).SAdd), varargs...)
+}
+
+// SMembers mocks base method.
+func (m *MockRedisQueue) SMembers(ctx context.Context, key string) *redis.StringSliceCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMembers", ctx, key)
+	ret0, _ := ret[0].(*redis.StringSliceCmd)
+	return ret0
+}
+
+// SMembers indicates an expected call of SMembers.
+func (mr *MockRedisQueueMockRecorder) SMembers(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockRedisQueue)(nil).SMembers), ctx, key)
+}
+
+// SRem mocks base method.
+func (m *MockRedisQueue) SRem(ctx context.Context, key string, members ...any) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SRem", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// SRem indicates an expected call of SRem.
+func (mr *MockRedisQueueMockRecorder) SRem(ctx, key any, members ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRem", reflect.TypeOf((*MockRedisQueue)(nil).SRem), varargs...)
+}
+
+// Set mocks base method.
+func (m *MockRedisQueue) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, expiration)
+	ret0, _ := ret[0].(*redis.StatusCmd)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockRedisQueueMockRecorder) Set(ctx, key, value, expiration any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx, timeout}, keys...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BRPop", reflect.TypeOf((*MockRedisPopper)(nil).BRPop), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockRedisQueue)(nil).Set), ctx, key, value, expiration)
 }
@@ -2,11 +2,12 @@ package queue
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"time"
 
+	"github.com/algo-shield/algo-shield/src/pkg/deadletters"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -17,32 +18,84 @@ var (
 	ErrInvalidData = errors.New("invalid queue data")
 )
 
-// RedisPopper defines interface for Redis BRPOP operation
-type RedisPopper interface {
+// RedisQueue defines the Redis list, set and key operations used by the queues
+type RedisQueue interface {
 	BRPop(ctx context.Context, timeout time.Duration, keys ...string) *redis.StringSliceCmd
+	LMove(ctx context.Context, source, destination, srcpos, destpos string) *redis.StringCmd
+	BLMove(ctx context.Context, source, destination, srcpos, destpos string, timeout time.Duration) *redis.StringCmd
+	LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+	LRem(ctx context.Context, key string, count int64, value interface{}) *redis.IntCmd
+	LLen(ctx context.Context, key string) *redis.IntCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Exists(ctx context.Context, keys ...string) *redis.IntCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	SAdd(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
+	SRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
+	SMembers(ctx context.Context, key string) *redis.StringSliceCmd
 }
 
-// QueueService handles transaction queue operations
+// Config configures how events are consumed and recovered
+type Config struct {
+	PopTimeout        time.Duration            // How long an idle pop blocks before returning ErrTimeout
+	HeartbeatInterval time.Duration            // How often the worker announces it is alive and looks for stopped workers
+	ConsumerTimeout   time.Duration            // How long without a heartbeat before a worker's in-flight events are requeued
+	StrictLanes       []models.QueueLane       // Lanes drained in order before any weighted lane
+	LaneWeights       map[models.QueueLane]int // Share of pops of the other lanes; unlisted lanes get 1
+}
+
+// QueueService is the Consumer of events queued on Redis lists, and the queue of backtest jobs
+// Popped events are moved atomically into a processing list owned by this worker and
+// stay there until acknowledged, so a crash never loses them: once the worker's
+// heartbeat expires, another worker moves them back to their queue.
+// Events the worker gives up on are stored as dead letters before leaving the processing list.
 type QueueService struct {
-	redis      RedisPopper
-	popTimeout time.Duration
+	redis       RedisQueue
+	deadLetters deadletters.Writer
+	cfg         Config
+	lanes       *laneScheduler
+	consumerID  string
 }
 
-// NewQueueService creates a new queue service
-func NewQueueService(redis RedisPopper, popTimeout time.Duration) *QueueService {
+// NewQueueService creates a new queue service with a unique consumer ID
+func NewQueueService(redis RedisQueue, deadLetters deadletters.Writer, cfg Config) *QueueService {
 	return &QueueService{
-		redis:      redis,
-		popTimeout: popTimeout,
+		redis:       redis,
+		deadLetters: deadLetters,
+		cfg:         cfg,
+		lanes:       newLaneScheduler(cfg.StrictLanes, cfg.LaneWeights, models.QueueLane.QueueKey),
+		consumerID:  newConsumerID(),
 	}
 }
 
-// PopTransaction pops an event from the Redis queue
+// ConsumerID returns the ID naming this worker's processing lists
+func (q *QueueService) ConsumerID() string {
+	return q.consumerID
+}
+
+// PopTransaction moves the next event into this worker's processing list
+// Strict lanes are drained first, then the weighted lanes share the pops by weight.
+// When every lane is empty it blocks on the first strict lane, or the heaviest weighted
+// one, so an event arriving on another lane of an idle worker waits at most the pop timeout.
+// The delivery must then be passed to Ack, Requeue or DeadLetter.
 // Returns ErrTimeout if no event is available (expected)
+// Returns ErrInvalidData for payloads that are not events, which are dead-lettered
 // Returns other errors for actual failures
-func (q *QueueService) PopTransaction(ctx context.Context) (*models.Event, error) {
-	result, err := q.redis.BRPop(ctx, q.popTimeout, "transaction:queue").Result()
+func (q *QueueService) PopTransaction(ctx context.Context) (*Delivery, error) {
+	var empty []string
+	for _, source := range q.lanes.order() {
+		payload, err := q.redis.LMove(ctx, source, q.processingKey(source), "RIGHT", "LEFT").Result()
+		if err == nil {
+			q.lanes.served(source, empty)
+			return decode(ctx, q, q.delivery(source, payload))
+		}
+		if err != redis.Nil {
+			return nil, err
+		}
+		empty = append(empty, source)
+	}
 
-	// Check if it's a timeout (expected) vs actual error
+	source := q.lanes.blockingQueue()
+	payload, err := q.redis.BLMove(ctx, source, q.processingKey(source), "RIGHT", "LEFT", q.cfg.PopTimeout).Result()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, ErrTimeout
@@ -50,16 +103,72 @@ func (q *QueueService) PopTransaction(ctx context.Context) (*models.Event, error
 		return nil, err
 	}
 
-	if len(result) < 2 {
-		return nil, ErrInvalidData
+	return decode(ctx, q, q.delivery(source, payload))
+}
+
+// delivery wraps a payload moved into the processing list
+func (q *QueueService) delivery(source, payload string) *Delivery {
+	return &Delivery{Payload: payload, ReceivedAt: time.Now(), queue: source}
+}
+
+// Ack removes a processed delivery from the processing list
+func (q *QueueService) Ack(ctx context.Context, delivery *Delivery) error {
+	return q.redis.LRem(ctx, q.processingKey(delivery.queue), 1, delivery.Payload).Err()
+}
+
+// Requeue puts a delivery that could not be processed yet back at the end of its queue
+// The event is pushed before it is removed from the processing list, so a failure in
+// between delivers it twice rather than never
+func (q *QueueService) Requeue(ctx context.Context, delivery *Delivery) error {
+	if err := q.redis.LPush(ctx, delivery.queue, delivery.Payload).Err(); err != nil {
+		return err
 	}
+	return q.Ack(ctx, delivery)
+}
 
-	eventJSON := result[1]
-	var event models.Event
+// DeadLetter stores a delivery the worker gave up on, then removes it from the processing list
+// When it cannot be stored it is requeued instead, so it is never lost
+func (q *QueueService) DeadLetter(ctx context.Context, delivery *Delivery, reason models.DeadLetterReason, cause error, attempts int) error {
+	return deadLetter(ctx, q, q.deadLetters, delivery, reason, cause, attempts)
+}
 
-	if err := json.Unmarshal([]byte(eventJSON), &event); err != nil {
-		return nil, ErrInvalidData
+// LaneDepths returns the number of events waiting in each lane, in flight ones excluded
+func (q *QueueService) LaneDepths(ctx context.Context) (map[models.QueueLane]int64, error) {
+	depths := make(map[models.QueueLane]int64, len(models.QueueLanes))
+	for _, lane := range models.QueueLanes {
+		depth, err := q.redis.LLen(ctx, lane.QueueKey()).Result()
+		if err != nil {
+			return nil, err
+		}
+		depths[lane] = depth
+	}
+	return depths, nil
+}
+
+// processingKey returns the list holding the events a consumer took from a queue
+func (q *QueueService) processingKey(source string) string {
+	return processingKey(source, q.consumerID)
+}
+
+// PopBacktest pops the ID of the next backtest job
+// Returns ErrTimeout if no job is available (expected)
+func (q *QueueService) PopBacktest(ctx context.Context) (uuid.UUID, error) {
+	result, err := q.redis.BRPop(ctx, q.cfg.PopTimeout, models.BacktestQueueKey).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return uuid.Nil, ErrTimeout
+		}
+		return uuid.Nil, err
+	}
+
+	if len(result) < 2 {
+		return uuid.Nil, ErrInvalidData
+	}
+
+	id, err := uuid.Parse(result[1])
+	if err != nil {
+		return uuid.Nil, ErrInvalidData
 	}
 
-	return &event, nil
+	return id, nil
 }
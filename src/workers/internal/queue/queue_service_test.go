@@ -8,12 +8,60 @@ import (
 	"time"
 
 	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
+// stringResult builds a command result holding a value, or redis.Nil when empty
+func stringResult(val string) *redis.StringCmd {
+	cmd := redis.NewStringCmd(context.Background())
+	if val == "" {
+		cmd.SetErr(redis.Nil)
+	} else {
+		cmd.SetVal(val)
+	}
+	return cmd
+}
+
+func intResult(val int64) *redis.IntCmd {
+	cmd := redis.NewIntCmd(context.Background())
+	cmd.SetVal(val)
+	return cmd
+}
+
+func newTestQueueService(mockRedis RedisQueue) *QueueService {
+	return newTestQueueServiceWithDeadLetters(mockRedis, nil)
+}
+
+func newTestQueueServiceWithDeadLetters(mockRedis RedisQueue, deadLetters *MockWriter) *QueueService {
+	service := NewQueueService(mockRedis, deadLetters, Config{
+		PopTimeout:  5 * time.Second,
+		StrictLanes: []models.QueueLane{models.LaneRealtime},
+	})
+	service.consumerID = "worker-1"
+	return service
+}
+
+func Test_QueueService_PopTransaction_WhenDecisionRequestQueued_ThenMovesItInFlightFirst(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventJSON, _ := json.Marshal(models.Event{"external_id": "ext-123"})
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockRedis.EXPECT().LMove(gomock.Any(), "transaction:decision:queue", "transaction:decision:queue:processing:worker-1", "RIGHT", "LEFT").
+		Return(stringResult(string(eventJSON)))
+	service := newTestQueueService(mockRedis)
+
+	result, err := service.PopTransaction(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "ext-123", result.Event["external_id"])
+	assert.Equal(t, string(eventJSON), result.Payload)
+}
+
 func Test_QueueService_PopTransaction_WhenEventAvailable_ThenReturnsEvent(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -24,28 +72,46 @@ func Test_QueueService_PopTransaction_WhenEventAvailable_ThenReturnsEvent(t *tes
 		"currency":    "USD",
 	}
 	eventJSON, _ := json.Marshal(event)
-	mockRedis := NewMockRedisPopper(ctrl)
-	cmd := redis.NewStringSliceCmd(context.Background())
-	cmd.SetVal([]string{"transaction:queue", string(eventJSON)})
-	mockRedis.EXPECT().BRPop(gomock.Any(), gomock.Any(), "transaction:queue").Return(cmd)
-	service := NewQueueService(mockRedis, 5*time.Second)
+	mockRedis := NewMockRedisQueue(ctrl)
+	gomock.InOrder(
+		mockRedis.EXPECT().LMove(gomock.Any(), "transaction:decision:queue", gomock.Any(), "RIGHT", "LEFT").Return(stringResult("")),
+		mockRedis.EXPECT().LMove(gomock.Any(), "transaction:queue", "transaction:queue:processing:worker-1", "RIGHT", "LEFT").
+			Return(stringResult(string(eventJSON))),
+	)
+	service := newTestQueueService(mockRedis)
 
 	result, err := service.PopTransaction(context.Background())
 
 	require.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Equal(t, "ext-123", (*result)["external_id"])
+	assert.Equal(t, "ext-123", result.Event["external_id"])
+}
+
+func Test_QueueService_PopTransaction_WhenQueuesEmpty_ThenBlocksOnDecisionQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventJSON, _ := json.Marshal(models.Event{"external_id": "ext-123"})
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockRedis.EXPECT().LMove(gomock.Any(), gomock.Any(), gomock.Any(), "RIGHT", "LEFT").Return(stringResult("")).Times(3)
+	mockRedis.EXPECT().BLMove(gomock.Any(), "transaction:decision:queue", "transaction:decision:queue:processing:worker-1", "RIGHT", "LEFT", 5*time.Second).
+		Return(stringResult(string(eventJSON)))
+	service := newTestQueueService(mockRedis)
+
+	result, err := service.PopTransaction(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "ext-123", result.Event["external_id"])
 }
 
 func Test_QueueService_PopTransaction_WhenTimeout_ThenReturnsErrTimeout(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockRedis := NewMockRedisPopper(ctrl)
-	cmd := redis.NewStringSliceCmd(context.Background())
-	cmd.SetErr(redis.Nil)
-	mockRedis.EXPECT().BRPop(gomock.Any(), gomock.Any(), "transaction:queue").Return(cmd)
-	service := NewQueueService(mockRedis, 5*time.Second)
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockRedis.EXPECT().LMove(gomock.Any(), gomock.Any(), gomock.Any(), "RIGHT", "LEFT").Return(stringResult("")).Times(3)
+	mockRedis.EXPECT().BLMove(gomock.Any(), gomock.Any(), gomock.Any(), "RIGHT", "LEFT", gomock.Any()).Return(stringResult(""))
+	service := newTestQueueService(mockRedis)
 
 	result, err := service.PopTransaction(context.Background())
 
@@ -53,48 +119,109 @@ func Test_QueueService_PopTransaction_WhenTimeout_ThenReturnsErrTimeout(t *testi
 	assert.ErrorIs(t, err, ErrTimeout)
 }
 
-func Test_QueueService_PopTransaction_WhenRedisError_ThenReturnsError(t *testing.T) {
+func Test_QueueService_PopTransaction_WhenNoStrictLane_ThenBlocksOnHeaviestLane(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockRedis := NewMockRedisPopper(ctrl)
-	cmd := redis.NewStringSliceCmd(context.Background())
-	cmd.SetErr(errors.New("redis connection error"))
-	mockRedis.EXPECT().BRPop(gomock.Any(), gomock.Any(), "transaction:queue").Return(cmd)
-	service := NewQueueService(mockRedis, 5*time.Second)
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockRedis.EXPECT().LMove(gomock.Any(), gomock.Any(), gomock.Any(), "RIGHT", "LEFT").Return(stringResult("")).Times(3)
+	mockRedis.EXPECT().BLMove(gomock.Any(), "transaction:queue:backfill", "transaction:queue:backfill:processing:worker-1", "RIGHT", "LEFT", 5*time.Second).
+		Return(stringResult(""))
+	service := NewQueueService(mockRedis, nil, Config{
+		PopTimeout:  5 * time.Second,
+		LaneWeights: map[models.QueueLane]int{models.LaneBackfill: 3},
+	})
+	service.consumerID = "worker-1"
 
-	result, err := service.PopTransaction(context.Background())
+	_, err := service.PopTransaction(context.Background())
 
-	assert.Nil(t, result)
-	assert.Error(t, err)
-	assert.NotErrorIs(t, err, ErrTimeout)
+	assert.ErrorIs(t, err, ErrTimeout)
 }
 
-func Test_QueueService_PopTransaction_WhenInvalidJSON_ThenReturnsErrInvalidData(t *testing.T) {
+func Test_QueueService_PopTransaction_WhenWeightedLanesBusy_ThenAlternatesByWeight(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockRedis := NewMockRedisPopper(ctrl)
-	cmd := redis.NewStringSliceCmd(context.Background())
-	cmd.SetVal([]string{"transaction:queue", "invalid json"})
-	mockRedis.EXPECT().BRPop(gomock.Any(), gomock.Any(), "transaction:queue").Return(cmd)
-	service := NewQueueService(mockRedis, 5*time.Second)
+	eventJSON, _ := json.Marshal(models.Event{"external_id": "ext-123"})
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockRedis.EXPECT().LMove(gomock.Any(), "transaction:decision:queue", gomock.Any(), "RIGHT", "LEFT").Return(stringResult("")).AnyTimes()
+	var popped []string
+	mockRedis.EXPECT().LMove(gomock.Any(), gomock.Not("transaction:decision:queue"), gomock.Any(), "RIGHT", "LEFT").
+		DoAndReturn(func(_ context.Context, source, _, _, _ string) *redis.StringCmd {
+			popped = append(popped, source)
+			return stringResult(string(eventJSON))
+		}).Times(3)
+	service := NewQueueService(mockRedis, nil, Config{
+		PopTimeout:  5 * time.Second,
+		StrictLanes: []models.QueueLane{models.LaneRealtime},
+		LaneWeights: map[models.QueueLane]int{models.LanePostTransaction: 2, models.LaneBackfill: 1},
+	})
+
+	for i := 0; i < 3; i++ {
+		_, err := service.PopTransaction(context.Background())
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"transaction:queue", "transaction:queue:backfill", "transaction:queue"}, popped)
+}
+
+func Test_QueueService_LaneDepths_WhenCalled_ThenReturnsLengthOfEachLane(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockRedis.EXPECT().LLen(gomock.Any(), "transaction:decision:queue").Return(intResult(2))
+	mockRedis.EXPECT().LLen(gomock.Any(), "transaction:queue").Return(intResult(40))
+	mockRedis.EXPECT().LLen(gomock.Any(), "transaction:queue:backfill").Return(intResult(0))
+	service := newTestQueueService(mockRedis)
+
+	depths, err := service.LaneDepths(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, map[models.QueueLane]int64{
+		models.LaneRealtime:        2,
+		models.LanePostTransaction: 40,
+		models.LaneBackfill:        0,
+	}, depths)
+}
+
+func Test_QueueService_PopTransaction_WhenRedisError_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisQueue(ctrl)
+	cmd := redis.NewStringCmd(context.Background())
+	cmd.SetErr(errors.New("redis connection error"))
+	mockRedis.EXPECT().LMove(gomock.Any(), "transaction:decision:queue", gomock.Any(), "RIGHT", "LEFT").Return(cmd)
+	service := newTestQueueService(mockRedis)
 
 	result, err := service.PopTransaction(context.Background())
 
 	assert.Nil(t, result)
-	assert.ErrorIs(t, err, ErrInvalidData)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrTimeout)
 }
 
-func Test_QueueService_PopTransaction_WhenEmptyResult_ThenReturnsErrInvalidData(t *testing.T) {
+func Test_QueueService_PopTransaction_WhenInvalidJSON_ThenDeadLettersItAndReturnsErrInvalidData(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockRedis := NewMockRedisPopper(ctrl)
-	cmd := redis.NewStringSliceCmd(context.Background())
-	cmd.SetVal([]string{})
-	mockRedis.EXPECT().BRPop(gomock.Any(), gomock.Any(), "transaction:queue").Return(cmd)
-	service := NewQueueService(mockRedis, 5*time.Second)
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockDeadLetters := NewMockWriter(ctrl)
+	mockRedis.EXPECT().LMove(gomock.Any(), "transaction:decision:queue", gomock.Any(), "RIGHT", "LEFT").Return(stringResult("invalid json"))
+	gomock.InOrder(
+		mockDeadLetters.EXPECT().AddDeadLetter(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, letter *models.DeadLetter) error {
+			assert.Equal(t, "transaction:decision:queue", letter.Queue)
+			assert.Equal(t, "invalid json", letter.Payload)
+			assert.Equal(t, models.DeadLetterInvalidPayload, letter.Reason)
+			assert.NotEmpty(t, letter.Error)
+			assert.Zero(t, letter.Attempts)
+			assert.False(t, letter.ReceivedAt.IsZero())
+			return nil
+		}),
+		mockRedis.EXPECT().LRem(gomock.Any(), "transaction:decision:queue:processing:worker-1", int64(1), "invalid json").Return(intResult(1)),
+	)
+	service := newTestQueueServiceWithDeadLetters(mockRedis, mockDeadLetters)
 
 	result, err := service.PopTransaction(context.Background())
 
@@ -102,15 +229,16 @@ func Test_QueueService_PopTransaction_WhenEmptyResult_ThenReturnsErrInvalidData(
 	assert.ErrorIs(t, err, ErrInvalidData)
 }
 
-func Test_QueueService_PopTransaction_WhenSingleElement_ThenReturnsErrInvalidData(t *testing.T) {
+func Test_QueueService_PopTransaction_WhenNullPayload_ThenReturnsErrInvalidData(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockRedis := NewMockRedisPopper(ctrl)
-	cmd := redis.NewStringSliceCmd(context.Background())
-	cmd.SetVal([]string{"transaction:queue"})
-	mockRedis.EXPECT().BRPop(gomock.Any(), gomock.Any(), "transaction:queue").Return(cmd)
-	service := NewQueueService(mockRedis, 5*time.Second)
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockDeadLetters := NewMockWriter(ctrl)
+	mockRedis.EXPECT().LMove(gomock.Any(), "transaction:decision:queue", gomock.Any(), "RIGHT", "LEFT").Return(stringResult("null"))
+	mockDeadLetters.EXPECT().AddDeadLetter(gomock.Any(), gomock.Any()).Return(nil)
+	mockRedis.EXPECT().LRem(gomock.Any(), gomock.Any(), int64(1), "null").Return(intResult(1))
+	service := newTestQueueServiceWithDeadLetters(mockRedis, mockDeadLetters)
 
 	result, err := service.PopTransaction(context.Background())
 
@@ -132,16 +260,180 @@ func Test_QueueService_PopTransaction_WhenValidComplexEvent_ThenReturnsEvent(t *
 		},
 	}
 	eventJSON, _ := json.Marshal(event)
-	mockRedis := NewMockRedisPopper(ctrl)
-	cmd := redis.NewStringSliceCmd(context.Background())
-	cmd.SetVal([]string{"transaction:queue", string(eventJSON)})
-	mockRedis.EXPECT().BRPop(gomock.Any(), gomock.Any(), "transaction:queue").Return(cmd)
-	service := NewQueueService(mockRedis, 5*time.Second)
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockRedis.EXPECT().LMove(gomock.Any(), "transaction:decision:queue", gomock.Any(), "RIGHT", "LEFT").Return(stringResult(string(eventJSON)))
+	service := newTestQueueService(mockRedis)
 
 	result, err := service.PopTransaction(context.Background())
 
 	require.NoError(t, err)
 	assert.NotNil(t, result)
-	metadata := (*result)["metadata"].(map[string]any)
+	metadata := result.Event["metadata"].(map[string]any)
 	assert.Equal(t, "api", metadata["source"])
 }
+
+func Test_QueueService_Ack_WhenCalled_ThenRemovesPayloadFromProcessingList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockRedis.EXPECT().LRem(gomock.Any(), "transaction:queue:processing:worker-1", int64(1), `{"external_id":"ext-1"}`).Return(intResult(1))
+	service := newTestQueueService(mockRedis)
+
+	err := service.Ack(context.Background(), &Delivery{Payload: `{"external_id":"ext-1"}`, queue: models.LanePostTransaction.QueueKey()})
+
+	assert.NoError(t, err)
+}
+
+func Test_QueueService_Requeue_WhenCalled_ThenPushesBeforeRemovingFromProcessingList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := `{"external_id":"ext-1"}`
+	mockRedis := NewMockRedisQueue(ctrl)
+	gomock.InOrder(
+		mockRedis.EXPECT().LPush(gomock.Any(), "transaction:queue", payload).Return(intResult(1)),
+		mockRedis.EXPECT().LRem(gomock.Any(), "transaction:queue:processing:worker-1", int64(1), payload).Return(intResult(1)),
+	)
+	service := newTestQueueService(mockRedis)
+
+	err := service.Requeue(context.Background(), &Delivery{Payload: payload, queue: models.LanePostTransaction.QueueKey()})
+
+	assert.NoError(t, err)
+}
+
+func Test_QueueService_Requeue_WhenPushFails_ThenKeepsEventInFlight(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisQueue(ctrl)
+	cmd := redis.NewIntCmd(context.Background())
+	cmd.SetErr(errors.New("redis connection error"))
+	mockRedis.EXPECT().LPush(gomock.Any(), "transaction:queue", gomock.Any()).Return(cmd)
+	service := newTestQueueService(mockRedis)
+
+	err := service.Requeue(context.Background(), &Delivery{Payload: "{}", queue: models.LanePostTransaction.QueueKey()})
+
+	assert.Error(t, err)
+}
+
+func Test_QueueService_PopBacktest_WhenJobAvailable_ThenReturnsID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.New()
+	mockRedis := NewMockRedisQueue(ctrl)
+	cmd := redis.NewStringSliceCmd(context.Background())
+	cmd.SetVal([]string{models.BacktestQueueKey, id.String()})
+	mockRedis.EXPECT().BRPop(gomock.Any(), gomock.Any(), models.BacktestQueueKey).Return(cmd)
+	service := NewQueueService(mockRedis, nil, Config{PopTimeout: 5 * time.Second})
+
+	result, err := service.PopBacktest(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, id, result)
+}
+
+func Test_QueueService_PopBacktest_WhenTimeout_ThenReturnsErrTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisQueue(ctrl)
+	cmd := redis.NewStringSliceCmd(context.Background())
+	cmd.SetErr(redis.Nil)
+	mockRedis.EXPECT().BRPop(gomock.Any(), gomock.Any(), models.BacktestQueueKey).Return(cmd)
+	service := NewQueueService(mockRedis, nil, Config{PopTimeout: 5 * time.Second})
+
+	_, err := service.PopBacktest(context.Background())
+
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func Test_QueueService_PopBacktest_WhenInvalidID_ThenReturnsErrInvalidData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisQueue(ctrl)
+	cmd := redis.NewStringSliceCmd(context.Background())
+	cmd.SetVal([]string{models.BacktestQueueKey, "not-a-uuid"})
+	mockRedis.EXPECT().BRPop(gomock.Any(), gomock.Any(), models.BacktestQueueKey).Return(cmd)
+	service := NewQueueService(mockRedis, nil, Config{PopTimeout: 5 * time.Second})
+
+	_, err := service.PopBacktest(context.Background())
+
+	assert.ErrorIs(t, err, ErrInvalidData)
+}
+
+func Test_QueueService_DeadLetter_WhenStored_ThenRemovesFromProcessingList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := `{"external_id":"ext-1"}`
+	receivedAt := time.Now().Add(-time.Second)
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockDeadLetters := NewMockWriter(ctrl)
+	gomock.InOrder(
+		mockDeadLetters.EXPECT().AddDeadLetter(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, letter *models.DeadLetter) error {
+			assert.Equal(t, "transaction:queue", letter.Queue)
+			assert.Equal(t, "ext-1", letter.ExternalID)
+			assert.Equal(t, payload, letter.Payload)
+			assert.Equal(t, models.DeadLetterProcessingFailed, letter.Reason)
+			assert.Equal(t, "database unavailable", letter.Error)
+			assert.Equal(t, 3, letter.Attempts)
+			assert.Equal(t, receivedAt, letter.ReceivedAt)
+			assert.True(t, letter.FailedAt.After(receivedAt))
+			return nil
+		}),
+		mockRedis.EXPECT().LRem(gomock.Any(), "transaction:queue:processing:worker-1", int64(1), payload).Return(intResult(1)),
+	)
+	service := newTestQueueServiceWithDeadLetters(mockRedis, mockDeadLetters)
+	delivery := &Delivery{
+		Event:      models.Event{"external_id": "ext-1"},
+		Payload:    payload,
+		ReceivedAt: receivedAt,
+		queue:      models.LanePostTransaction.QueueKey(),
+	}
+
+	err := service.DeadLetter(context.Background(), delivery, models.DeadLetterProcessingFailed, errors.New("database unavailable"), 3)
+
+	assert.NoError(t, err)
+}
+
+func Test_QueueService_DeadLetter_WhenStoreFails_ThenRequeuesEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := `{"external_id":"ext-1"}`
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockDeadLetters := NewMockWriter(ctrl)
+	gomock.InOrder(
+		mockDeadLetters.EXPECT().AddDeadLetter(gomock.Any(), gomock.Any()).Return(errors.New("database unavailable")),
+		mockRedis.EXPECT().LPush(gomock.Any(), "transaction:queue", payload).Return(intResult(1)),
+		mockRedis.EXPECT().LRem(gomock.Any(), "transaction:queue:processing:worker-1", int64(1), payload).Return(intResult(1)),
+	)
+	service := newTestQueueServiceWithDeadLetters(mockRedis, mockDeadLetters)
+
+	err := service.DeadLetter(context.Background(), &Delivery{Payload: payload, queue: models.LanePostTransaction.QueueKey()},
+		models.DeadLetterProcessingFailed, errors.New("timeout"), 3)
+
+	assert.Error(t, err)
+}
+
+func Test_QueueService_DeadLetter_WhenPayloadHasNulBytes_ThenStoresSanitizedPayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisQueue(ctrl)
+	mockDeadLetters := NewMockWriter(ctrl)
+	mockDeadLetters.EXPECT().AddDeadLetter(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, letter *models.DeadLetter) error {
+		assert.Equal(t, "ab\uFFFD", letter.Payload)
+		return nil
+	})
+	mockRedis.EXPECT().LRem(gomock.Any(), gomock.Any(), int64(1), "a\x00b\xff").Return(intResult(1))
+	service := newTestQueueServiceWithDeadLetters(mockRedis, mockDeadLetters)
+
+	err := service.DeadLetter(context.Background(), &Delivery{Payload: "a\x00b\xff", queue: models.LanePostTransaction.QueueKey()},
+		models.DeadLetterInvalidPayload, errors.New("invalid character"), 0)
+
+	assert.NoError(t, err)
+}
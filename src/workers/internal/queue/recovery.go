@@ -0,0 +1,147 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// consumersKey is the set of consumers that may own processing lists
+	consumersKey = "transaction:consumers"
+	// heartbeatKeyPrefix prefixes the key a consumer refreshes while it is alive
+	heartbeatKeyPrefix = "transaction:consumer:"
+)
+
+// newConsumerID returns an ID unique to this process, readable in Redis
+func newConsumerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+}
+
+// processingKey returns the list holding the events a consumer took from a queue
+func processingKey(source, consumerID string) string {
+	return source + ":processing:" + consumerID
+}
+
+// heartbeatKey returns the key that expires when a consumer stops refreshing it
+func heartbeatKey(consumerID string) string {
+	return heartbeatKeyPrefix + consumerID
+}
+
+// Register announces this consumer so its in-flight events are recovered if it stops
+// Must be called before the first PopTransaction
+func (q *QueueService) Register(ctx context.Context) error {
+	// The heartbeat exists before the consumer is listed, so it is never taken for stopped
+	if err := q.redis.Set(ctx, heartbeatKey(q.consumerID), time.Now().Unix(), q.cfg.ConsumerTimeout).Err(); err != nil {
+		return err
+	}
+	return q.redis.SAdd(ctx, consumersKey, q.consumerID).Err()
+}
+
+// Start refreshes the heartbeat and recovers the events of stopped consumers
+// until the context is cancelled
+// This is a blocking function that should be called in a goroutine managed by errgroup
+func (q *QueueService) Start(ctx context.Context) {
+	if q.cfg.HeartbeatInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(q.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Registering again also restores a consumer another worker took for stopped
+			if err := q.Register(ctx); err != nil {
+				log.Printf("Failed to refresh queue heartbeat: %v", err)
+			}
+			if err := q.RecoverStopped(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to recover in-flight events: %v", err)
+			}
+		}
+	}
+}
+
+// RecoverStopped moves the in-flight events of consumers whose heartbeat expired back
+// to their queues and forgets those consumers
+// Safe to run from several workers at once: each event is moved atomically by one of them
+func (q *QueueService) RecoverStopped(ctx context.Context) error {
+	consumers, err := q.redis.SMembers(ctx, consumersKey).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, consumerID := range consumers {
+		if consumerID == q.consumerID {
+			continue
+		}
+
+		alive, err := q.redis.Exists(ctx, heartbeatKey(consumerID)).Result()
+		if err != nil {
+			return err
+		}
+		if alive > 0 {
+			continue
+		}
+
+		moved, err := q.requeueInFlight(ctx, consumerID)
+		if err != nil {
+			return err
+		}
+		if err := q.redis.SRem(ctx, consumersKey, consumerID).Err(); err != nil {
+			return err
+		}
+
+		log.Printf("Requeued %d in-flight events of stopped worker %s", moved, consumerID)
+	}
+
+	return nil
+}
+
+// Release moves the events still in flight back to their queues and unregisters
+// Called on shutdown once every worker goroutine has stopped
+func (q *QueueService) Release(ctx context.Context) error {
+	moved, err := q.requeueInFlight(ctx, q.consumerID)
+	if err != nil {
+		return err
+	}
+	if moved > 0 {
+		log.Printf("Requeued %d in-flight events on shutdown", moved)
+	}
+
+	if err := q.redis.SRem(ctx, consumersKey, q.consumerID).Err(); err != nil {
+		return err
+	}
+	return q.redis.Del(ctx, heartbeatKey(q.consumerID)).Err()
+}
+
+// requeueInFlight empties a consumer's processing lists into the front of their queues
+// The oldest events end up popped first
+func (q *QueueService) requeueInFlight(ctx context.Context, consumerID string) (int, error) {
+	moved := 0
+	for _, source := range laneQueues() {
+		for {
+			err := q.redis.LMove(ctx, processingKey(source, consumerID), source, "LEFT", "RIGHT").Err()
+			if err == redis.Nil {
+				break
+			}
+			if err != nil {
+				return moved, err
+			}
+			moved++
+		}
+	}
+	return moved, nil
+}
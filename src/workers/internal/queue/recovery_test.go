@@ -0,0 +1,103 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func statusResult() *redis.StatusCmd {
+	cmd := redis.NewStatusCmd(context.Background())
+	cmd.SetVal("OK")
+	return cmd
+}
+
+func Test_QueueService_Register_WhenCalled_ThenSetsHeartbeatBeforeListingConsumer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisQueue(ctrl)
+	gomock.InOrder(
+		mockRedis.EXPECT().Set(gomock.Any(), "transaction:consumer:worker-1", gomock.Any(), 30*time.Second).Return(statusResult()),
+		mockRedis.EXPECT().SAdd(gomock.Any(), "transaction:consumers", "worker-1").Return(intResult(1)),
+	)
+	service := NewQueueService(mockRedis, nil, Config{ConsumerTimeout: 30 * time.Second})
+	service.consumerID = "worker-1"
+
+	err := service.Register(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func Test_QueueService_RecoverStopped_WhenHeartbeatExpired_ThenRequeuesInFlightEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisQueue(ctrl)
+	members := redis.NewStringSliceCmd(context.Background())
+	members.SetVal([]string{"worker-1", "worker-2", "worker-3"})
+	mockRedis.EXPECT().SMembers(gomock.Any(), "transaction:consumers").Return(members)
+	mockRedis.EXPECT().Exists(gomock.Any(), "transaction:consumer:worker-2").Return(intResult(1))
+	mockRedis.EXPECT().Exists(gomock.Any(), "transaction:consumer:worker-3").Return(intResult(0))
+	gomock.InOrder(
+		mockRedis.EXPECT().LMove(gomock.Any(), "transaction:decision:queue:processing:worker-3", "transaction:decision:queue", "LEFT", "RIGHT").
+			Return(stringResult("")),
+		mockRedis.EXPECT().LMove(gomock.Any(), "transaction:queue:processing:worker-3", "transaction:queue", "LEFT", "RIGHT").
+			Return(stringResult(`{"external_id":"ext-2"}`)),
+		mockRedis.EXPECT().LMove(gomock.Any(), "transaction:queue:processing:worker-3", "transaction:queue", "LEFT", "RIGHT").
+			Return(stringResult(`{"external_id":"ext-1"}`)),
+		mockRedis.EXPECT().LMove(gomock.Any(), "transaction:queue:processing:worker-3", "transaction:queue", "LEFT", "RIGHT").
+			Return(stringResult("")),
+		mockRedis.EXPECT().LMove(gomock.Any(), "transaction:queue:backfill:processing:worker-3", "transaction:queue:backfill", "LEFT", "RIGHT").
+			Return(stringResult("")),
+		mockRedis.EXPECT().SRem(gomock.Any(), "transaction:consumers", "worker-3").Return(intResult(1)),
+	)
+	service := newTestQueueService(mockRedis)
+
+	err := service.RecoverStopped(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func Test_QueueService_RecoverStopped_WhenOnlyItselfRegistered_ThenMovesNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisQueue(ctrl)
+	members := redis.NewStringSliceCmd(context.Background())
+	members.SetVal([]string{"worker-1"})
+	mockRedis.EXPECT().SMembers(gomock.Any(), "transaction:consumers").Return(members)
+	service := newTestQueueService(mockRedis)
+
+	err := service.RecoverStopped(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func Test_QueueService_Release_WhenEventsInFlight_ThenRequeuesThemAndUnregisters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisQueue(ctrl)
+	gomock.InOrder(
+		mockRedis.EXPECT().LMove(gomock.Any(), "transaction:decision:queue:processing:worker-1", "transaction:decision:queue", "LEFT", "RIGHT").
+			Return(stringResult(`{"external_id":"ext-1"}`)),
+		mockRedis.EXPECT().LMove(gomock.Any(), "transaction:decision:queue:processing:worker-1", "transaction:decision:queue", "LEFT", "RIGHT").
+			Return(stringResult("")),
+		mockRedis.EXPECT().LMove(gomock.Any(), "transaction:queue:processing:worker-1", "transaction:queue", "LEFT", "RIGHT").
+			Return(stringResult("")),
+		mockRedis.EXPECT().LMove(gomock.Any(), "transaction:queue:backfill:processing:worker-1", "transaction:queue:backfill", "LEFT", "RIGHT").
+			Return(stringResult("")),
+		mockRedis.EXPECT().SRem(gomock.Any(), "transaction:consumers", "worker-1").Return(intResult(1)),
+		mockRedis.EXPECT().Del(gomock.Any(), "transaction:consumer:worker-1").Return(intResult(1)),
+	)
+	service := newTestQueueService(mockRedis)
+
+	err := service.Release(context.Background())
+
+	assert.NoError(t, err)
+}
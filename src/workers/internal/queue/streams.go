@@ -0,0 +1,269 @@
+package queue
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/deadletters"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// streamGroup is the consumer group shared by every worker
+	streamGroup = "algo-shield-workers"
+	// streamRecoveryBatch bounds the entries claimed or released per command
+	streamRecoveryBatch = 100
+)
+
+// RedisStreams defines the Redis stream operations used by StreamConsumer
+type RedisStreams interface {
+	XGroupCreateMkStream(ctx context.Context, stream, group, start string) *redis.StatusCmd
+	XReadGroup(ctx context.Context, a *redis.XReadGroupArgs) *redis.XStreamSliceCmd
+	XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd
+	XAck(ctx context.Context, stream, group string, ids ...string) *redis.IntCmd
+	XDel(ctx context.Context, stream string, ids ...string) *redis.IntCmd
+	XAutoClaim(ctx context.Context, a *redis.XAutoClaimArgs) *redis.XAutoClaimCmd
+	XLen(ctx context.Context, stream string) *redis.IntCmd
+	XPending(ctx context.Context, stream, group string) *redis.XPendingCmd
+}
+
+// StreamConsumer is the Consumer of events added to Redis streams, one per lane
+// Workers share a consumer group, so each entry is delivered to one of them and stays
+// pending until acknowledged. Entries pending longer than the consumer timeout, such as
+// those of a crashed worker, are claimed by another worker and added back to their stream.
+type StreamConsumer struct {
+	redis       RedisStreams
+	deadLetters deadletters.Writer
+	cfg         Config
+	lanes       *laneScheduler
+	consumerID  string
+}
+
+// NewStreamConsumer creates a stream consumer with a unique consumer name
+func NewStreamConsumer(redis RedisStreams, deadLetters deadletters.Writer, cfg Config) *StreamConsumer {
+	return &StreamConsumer{
+		redis:       redis,
+		deadLetters: deadLetters,
+		cfg:         cfg,
+		lanes:       newLaneScheduler(cfg.StrictLanes, cfg.LaneWeights, models.QueueLane.StreamKey),
+		consumerID:  newConsumerID(),
+	}
+}
+
+// Register creates the lane streams and their consumer group when they do not exist yet
+func (s *StreamConsumer) Register(ctx context.Context) error {
+	for _, lane := range models.QueueLanes {
+		err := s.redis.XGroupCreateMkStream(ctx, lane.StreamKey(), streamGroup, "0").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start recovers the entries left pending by stopped workers until the context is cancelled
+// This is a blocking function that should be called in a goroutine managed by errgroup
+func (s *StreamConsumer) Start(ctx context.Context) {
+	if s.cfg.HeartbeatInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RecoverStopped(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to recover pending stream entries: %v", err)
+			}
+		}
+	}
+}
+
+// RecoverStopped adds the entries pending longer than the consumer timeout back to their stream
+// Safe to run from several workers at once: each entry is claimed by one of them
+func (s *StreamConsumer) RecoverStopped(ctx context.Context) error {
+	moved := 0
+	for _, lane := range models.QueueLanes {
+		stream := lane.StreamKey()
+		start := "0-0"
+		for {
+			messages, next, err := s.redis.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+				Stream:   stream,
+				Group:    streamGroup,
+				Consumer: s.consumerID,
+				MinIdle:  s.cfg.ConsumerTimeout,
+				Start:    start,
+				Count:    streamRecoveryBatch,
+			}).Result()
+			if err != nil {
+				return err
+			}
+
+			for _, message := range messages {
+				if err := s.Requeue(ctx, s.delivery(stream, message)); err != nil {
+					return err
+				}
+				moved++
+			}
+
+			if next == "0-0" || len(messages) == 0 {
+				break
+			}
+			start = next
+		}
+	}
+
+	if moved > 0 {
+		log.Printf("Requeued %d pending stream entries of stopped workers", moved)
+	}
+	return nil
+}
+
+// Release adds the entries this worker still has pending back to their stream
+// Called on shutdown once every worker goroutine has stopped
+func (s *StreamConsumer) Release(ctx context.Context) error {
+	moved := 0
+	for _, lane := range models.QueueLanes {
+		stream := lane.StreamKey()
+		for {
+			// Reading from ID 0 returns this consumer's pending entries instead of new ones
+			streams, err := s.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+				Group:    streamGroup,
+				Consumer: s.consumerID,
+				Streams:  []string{stream, "0"},
+				Count:    streamRecoveryBatch,
+				Block:    -1,
+			}).Result()
+			if err != nil && err != redis.Nil {
+				return err
+			}
+			if len(streams) == 0 || len(streams[0].Messages) == 0 {
+				break
+			}
+
+			for _, message := range streams[0].Messages {
+				if err := s.Requeue(ctx, s.delivery(stream, message)); err != nil {
+					return err
+				}
+				moved++
+			}
+		}
+	}
+
+	if moved > 0 {
+		log.Printf("Requeued %d in-flight events on shutdown", moved)
+	}
+	return nil
+}
+
+// PopTransaction reads the next entry for this worker
+// Strict lanes are read first, then the weighted lanes share the reads by weight.
+// When every lane is empty it blocks on the first strict lane, or the heaviest weighted one.
+func (s *StreamConsumer) PopTransaction(ctx context.Context) (*Delivery, error) {
+	var empty []string
+	for _, stream := range s.lanes.order() {
+		delivery, err := s.read(ctx, stream, -1)
+		if err == nil {
+			s.lanes.served(stream, empty)
+			return decode(ctx, s, delivery)
+		}
+		if err != redis.Nil {
+			return nil, err
+		}
+		empty = append(empty, stream)
+	}
+
+	delivery, err := s.read(ctx, s.lanes.blockingQueue(), s.cfg.PopTimeout)
+	if err != nil {
+		if err == redis.Nil {
+			return nil, ErrTimeout
+		}
+		return nil, err
+	}
+
+	return decode(ctx, s, delivery)
+}
+
+// read takes one new entry of a stream, blocking up to block when it is not negative
+// Returns redis.Nil when the stream has no new entry
+func (s *StreamConsumer) read(ctx context.Context, stream string, block time.Duration) (*Delivery, error) {
+	streams, err := s.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    streamGroup,
+		Consumer: s.consumerID,
+		Streams:  []string{stream, ">"},
+		Count:    1,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(streams) == 0 || len(streams[0].Messages) == 0 {
+		return nil, redis.Nil
+	}
+
+	return s.delivery(stream, streams[0].Messages[0]), nil
+}
+
+// delivery wraps a stream entry; entries without a payload field fail to decode
+func (s *StreamConsumer) delivery(stream string, message redis.XMessage) *Delivery {
+	payload, _ := message.Values[models.StreamPayloadField].(string)
+	return &Delivery{Payload: payload, ReceivedAt: time.Now(), queue: stream, ref: message.ID}
+}
+
+// Ack acknowledges an entry and deletes it, so streams only hold waiting and pending entries
+func (s *StreamConsumer) Ack(ctx context.Context, delivery *Delivery) error {
+	id, _ := delivery.ref.(string)
+	if err := s.redis.XAck(ctx, delivery.queue, streamGroup, id).Err(); err != nil {
+		return err
+	}
+	return s.redis.XDel(ctx, delivery.queue, id).Err()
+}
+
+// Requeue adds a delivery back at the end of its stream, then acknowledges the original entry
+// A failure in between delivers it twice rather than never
+func (s *StreamConsumer) Requeue(ctx context.Context, delivery *Delivery) error {
+	err := s.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: delivery.queue,
+		Values: map[string]any{models.StreamPayloadField: delivery.Payload},
+	}).Err()
+	if err != nil {
+		return err
+	}
+	return s.Ack(ctx, delivery)
+}
+
+// DeadLetter stores a delivery the worker gave up on, then acknowledges it
+// When it cannot be stored it is requeued instead, so it is never lost
+func (s *StreamConsumer) DeadLetter(ctx context.Context, delivery *Delivery, reason models.DeadLetterReason, cause error, attempts int) error {
+	return deadLetter(ctx, s, s.deadLetters, delivery, reason, cause, attempts)
+}
+
+// LaneDepths returns the number of entries waiting in each lane's stream
+// Acknowledged entries are deleted, so the waiting ones are those not pending
+func (s *StreamConsumer) LaneDepths(ctx context.Context) (map[models.QueueLane]int64, error) {
+	depths := make(map[models.QueueLane]int64, len(models.QueueLanes))
+	for _, lane := range models.QueueLanes {
+		length, err := s.redis.XLen(ctx, lane.StreamKey()).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		pending, err := s.redis.XPending(ctx, lane.StreamKey(), streamGroup).Result()
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+		if pending != nil {
+			length -= pending.Count
+		}
+
+		depths[lane] = max(length, 0)
+	}
+	return depths, nil
+}
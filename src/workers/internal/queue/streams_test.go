@@ -0,0 +1,240 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// streamResult builds an XREADGROUP result holding the entries of one stream, or redis.Nil when empty
+func streamResult(stream string, messages ...redis.XMessage) *redis.XStreamSliceCmd {
+	cmd := redis.NewXStreamSliceCmd(context.Background())
+	if len(messages) == 0 {
+		cmd.SetErr(redis.Nil)
+	} else {
+		cmd.SetVal([]redis.XStream{{Stream: stream, Messages: messages}})
+	}
+	return cmd
+}
+
+func streamEntry(id, payload string) redis.XMessage {
+	return redis.XMessage{ID: id, Values: map[string]any{models.StreamPayloadField: payload}}
+}
+
+// readGroupArgs matches the XREADGROUP arguments of one stream and blocking mode
+func readGroupArgs(stream, start string, block time.Duration) gomock.Matcher {
+	return gomock.Cond(func(a any) bool {
+		args := a.(*redis.XReadGroupArgs)
+		return args.Group == streamGroup && args.Consumer == "worker-1" &&
+			args.Streams[0] == stream && args.Streams[1] == start && args.Block == block
+	})
+}
+
+func newTestStreamConsumer(mockRedis RedisStreams, deadLetters *MockWriter) *StreamConsumer {
+	consumer := NewStreamConsumer(mockRedis, deadLetters, Config{
+		PopTimeout:      5 * time.Second,
+		ConsumerTimeout: 30 * time.Second,
+		StrictLanes:     []models.QueueLane{models.LaneRealtime},
+	})
+	consumer.consumerID = "worker-1"
+	return consumer
+}
+
+func Test_StreamConsumer_Register_WhenGroupExists_ThenIgnoresBusyGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	busy := redis.NewStatusCmd(context.Background())
+	busy.SetErr(errors.New("BUSYGROUP Consumer Group name already exists"))
+	mockRedis := NewMockRedisStreams(ctrl)
+	for _, lane := range models.QueueLanes {
+		mockRedis.EXPECT().XGroupCreateMkStream(gomock.Any(), lane.StreamKey(), streamGroup, "0").Return(busy)
+	}
+	consumer := newTestStreamConsumer(mockRedis, nil)
+
+	err := consumer.Register(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func Test_StreamConsumer_PopTransaction_WhenEventAvailable_ThenReadsLanesInOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := `{"external_id":"ext-123"}`
+	mockRedis := NewMockRedisStreams(ctrl)
+	gomock.InOrder(
+		mockRedis.EXPECT().XReadGroup(gomock.Any(), readGroupArgs("transaction:stream:realtime", ">", -1)).
+			Return(streamResult("transaction:stream:realtime")),
+		mockRedis.EXPECT().XReadGroup(gomock.Any(), readGroupArgs("transaction:stream:post_transaction", ">", -1)).
+			Return(streamResult("transaction:stream:post_transaction", streamEntry("1-0", payload))),
+	)
+	consumer := newTestStreamConsumer(mockRedis, nil)
+
+	result, err := consumer.PopTransaction(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "ext-123", result.Event["external_id"])
+	assert.Equal(t, "transaction:stream:post_transaction", result.queue)
+	assert.Equal(t, "1-0", result.ref)
+}
+
+func Test_StreamConsumer_PopTransaction_WhenStreamsEmpty_ThenBlocksOnStrictLaneAndTimesOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisStreams(ctrl)
+	mockRedis.EXPECT().XReadGroup(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, args *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+		assert.Equal(t, time.Duration(-1), args.Block)
+		return streamResult(args.Streams[0])
+	}).Times(len(models.QueueLanes))
+	mockRedis.EXPECT().XReadGroup(gomock.Any(), readGroupArgs("transaction:stream:realtime", ">", 5*time.Second)).
+		Return(streamResult("transaction:stream:realtime"))
+	consumer := newTestStreamConsumer(mockRedis, nil)
+
+	_, err := consumer.PopTransaction(context.Background())
+
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func Test_StreamConsumer_PopTransaction_WhenEntryHasNoPayload_ThenDeadLettersIt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisStreams(ctrl)
+	mockDeadLetters := NewMockWriter(ctrl)
+	mockRedis.EXPECT().XReadGroup(gomock.Any(), readGroupArgs("transaction:stream:realtime", ">", -1)).
+		Return(streamResult("transaction:stream:realtime", redis.XMessage{ID: "1-0", Values: map[string]any{"data": "x"}}))
+	gomock.InOrder(
+		mockDeadLetters.EXPECT().AddDeadLetter(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, letter *models.DeadLetter) error {
+			assert.Equal(t, "transaction:stream:realtime", letter.Queue)
+			assert.Equal(t, models.DeadLetterInvalidPayload, letter.Reason)
+			return nil
+		}),
+		mockRedis.EXPECT().XAck(gomock.Any(), "transaction:stream:realtime", streamGroup, "1-0").Return(intResult(1)),
+		mockRedis.EXPECT().XDel(gomock.Any(), "transaction:stream:realtime", "1-0").Return(intResult(1)),
+	)
+	consumer := newTestStreamConsumer(mockRedis, mockDeadLetters)
+
+	_, err := consumer.PopTransaction(context.Background())
+
+	assert.ErrorIs(t, err, ErrInvalidData)
+}
+
+func Test_StreamConsumer_Requeue_WhenCalled_ThenAddsEntryBeforeAcknowledgingOriginal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := `{"external_id":"ext-1"}`
+	mockRedis := NewMockRedisStreams(ctrl)
+	gomock.InOrder(
+		mockRedis.EXPECT().XAdd(gomock.Any(), &redis.XAddArgs{
+			Stream: "transaction:stream:backfill",
+			Values: map[string]any{models.StreamPayloadField: payload},
+		}).Return(stringResult("2-0")),
+		mockRedis.EXPECT().XAck(gomock.Any(), "transaction:stream:backfill", streamGroup, "1-0").Return(intResult(1)),
+		mockRedis.EXPECT().XDel(gomock.Any(), "transaction:stream:backfill", "1-0").Return(intResult(1)),
+	)
+	consumer := newTestStreamConsumer(mockRedis, nil)
+
+	err := consumer.Requeue(context.Background(), &Delivery{Payload: payload, queue: "transaction:stream:backfill", ref: "1-0"})
+
+	assert.NoError(t, err)
+}
+
+func Test_StreamConsumer_Requeue_WhenAddFails_ThenKeepsEntryPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failed := redis.NewStringCmd(context.Background())
+	failed.SetErr(errors.New("connection refused"))
+	mockRedis := NewMockRedisStreams(ctrl)
+	mockRedis.EXPECT().XAdd(gomock.Any(), gomock.Any()).Return(failed)
+	consumer := newTestStreamConsumer(mockRedis, nil)
+
+	err := consumer.Requeue(context.Background(), &Delivery{Payload: "{}", queue: "transaction:stream:backfill", ref: "1-0"})
+
+	assert.Error(t, err)
+}
+
+func Test_StreamConsumer_RecoverStopped_WhenEntriesIdle_ThenRequeuesThem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := `{"external_id":"ext-1"}`
+	mockRedis := NewMockRedisStreams(ctrl)
+	mockRedis.EXPECT().XAutoClaim(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, args *redis.XAutoClaimArgs) *redis.XAutoClaimCmd {
+		assert.Equal(t, 30*time.Second, args.MinIdle)
+		assert.Equal(t, "worker-1", args.Consumer)
+		cmd := redis.NewXAutoClaimCmd(context.Background())
+		if args.Stream == "transaction:stream:post_transaction" {
+			cmd.SetVal([]redis.XMessage{streamEntry("1-0", payload)}, "0-0")
+		} else {
+			cmd.SetVal(nil, "0-0")
+		}
+		return cmd
+	}).Times(len(models.QueueLanes))
+	gomock.InOrder(
+		mockRedis.EXPECT().XAdd(gomock.Any(), gomock.Any()).Return(stringResult("2-0")),
+		mockRedis.EXPECT().XAck(gomock.Any(), "transaction:stream:post_transaction", streamGroup, "1-0").Return(intResult(1)),
+		mockRedis.EXPECT().XDel(gomock.Any(), "transaction:stream:post_transaction", "1-0").Return(intResult(1)),
+	)
+	consumer := newTestStreamConsumer(mockRedis, nil)
+
+	err := consumer.RecoverStopped(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func Test_StreamConsumer_Release_WhenEntriesPending_ThenRequeuesThem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := `{"external_id":"ext-1"}`
+	mockRedis := NewMockRedisStreams(ctrl)
+	gomock.InOrder(
+		mockRedis.EXPECT().XReadGroup(gomock.Any(), readGroupArgs("transaction:stream:realtime", "0", -1)).
+			Return(streamResult("transaction:stream:realtime", streamEntry("1-0", payload))),
+		mockRedis.EXPECT().XAdd(gomock.Any(), gomock.Any()).Return(stringResult("2-0")),
+		mockRedis.EXPECT().XAck(gomock.Any(), "transaction:stream:realtime", streamGroup, "1-0").Return(intResult(1)),
+		mockRedis.EXPECT().XDel(gomock.Any(), "transaction:stream:realtime", "1-0").Return(intResult(1)),
+		mockRedis.EXPECT().XReadGroup(gomock.Any(), readGroupArgs("transaction:stream:realtime", "0", -1)).
+			Return(streamResult("transaction:stream:realtime")),
+	)
+	mockRedis.EXPECT().XReadGroup(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, args *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+		return streamResult(args.Streams[0])
+	}).Times(len(models.QueueLanes) - 1)
+	consumer := newTestStreamConsumer(mockRedis, nil)
+
+	err := consumer.Release(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func Test_StreamConsumer_LaneDepths_WhenEntriesPending_ThenExcludesThem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := NewMockRedisStreams(ctrl)
+	for _, lane := range models.QueueLanes {
+		mockRedis.EXPECT().XLen(gomock.Any(), lane.StreamKey()).Return(intResult(5))
+		pending := redis.NewXPendingCmd(context.Background())
+		pending.SetVal(&redis.XPending{Count: 2})
+		mockRedis.EXPECT().XPending(gomock.Any(), lane.StreamKey(), streamGroup).Return(pending)
+	}
+	consumer := newTestStreamConsumer(mockRedis, nil)
+
+	depths, err := consumer.LaneDepths(context.Background())
+
+	require.NoError(t, err)
+	for _, lane := range models.QueueLanes {
+		assert.Equal(t, int64(3), depths[lane])
+	}
+}
@@ -0,0 +1,65 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/algo-shield/algo-shield/src/workers/internal/schemas"
+	"github.com/expr-lang/expr/vm"
+)
+
+var (
+	// ErrMissingExpression is returned when a rule has no custom_expression condition
+	ErrMissingExpression = errors.New("rule missing or invalid custom_expression condition")
+	// ErrMissingSchemaID is returned when a rule is not associated with a schema
+	ErrMissingSchemaID = errors.New("rule missing schema_id")
+	// ErrSchemaNotFound is returned when a rule references a schema that is not loaded
+	ErrSchemaNotFound = errors.New("schema not found")
+)
+
+// CompiledRule pairs a rule with its precompiled expression program
+// A rule that failed to compile keeps the error in Err and never matches
+type CompiledRule struct {
+	Rule    models.Rule
+	Schema  *schemas.EventSchema
+	Program *vm.Program
+	Err     error
+}
+
+// compileRule compiles a rule's custom_expression against its schema's typed environment,
+// inlining the macros it references
+func compileRule(rule models.Rule, provider SchemaProvider, macros *expressions.Macros) CompiledRule {
+	compiled := CompiledRule{Rule: rule}
+
+	expression, ok := rule.Conditions["custom_expression"].(string)
+	if !ok {
+		compiled.Err = ErrMissingExpression
+		return compiled
+	}
+
+	if rule.SchemaID == nil {
+		compiled.Err = ErrMissingSchemaID
+		return compiled
+	}
+
+	var schema *schemas.EventSchema
+	if provider != nil {
+		schema = provider.GetSchema(*rule.SchemaID)
+	}
+	if schema == nil {
+		compiled.Err = fmt.Errorf("%w: %s", ErrSchemaNotFound, *rule.SchemaID)
+		return compiled
+	}
+	compiled.Schema = schema
+
+	program, err := schemas.CompileExpressionWithMacros(expression, schema, macros)
+	if err != nil {
+		compiled.Err = fmt.Errorf("expression compile error: %w", err)
+		return compiled
+	}
+	compiled.Program = program
+
+	return compiled
+}
@@ -0,0 +1,78 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+)
+
+// ConflictPolicy selects which matched rule decides the outcome when several rules match
+type ConflictPolicy string
+
+const (
+	// PolicyFirstMatch lets the first matched rule in evaluation order (priority ASC) decide
+	PolicyFirstMatch ConflictPolicy = "first_match"
+	// PolicyMostSevere lets the most severe action decide (block > review > allow),
+	// ties are resolved by evaluation order
+	PolicyMostSevere ConflictPolicy = "most_severe"
+	// PolicyHighestPriority lets the matched rule with the highest priority (lowest number) decide,
+	// ties are resolved by the most severe action
+	PolicyHighestPriority ConflictPolicy = "highest_priority"
+)
+
+// ParseConflictPolicy parses a conflict policy name
+// An empty name defaults to PolicyMostSevere
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	switch ConflictPolicy(name) {
+	case "":
+		return PolicyMostSevere, nil
+	case PolicyFirstMatch, PolicyMostSevere, PolicyHighestPriority:
+		return ConflictPolicy(name), nil
+	default:
+		return "", fmt.Errorf("unknown conflict policy %q (expected first_match, most_severe or highest_priority)", name)
+	}
+}
+
+// Resolve returns the rule that decides the outcome among matched rules
+// matched must be in evaluation order; returns nil when nothing matched
+func (p ConflictPolicy) Resolve(matched []*CompiledRule) *CompiledRule {
+	if len(matched) == 0 {
+		return nil
+	}
+
+	decider := matched[0]
+	if p == PolicyFirstMatch {
+		return decider
+	}
+
+	for _, rule := range matched[1:] {
+		severity := statusSeverity(actionStatus(rule.Rule.Action))
+		deciderSeverity := statusSeverity(actionStatus(decider.Rule.Action))
+
+		switch p {
+		case PolicyHighestPriority:
+			if rule.Rule.Priority < decider.Rule.Priority ||
+				(rule.Rule.Priority == decider.Rule.Priority && severity > deciderSeverity) {
+				decider = rule
+			}
+		default:
+			if severity > deciderSeverity {
+				decider = rule
+			}
+		}
+	}
+
+	return decider
+}
+
+// actionStatus maps a rule action to the transaction status it produces
+func actionStatus(action models.RuleAction) models.TransactionStatus {
+	switch action {
+	case models.ActionBlock:
+		return models.StatusRejected
+	case models.ActionReview:
+		return models.StatusInReview
+	default:
+		return models.StatusApproved
+	}
+}
@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseConflictPolicy_WhenValidName_ThenReturnsPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected ConflictPolicy
+	}{
+		{name: "empty defaults to most severe", input: "", expected: PolicyMostSevere},
+		{name: "first match", input: "first_match", expected: PolicyFirstMatch},
+		{name: "most severe", input: "most_severe", expected: PolicyMostSevere},
+		{name: "highest priority", input: "highest_priority", expected: PolicyHighestPriority},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParseConflictPolicy(tt.input)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, policy)
+		})
+	}
+}
+
+func Test_ParseConflictPolicy_WhenUnknownName_ThenReturnsError(t *testing.T) {
+	_, err := ParseConflictPolicy("last_match")
+
+	assert.Error(t, err)
+}
+
+func Test_ConflictPolicy_Resolve_WhenNoMatches_ThenReturnsNil(t *testing.T) {
+	assert.Nil(t, PolicyMostSevere.Resolve(nil))
+}
+
+func Test_ConflictPolicy_Resolve_WhenRulesConflict_ThenAppliesPolicy(t *testing.T) {
+	allow := &CompiledRule{Rule: models.Rule{Name: "allow", Action: models.ActionAllow, Priority: 1}}
+	review := &CompiledRule{Rule: models.Rule{Name: "review", Action: models.ActionReview, Priority: 5}}
+	block := &CompiledRule{Rule: models.Rule{Name: "block", Action: models.ActionBlock, Priority: 5}}
+	matched := []*CompiledRule{allow, review, block}
+
+	tests := []struct {
+		name     string
+		policy   ConflictPolicy
+		expected *CompiledRule
+	}{
+		{name: "first match", policy: PolicyFirstMatch, expected: allow},
+		{name: "most severe", policy: PolicyMostSevere, expected: block},
+		{name: "highest priority", policy: PolicyHighestPriority, expected: allow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Same(t, tt.expected, tt.policy.Resolve(matched))
+		})
+	}
+}
+
+func Test_ConflictPolicy_Resolve_WhenHighestPriorityTie_ThenMostSevereWins(t *testing.T) {
+	review := &CompiledRule{Rule: models.Rule{Name: "review", Action: models.ActionReview, Priority: 5}}
+	block := &CompiledRule{Rule: models.Rule{Name: "block", Action: models.ActionBlock, Priority: 5}}
+
+	decider := PolicyHighestPriority.Resolve([]*CompiledRule{review, block})
+
+	assert.Same(t, block, decider)
+}
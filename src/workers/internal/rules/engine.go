@@ -5,49 +5,184 @@ import (
 	"log"
 	"time"
 
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	geopkg "github.com/algo-shield/algo-shield/src/pkg/geo"
+	listspkg "github.com/algo-shield/algo-shield/src/pkg/lists"
+	macrospkg "github.com/algo-shield/algo-shield/src/pkg/macros"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/algo-shield/algo-shield/src/pkg/rules"
+	"github.com/algo-shield/algo-shield/src/pkg/sanctions"
+	"github.com/algo-shield/algo-shield/src/pkg/timeline"
+	"github.com/algo-shield/algo-shield/src/pkg/transactions"
+	"github.com/algo-shield/algo-shield/src/workers/internal/geo"
+	"github.com/algo-shield/algo-shield/src/workers/internal/macros"
 	"github.com/algo-shield/algo-shield/src/workers/internal/schemas"
-	"github.com/algo-shield/algo-shield/src/workers/internal/transactions"
+	"github.com/expr-lang/expr/vm"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
+// EngineConfig configures how the engine evaluates rules and reaches a decision
+type EngineConfig struct {
+	RuleEvaluationTimeout time.Duration     // Default timeout of a single rule, 0 or less disables it
+	EvaluationTimeout     time.Duration     // Timeout of evaluating all rules for one event, 0 or less disables it
+	TimeoutFallback       models.RuleAction // Decision applied when evaluation times out; allow fails open
+	ScoreBands            ScoreBands
+	ConflictPolicy        ConflictPolicy
+	Health                HealthConfig
+	Velocity              VelocityConfig
+	Timeline              TimelineConfig
+	IPDatabase            *geopkg.IPDatabase // Local IP database for ipCountry and ipASN, nil disables them
+	SanctionsThreshold    float64            // Minimum name similarity for sanctionsHit to match
+}
+
+// VelocityConfig configures the transaction history queried by velocity helpers
+type VelocityConfig struct {
+	Redis     bool          // Query Redis sliding windows, falling back to Postgres; otherwise query Postgres only
+	Retention time.Duration // History kept in Redis
+}
+
+// TimelineConfig configures the per-entity event timelines queried by sequence helpers
+type TimelineConfig struct {
+	Retention time.Duration // How long events are kept, 0 or less disables timelines
+	MaxEvents int           // Most recent events kept per entity, 0 or less keeps every retained event
+}
+
 // Engine evaluates events against rules using schemas
 type Engine struct {
-	ruleService    *RuleService
-	schemaService  *schemas.SchemaService
-	historyRepo    transactions.TransactionHistoryRepository
-	defaultTimeout time.Duration
+	ruleService       *RuleService
+	schemaService     *schemas.SchemaService
+	macroService      *macros.MacroService
+	historyRepo       transactions.TransactionHistoryRepository
+	historyRecorder   transactions.HistoryRecorder
+	timeline          *timeline.RedisTimeline
+	countryRisk       *geo.CountryRiskCache
+	lists             *listspkg.ListService
+	ipDatabase        *geopkg.IPDatabase
+	sanctions         *sanctions.Screener
+	health            *HealthTracker
+	defaultTimeout    time.Duration
+	evaluationTimeout time.Duration
+	timeoutFallback   models.TransactionStatus
+	scoreBands        ScoreBands
+	conflictPolicy    ConflictPolicy
 }
 
 // NewEngine creates a new rule engine
-func NewEngine(db *pgxpool.Pool, redis *redis.Client, ruleEvaluationTimeout time.Duration) *Engine {
-	// Create rule repository and service with dependency injection
-	ruleRepo := rules.NewPostgresRepository(db, redis)
-	ruleService := NewRuleService(ruleRepo)
-
+func NewEngine(db *pgxpool.Pool, redis *redis.Client, cfg EngineConfig) *Engine {
 	// Create schema repository and service
 	schemaRepo := schemas.NewPostgresRepository(db)
 	schemaService := schemas.NewSchemaService(schemaRepo, redis)
 
+	// Macros are inlined into the rules referencing them when compiling
+	macroService := macros.NewMacroService(macrospkg.NewPostgresRepository(db, redis), redis)
+
+	// Create rule repository and service with dependency injection
+	// Rules are compiled against the cached schemas and macros
+	ruleRepo := rules.NewPostgresRepository(db, redis)
+	ruleService := NewRuleService(ruleRepo, schemaService, macroService)
+
+	// Recompile rules whenever a schema changes so field types stay in sync
+	schemaService.OnInvalidate(func(uuid.UUID) {
+		ruleService.Recompile()
+	})
+
+	// Recompile rules whenever a macro changes so rules using it pick up the new expression
+	macroService.OnInvalidate(ruleService.Recompile)
+
 	// Create history repository for velocity helpers
-	historyRepo := transactions.NewPostgresHistoryRepository(db)
+	// With Redis the history is fed by the transaction service on ingest and Postgres stays as the fallback
+	var historyRepo transactions.TransactionHistoryRepository = transactions.NewPostgresHistoryRepository(db)
+	var historyRecorder transactions.HistoryRecorder
+	if cfg.Velocity.Redis {
+		redisHistory := transactions.NewRedisHistoryRepository(redis, cfg.Velocity.Retention, historyRepo)
+		historyRepo, historyRecorder = redisHistory, redisHistory
+	}
+
+	// Keep recent events per entity in Redis so sequence helpers can look across schemas
+	var eventTimeline *timeline.RedisTimeline
+	if cfg.Timeline.Retention > 0 {
+		eventTimeline = timeline.NewRedisTimeline(redis, cfg.Timeline.Retention, cfg.Timeline.MaxEvents)
+	}
+
+	// Track per-rule errors on live traffic; failing rules are disabled through the rule repository
+	health := NewHealthTracker(rules.NewPostgresHealthRepository(db), ruleRepo, cfg.Health)
 
 	return &Engine{
-		ruleService:    ruleService,
-		schemaService:  schemaService,
-		historyRepo:    historyRepo,
-		defaultTimeout: ruleEvaluationTimeout,
+		ruleService:       ruleService,
+		schemaService:     schemaService,
+		macroService:      macroService,
+		historyRepo:       historyRepo,
+		historyRecorder:   historyRecorder,
+		timeline:          eventTimeline,
+		countryRisk:       geo.NewCountryRiskCache(geopkg.NewPostgresCountryRiskRepository(db)),
+		lists:             listspkg.NewListService(listspkg.NewPostgresRepository(db, redis), redis),
+		ipDatabase:        cfg.IPDatabase,
+		sanctions:         sanctions.NewScreener(sanctions.NewPostgresRepository(db, redis), redis, cfg.SanctionsThreshold),
+		health:            health,
+		defaultTimeout:    cfg.RuleEvaluationTimeout,
+		evaluationTimeout: cfg.EvaluationTimeout,
+		timeoutFallback:   models.StatusForDecision(cfg.TimeoutFallback),
+		scoreBands:        cfg.ScoreBands,
+		conflictPolicy:    cfg.ConflictPolicy,
 	}
 }
 
-// LoadRules loads rules and schemas
+// LoadRules loads schemas, macros and then rules, compiling rules against the fresh schemas and macros
+// Country risk scores are reloaded with the rules; failing to load them keeps the previous scores
 func (e *Engine) LoadRules(ctx context.Context) error {
+	if err := e.schemaService.LoadSchemas(ctx); err != nil {
+		return err
+	}
+	if err := e.macroService.Load(ctx); err != nil {
+		return err
+	}
 	if err := e.ruleService.LoadRules(ctx); err != nil {
 		return err
 	}
-	return e.schemaService.LoadSchemas(ctx)
+	if e.countryRisk != nil {
+		if err := e.countryRisk.Load(ctx); err != nil {
+			log.Printf("Failed to load country risk scores: %v", err)
+		}
+	}
+	return nil
+}
+
+// lookups returns the data behind the expression helpers
+func (e *Engine) lookups() expressions.Lookups {
+	return expressions.Lookups{
+		History:     e.historyRepo,
+		CountryRisk: e.countryRisk,
+		Lists:       e.lists,
+		IPs:         e.ipDatabase,
+		Sanctions:   e.sanctions,
+		Timeline:    e.timeline,
+	}
+}
+
+// LoadLists loads the managed lists checked by inList
+// Lists are not reloaded with the rules; changes arrive through StartListInvalidationSubscription
+func (e *Engine) LoadLists(ctx context.Context) error {
+	return e.lists.LoadLists(ctx)
+}
+
+// StartListInvalidationSubscription starts listening for list changes
+// This is a blocking function that should be called in a goroutine managed by errgroup
+func (e *Engine) StartListInvalidationSubscription(ctx context.Context) {
+	e.lists.SubscribeToInvalidations(ctx)
+}
+
+// LoadSanctions loads the sanctions entries screened by sanctionsHit
+// New imports arrive through StartSanctionsInvalidationSubscription
+func (e *Engine) LoadSanctions(ctx context.Context) error {
+	return e.sanctions.Load(ctx)
+}
+
+// StartSanctionsInvalidationSubscription starts listening for sanctions imports
+// This is a blocking function that should be called in a goroutine managed by errgroup
+func (e *Engine) StartSanctionsInvalidationSubscription(ctx context.Context) {
+	e.sanctions.SubscribeToInvalidations(ctx)
 }
 
 // StartSchemaInvalidationSubscription starts listening for schema changes
@@ -56,70 +191,241 @@ func (e *Engine) StartSchemaInvalidationSubscription(ctx context.Context) {
 	e.schemaService.SubscribeToInvalidations(ctx)
 }
 
+// StartMacroInvalidationSubscription starts listening for macro changes
+// This is a blocking function that should be called in a goroutine managed by errgroup
+func (e *Engine) StartMacroInvalidationSubscription(ctx context.Context) {
+	e.macroService.SubscribeToInvalidations(ctx)
+}
+
+// HistoryRecorder returns the recorder feeding velocity history on ingest
+// Returns nil when velocity helpers query Postgres directly
+func (e *Engine) HistoryRecorder() transactions.HistoryRecorder {
+	return e.historyRecorder
+}
+
+// TimelineRecorder returns the recorder adding events to entity timelines before evaluation
+// Returns nil when timelines are disabled
+func (e *Engine) TimelineRecorder() timeline.Recorder {
+	if e.timeline == nil {
+		return nil
+	}
+	return e.timeline
+}
+
+// StartHealthFlush periodically persists per-rule health counters
+// This is a blocking function that should be called in a goroutine managed by errgroup
+func (e *Engine) StartHealthFlush(ctx context.Context) {
+	e.health.Start(ctx)
+}
+
 // Evaluate evaluates an event against all loaded rules using schema-based evaluation
+// The compiled rule set is read once so a concurrent hot-reload never yields a mixed set
+// Rule errors and matches are recorded in the rule health summary
 func (e *Engine) Evaluate(ctx context.Context, event models.Event) (*models.TransactionResult, error) {
+	result, _ := e.evaluateRules(ctx, event, e.ruleService.GetCompiledRules(), e.health)
+	return result, nil
+}
+
+// ActiveRules returns the currently loaded rules
+func (e *Engine) ActiveRules() []models.Rule {
+	return e.ruleService.GetRules()
+}
+
+// CompileRules compiles rules against the cached schemas in evaluation order
+// Used to evaluate rule sets other than the loaded one, such as backtest candidates
+func (e *Engine) CompileRules(rules []models.Rule) []CompiledRule {
+	return e.ruleService.compileRules(rules)
+}
+
+// EvaluateRules evaluates an event against the given compiled rules
+// Returns the decision and the active rules that matched, in evaluation order
+// Shadow rules are evaluated and reported in ShadowMatchedRules but never affect the decision
+// Rule health is not recorded, so replays never affect live rule health
+func (e *Engine) EvaluateRules(ctx context.Context, event models.Event, compiledRules []CompiledRule) (*models.TransactionResult, []*CompiledRule) {
+	return e.evaluateRules(ctx, event, compiledRules, nil)
+}
+
+// evaluateRules evaluates an event against the given compiled rules, recording outcomes in health when set
+// Each rule is bounded by its own timeout and the whole evaluation by the evaluation timeout.
+// Active rules that time out, or are skipped once the evaluation deadline expires, trigger the
+// timeout fallback, which can only escalate the decision
+func (e *Engine) evaluateRules(ctx context.Context, event models.Event, compiledRules []CompiledRule, health *HealthTracker) (*models.TransactionResult, []*CompiledRule) {
 	startTime := time.Now()
 
+	if e.evaluationTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.evaluationTimeout)
+		defer cancel()
+	}
+
 	matchedRules := make([]string, 0)
-	status := models.StatusApproved
+	matchedRuleVersions := make([]uuid.UUID, 0)
+	shadowMatchedRules := make([]string, 0)
+	shadowRuleVersions := make([]uuid.UUID, 0)
+	timedOutRules := make([]string, 0)
+	matched := make([]*CompiledRule, 0)
+	var terminal *CompiledRule
+	riskScore := 0
+
+	// Evaluate each rule in priority order
+	for i := range compiledRules {
+		rule := &compiledRules[i]
+		shadow := rule.Rule.IsShadow()
+
+		// After a terminal match only shadow rules are still evaluated, so they see all traffic
+		if terminal != nil && !shadow {
+			continue
+		}
 
-	// Evaluate each rule
-	rules := e.ruleService.GetRules()
-	for _, rule := range rules {
-		matched := e.evaluateRule(ctx, event, rule)
-		if matched {
-			matchedRules = append(matchedRules, rule.Name)
-
-			// Determine action
-			switch rule.Action {
-			case models.ActionBlock:
-				status = models.StatusRejected
-			case models.ActionReview:
-				if status != models.StatusRejected {
-					status = models.StatusInReview
-				}
-			case models.ActionAllow:
-				status = models.StatusApproved
+		// Once the evaluation deadline expires the remaining rules are skipped
+		if ctx.Err() != nil {
+			if !shadow {
+				timedOutRules = append(timedOutRules, rule.Rule.Name)
 			}
+			continue
+		}
+
+		ruleMatched, timedOut := e.evaluateRule(ctx, event, rule, health)
+		if timedOut && !shadow {
+			timedOutRules = append(timedOutRules, rule.Rule.Name)
+		}
+		if !ruleMatched {
+			continue
+		}
+
+		if shadow {
+			shadowMatchedRules = append(shadowMatchedRules, rule.Rule.Name)
+			shadowRuleVersions = append(shadowRuleVersions, rule.Rule.VersionID)
+			continue
+		}
+
+		matched = append(matched, rule)
+		matchedRules = append(matchedRules, rule.Rule.Name)
+		matchedRuleVersions = append(matchedRuleVersions, rule.Rule.VersionID)
+		riskScore += rule.Rule.Score
+
+		// A terminal rule short-circuits the remaining active rules
+		if rule.Rule.StopProcessing {
+			terminal = rule
 		}
 	}
 
+	// A terminal rule is resolved with the rules matched before it, so it never overrides
+	// a higher-priority match the conflict policy prefers
+	decider := e.conflictPolicy.Resolve(matched)
+
+	status := models.StatusApproved
+	var decidedBy *uuid.UUID
+	if decider != nil {
+		status = actionStatus(decider.Rule.Action)
+		id := decider.Rule.ID
+		decidedBy = &id
+	}
+
+	// The accumulated score can only escalate a non-terminal decision
+	if terminal == nil {
+		if bandStatus := e.scoreBands.Status(riskScore); statusSeverity(bandStatus) > statusSeverity(status) {
+			status = bandStatus
+			decidedBy = nil // Decided by the score band, not by a rule
+		}
+	}
+
+	// Rules that could not finish may have matched: the fallback decides how much to trust the rest
+	timeoutFallback := len(timedOutRules) > 0
+	if timeoutFallback && statusSeverity(e.timeoutFallback) > statusSeverity(status) {
+		status = e.timeoutFallback
+		decidedBy = nil // Decided by the timeout fallback, not by a rule
+	}
+
 	processingTime := time.Since(startTime).Milliseconds()
 
 	result := &models.TransactionResult{
-		Status:         status,
-		MatchedRules:   matchedRules,
-		ProcessingTime: processingTime,
+		Status:             status,
+		RiskScore:          riskScore,
+		MatchedRules:       matchedRules,
+		MatchedVersions:    matchedRuleVersions,
+		ShadowMatchedRules: shadowMatchedRules,
+		ShadowVersions:     shadowRuleVersions,
+		DecidedBy:          decidedBy,
+		TimedOutRules:      timedOutRules,
+		TimeoutFallback:    timeoutFallback,
+		ProcessingTime:     processingTime,
 	}
 
-	return result, nil
+	return result, matched
 }
 
-// evaluateRule evaluates a single rule against an event
+// evaluateRule evaluates a single compiled rule against an event
 // All rules use custom expressions (schema-based)
-func (e *Engine) evaluateRule(ctx context.Context, event models.Event, rule models.Rule) bool {
-	return e.evaluateCustomRule(ctx, event, rule)
+// Reports whether the rule matched and whether it was abandoned on timeout
+func (e *Engine) evaluateRule(ctx context.Context, event models.Event, rule *CompiledRule, health *HealthTracker) (bool, bool) {
+	// Rules that failed to compile were logged at load time
+	if rule.Err != nil {
+		health.RecordError(ctx, &rule.Rule, compileErrorKind(rule.Err), rule.Err)
+		return false, false
+	}
+
+	ruleCtx := ctx
+	if timeout := e.ruleTimeout(&rule.Rule); timeout > 0 {
+		var cancel context.CancelFunc
+		ruleCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Use schema-based environment with the lookups behind velocity, baseline and geo helpers
+	// The environment is built per rule so helpers query with the rule's context and stop at its deadline
+	env := schemas.BuildExpressionEnv(ruleCtx, event, rule.Schema, e.lookups())
+
+	matched, err := runWithContext(ruleCtx, rule.Program, env)
+
+	// Helpers swallow their errors, so a result reached after the deadline is not trusted
+	if ruleCtx.Err() != nil {
+		log.Printf("Rule %s timed out: %v", rule.Rule.Name, ruleCtx.Err())
+		health.RecordError(ctx, &rule.Rule, models.RuleErrorTimeout, ruleCtx.Err())
+		return false, true
+	}
+
+	if err != nil {
+		log.Printf("Expression runtime error in rule %s: %v", rule.Rule.Name, err)
+		health.RecordError(ctx, &rule.Rule, models.RuleErrorRuntime, err)
+		return false, false
+	}
+
+	health.RecordSuccess(ctx, &rule.Rule, matched)
+	return matched, false
 }
 
-// evaluateCustomRule evaluates custom expression against event using schema
-func (e *Engine) evaluateCustomRule(ctx context.Context, event models.Event, rule models.Rule) bool {
-	expression, ok := rule.Conditions["custom_expression"].(string)
-	if !ok {
-		log.Printf("Custom rule missing or invalid custom_expression condition")
-		return false
+// ruleTimeout returns the evaluation timeout of a rule, falling back to the engine default
+func (e *Engine) ruleTimeout(rule *models.Rule) time.Duration {
+	if rule.TimeoutMs > 0 {
+		return time.Duration(rule.TimeoutMs) * time.Millisecond
 	}
+	return e.defaultTimeout
+}
 
-	// Get schema for this rule
-	if rule.SchemaID == nil {
-		log.Printf("Custom rule missing schema_id")
-		return false
+// runWithContext runs a compiled expression, giving up when the context is done
+// Expressions cannot be interrupted, so an abandoned run finishes in the background
+// and its result is discarded
+func runWithContext(ctx context.Context, program *vm.Program, env map[string]any) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return schemas.RunExpression(program, env)
 	}
-	schema := e.schemaService.GetSchema(*rule.SchemaID)
-	if schema == nil {
-		log.Printf("Schema %s not found for rule %s", *rule.SchemaID, rule.Name)
-		return false
+
+	type outcome struct {
+		matched bool
+		err     error
 	}
 
-	// Use schema-based expression evaluation with history repository for velocity helpers
-	return schemas.EvaluateExpressionWithSchema(ctx, expression, event, schema, e.historyRepo)
+	done := make(chan outcome, 1) // Buffered so an abandoned run does not block forever
+	go func() {
+		matched, err := schemas.RunExpression(program, env)
+		done <- outcome{matched: matched, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.matched, result.err
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
 }
@@ -0,0 +1,410 @@
+package rules
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/algo-shield/algo-shield/src/workers/internal/schemas"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestEngine(t *testing.T, ctrl *gomock.Controller, cfg EngineConfig, loaded []models.Rule) *Engine {
+	t.Helper()
+
+	schema := &schemas.EventSchema{
+		ID: uuid.New(),
+		ExtractedFields: []schemas.ExtractedField{
+			{Path: "amount", Type: schemas.FieldTypeNumber},
+			{Path: "origin", Type: schemas.FieldTypeString},
+		},
+	}
+	for i := range loaded {
+		loaded[i].ID = uuid.New()
+		loaded[i].SchemaID = &schema.ID
+	}
+
+	mockRepo := NewMockRuleReader(ctrl)
+	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(loaded, nil)
+	mockSchemas := NewMockSchemaProvider(ctrl)
+	mockSchemas.EXPECT().GetSchema(schema.ID).Return(schema).AnyTimes()
+	ruleService := NewRuleService(mockRepo, mockSchemas, nil)
+	require.NoError(t, ruleService.LoadRules(context.Background()))
+
+	return &Engine{
+		ruleService:       ruleService,
+		defaultTimeout:    cfg.RuleEvaluationTimeout,
+		evaluationTimeout: cfg.EvaluationTimeout,
+		timeoutFallback:   models.StatusForDecision(cfg.TimeoutFallback),
+		scoreBands:        cfg.ScoreBands,
+		conflictPolicy:    cfg.ConflictPolicy,
+	}
+}
+
+// slowHistoryRepo answers velocity queries after a delay, or when the context is done
+type slowHistoryRepo struct {
+	delay     time.Duration
+	cancelled atomic.Int32 // Queries given up because their context was done
+}
+
+func (r *slowHistoryRepo) CountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (int, error) {
+	select {
+	case <-time.After(r.delay):
+		return 1, nil
+	case <-ctx.Done():
+		r.cancelled.Add(1)
+		return 0, ctx.Err()
+	}
+}
+
+func (r *slowHistoryRepo) SumAmountByAccountInTimeWindow(ctx context.Context, account string, timeWindowSeconds int) (float64, error) {
+	return 0, nil
+}
+
+func (r *slowHistoryRepo) CountByFieldInTimeWindow(ctx context.Context, path string, value any, timeWindowSeconds int) (int, error) {
+	return 0, nil
+}
+
+func (r *slowHistoryRepo) AggregateAmountByFieldInTimeWindow(ctx context.Context, path string, value any, aggregate models.AmountAggregate, timeWindowSeconds int) (float64, error) {
+	return 0, nil
+}
+
+func (r *slowHistoryRepo) CountDistinctByFieldInTimeWindow(ctx context.Context, distinctPath, path string, value any, timeWindowSeconds int) (int, error) {
+	return 0, nil
+}
+
+func (r *slowHistoryRepo) AmountStatsByAccount(ctx context.Context, account string, days int) (models.AmountStats, error) {
+	return models.AmountStats{}, nil
+}
+
+func (r *slowHistoryRepo) FirstSeenByAccount(ctx context.Context, account string) (*time.Time, error) {
+	return nil, nil
+}
+
+func (r *slowHistoryRepo) LastLocationByAccount(ctx context.Context, account string) (*models.AccountLocation, error) {
+	return nil, nil
+}
+
+func expression(expr string) map[string]any {
+	return map[string]any{"custom_expression": expr}
+}
+
+func Test_Engine_Evaluate_WhenAllowMatchesAfterBlock_ThenMostSevereWins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{ConflictPolicy: PolicyMostSevere}, []models.Rule{
+		{Name: "block", Action: models.ActionBlock, Priority: 1, Conditions: expression("amount > 100")},
+		{Name: "allow", Action: models.ActionAllow, Priority: 2, Conditions: expression(`origin == "ACC1"`)},
+	})
+
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRejected, result.Status)
+	assert.Equal(t, []string{"block", "allow"}, result.MatchedRules)
+	require.NotNil(t, result.DecidedBy)
+	assert.Equal(t, engine.ruleService.GetRules()[0].ID, *result.DecidedBy)
+}
+
+func Test_Engine_Evaluate_WhenTerminalRuleMatches_ThenStopsProcessing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{
+		ConflictPolicy: PolicyMostSevere,
+		ScoreBands:     ScoreBands{ReviewThreshold: 1},
+	}, []models.Rule{
+		{Name: "allowlist", Action: models.ActionAllow, Priority: 1, Score: 5, StopProcessing: true, Conditions: expression(`origin == "ACC1"`)},
+		{Name: "block", Action: models.ActionBlock, Priority: 2, Conditions: expression("amount > 100")},
+	})
+
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, result.Status)
+	assert.Equal(t, []string{"allowlist"}, result.MatchedRules)
+	assert.Equal(t, 5, result.RiskScore)
+	require.NotNil(t, result.DecidedBy)
+	assert.Equal(t, engine.ruleService.GetRules()[0].ID, *result.DecidedBy)
+}
+
+func Test_Engine_Evaluate_WhenTerminalAllowFollowsBlock_ThenBlockStillDecides(t *testing.T) {
+	for _, policy := range []ConflictPolicy{PolicyMostSevere, PolicyFirstMatch, PolicyHighestPriority} {
+		t.Run(string(policy), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			engine := newTestEngine(t, ctrl, EngineConfig{ConflictPolicy: policy}, []models.Rule{
+				{Name: "block", Action: models.ActionBlock, Priority: 1, Conditions: expression("amount > 100")},
+				{Name: "allowlist", Action: models.ActionAllow, Priority: 10, StopProcessing: true, Conditions: expression(`origin == "ACC1"`)},
+				{Name: "review", Action: models.ActionReview, Priority: 20, Conditions: expression("amount > 0")},
+			})
+
+			result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+
+			require.NoError(t, err)
+			assert.Equal(t, models.StatusRejected, result.Status)
+			assert.Equal(t, []string{"block", "allowlist"}, result.MatchedRules)
+			require.NotNil(t, result.DecidedBy)
+			assert.Equal(t, engine.ruleService.GetRules()[0].ID, *result.DecidedBy)
+		})
+	}
+}
+
+func Test_Engine_Evaluate_WhenScoreExceedsBand_ThenEscalatesWithoutDecidingRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{
+		ConflictPolicy: PolicyMostSevere,
+		ScoreBands:     ScoreBands{ReviewThreshold: 50, BlockThreshold: 80},
+	}, []models.Rule{
+		{Name: "amount", Action: models.ActionReview, Priority: 1, Score: 50, Conditions: expression("amount > 100")},
+		{Name: "origin", Action: models.ActionReview, Priority: 2, Score: 40, Conditions: expression(`origin == "ACC1"`)},
+	})
+
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRejected, result.Status)
+	assert.Equal(t, 90, result.RiskScore)
+	assert.Nil(t, result.DecidedBy)
+}
+
+func Test_Engine_Evaluate_WhenNoRuleMatches_ThenApproves(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{ConflictPolicy: PolicyFirstMatch}, []models.Rule{
+		{Name: "block", Action: models.ActionBlock, Conditions: expression("amount > 100")},
+	})
+
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 50.0, "origin": "ACC1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, result.Status)
+	assert.Empty(t, result.MatchedRules)
+	assert.Nil(t, result.DecidedBy)
+}
+
+func Test_Engine_Evaluate_WhenShadowRuleMatches_ThenRecordsMatchWithoutAffectingDecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{
+		ConflictPolicy: PolicyMostSevere,
+		ScoreBands:     ScoreBands{ReviewThreshold: 10},
+	}, []models.Rule{
+		{Name: "shadow block", Action: models.ActionBlock, Priority: 1, Score: 50, Mode: models.RuleModeShadow, Conditions: expression("amount > 100")},
+		{Name: "review", Action: models.ActionReview, Priority: 2, Mode: models.RuleModeActive, Conditions: expression("amount > 1000")},
+	})
+
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, result.Status)
+	assert.Equal(t, 0, result.RiskScore, "shadow scores do not count")
+	assert.Empty(t, result.MatchedRules)
+	assert.Equal(t, []string{"shadow block"}, result.ShadowMatchedRules)
+	assert.Nil(t, result.DecidedBy)
+}
+
+func Test_Engine_Evaluate_WhenTerminalRuleMatches_ThenStillEvaluatesShadowRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{ConflictPolicy: PolicyMostSevere}, []models.Rule{
+		{Name: "allowlist", Action: models.ActionAllow, Priority: 1, StopProcessing: true, Conditions: expression(`origin == "ACC1"`)},
+		{Name: "block", Action: models.ActionBlock, Priority: 2, Conditions: expression("amount > 100")},
+		{Name: "shadow review", Action: models.ActionReview, Priority: 3, Mode: models.RuleModeShadow, Conditions: expression("amount > 100")},
+	})
+
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, result.Status)
+	assert.Equal(t, []string{"allowlist"}, result.MatchedRules)
+	assert.Equal(t, []string{"shadow review"}, result.ShadowMatchedRules)
+}
+
+func Test_Engine_Evaluate_WhenRulesMatch_ThenRecordsMatchedRuleVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	activeVersion := uuid.New()
+	shadowVersion := uuid.New()
+	engine := newTestEngine(t, ctrl, EngineConfig{ConflictPolicy: PolicyMostSevere}, []models.Rule{
+		{Name: "review", Action: models.ActionReview, Priority: 1, VersionID: activeVersion, Conditions: expression("amount > 100")},
+		{Name: "shadow", Action: models.ActionBlock, Priority: 2, VersionID: shadowVersion, Mode: models.RuleModeShadow, Conditions: expression("amount > 100")},
+		{Name: "no match", Action: models.ActionBlock, Priority: 3, VersionID: uuid.New(), Conditions: expression("amount > 1000")},
+	})
+
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{activeVersion}, result.MatchedVersions)
+	assert.Equal(t, []uuid.UUID{shadowVersion}, result.ShadowVersions)
+}
+
+func Test_Engine_Evaluate_WhenRuleFailsToCompile_ThenRecordsRuleError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{ConflictPolicy: PolicyMostSevere}, []models.Rule{
+		{Name: "broken", Action: models.ActionBlock, Priority: 1, Conditions: expression("unknown_field > 1")},
+	})
+	healthRepo := NewMockHealthRepository(ctrl)
+	engine.health = NewHealthTracker(healthRepo, nil, HealthConfig{})
+
+	_, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+	require.NoError(t, err)
+
+	brokenID := engine.ruleService.GetRules()[0].ID
+	healthRepo.EXPECT().RecordHealth(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, delta models.RuleHealthDelta) (*models.RuleHealth, error) {
+			assert.Equal(t, brokenID, delta.RuleID)
+			assert.Equal(t, int64(1), delta.Errors)
+			assert.Equal(t, models.RuleErrorCompile, delta.LastErrorKind)
+			return &models.RuleHealth{RuleID: brokenID, ConsecutiveFailures: 1}, nil
+		},
+	)
+	engine.health.Flush(context.Background())
+}
+
+func Test_Engine_EvaluateRules_WhenReplaying_ThenDoesNotRecordRuleHealth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{ConflictPolicy: PolicyMostSevere}, []models.Rule{
+		{Name: "broken", Action: models.ActionBlock, Priority: 1, Conditions: expression("unknown_field > 1")},
+	})
+	engine.health = NewHealthTracker(NewMockHealthRepository(ctrl), nil, HealthConfig{})
+
+	engine.EvaluateRules(context.Background(), models.Event{"amount": 500.0}, engine.ruleService.GetCompiledRules())
+
+	// No RecordHealth expectation: nothing was counted
+	engine.health.Flush(context.Background())
+}
+
+func Test_Engine_Evaluate_WhenRuleTimesOutWithFailClosed_ThenEscalatesToFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{
+		RuleEvaluationTimeout: 20 * time.Millisecond,
+		TimeoutFallback:       models.ActionReview,
+		ConflictPolicy:        PolicyMostSevere,
+	}, []models.Rule{
+		{Name: "slow velocity", Action: models.ActionBlock, Priority: 1, Conditions: expression("velocityCount(origin, 60) > 0")},
+		{Name: "fast", Action: models.ActionAllow, Priority: 2, Conditions: expression("amount > 100")},
+	})
+	historyRepo := &slowHistoryRepo{delay: time.Second}
+	engine.historyRepo = historyRepo
+
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusInReview, result.Status)
+	assert.Equal(t, []string{"slow velocity"}, result.TimedOutRules)
+	assert.True(t, result.TimeoutFallback)
+	assert.Equal(t, []string{"fast"}, result.MatchedRules, "rules after a timed out rule are still evaluated")
+	assert.Nil(t, result.DecidedBy)
+	// The abandoned run sees the cancellation in the background
+	assert.Eventually(t, func() bool { return historyRepo.cancelled.Load() == 1 }, time.Second, time.Millisecond, "the rule timeout cancels the helper query")
+}
+
+func Test_Engine_Evaluate_WhenRuleTimesOutWithFailOpen_ThenKeepsRuleDecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{
+		RuleEvaluationTimeout: 20 * time.Millisecond,
+		TimeoutFallback:       models.ActionAllow,
+		ConflictPolicy:        PolicyMostSevere,
+	}, []models.Rule{
+		{Name: "slow velocity", Action: models.ActionBlock, Priority: 1, Conditions: expression("velocityCount(origin, 60) > 0")},
+	})
+	engine.historyRepo = &slowHistoryRepo{delay: time.Second}
+
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, result.Status)
+	assert.Equal(t, []string{"slow velocity"}, result.TimedOutRules)
+	assert.True(t, result.TimeoutFallback)
+}
+
+func Test_Engine_Evaluate_WhenExpressionRunsPastTimeout_ThenAbandonsItAndAppliesFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Matching a long string against the pattern over and over keeps the expression busy for about a second
+	slowExpression := `let s = repeat("a", 900000); ` + strings.Repeat(`s matches "^(a|aa|aaa)*b" or `, 15) + `false`
+	engine := newTestEngine(t, ctrl, EngineConfig{
+		RuleEvaluationTimeout: 20 * time.Millisecond,
+		TimeoutFallback:       models.ActionReview,
+		ConflictPolicy:        PolicyMostSevere,
+	}, []models.Rule{
+		{Name: "pathological", Action: models.ActionAllow, Priority: 1, Conditions: expression(slowExpression)},
+	})
+
+	start := time.Now()
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusInReview, result.Status)
+	assert.Equal(t, []string{"pathological"}, result.TimedOutRules)
+	assert.True(t, result.TimeoutFallback)
+	assert.Less(t, elapsed, 300*time.Millisecond, "the worker does not wait for the expression to finish")
+}
+
+func Test_Engine_Evaluate_WhenRuleOverridesTimeout_ThenUsesRuleTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{
+		RuleEvaluationTimeout: 10 * time.Millisecond,
+		TimeoutFallback:       models.ActionReview,
+		ConflictPolicy:        PolicyMostSevere,
+	}, []models.Rule{
+		{Name: "patient velocity", Action: models.ActionBlock, Priority: 1, TimeoutMs: 1000, Conditions: expression("velocityCount(origin, 60) > 0")},
+	})
+	engine.historyRepo = &slowHistoryRepo{delay: 50 * time.Millisecond}
+
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRejected, result.Status)
+	assert.Empty(t, result.TimedOutRules)
+	assert.False(t, result.TimeoutFallback)
+}
+
+func Test_Engine_Evaluate_WhenEvaluationDeadlineExpires_ThenSkipsRemainingRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	engine := newTestEngine(t, ctrl, EngineConfig{
+		EvaluationTimeout: 20 * time.Millisecond,
+		TimeoutFallback:   models.ActionBlock,
+		ConflictPolicy:    PolicyMostSevere,
+	}, []models.Rule{
+		{Name: "slow velocity", Action: models.ActionReview, Priority: 1, Conditions: expression("velocityCount(origin, 60) > 0")},
+		{Name: "skipped", Action: models.ActionAllow, Priority: 2, Conditions: expression("amount > 100")},
+	})
+	engine.historyRepo = &slowHistoryRepo{delay: time.Second}
+
+	result, err := engine.Evaluate(context.Background(), models.Event{"amount": 500.0, "origin": "ACC1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRejected, result.Status)
+	assert.Equal(t, []string{"slow velocity", "skipped"}, result.TimedOutRules)
+	assert.Empty(t, result.MatchedRules)
+}
@@ -0,0 +1,230 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/algo-shield/algo-shield/src/pkg/rules"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	// meter is the OpenTelemetry meter for rule evaluation metrics
+	meter = otel.Meter("github.com/algo-shield/algo-shield/rules")
+)
+
+// healthFlushTimeout bounds the final flush performed on shutdown
+const healthFlushTimeout = 5 * time.Second
+
+// HealthConfig configures how rule evaluation outcomes are persisted and acted on
+type HealthConfig struct {
+	FlushInterval    time.Duration // How often counters are written to the database, 0 or less disables flushing
+	FailureThreshold int           // Consecutive failures before a rule is flagged, 0 or less disables flagging
+	AutoDisable      bool          // Disable flagged rules instead of only flagging them
+}
+
+// RuleUpdater defines the rule operations needed to auto-disable a failing rule
+// Disabling goes through UpdateRule so it is recorded as a new rule version
+type RuleUpdater interface {
+	GetRule(ctx context.Context, id uuid.UUID) (*models.Rule, error)
+	UpdateRule(ctx context.Context, rule *models.Rule) error
+}
+
+// HealthTracker counts per-rule evaluation errors and matches on live traffic
+// Counters are exported as OpenTelemetry metrics and periodically merged into
+// the persisted rule health summary. A nil tracker records nothing.
+type HealthTracker struct {
+	repo     rules.HealthRepository
+	ruleRepo RuleUpdater
+	cfg      HealthConfig
+
+	// OpenTelemetry metrics (thread-safe by design)
+	errorsCounter  metric.Int64Counter
+	matchesCounter metric.Int64Counter
+
+	// Outcomes observed since the last flush, keyed by rule ID
+	mu     sync.Mutex
+	deltas map[uuid.UUID]*models.RuleHealthDelta
+}
+
+// NewHealthTracker creates a new rule health tracker with dependency injection
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+func NewHealthTracker(repo rules.HealthRepository, ruleRepo RuleUpdater, cfg HealthConfig) *HealthTracker {
+	// These operations are safe and will not fail with the global meter
+	errorsCounter, _ := meter.Int64Counter(
+		"rule_evaluation_errors_total",
+		metric.WithDescription("Total number of rule evaluation errors by rule and error kind"),
+	)
+
+	matchesCounter, _ := meter.Int64Counter(
+		"rule_matches_total",
+		metric.WithDescription("Total number of rule matches by rule"),
+	)
+
+	return &HealthTracker{
+		repo:           repo,
+		ruleRepo:       ruleRepo,
+		cfg:            cfg,
+		errorsCounter:  errorsCounter,
+		matchesCounter: matchesCounter,
+		deltas:         make(map[uuid.UUID]*models.RuleHealthDelta),
+	}
+}
+
+// RecordSuccess records an evaluation that ran without error, resetting the failure streak
+func (t *HealthTracker) RecordSuccess(ctx context.Context, rule *models.Rule, matched bool) {
+	if t == nil {
+		return
+	}
+
+	if matched {
+		t.matchesCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("rule_id", rule.ID.String())))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delta := t.delta(rule.ID)
+	delta.Evaluations++
+	delta.ConsecutiveFailures = 0
+	delta.Recovered = true
+	if matched {
+		now := time.Now()
+		delta.LastMatchAt = &now
+	}
+}
+
+// RecordError records an evaluation that failed, extending the failure streak
+func (t *HealthTracker) RecordError(ctx context.Context, rule *models.Rule, kind models.RuleErrorKind, err error) {
+	if t == nil {
+		return
+	}
+
+	t.errorsCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("rule_id", rule.ID.String()),
+		attribute.String("kind", string(kind)),
+	))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	delta := t.delta(rule.ID)
+	delta.Evaluations++
+	delta.Errors++
+	delta.ConsecutiveFailures++
+	delta.LastError = err.Error()
+	delta.LastErrorKind = kind
+	delta.LastErrorAt = &now
+}
+
+// delta returns the pending outcomes of a rule, creating them if needed
+// Must be called with mu held
+func (t *HealthTracker) delta(ruleID uuid.UUID) *models.RuleHealthDelta {
+	delta, ok := t.deltas[ruleID]
+	if !ok {
+		delta = &models.RuleHealthDelta{RuleID: ruleID}
+		t.deltas[ruleID] = delta
+	}
+	return delta
+}
+
+// Start flushes counters periodically until the context is cancelled
+// This is a blocking function that should be called in a goroutine managed by errgroup
+func (t *HealthTracker) Start(ctx context.Context) {
+	if t == nil || t.cfg.FlushInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(t.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Persist what was counted since the last tick before stopping
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), healthFlushTimeout)
+			t.Flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			t.Flush(ctx)
+		}
+	}
+}
+
+// Flush merges the outcomes observed since the last flush into the persisted summary
+// and flags rules whose failure streak reached the threshold
+// Outcomes that fail to persist are dropped; the OpenTelemetry counters still have them
+func (t *HealthTracker) Flush(ctx context.Context) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	deltas := t.deltas
+	t.deltas = make(map[uuid.UUID]*models.RuleHealthDelta)
+	t.mu.Unlock()
+
+	for _, delta := range deltas {
+		health, err := t.repo.RecordHealth(ctx, *delta)
+		if err != nil {
+			log.Printf("Failed to record health for rule %s: %v", delta.RuleID, err)
+			continue
+		}
+
+		if t.cfg.FailureThreshold > 0 && !health.Flagged && health.ConsecutiveFailures >= t.cfg.FailureThreshold {
+			t.flag(ctx, health)
+		}
+	}
+}
+
+// flag marks a failing rule and disables it when auto-disable is enabled
+func (t *HealthTracker) flag(ctx context.Context, health *models.RuleHealth) {
+	disabled := false
+	if t.cfg.AutoDisable {
+		if err := t.disable(ctx, health.RuleID); err != nil {
+			log.Printf("Failed to auto-disable rule %s: %v", health.RuleID, err)
+		} else {
+			disabled = true
+		}
+	}
+
+	if err := t.repo.FlagRule(ctx, health.RuleID, disabled); err != nil {
+		log.Printf("Failed to flag rule %s: %v", health.RuleID, err)
+		return
+	}
+
+	log.Printf("Rule %s flagged after %d consecutive failures (auto_disabled=%t): %s",
+		health.RuleID, health.ConsecutiveFailures, disabled, health.LastError)
+}
+
+// disable turns off a rule as a new rule version
+func (t *HealthTracker) disable(ctx context.Context, ruleID uuid.UUID) error {
+	rule, err := t.ruleRepo.GetRule(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+	if !rule.Enabled {
+		return nil
+	}
+
+	rule.Enabled = false
+	rule.UpdatedAt = time.Now()
+	return t.ruleRepo.UpdateRule(ctx, rule)
+}
+
+// compileErrorKind classifies why a rule could not be compiled
+func compileErrorKind(err error) models.RuleErrorKind {
+	if errors.Is(err, ErrSchemaNotFound) {
+		return models.RuleErrorSchemaMissing
+	}
+	return models.RuleErrorCompile
+}
@@ -0,0 +1,137 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_HealthTracker_Flush_WhenErrorsRecorded_ThenPersistsFailureStreak(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockHealthRepository(ctrl)
+	tracker := NewHealthTracker(repo, nil, HealthConfig{FailureThreshold: 10})
+	rule := &models.Rule{ID: uuid.New()}
+
+	tracker.RecordError(context.Background(), rule, models.RuleErrorRuntime, errors.New("boom"))
+	tracker.RecordError(context.Background(), rule, models.RuleErrorRuntime, errors.New("boom again"))
+
+	repo.EXPECT().RecordHealth(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, delta models.RuleHealthDelta) (*models.RuleHealth, error) {
+			assert.Equal(t, rule.ID, delta.RuleID)
+			assert.Equal(t, int64(2), delta.Evaluations)
+			assert.Equal(t, int64(2), delta.Errors)
+			assert.Equal(t, 2, delta.ConsecutiveFailures)
+			assert.False(t, delta.Recovered)
+			assert.Equal(t, "boom again", delta.LastError)
+			assert.Equal(t, models.RuleErrorRuntime, delta.LastErrorKind)
+			assert.NotNil(t, delta.LastErrorAt)
+			return &models.RuleHealth{RuleID: rule.ID, ConsecutiveFailures: 2}, nil
+		},
+	)
+
+	tracker.Flush(context.Background())
+}
+
+func Test_HealthTracker_Flush_WhenRuleRecovers_ThenResetsStreak(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockHealthRepository(ctrl)
+	tracker := NewHealthTracker(repo, nil, HealthConfig{FailureThreshold: 10})
+	rule := &models.Rule{ID: uuid.New()}
+
+	tracker.RecordError(context.Background(), rule, models.RuleErrorRuntime, errors.New("boom"))
+	tracker.RecordSuccess(context.Background(), rule, true)
+
+	repo.EXPECT().RecordHealth(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, delta models.RuleHealthDelta) (*models.RuleHealth, error) {
+			assert.Equal(t, int64(2), delta.Evaluations)
+			assert.Equal(t, int64(1), delta.Errors)
+			assert.Equal(t, 0, delta.ConsecutiveFailures)
+			assert.True(t, delta.Recovered)
+			assert.NotNil(t, delta.LastMatchAt)
+			return &models.RuleHealth{RuleID: rule.ID}, nil
+		},
+	)
+
+	tracker.Flush(context.Background())
+
+	// Counters are cleared after a flush
+	tracker.Flush(context.Background())
+}
+
+func Test_HealthTracker_Flush_WhenThresholdReachedWithAutoDisable_ThenDisablesAndFlagsRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockHealthRepository(ctrl)
+	ruleRepo := NewMockRuleUpdater(ctrl)
+	tracker := NewHealthTracker(repo, ruleRepo, HealthConfig{FailureThreshold: 3, AutoDisable: true})
+	rule := &models.Rule{ID: uuid.New(), Enabled: true}
+
+	tracker.RecordError(context.Background(), rule, models.RuleErrorCompile, errors.New("bad expression"))
+
+	repo.EXPECT().RecordHealth(gomock.Any(), gomock.Any()).
+		Return(&models.RuleHealth{RuleID: rule.ID, ConsecutiveFailures: 3}, nil)
+	ruleRepo.EXPECT().GetRule(gomock.Any(), rule.ID).Return(rule, nil)
+	ruleRepo.EXPECT().UpdateRule(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, r *models.Rule) error {
+			assert.False(t, r.Enabled)
+			return nil
+		},
+	)
+	repo.EXPECT().FlagRule(gomock.Any(), rule.ID, true).Return(nil)
+
+	tracker.Flush(context.Background())
+}
+
+func Test_HealthTracker_Flush_WhenThresholdReachedWithoutAutoDisable_ThenOnlyFlagsRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockHealthRepository(ctrl)
+	ruleRepo := NewMockRuleUpdater(ctrl)
+	tracker := NewHealthTracker(repo, ruleRepo, HealthConfig{FailureThreshold: 3})
+	rule := &models.Rule{ID: uuid.New(), Enabled: true}
+
+	tracker.RecordError(context.Background(), rule, models.RuleErrorCompile, errors.New("bad expression"))
+
+	repo.EXPECT().RecordHealth(gomock.Any(), gomock.Any()).
+		Return(&models.RuleHealth{RuleID: rule.ID, ConsecutiveFailures: 5}, nil)
+	repo.EXPECT().FlagRule(gomock.Any(), rule.ID, false).Return(nil)
+
+	tracker.Flush(context.Background())
+}
+
+func Test_HealthTracker_Flush_WhenAlreadyFlagged_ThenDoesNotFlagAgain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockHealthRepository(ctrl)
+	tracker := NewHealthTracker(repo, nil, HealthConfig{FailureThreshold: 3, AutoDisable: true})
+	rule := &models.Rule{ID: uuid.New()}
+
+	tracker.RecordError(context.Background(), rule, models.RuleErrorRuntime, errors.New("boom"))
+
+	repo.EXPECT().RecordHealth(gomock.Any(), gomock.Any()).
+		Return(&models.RuleHealth{RuleID: rule.ID, ConsecutiveFailures: 8, Flagged: true}, nil)
+
+	tracker.Flush(context.Background())
+}
+
+func Test_HealthTracker_WhenNil_ThenRecordsNothing(t *testing.T) {
+	var tracker *HealthTracker
+
+	assert.NotPanics(t, func() {
+		tracker.RecordSuccess(context.Background(), &models.Rule{}, true)
+		tracker.RecordError(context.Background(), &models.Rule{}, models.RuleErrorRuntime, errors.New("boom"))
+		tracker.Flush(context.Background())
+	})
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/workers/internal/rules/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/workers/internal/rules/service.go -destination=src/workers/internal/rules/mock_macro_provider_test.go -package=rules -exclude_interfaces=SchemaProvider
+//
+
+// Package rules is a generated GoMock package.
+package rules
+
+import (
+	reflect "reflect"
+
+	expressions "github.com/algo-shield/algo-shield/src/pkg/expressions"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMacroProvider is a mock of MacroProvider interface.
+type MockMacroProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockMacroProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockMacroProviderMockRecorder is the mock recorder for MockMacroProvider.
+type MockMacroProviderMockRecorder struct {
+	mock *MockMacroProvider
+}
+
+// NewMockMacroProvider creates a new mock instance.
+func NewMockMacroProvider(ctrl *gomock.Controller) *MockMacroProvider {
+	mock := &MockMacroProvider{ctrl: ctrl}
+	mock.recorder = &MockMacroProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMacroProvider) EXPECT() *MockMacroProviderMockRecorder {
+	return m.recorder
+}
+
+// Macros mocks base method.
+func (m *MockMacroProvider) Macros() *expressions.Macros {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Macros")
+	ret0, _ := ret[0].(*expressions.Macros)
+	return ret0
+}
+
+// Macros indicates an expected call of Macros.
+func (mr *MockMacroProviderMockRecorder) Macros() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Macros", reflect.TypeOf((*MockMacroProvider)(nil).Macros))
+}
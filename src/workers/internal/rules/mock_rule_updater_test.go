@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/workers/internal/rules/health.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/workers/internal/rules/health.go -destination=src/workers/internal/rules/mock_rule_updater_test.go -package=rules RuleUpdater
+//
+
+// Package rules is a generated GoMock package.
+package rules
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRuleUpdater is a mock of RuleUpdater interface.
+type MockRuleUpdater struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuleUpdaterMockRecorder
+	isgomock struct{}
+}
+
+// MockRuleUpdaterMockRecorder is the mock recorder for MockRuleUpdater.
+type MockRuleUpdaterMockRecorder struct {
+	mock *MockRuleUpdater
+}
+
+// NewMockRuleUpdater creates a new mock instance.
+func NewMockRuleUpdater(ctrl *gomock.Controller) *MockRuleUpdater {
+	mock := &MockRuleUpdater{ctrl: ctrl}
+	mock.recorder = &MockRuleUpdaterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRuleUpdater) EXPECT() *MockRuleUpdaterMockRecorder {
+	return m.recorder
+}
+
+// GetRule mocks base method.
+func (m *MockRuleUpdater) GetRule(ctx context.Context, id uuid.UUID) (*models.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRule", ctx, id)
+	ret0, _ := ret[0].(*models.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRule indicates an expected call of GetRule.
+func (mr *MockRuleUpdaterMockRecorder) GetRule(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRule", reflect.TypeOf((*MockRuleUpdater)(nil).GetRule), ctx, id)
+}
+
+// UpdateRule mocks base method.
+func (m *MockRuleUpdater) UpdateRule(ctx context.Context, rule *models.Rule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRule", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRule indicates an expected call of UpdateRule.
+func (mr *MockRuleUpdaterMockRecorder) UpdateRule(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRule", reflect.TypeOf((*MockRuleUpdater)(nil).UpdateRule), ctx, rule)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/workers/internal/rules/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/workers/internal/rules/service.go -destination=src/workers/internal/rules/mock_schema_provider_test.go -package=rules -exclude_interfaces=MacroProvider
+//
+
+// Package rules is a generated GoMock package.
+package rules
+
+import (
+	reflect "reflect"
+
+	schemas "github.com/algo-shield/algo-shield/src/workers/internal/schemas"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSchemaProvider is a mock of SchemaProvider interface.
+type MockSchemaProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchemaProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockSchemaProviderMockRecorder is the mock recorder for MockSchemaProvider.
+type MockSchemaProviderMockRecorder struct {
+	mock *MockSchemaProvider
+}
+
+// NewMockSchemaProvider creates a new mock instance.
+func NewMockSchemaProvider(ctrl *gomock.Controller) *MockSchemaProvider {
+	mock := &MockSchemaProvider{ctrl: ctrl}
+	mock.recorder = &MockSchemaProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchemaProvider) EXPECT() *MockSchemaProviderMockRecorder {
+	return m.recorder
+}
+
+// GetSchema mocks base method.
+func (m *MockSchemaProvider) GetSchema(id uuid.UUID) *schemas.EventSchema {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchema", id)
+	ret0, _ := ret[0].(*schemas.EventSchema)
+	return ret0
+}
+
+// GetSchema indicates an expected call of GetSchema.
+func (mr *MockSchemaProviderMockRecorder) GetSchema(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchema", reflect.TypeOf((*MockSchemaProvider)(nil).GetSchema), id)
+}
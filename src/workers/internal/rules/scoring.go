@@ -0,0 +1,34 @@
+package rules
+
+import "github.com/algo-shield/algo-shield/src/pkg/models"
+
+// ScoreBands maps an accumulated risk score to a decision
+// A threshold of 0 or less disables that band
+type ScoreBands struct {
+	ReviewThreshold int
+	BlockThreshold  int
+}
+
+// Status returns the status for a risk score
+// Returns StatusApproved when the score falls below every enabled band
+func (b ScoreBands) Status(score int) models.TransactionStatus {
+	if b.BlockThreshold > 0 && score >= b.BlockThreshold {
+		return models.StatusRejected
+	}
+	if b.ReviewThreshold > 0 && score >= b.ReviewThreshold {
+		return models.StatusInReview
+	}
+	return models.StatusApproved
+}
+
+// statusSeverity orders statuses from least to most severe
+func statusSeverity(status models.TransactionStatus) int {
+	switch status {
+	case models.StatusRejected:
+		return 2
+	case models.StatusInReview:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,38 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ScoreBands_Status_WhenScoreInBands_ThenReturnsBandStatus(t *testing.T) {
+	bands := ScoreBands{ReviewThreshold: 50, BlockThreshold: 80}
+
+	tests := []struct {
+		name     string
+		score    int
+		expected models.TransactionStatus
+	}{
+		{name: "below review", score: 49, expected: models.StatusApproved},
+		{name: "at review", score: 50, expected: models.StatusInReview},
+		{name: "below block", score: 79, expected: models.StatusInReview},
+		{name: "at block", score: 80, expected: models.StatusRejected},
+		{name: "above block", score: 250, expected: models.StatusRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, bands.Status(tt.score))
+		})
+	}
+}
+
+func Test_ScoreBands_Status_WhenBandsDisabled_ThenReturnsApproved(t *testing.T) {
+	bands := ScoreBands{}
+
+	status := bands.Status(1000)
+
+	assert.Equal(t, models.StatusApproved, status)
+}
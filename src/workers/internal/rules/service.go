@@ -2,52 +2,117 @@ package rules
 
 import (
 	"context"
+	"log"
+	"sort"
+	"sync"
 	"sync/atomic"
 
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/algo-shield/algo-shield/src/pkg/rules"
+	"github.com/algo-shield/algo-shield/src/workers/internal/schemas"
+	"github.com/google/uuid"
 )
 
-// RuleService handles rule loading and caching for the worker
+// SchemaProvider defines the interface for looking up cached schemas during rule compilation
+type SchemaProvider interface {
+	GetSchema(id uuid.UUID) *schemas.EventSchema
+}
+
+// MacroProvider defines the interface for looking up the macros inlined during rule compilation
+type MacroProvider interface {
+	Macros() *expressions.Macros
+}
+
+// RuleService handles rule loading, compilation and caching for the worker
 // Uses atomic.Value for lock-free reads and safe concurrent updates
 // Uses RuleReader interface (ISP) - worker only needs LoadRules, not full CRUD
 type RuleService struct {
 	repo        rules.RuleReader // Only needs read access, not full Repository
-	loadedRules atomic.Value     // stores []models.Rule
+	schemas     SchemaProvider
+	macros      MacroProvider
+	loadedRules atomic.Value // stores []CompiledRule
+	reloadMu    sync.Mutex   // serializes LoadRules and Recompile so a stale compile never wins
 }
 
 // NewRuleService creates a new rule service for the worker with dependency injection
-// Follows Dependency Inversion Principle - receives interface, not concrete type
-func NewRuleService(repo rules.RuleReader) *RuleService {
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+// macroProvider may be nil, in which case rules are compiled without macros
+func NewRuleService(repo rules.RuleReader, schemaProvider SchemaProvider, macroProvider MacroProvider) *RuleService {
 	rs := &RuleService{
-		repo: repo,
+		repo:    repo,
+		schemas: schemaProvider,
+		macros:  macroProvider,
 	}
 	// Initialize with empty slice
-	rs.loadedRules.Store(make([]models.Rule, 0))
+	rs.loadedRules.Store(make([]CompiledRule, 0))
 	return rs
 }
 
-// LoadRules loads rules from the repository
-// Thread-safe: uses atomic.Value.Store() for lock-free updates
+// LoadRules loads rules from the repository and compiles their expressions
+// Thread-safe: the compiled rule set is swapped with a single atomic.Value.Store()
+// The lock is held while fetching, so a slow reload never stores rules older than a concurrent one
 func (rl *RuleService) LoadRules(ctx context.Context) error {
+	rl.reloadMu.Lock()
+	defer rl.reloadMu.Unlock()
+
 	rules, err := rl.repo.LoadRules(ctx)
 	if err != nil {
 		return err
 	}
-	// Store a copy to ensure immutability
-	rulesCopy := make([]models.Rule, len(rules))
-	copy(rulesCopy, rules)
-	rl.loadedRules.Store(rulesCopy)
+	rl.loadedRules.Store(rl.compileRules(rules))
 	return nil
 }
 
+// Recompile recompiles the currently loaded rules against the latest schemas and macros
+// Called when a schema or macro is invalidated so rules pick up new field types and macro expressions
+func (rl *RuleService) Recompile() {
+	rl.reloadMu.Lock()
+	defer rl.reloadMu.Unlock()
+	rl.loadedRules.Store(rl.compileRules(rl.GetRules()))
+}
+
+// compileRules compiles every rule, logging rules that fail to compile
+// Rules are kept in evaluation order (priority ASC); the sort is stable so the
+// repository order breaks ties deterministically
+func (rl *RuleService) compileRules(rules []models.Rule) []CompiledRule {
+	sorted := make([]models.Rule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority < sorted[j].Priority
+	})
+	rules = sorted
+
+	var macros *expressions.Macros
+	if rl.macros != nil {
+		macros = rl.macros.Macros()
+	}
+
+	compiled := make([]CompiledRule, len(rules))
+	for i, rule := range rules {
+		compiled[i] = compileRule(rule, rl.schemas, macros)
+		if compiled[i].Err != nil {
+			log.Printf("Rule %s will not match: %v", rule.Name, compiled[i].Err)
+		}
+	}
+	return compiled
+}
+
 // GetRules returns the currently loaded rules
 // Thread-safe: uses atomic.Value.Load() for lock-free reads
 // Returns a copy to prevent external modification
 func (rl *RuleService) GetRules() []models.Rule {
-	loadedRules := rl.loadedRules.Load().([]models.Rule)
-	// Return a copy to prevent external modification
-	result := make([]models.Rule, len(loadedRules))
-	copy(result, loadedRules)
+	compiled := rl.GetCompiledRules()
+	result := make([]models.Rule, len(compiled))
+	for i := range compiled {
+		result[i] = compiled[i].Rule
+	}
 	return result
 }
+
+// GetCompiledRules returns the currently loaded compiled rule set
+// Thread-safe: uses atomic.Value.Load() for lock-free reads
+// The returned slice is shared and must not be modified
+func (rl *RuleService) GetCompiledRules() []CompiledRule {
+	return rl.loadedRules.Load().([]CompiledRule)
+}
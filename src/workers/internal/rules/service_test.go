@@ -5,7 +5,10 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
 	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/algo-shield/algo-shield/src/workers/internal/schemas"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -21,7 +24,7 @@ func Test_RuleService_LoadRules_WhenSuccess_ThenStoresRules(t *testing.T) {
 	}
 	mockRepo := NewMockRuleReader(ctrl)
 	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(expectedRules, nil)
-	service := NewRuleService(mockRepo)
+	service := NewRuleService(mockRepo, nil, nil)
 
 	err := service.LoadRules(context.Background())
 
@@ -36,7 +39,7 @@ func Test_RuleService_LoadRules_WhenRepositoryFails_ThenReturnsError(t *testing.
 
 	mockRepo := NewMockRuleReader(ctrl)
 	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(nil, errors.New("database error"))
-	service := NewRuleService(mockRepo)
+	service := NewRuleService(mockRepo, nil, nil)
 
 	err := service.LoadRules(context.Background())
 
@@ -48,7 +51,7 @@ func Test_RuleService_GetRules_WhenEmpty_ThenReturnsEmptySlice(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := NewMockRuleReader(ctrl)
-	service := NewRuleService(mockRepo)
+	service := NewRuleService(mockRepo, nil, nil)
 
 	rules := service.GetRules()
 
@@ -64,7 +67,7 @@ func Test_RuleService_GetRules_WhenRulesLoaded_ThenReturnsCopy(t *testing.T) {
 	}
 	mockRepo := NewMockRuleReader(ctrl)
 	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(expectedRules, nil)
-	service := NewRuleService(mockRepo)
+	service := NewRuleService(mockRepo, nil, nil)
 	err := service.LoadRules(context.Background())
 	require.NoError(t, err)
 
@@ -84,7 +87,7 @@ func Test_RuleService_LoadRules_WhenCalledMultipleTimes_ThenUpdatesRules(t *test
 	mockRepo := NewMockRuleReader(ctrl)
 	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(firstRules, nil)
 	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(secondRules, nil)
-	service := NewRuleService(mockRepo)
+	service := NewRuleService(mockRepo, nil, nil)
 
 	err := service.LoadRules(context.Background())
 	require.NoError(t, err)
@@ -99,6 +102,35 @@ func Test_RuleService_LoadRules_WhenCalledMultipleTimes_ThenUpdatesRules(t *test
 	assert.Equal(t, secondRules, rules2)
 }
 
+func Test_RuleService_LoadRules_WhenReloadsOverlap_ThenStoresLatestFetch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	mockRepo := NewMockRuleReader(ctrl)
+	gomock.InOrder(
+		mockRepo.EXPECT().LoadRules(gomock.Any()).DoAndReturn(func(context.Context) ([]models.Rule, error) {
+			close(fetching)
+			<-release
+			return []models.Rule{{Name: "stale"}}, nil
+		}),
+		mockRepo.EXPECT().LoadRules(gomock.Any()).Return([]models.Rule{{Name: "latest"}}, nil),
+	)
+	service := NewRuleService(mockRepo, nil, nil)
+
+	slow := make(chan error)
+	go func() { slow <- service.LoadRules(context.Background()) }()
+	<-fetching
+	fast := make(chan error)
+	go func() { fast <- service.LoadRules(context.Background()) }()
+	close(release)
+
+	require.NoError(t, <-slow)
+	require.NoError(t, <-fast)
+	assert.Equal(t, "latest", service.GetRules()[0].Name)
+}
+
 func Test_RuleService_GetRules_WhenModifyingReturned_ThenDoesNotAffectStored(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -106,7 +138,7 @@ func Test_RuleService_GetRules_WhenModifyingReturned_ThenDoesNotAffectStored(t *
 	expectedRules := []models.Rule{{Name: "rule1", Action: models.ActionAllow, Conditions: map[string]any{}}}
 	mockRepo := NewMockRuleReader(ctrl)
 	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(expectedRules, nil)
-	service := NewRuleService(mockRepo)
+	service := NewRuleService(mockRepo, nil, nil)
 	err := service.LoadRules(context.Background())
 	require.NoError(t, err)
 
@@ -116,3 +148,160 @@ func Test_RuleService_GetRules_WhenModifyingReturned_ThenDoesNotAffectStored(t *
 	storedRules := service.GetRules()
 	assert.Equal(t, "rule1", storedRules[0].Name, "stored rules should not be affected by external modification")
 }
+
+func Test_RuleService_LoadRules_WhenExpressionValid_ThenCompilesProgram(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	schema := &schemas.EventSchema{
+		ID:              schemaID,
+		ExtractedFields: []schemas.ExtractedField{{Path: "amount", Type: schemas.FieldTypeNumber}},
+	}
+	loaded := []models.Rule{
+		{Name: "rule1", SchemaID: &schemaID, Conditions: map[string]any{"custom_expression": "amount > 100"}},
+	}
+	mockRepo := NewMockRuleReader(ctrl)
+	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(loaded, nil)
+	mockSchemas := NewMockSchemaProvider(ctrl)
+	mockSchemas.EXPECT().GetSchema(schemaID).Return(schema)
+	service := NewRuleService(mockRepo, mockSchemas, nil)
+
+	err := service.LoadRules(context.Background())
+
+	require.NoError(t, err)
+	compiled := service.GetCompiledRules()
+	require.Len(t, compiled, 1)
+	assert.NoError(t, compiled[0].Err)
+	assert.NotNil(t, compiled[0].Program)
+	assert.Same(t, schema, compiled[0].Schema)
+}
+
+func Test_RuleService_LoadRules_WhenExpressionInvalid_ThenStoresCompileError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	schema := &schemas.EventSchema{
+		ID:              schemaID,
+		ExtractedFields: []schemas.ExtractedField{{Path: "currency", Type: schemas.FieldTypeString}},
+	}
+	loaded := []models.Rule{
+		{Name: "rule1", SchemaID: &schemaID, Conditions: map[string]any{"custom_expression": "currency > 100"}},
+	}
+	mockRepo := NewMockRuleReader(ctrl)
+	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(loaded, nil)
+	mockSchemas := NewMockSchemaProvider(ctrl)
+	mockSchemas.EXPECT().GetSchema(schemaID).Return(schema)
+	service := NewRuleService(mockRepo, mockSchemas, nil)
+
+	err := service.LoadRules(context.Background())
+
+	require.NoError(t, err)
+	compiled := service.GetCompiledRules()
+	require.Len(t, compiled, 1)
+	assert.Error(t, compiled[0].Err)
+	assert.Nil(t, compiled[0].Program)
+}
+
+func Test_RuleService_LoadRules_WhenSchemaMissing_ThenStoresError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	loaded := []models.Rule{
+		{Name: "no-schema-id", Conditions: map[string]any{"custom_expression": "true"}},
+		{Name: "unknown-schema", SchemaID: &schemaID, Conditions: map[string]any{"custom_expression": "true"}},
+		{Name: "no-expression", SchemaID: &schemaID, Conditions: map[string]any{}},
+	}
+	mockRepo := NewMockRuleReader(ctrl)
+	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(loaded, nil)
+	mockSchemas := NewMockSchemaProvider(ctrl)
+	mockSchemas.EXPECT().GetSchema(schemaID).Return(nil)
+	service := NewRuleService(mockRepo, mockSchemas, nil)
+
+	err := service.LoadRules(context.Background())
+
+	require.NoError(t, err)
+	compiled := service.GetCompiledRules()
+	require.Len(t, compiled, 3)
+	assert.ErrorIs(t, compiled[0].Err, ErrMissingSchemaID)
+	assert.Error(t, compiled[1].Err)
+	assert.ErrorIs(t, compiled[2].Err, ErrMissingExpression)
+}
+
+func Test_RuleService_Recompile_WhenSchemaChanged_ThenUsesNewSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	oldSchema := &schemas.EventSchema{ID: schemaID}
+	newSchema := &schemas.EventSchema{
+		ID:              schemaID,
+		ExtractedFields: []schemas.ExtractedField{{Path: "user.country", Type: schemas.FieldTypeString}},
+	}
+	loaded := []models.Rule{
+		{Name: "rule1", SchemaID: &schemaID, Conditions: map[string]any{"custom_expression": `user.country == "BR"`}},
+	}
+	mockRepo := NewMockRuleReader(ctrl)
+	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(loaded, nil)
+	mockSchemas := NewMockSchemaProvider(ctrl)
+	gomock.InOrder(
+		mockSchemas.EXPECT().GetSchema(schemaID).Return(oldSchema),
+		mockSchemas.EXPECT().GetSchema(schemaID).Return(newSchema),
+	)
+	service := NewRuleService(mockRepo, mockSchemas, nil)
+	require.NoError(t, service.LoadRules(context.Background()))
+	require.Error(t, service.GetCompiledRules()[0].Err)
+
+	service.Recompile()
+
+	compiled := service.GetCompiledRules()
+	require.Len(t, compiled, 1)
+	assert.NoError(t, compiled[0].Err)
+	assert.Same(t, newSchema, compiled[0].Schema)
+}
+
+func Test_RuleService_Recompile_WhenMacroChanged_ThenInlinesNewExpression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schemaID := uuid.New()
+	schema := &schemas.EventSchema{
+		ID:              schemaID,
+		ExtractedFields: []schemas.ExtractedField{{Path: "amount", Type: schemas.FieldTypeNumber}},
+	}
+	loaded := []models.Rule{
+		{Name: "rule1", SchemaID: &schemaID, Conditions: map[string]any{"custom_expression": "highAmount"}},
+	}
+	mockRepo := NewMockRuleReader(ctrl)
+	mockRepo.EXPECT().LoadRules(gomock.Any()).Return(loaded, nil)
+	mockSchemas := NewMockSchemaProvider(ctrl)
+	mockSchemas.EXPECT().GetSchema(schemaID).Return(schema).Times(2)
+
+	valid, err := expressions.NewMacros([]models.Macro{{Name: "highAmount", Expression: "amount > 100"}})
+	require.NoError(t, err)
+	mistyped, err := expressions.NewMacros([]models.Macro{{Name: "highAmount", Expression: `amount > "100"`}})
+	require.NoError(t, err)
+
+	mockMacros := NewMockMacroProvider(ctrl)
+	gomock.InOrder(
+		mockMacros.EXPECT().Macros().Return(valid),
+		mockMacros.EXPECT().Macros().Return(mistyped),
+	)
+	service := NewRuleService(mockRepo, mockSchemas, mockMacros)
+
+	require.NoError(t, service.LoadRules(context.Background()))
+	compiled := service.GetCompiledRules()
+	require.Len(t, compiled, 1)
+	require.NoError(t, compiled[0].Err)
+	matched, err := schemas.RunExpression(compiled[0].Program, map[string]any{"amount": 150.0})
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	service.Recompile()
+
+	compiled = service.GetCompiledRules()
+	require.Len(t, compiled, 1)
+	assert.Error(t, compiled[0].Err)
+}
@@ -0,0 +1,87 @@
+package schemas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/algo-shield/algo-shield/src/pkg/expressions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSchema() *EventSchema {
+	return &EventSchema{
+		ExtractedFields: []ExtractedField{
+			{Path: "amount", Type: FieldTypeNumber},
+			{Path: "currency", Type: FieldTypeString},
+			{Path: "user.country", Type: FieldTypeString},
+			{Path: "tags", Type: FieldTypeArray},
+		},
+	}
+}
+
+func Test_CompileExpression_WhenNestedField_ThenCompiles(t *testing.T) {
+	program, err := CompileExpression(`user.country == "BR" and amount > 100`, testSchema())
+
+	require.NoError(t, err)
+	assert.NotNil(t, program)
+}
+
+func Test_CompileExpression_WhenTypeMismatch_ThenReturnsError(t *testing.T) {
+	_, err := CompileExpression(`currency > 100`, testSchema())
+
+	assert.Error(t, err)
+}
+
+func Test_CompileExpression_WhenUnknownField_ThenReturnsError(t *testing.T) {
+	_, err := CompileExpression(`unknown_field == 1`, testSchema())
+
+	assert.Error(t, err)
+}
+
+func Test_CompileExpression_WhenNotBoolean_ThenReturnsError(t *testing.T) {
+	_, err := CompileExpression(`amount + 1`, testSchema())
+
+	assert.Error(t, err)
+}
+
+func Test_CompileExpression_WhenEmpty_ThenReturnsErrEmptyExpression(t *testing.T) {
+	_, err := CompileExpression("", testSchema())
+
+	assert.ErrorIs(t, err, ErrEmptyExpression)
+}
+
+func Test_RunExpression_WhenEventMatches_ThenReturnsTrue(t *testing.T) {
+	schema := testSchema()
+	program, err := CompileExpression(`user.country == "BR" and amount > 100 and "vip" in tags`, schema)
+	require.NoError(t, err)
+	event := map[string]any{
+		"amount":   150.0,
+		"currency": "BRL",
+		"user":     map[string]any{"country": "BR"},
+		"tags":     []any{"vip"},
+	}
+	env := BuildExpressionEnv(context.Background(), event, schema, expressions.Lookups{})
+
+	matched, err := RunExpression(program, env)
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func Test_RunExpression_WhenFieldMissingAtRuntime_ThenReturnsError(t *testing.T) {
+	schema := testSchema()
+	program, err := CompileExpression(`amount > 100`, schema)
+	require.NoError(t, err)
+	env := BuildExpressionEnv(context.Background(), map[string]any{"currency": "USD"}, schema, expressions.Lookups{})
+
+	_, err = RunExpression(program, env)
+
+	assert.Error(t, err)
+}
+
+func Test_CompileExpression_WhenUsingHelpers_ThenCompiles(t *testing.T) {
+	_, err := CompileExpression(`velocityCount(currency, 3600) > 10 or velocitySum(currency, 60) > 1000.0`, testSchema())
+
+	assert.NoError(t, err)
+}
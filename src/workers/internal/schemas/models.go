@@ -3,28 +3,25 @@ package schemas
 import (
 	"time"
 
+	"github.com/algo-shield/algo-shield/src/pkg/models"
 	"github.com/google/uuid"
 )
 
 // FieldType represents the inferred type of a JSON field
-type FieldType string
+// Shared with the worker so both sides type expressions the same way
+type FieldType = models.FieldType
 
 const (
-	FieldTypeString  FieldType = "string"
-	FieldTypeNumber  FieldType = "number"
-	FieldTypeBoolean FieldType = "boolean"
-	FieldTypeArray   FieldType = "array"
-	FieldTypeObject  FieldType = "object"
-	FieldTypeNull    FieldType = "null"
+	FieldTypeString  = models.FieldTypeString
+	FieldTypeNumber  = models.FieldTypeNumber
+	FieldTypeBoolean = models.FieldTypeBoolean
+	FieldTypeArray   = models.FieldTypeArray
+	FieldTypeObject  = models.FieldTypeObject
+	FieldTypeNull    = models.FieldTypeNull
 )
 
 // ExtractedField represents a field extracted from sample JSON
-type ExtractedField struct {
-	Path        string    `json:"path"`
-	Type        FieldType `json:"type"`
-	Nullable    bool      `json:"nullable"`
-	SampleValue any       `json:"sample_value,omitempty"`
-}
+type ExtractedField = models.ExtractedField
 
 // EventSchema represents a user-defined event schema
 type EventSchema struct {
@@ -17,10 +17,11 @@ const (
 // SchemaService handles schema loading and caching for the worker
 // Uses sync.RWMutex for thread-safe reads and writes
 type SchemaService struct {
-	repo    Repository
-	redis   *redis.Client
-	schemas map[uuid.UUID]*EventSchema
-	mu      sync.RWMutex
+	repo         Repository
+	redis        *redis.Client
+	schemas      map[uuid.UUID]*EventSchema
+	mu           sync.RWMutex
+	onInvalidate []func(id uuid.UUID)
 }
 
 // NewSchemaService creates a new schema service for the worker
@@ -61,22 +62,32 @@ func (s *SchemaService) GetSchema(id uuid.UUID) *EventSchema {
 	return s.schemas[id]
 }
 
-// InvalidateSchema removes a schema from the cache, forcing a reload on next access
-func (s *SchemaService) InvalidateSchema(ctx context.Context, id uuid.UUID) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	delete(s.schemas, id)
+// OnInvalidate registers a callback invoked after a schema has been reloaded or removed
+// Callbacks run outside the cache lock, so they may safely call GetSchema
+// Must be called before SubscribeToInvalidations starts
+func (s *SchemaService) OnInvalidate(fn func(id uuid.UUID)) {
+	s.onInvalidate = append(s.onInvalidate, fn)
+}
 
-	// Reload from database if needed
+// InvalidateSchema reloads a schema from the repository, removing it from the cache if it no longer exists
+func (s *SchemaService) InvalidateSchema(ctx context.Context, id uuid.UUID) {
+	// Reload from database outside the lock to keep readers unblocked
 	schema, err := s.repo.GetByID(ctx, id)
+
+	s.mu.Lock()
 	if err != nil {
 		// Schema was deleted, just remove from cache
+		delete(s.schemas, id)
 		log.Printf("Schema %s was deleted or not found", id)
-		return
+	} else {
+		s.schemas[id] = schema
+		log.Printf("Reloaded schema %s", id)
 	}
+	s.mu.Unlock()
 
-	s.schemas[id] = schema
-	log.Printf("Reloaded schema %s", id)
+	for _, fn := range s.onInvalidate {
+		fn(id)
+	}
 }
 
 // SubscribeToInvalidations subscribes to Redis pub/sub for schema invalidation events
@@ -55,3 +55,41 @@ func (mr *MockRuleEvaluatorMockRecorder) Evaluate(ctx, event any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evaluate", reflect.TypeOf((*MockRuleEvaluator)(nil).Evaluate), ctx, event)
 }
+
+// MockDecisionReplier is a mock of DecisionReplier interface.
+type MockDecisionReplier struct {
+	ctrl     *gomock.Controller
+	recorder *MockDecisionReplierMockRecorder
+	isgomock struct{}
+}
+
+// MockDecisionReplierMockRecorder is the mock recorder for MockDecisionReplier.
+type MockDecisionReplierMockRecorder struct {
+	mock *MockDecisionReplier
+}
+
+// NewMockDecisionReplier creates a new mock instance.
+func NewMockDecisionReplier(ctrl *gomock.Controller) *MockDecisionReplier {
+	mock := &MockDecisionReplier{ctrl: ctrl}
+	mock.recorder = &MockDecisionReplierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDecisionReplier) EXPECT() *MockDecisionReplierMockRecorder {
+	return m.recorder
+}
+
+// PublishDecision mocks base method.
+func (m *MockDecisionReplier) PublishDecision(ctx context.Context, correlationID string, result *models.TransactionResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDecision", ctx, correlationID, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishDecision indicates an expected call of PublishDecision.
+func (mr *MockDecisionReplierMockRecorder) PublishDecision(ctx, correlationID, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDecision", reflect.TypeOf((*MockDecisionReplier)(nil).PublishDecision), ctx, correlationID, result)
+}
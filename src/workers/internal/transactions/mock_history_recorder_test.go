@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/workers/internal/transactions/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/workers/internal/transactions/service.go -destination=src/workers/internal/transactions/mock_history_recorder_test.go -package=transactions -exclude_interfaces=RuleEvaluator,DecisionReplier,TimelineRecorder
+//
+
+// Package transactions is a generated GoMock package.
+package transactions
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHistoryRecorder is a mock of HistoryRecorder interface.
+type MockHistoryRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockHistoryRecorderMockRecorder is the mock recorder for MockHistoryRecorder.
+type MockHistoryRecorderMockRecorder struct {
+	mock *MockHistoryRecorder
+}
+
+// NewMockHistoryRecorder creates a new mock instance.
+func NewMockHistoryRecorder(ctrl *gomock.Controller) *MockHistoryRecorder {
+	mock := &MockHistoryRecorder{ctrl: ctrl}
+	mock.recorder = &MockHistoryRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryRecorder) EXPECT() *MockHistoryRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordTransaction mocks base method.
+func (m *MockHistoryRecorder) RecordTransaction(ctx context.Context, account, transactionID string, amount float64, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordTransaction", ctx, account, transactionID, amount, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordTransaction indicates an expected call of RecordTransaction.
+func (mr *MockHistoryRecorderMockRecorder) RecordTransaction(ctx, account, transactionID, amount, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordTransaction", reflect.TypeOf((*MockHistoryRecorder)(nil).RecordTransaction), ctx, account, transactionID, amount, at)
+}
+
+// RemoveTransaction mocks base method.
+func (m *MockHistoryRecorder) RemoveTransaction(ctx context.Context, account, transactionID string, amount float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTransaction", ctx, account, transactionID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTransaction indicates an expected call of RemoveTransaction.
+func (mr *MockHistoryRecorderMockRecorder) RemoveTransaction(ctx, account, transactionID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTransaction", reflect.TypeOf((*MockHistoryRecorder)(nil).RemoveTransaction), ctx, account, transactionID, amount)
+}
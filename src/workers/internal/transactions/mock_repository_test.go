@@ -41,6 +41,21 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// GetResultByExternalID mocks base method.
+func (m *MockRepository) GetResultByExternalID(ctx context.Context, externalID string) (*models.TransactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResultByExternalID", ctx, externalID)
+	ret0, _ := ret[0].(*models.TransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResultByExternalID indicates an expected call of GetResultByExternalID.
+func (mr *MockRepositoryMockRecorder) GetResultByExternalID(ctx, externalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResultByExternalID", reflect.TypeOf((*MockRepository)(nil).GetResultByExternalID), ctx, externalID)
+}
+
 // SaveTransaction mocks base method.
 func (m *MockRepository) SaveTransaction(ctx context.Context, transaction *models.Transaction) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/workers/internal/transactions/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=src/workers/internal/transactions/service.go -destination=src/workers/internal/transactions/mock_timeline_recorder_test.go -package=transactions -exclude_interfaces=RuleEvaluator,DecisionReplier,HistoryRecorder
+//
+
+// Package transactions is a generated GoMock package.
+package transactions
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/algo-shield/algo-shield/src/pkg/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTimelineRecorder is a mock of TimelineRecorder interface.
+type MockTimelineRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockTimelineRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockTimelineRecorderMockRecorder is the mock recorder for MockTimelineRecorder.
+type MockTimelineRecorderMockRecorder struct {
+	mock *MockTimelineRecorder
+}
+
+// NewMockTimelineRecorder creates a new mock instance.
+func NewMockTimelineRecorder(ctrl *gomock.Controller) *MockTimelineRecorder {
+	mock := &MockTimelineRecorder{ctrl: ctrl}
+	mock.recorder = &MockTimelineRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTimelineRecorder) EXPECT() *MockTimelineRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordEvent mocks base method.
+func (m *MockTimelineRecorder) RecordEvent(ctx context.Context, entity string, event models.TimelineEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordEvent", ctx, entity, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordEvent indicates an expected call of RecordEvent.
+func (mr *MockTimelineRecorderMockRecorder) RecordEvent(ctx, entity, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordEvent", reflect.TypeOf((*MockTimelineRecorder)(nil).RecordEvent), ctx, entity, event)
+}
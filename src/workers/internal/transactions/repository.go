@@ -3,15 +3,36 @@ package transactions
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var (
+	// ErrDuplicateTransaction is returned when a transaction with the same external_id is already saved
+	ErrDuplicateTransaction = errors.New("transaction already saved")
+	// ErrRejectedTransaction is returned when the database rejects the transaction data,
+	// which saving again cannot fix
+	ErrRejectedTransaction = errors.New("transaction rejected by the database")
+)
+
+// externalIDConstraint is the unique constraint on transactions.external_id
+const externalIDConstraint = "transactions_external_id_key"
+
 // Repository defines the interface for transaction data access operations
 type Repository interface {
 	// SaveTransaction saves a processed transaction to the database
+	// Returns ErrDuplicateTransaction when its external_id is already saved
+	// and ErrRejectedTransaction when the data is invalid
 	SaveTransaction(ctx context.Context, transaction *models.Transaction) error
+	// GetResultByExternalID returns the decision saved for a transaction
+	// Returns pgx.ErrNoRows when no transaction has this external_id
+	GetResultByExternalID(ctx context.Context, externalID string) (*models.TransactionResult, error)
 }
 
 // PostgresRepository is the PostgreSQL implementation of Repository
@@ -26,17 +47,29 @@ func NewPostgresRepository(db *pgxpool.Pool) Repository {
 
 func (r *PostgresRepository) SaveTransaction(ctx context.Context, transaction *models.Transaction) error {
 	matchedRulesJSON, _ := json.Marshal(transaction.MatchedRules)
+	matchedVersionsJSON, _ := json.Marshal(transaction.MatchedVersions)
+	shadowMatchedJSON, _ := json.Marshal(transaction.ShadowMatchedRules)
+	shadowVersionsJSON, _ := json.Marshal(transaction.ShadowVersions)
+	timedOutJSON, _ := json.Marshal(transaction.TimedOutRules)
 	metadataJSON, _ := json.Marshal(transaction.Metadata)
+	eventJSON, _ := json.Marshal(transaction.Event)
 
 	query := `
 		INSERT INTO transactions (
 			id, external_id, amount, currency, origin, destination, 
-			type, status, processing_time, 
-			matched_rules, metadata, created_at, processed_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			type, status, risk_score, processing_time, 
+			matched_rules, matched_rule_versions, shadow_matched_rules, shadow_matched_rule_versions, decided_by_rule_id, timed_out_rules, timeout_fallback,
+			metadata, event_payload, created_at, processed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 	`
 
-	_, err := r.db.Exec(ctx, query,
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, query,
 		transaction.ID,
 		transaction.ExternalID,
 		transaction.Amount,
@@ -45,12 +78,124 @@ func (r *PostgresRepository) SaveTransaction(ctx context.Context, transaction *m
 		transaction.Destination,
 		transaction.Type,
 		transaction.Status,
+		transaction.RiskScore,
 		transaction.ProcessingTime,
 		matchedRulesJSON,
+		matchedVersionsJSON,
+		shadowMatchedJSON,
+		shadowVersionsJSON,
+		transaction.DecidedBy,
+		timedOutJSON,
+		transaction.TimeoutFallback,
 		metadataJSON,
+		eventJSON,
 		transaction.CreatedAt,
 		transaction.ProcessedAt,
 	)
+	if err != nil {
+		return classifySaveError(err, transaction.ExternalID)
+	}
+
+	// Baselines are updated with the transaction, so a failed or duplicate save never counts twice
+	if transaction.Origin != "" {
+		if err := updateAccountStats(ctx, tx, transaction); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// classifySaveError tells duplicate and invalid transactions apart from transient failures
+func classifySaveError(err error, externalID string) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	// Events without an ID all share the empty external_id, they are not retries of each other
+	if pgErr.Code == "23505" && pgErr.ConstraintName == externalIDConstraint && externalID != "" {
+		return fmt.Errorf("%w: %s", ErrDuplicateTransaction, externalID)
+	}
+
+	// Class 22 is data exceptions and class 23 integrity constraint violations
+	if strings.HasPrefix(pgErr.Code, "22") || strings.HasPrefix(pgErr.Code, "23") {
+		return fmt.Errorf("%w: %v", ErrRejectedTransaction, err)
+	}
+
+	return err
+}
+
+func (r *PostgresRepository) GetResultByExternalID(ctx context.Context, externalID string) (*models.TransactionResult, error) {
+	var result models.TransactionResult
+
+	query := `
+		SELECT id, status, risk_score, processing_time,
+		       matched_rules, matched_rule_versions, shadow_matched_rules, shadow_matched_rule_versions, decided_by_rule_id, timed_out_rules, timeout_fallback
+		FROM transactions
+		WHERE external_id = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, externalID).Scan(
+		&result.TransactionID,
+		&result.Status,
+		&result.RiskScore,
+		&result.ProcessingTime,
+		&result.MatchedRules,
+		&result.MatchedVersions,
+		&result.ShadowMatchedRules,
+		&result.ShadowVersions,
+		&result.DecidedBy,
+		&result.TimedOutRules,
+		&result.TimeoutFallback,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// updateAccountStats adds a transaction to its account's profile, last known location and daily amount sums
+func updateAccountStats(ctx context.Context, tx pgx.Tx, transaction *models.Transaction) error {
+	profileQuery := `
+		INSERT INTO account_profiles (account, first_seen_at, last_seen_at, transaction_count)
+		VALUES ($1, $2, $2, 1)
+		ON CONFLICT (account) DO UPDATE SET
+			first_seen_at = LEAST(account_profiles.first_seen_at, EXCLUDED.first_seen_at),
+			last_seen_at = GREATEST(account_profiles.last_seen_at, EXCLUDED.last_seen_at),
+			transaction_count = account_profiles.transaction_count + 1
+	`
+
+	if _, err := tx.Exec(ctx, profileQuery, transaction.Origin, transaction.CreatedAt); err != nil {
+		return err
+	}
+
+	// Out-of-order saves never replace a more recent location
+	if transaction.Location != nil {
+		locationQuery := `
+			UPDATE account_profiles
+			SET last_latitude = $2, last_longitude = $3, last_location_at = $4
+			WHERE account = $1
+			AND (last_location_at IS NULL OR last_location_at <= $4)
+		`
+
+		_, err := tx.Exec(ctx, locationQuery, transaction.Origin,
+			transaction.Location.Latitude, transaction.Location.Longitude, transaction.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	dailyQuery := `
+		INSERT INTO account_daily_stats (account, day, transaction_count, amount_sum, amount_sum_squares)
+		VALUES ($1, $2::timestamptz::date, 1, $3::numeric, $3::numeric * $3::numeric)
+		ON CONFLICT (account, day) DO UPDATE SET
+			transaction_count = account_daily_stats.transaction_count + 1,
+			amount_sum = account_daily_stats.amount_sum + EXCLUDED.amount_sum,
+			amount_sum_squares = account_daily_stats.amount_sum_squares + EXCLUDED.amount_sum_squares
+	`
 
+	_, err := tx.Exec(ctx, dailyQuery, transaction.Origin, transaction.CreatedAt, transaction.Amount)
 	return err
 }
@@ -0,0 +1,36 @@
+package transactions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_classifySaveError_WhenExternalIDExists_ThenReturnsDuplicate(t *testing.T) {
+	err := classifySaveError(&pgconn.PgError{Code: "23505", ConstraintName: externalIDConstraint}, "tx-123")
+
+	assert.ErrorIs(t, err, ErrDuplicateTransaction)
+}
+
+func Test_classifySaveError_WhenExternalIDEmpty_ThenReturnsRejected(t *testing.T) {
+	err := classifySaveError(&pgconn.PgError{Code: "23505", ConstraintName: externalIDConstraint}, "")
+
+	assert.ErrorIs(t, err, ErrRejectedTransaction)
+	assert.NotErrorIs(t, err, ErrDuplicateTransaction)
+}
+
+func Test_classifySaveError_WhenDataInvalid_ThenReturnsRejected(t *testing.T) {
+	err := classifySaveError(&pgconn.PgError{Code: "22001"}, "tx-123")
+
+	assert.ErrorIs(t, err, ErrRejectedTransaction)
+}
+
+func Test_classifySaveError_WhenFailureIsTransient_ThenReturnsErrorUnchanged(t *testing.T) {
+	connErr := errors.New("connection refused")
+	deadlock := &pgconn.PgError{Code: "40P01"}
+
+	assert.Equal(t, connErr, classifySaveError(connErr, "tx-123"))
+	assert.Equal(t, deadlock, classifySaveError(deadlock, "tx-123"))
+}
@@ -2,6 +2,7 @@ package transactions
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 
@@ -14,23 +15,53 @@ type RuleEvaluator interface {
 	Evaluate(ctx context.Context, event models.Event) (*models.TransactionResult, error)
 }
 
+// DecisionReplier defines the interface for replying to synchronous decision requests
+type DecisionReplier interface {
+	PublishDecision(ctx context.Context, correlationID string, result *models.TransactionResult) error
+}
+
+// HistoryRecorder defines the interface for recording transactions for velocity helpers
+type HistoryRecorder interface {
+	RecordTransaction(ctx context.Context, account, transactionID string, amount float64, at time.Time) error
+	RemoveTransaction(ctx context.Context, account, transactionID string, amount float64) error
+}
+
+// TimelineRecorder defines the interface for adding events to entity timelines for sequence helpers
+type TimelineRecorder interface {
+	RecordEvent(ctx context.Context, entity string, event models.TimelineEvent) error
+}
+
 // Service handles transaction processing business logic
 type Service struct {
 	repo          Repository
 	ruleEvaluator RuleEvaluator
+	replier       DecisionReplier
+	history       HistoryRecorder
+	timeline      TimelineRecorder
 }
 
 // NewService creates a new transaction service with dependency injection
-// Follows Dependency Inversion Principle - receives interface, not concrete type
-func NewService(repo Repository, ruleEvaluator RuleEvaluator) *Service {
+// Follows Dependency Inversion Principle - receives interfaces, not concrete types
+// history may be nil when velocity helpers query the database directly,
+// and timeline may be nil when timelines are disabled
+func NewService(repo Repository, ruleEvaluator RuleEvaluator, replier DecisionReplier, history HistoryRecorder, timeline TimelineRecorder) *Service {
 	return &Service{
 		repo:          repo,
 		ruleEvaluator: ruleEvaluator,
+		replier:       replier,
+		history:       history,
+		timeline:      timeline,
 	}
 }
 
 // ProcessTransaction processes an event by evaluating rules and saving the result
 func (s *Service) ProcessTransaction(ctx context.Context, event models.Event) error {
+	transactionID := uuid.New()
+	now := time.Now()
+
+	// Add the event to its entity's timeline before evaluating, so a sequence can end with this event
+	s.recordTimelineEvent(ctx, event, transactionID, now)
+
 	// Evaluate event against rules
 	result, err := s.ruleEvaluator.Evaluate(ctx, event)
 	if err != nil {
@@ -38,9 +69,6 @@ func (s *Service) ProcessTransaction(ctx context.Context, event models.Event) er
 	}
 
 	// Create transaction record
-	transactionID := uuid.New()
-	now := time.Now()
-
 	// Extract fields from generic event (with fallbacks for common field names)
 	externalID := extractStringFromEvent(event, "external_id", "id", "event_id")
 	amount := extractFloat64FromEvent(event, "amount", "value", "total")
@@ -58,35 +86,197 @@ func (s *Service) ProcessTransaction(ctx context.Context, event models.Event) er
 		metadata = make(map[string]any)
 	}
 
+	// Make the transaction visible to velocity helpers on ingest, without waiting for it to be saved,
+	// so concurrent transactions count. Retries record the same entry, and DiscardTransaction
+	// removes it when the event is dead-lettered. Velocity is best effort: failures are only logged
+	s.recordHistory(ctx, origin, historyID(externalID, transactionID), amount, now)
+
 	transaction := &models.Transaction{
-		ID:             transactionID,
-		ExternalID:     externalID,
-		Amount:         amount,
-		Currency:       currency,
-		Origin:         origin,
-		Destination:    destination,
-		Type:           eventType,
-		Status:         result.Status,
-		ProcessingTime: result.ProcessingTime,
-		MatchedRules:   result.MatchedRules,
-		Metadata:       metadata,
-		CreatedAt:      now,
-		ProcessedAt:    &now,
+		ID:                 transactionID,
+		ExternalID:         externalID,
+		Amount:             amount,
+		Currency:           currency,
+		Origin:             origin,
+		Destination:        destination,
+		Type:               eventType,
+		Status:             result.Status,
+		RiskScore:          result.RiskScore,
+		ProcessingTime:     result.ProcessingTime,
+		MatchedRules:       result.MatchedRules,
+		MatchedVersions:    result.MatchedVersions,
+		ShadowMatchedRules: result.ShadowMatchedRules,
+		ShadowVersions:     result.ShadowVersions,
+		DecidedBy:          result.DecidedBy,
+		TimedOutRules:      result.TimedOutRules,
+		TimeoutFallback:    result.TimeoutFallback,
+		Metadata:           metadata,
+		Event:              storedEvent(event),
+		Location:           extractLocationFromEvent(event),
+		CreatedAt:          now,
+		ProcessedAt:        &now,
 	}
 
 	// Save transaction to database
+	// A duplicate is a retry of a transaction already processed: the first decision stands
 	if err := s.repo.SaveTransaction(ctx, transaction); err != nil {
+		if errors.Is(err, ErrDuplicateTransaction) {
+			log.Printf("Transaction %s already processed, skipping duplicate", externalID)
+			s.replyWithSavedDecision(ctx, event, externalID)
+			return nil
+		}
 		return err
 	}
 
+	// Reply to synchronous decision requests once the decision is persisted
+	// A failed reply is not retried: the API falls back to its default decision
+	if correlationID := extractStringFromEvent(event, models.DecisionReplyToField); correlationID != "" && s.replier != nil {
+		result.TransactionID = transactionID
+		if err := s.replier.PublishDecision(ctx, correlationID, result); err != nil {
+			log.Printf("Failed to publish decision for transaction %s: %v", externalID, err)
+		}
+	}
+
 	log.Printf(
-		"Processed transaction %s: status=%s, time=%dms",
-		externalID, result.Status, result.ProcessingTime,
+		"Processed transaction %s: status=%s, risk_score=%d, time=%dms",
+		externalID, result.Status, result.RiskScore, result.ProcessingTime,
 	)
 
 	return nil
 }
 
+// AssignEventID gives an event without an external ID a new one, in place
+// Call it before the first attempt, so every attempt records the event under the same ID
+func AssignEventID(event models.Event) {
+	if extractStringFromEvent(event, "external_id", "id", "event_id") == "" {
+		event["external_id"] = uuid.NewString()
+	}
+}
+
+// DiscardTransaction removes what processing an event left behind, once it is given up on
+// and dead-lettered, so a transaction that was never saved stops counting towards velocity
+// Events without an external ID cannot be found, see AssignEventID
+func (s *Service) DiscardTransaction(ctx context.Context, event models.Event) {
+	externalID := extractStringFromEvent(event, "external_id", "id", "event_id")
+	origin := extractStringFromEvent(event, "origin", "from_account", "account", "user_id", "customer_id")
+	if s.history == nil || externalID == "" || origin == "" {
+		return
+	}
+
+	amount := extractFloat64FromEvent(event, "amount", "value", "total")
+	if err := s.history.RemoveTransaction(ctx, origin, externalID, amount); err != nil {
+		log.Printf("Failed to remove velocity history for transaction %s: %v", externalID, err)
+	}
+}
+
+// recordHistory adds a transaction to its account's velocity history
+func (s *Service) recordHistory(ctx context.Context, origin, historyID string, amount float64, at time.Time) {
+	if s.history == nil || origin == "" {
+		return
+	}
+
+	if err := s.history.RecordTransaction(ctx, origin, historyID, amount, at); err != nil {
+		log.Printf("Failed to record velocity history for transaction %s: %v", historyID, err)
+	}
+}
+
+// historyID identifies a transaction in velocity history: its external ID when set, so retries
+// of the same event record one entry, otherwise the ID it is saved under
+func historyID(externalID string, transactionID uuid.UUID) string {
+	if externalID != "" {
+		return externalID
+	}
+	return transactionID.String()
+}
+
+// replyWithSavedDecision answers a decision request for a duplicate with the decision saved first
+func (s *Service) replyWithSavedDecision(ctx context.Context, event models.Event, externalID string) {
+	correlationID := extractStringFromEvent(event, models.DecisionReplyToField)
+	if correlationID == "" || s.replier == nil {
+		return
+	}
+
+	result, err := s.repo.GetResultByExternalID(ctx, externalID)
+	if err != nil {
+		log.Printf("Failed to load saved decision for transaction %s: %v", externalID, err)
+		return
+	}
+
+	if err := s.replier.PublishDecision(ctx, correlationID, result); err != nil {
+		log.Printf("Failed to publish decision for transaction %s: %v", externalID, err)
+	}
+}
+
+// recordTimelineEvent adds an event to the timeline of its entity, read from the same fields as the origin
+// Events of any schema share the entity's timeline. Timelines are best effort: failures are only logged
+func (s *Service) recordTimelineEvent(ctx context.Context, event models.Event, transactionID uuid.UUID, at time.Time) {
+	if s.timeline == nil {
+		return
+	}
+
+	entity := extractStringFromEvent(event, "origin", "from_account", "account", "user_id", "customer_id")
+	eventType := extractStringFromEvent(event, "type", "transaction_type", "event_type")
+	if entity == "" || eventType == "" {
+		return
+	}
+
+	eventID := extractStringFromEvent(event, "external_id", "id", "event_id")
+	if eventID == "" {
+		eventID = transactionID.String()
+	}
+
+	if err := s.timeline.RecordEvent(ctx, entity, models.TimelineEvent{ID: eventID, Type: eventType, At: at}); err != nil {
+		log.Printf("Failed to record timeline event %s: %v", eventID, err)
+	}
+}
+
+// storedEvent returns the event payload as it should be persisted for backtesting
+// Transport-only fields such as the decision reply address are dropped
+func storedEvent(event models.Event) models.Event {
+	if _, ok := event[models.DecisionReplyToField]; !ok {
+		return event
+	}
+
+	stored := make(models.Event, len(event))
+	for key, value := range event {
+		if key != models.DecisionReplyToField {
+			stored[key] = value
+		}
+	}
+	return stored
+}
+
+// extractLocationFromEvent reads the event coordinates from common field names,
+// either at the top level or in a "location", "geo" or "coordinates" object
+// Returns nil unless both latitude and longitude are present
+func extractLocationFromEvent(event models.Event) *models.GeoPoint {
+	candidates := []models.Event{event}
+	for _, name := range []string{"location", "geo", "coordinates"} {
+		if nested, ok := event[name].(map[string]any); ok {
+			candidates = append(candidates, nested)
+		}
+	}
+
+	for _, candidate := range candidates {
+		lat, latOK := extractOptionalFloat64(candidate, "lat", "latitude")
+		lon, lonOK := extractOptionalFloat64(candidate, "lon", "lng", "longitude")
+		if latOK && lonOK {
+			return &models.GeoPoint{Latitude: lat, Longitude: lon}
+		}
+	}
+	return nil
+}
+
+func extractOptionalFloat64(event models.Event, fieldNames ...string) (float64, bool) {
+	for _, name := range fieldNames {
+		if val, ok := event[name]; ok {
+			if f, ok := toFloat64(val); ok {
+				return f, true
+			}
+		}
+	}
+	return 0, false
+}
+
 // Helper functions to extract values from generic event
 func extractStringFromEvent(event models.Event, fieldNames ...string) string {
 	for _, name := range fieldNames {
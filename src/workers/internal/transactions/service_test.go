@@ -3,9 +3,11 @@ package transactions
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/algo-shield/algo-shield/src/pkg/models"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -18,7 +20,7 @@ func Test_Service_ProcessTransaction_WhenValidEvent_ThenSavesTransaction(t *test
 	mockRepo := NewMockRepository(ctrl)
 	mockEvaluator := NewMockRuleEvaluator(ctrl)
 
-	service := NewService(mockRepo, mockEvaluator)
+	service := NewService(mockRepo, mockEvaluator, nil, nil, nil)
 
 	ctx := context.Background()
 	event := models.Event{
@@ -35,6 +37,7 @@ func Test_Service_ProcessTransaction_WhenValidEvent_ThenSavesTransaction(t *test
 
 	expectedResult := &models.TransactionResult{
 		Status:         "APPROVED",
+		RiskScore:      30,
 		ProcessingTime: 50,
 		MatchedRules:   []string{"rule-1"},
 	}
@@ -53,6 +56,7 @@ func Test_Service_ProcessTransaction_WhenValidEvent_ThenSavesTransaction(t *test
 			assert.Equal(t, "account-2", txn.Destination)
 			assert.Equal(t, "transfer", txn.Type)
 			assert.Equal(t, models.TransactionStatus("APPROVED"), txn.Status)
+			assert.Equal(t, 30, txn.RiskScore)
 			assert.NotNil(t, txn.ProcessedAt)
 			return nil
 		})
@@ -69,7 +73,7 @@ func Test_Service_ProcessTransaction_WhenEvaluatorFails_ThenReturnsError(t *test
 	mockRepo := NewMockRepository(ctrl)
 	mockEvaluator := NewMockRuleEvaluator(ctrl)
 
-	service := NewService(mockRepo, mockEvaluator)
+	service := NewService(mockRepo, mockEvaluator, nil, nil, nil)
 
 	ctx := context.Background()
 	event := models.Event{"external_id": "tx-123"}
@@ -91,7 +95,7 @@ func Test_Service_ProcessTransaction_WhenSaveFails_ThenReturnsError(t *testing.T
 	mockRepo := NewMockRepository(ctrl)
 	mockEvaluator := NewMockRuleEvaluator(ctrl)
 
-	service := NewService(mockRepo, mockEvaluator)
+	service := NewService(mockRepo, mockEvaluator, nil, nil, nil)
 
 	ctx := context.Background()
 	event := models.Event{"external_id": "tx-123"}
@@ -123,7 +127,7 @@ func Test_Service_ProcessTransaction_WhenAlternativeFieldNames_ThenExtractsCorre
 	mockRepo := NewMockRepository(ctrl)
 	mockEvaluator := NewMockRuleEvaluator(ctrl)
 
-	service := NewService(mockRepo, mockEvaluator)
+	service := NewService(mockRepo, mockEvaluator, nil, nil, nil)
 
 	ctx := context.Background()
 	event := models.Event{
@@ -170,7 +174,7 @@ func Test_Service_ProcessTransaction_WhenMissingFields_ThenUsesDefaults(t *testi
 	mockRepo := NewMockRepository(ctrl)
 	mockEvaluator := NewMockRuleEvaluator(ctrl)
 
-	service := NewService(mockRepo, mockEvaluator)
+	service := NewService(mockRepo, mockEvaluator, nil, nil, nil)
 
 	ctx := context.Background()
 	event := models.Event{}
@@ -301,3 +305,304 @@ func Test_ToFloat64_WithString_ThenReturnsFalse(t *testing.T) {
 	assert.False(t, ok)
 	assert.Equal(t, 0.0, value)
 }
+
+func Test_Service_ProcessTransaction_WhenDecisionRequested_ThenPublishesReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockEvaluator := NewMockRuleEvaluator(ctrl)
+	mockReplier := NewMockDecisionReplier(ctrl)
+	service := NewService(mockRepo, mockEvaluator, mockReplier, nil, nil)
+	ctx := context.Background()
+	event := models.Event{
+		"external_id":               "tx-123",
+		models.DecisionReplyToField: "corr-1",
+	}
+	result := &models.TransactionResult{Status: models.StatusInReview}
+	var savedID uuid.UUID
+
+	mockEvaluator.EXPECT().Evaluate(ctx, event).Return(result, nil)
+	mockRepo.EXPECT().SaveTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, txn *models.Transaction) error {
+			savedID = txn.ID
+			assert.Equal(t, models.Event{"external_id": "tx-123"}, txn.Event, "reply address must not be stored")
+			return nil
+		})
+	mockReplier.EXPECT().PublishDecision(ctx, "corr-1", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, correlationID string, r *models.TransactionResult) error {
+			assert.Equal(t, savedID, r.TransactionID)
+			assert.Equal(t, models.StatusInReview, r.Status)
+			return nil
+		})
+
+	err := service.ProcessTransaction(ctx, event)
+
+	require.NoError(t, err)
+}
+
+func Test_Service_ProcessTransaction_WhenSaveFails_ThenDoesNotPublishReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockEvaluator := NewMockRuleEvaluator(ctrl)
+	mockReplier := NewMockDecisionReplier(ctrl)
+	service := NewService(mockRepo, mockEvaluator, mockReplier, nil, nil)
+	ctx := context.Background()
+	event := models.Event{models.DecisionReplyToField: "corr-1"}
+
+	mockEvaluator.EXPECT().Evaluate(ctx, event).Return(&models.TransactionResult{}, nil)
+	mockRepo.EXPECT().SaveTransaction(ctx, gomock.Any()).Return(errors.New("db error"))
+
+	err := service.ProcessTransaction(ctx, event)
+
+	assert.Error(t, err)
+}
+
+func Test_Service_ProcessTransaction_WhenTransactionAlreadySaved_ThenSucceeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockEvaluator := NewMockRuleEvaluator(ctrl)
+	service := NewService(mockRepo, mockEvaluator, nil, nil, nil)
+	ctx := context.Background()
+	event := models.Event{"external_id": "tx-123"}
+
+	mockEvaluator.EXPECT().Evaluate(ctx, event).Return(&models.TransactionResult{}, nil)
+	mockRepo.EXPECT().SaveTransaction(ctx, gomock.Any()).Return(fmt.Errorf("%w: tx-123", ErrDuplicateTransaction))
+
+	err := service.ProcessTransaction(ctx, event)
+
+	require.NoError(t, err)
+}
+
+func Test_Service_ProcessTransaction_WhenDuplicateDecisionRequested_ThenRepliesWithSavedDecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockEvaluator := NewMockRuleEvaluator(ctrl)
+	mockReplier := NewMockDecisionReplier(ctrl)
+	service := NewService(mockRepo, mockEvaluator, mockReplier, nil, nil)
+	ctx := context.Background()
+	event := models.Event{
+		"external_id":               "tx-123",
+		models.DecisionReplyToField: "corr-1",
+	}
+	saved := &models.TransactionResult{TransactionID: uuid.New(), Status: models.StatusRejected, RiskScore: 90}
+
+	mockEvaluator.EXPECT().Evaluate(ctx, event).Return(&models.TransactionResult{Status: models.StatusApproved}, nil)
+	mockRepo.EXPECT().SaveTransaction(ctx, gomock.Any()).Return(fmt.Errorf("%w: tx-123", ErrDuplicateTransaction))
+	mockRepo.EXPECT().GetResultByExternalID(ctx, "tx-123").Return(saved, nil)
+	mockReplier.EXPECT().PublishDecision(ctx, "corr-1", saved).Return(nil)
+
+	err := service.ProcessTransaction(ctx, event)
+
+	require.NoError(t, err)
+}
+
+func Test_Service_ProcessTransaction_WhenTransactionRejected_ThenReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockEvaluator := NewMockRuleEvaluator(ctrl)
+	service := NewService(mockRepo, mockEvaluator, nil, nil, nil)
+	ctx := context.Background()
+	event := models.Event{"external_id": "tx-123"}
+
+	mockEvaluator.EXPECT().Evaluate(ctx, event).Return(&models.TransactionResult{}, nil)
+	mockRepo.EXPECT().SaveTransaction(ctx, gomock.Any()).Return(fmt.Errorf("%w: value too long", ErrRejectedTransaction))
+
+	err := service.ProcessTransaction(ctx, event)
+
+	assert.ErrorIs(t, err, ErrRejectedTransaction)
+}
+
+func Test_Service_ProcessTransaction_WhenHistoryRecorderSet_ThenRecordsBeforeSaving(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockEvaluator := NewMockRuleEvaluator(ctrl)
+	mockHistory := NewMockHistoryRecorder(ctrl)
+
+	service := NewService(mockRepo, mockEvaluator, nil, mockHistory, nil)
+
+	ctx := context.Background()
+	event := models.Event{"external_id": "tx-123", "amount": 250.0, "origin": "account-1"}
+
+	mockEvaluator.EXPECT().Evaluate(ctx, event).Return(&models.TransactionResult{Status: models.StatusApproved}, nil)
+	gomock.InOrder(
+		mockHistory.EXPECT().RecordTransaction(ctx, "account-1", "tx-123", 250.0, gomock.Any()).Return(nil),
+		mockRepo.EXPECT().SaveTransaction(ctx, gomock.Any()).Return(nil),
+	)
+
+	err := service.ProcessTransaction(ctx, event)
+
+	require.NoError(t, err)
+}
+
+func Test_Service_ProcessTransaction_WhenHistoryRecordFails_ThenStillSavesTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockEvaluator := NewMockRuleEvaluator(ctrl)
+	mockHistory := NewMockHistoryRecorder(ctrl)
+
+	service := NewService(mockRepo, mockEvaluator, nil, mockHistory, nil)
+
+	ctx := context.Background()
+	event := models.Event{"external_id": "tx-123", "amount": 250.0, "origin": "account-1"}
+
+	mockEvaluator.EXPECT().Evaluate(ctx, event).Return(&models.TransactionResult{Status: models.StatusApproved}, nil)
+	mockHistory.EXPECT().RecordTransaction(ctx, "account-1", "tx-123", 250.0, gomock.Any()).Return(errors.New("redis down"))
+	mockRepo.EXPECT().SaveTransaction(ctx, gomock.Any()).Return(nil)
+
+	err := service.ProcessTransaction(ctx, event)
+
+	require.NoError(t, err)
+}
+
+func Test_Service_ProcessTransaction_WhenRetriedAfterSaveFails_ThenRecordsSameHistoryEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockEvaluator := NewMockRuleEvaluator(ctrl)
+	mockHistory := NewMockHistoryRecorder(ctrl)
+
+	service := NewService(mockRepo, mockEvaluator, nil, mockHistory, nil)
+
+	ctx := context.Background()
+	event := models.Event{"external_id": "tx-123", "amount": 250.0, "origin": "account-1"}
+
+	mockEvaluator.EXPECT().Evaluate(ctx, event).Return(&models.TransactionResult{Status: models.StatusApproved}, nil).Times(2)
+	mockHistory.EXPECT().RecordTransaction(ctx, "account-1", "tx-123", 250.0, gomock.Any()).Return(nil).Times(2)
+	gomock.InOrder(
+		mockRepo.EXPECT().SaveTransaction(ctx, gomock.Any()).Return(errors.New("database unavailable")),
+		mockRepo.EXPECT().SaveTransaction(ctx, gomock.Any()).Return(nil),
+	)
+
+	require.Error(t, service.ProcessTransaction(ctx, event))
+	require.NoError(t, service.ProcessTransaction(ctx, event))
+}
+
+func Test_Service_DiscardTransaction_WhenHistoryRecorderSet_ThenRemovesHistoryEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHistory := NewMockHistoryRecorder(ctrl)
+	service := NewService(NewMockRepository(ctrl), NewMockRuleEvaluator(ctrl), nil, mockHistory, nil)
+
+	ctx := context.Background()
+	mockHistory.EXPECT().RemoveTransaction(ctx, "account-1", "tx-123", 250.0).Return(errors.New("redis down"))
+
+	service.DiscardTransaction(ctx, models.Event{"external_id": "tx-123", "amount": 250.0, "origin": "account-1"})
+}
+
+func Test_Service_DiscardTransaction_WhenEventHasNoExternalID_ThenRemovesNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No RemoveTransaction expectation: the entry cannot be found
+	service := NewService(NewMockRepository(ctrl), NewMockRuleEvaluator(ctrl), nil, NewMockHistoryRecorder(ctrl), nil)
+
+	service.DiscardTransaction(context.Background(), models.Event{"amount": 250.0, "origin": "account-1"})
+}
+
+func Test_AssignEventID_WhenEventHasNoID_ThenAssignsExternalIDOnce(t *testing.T) {
+	event := models.Event{"amount": 100.0}
+
+	AssignEventID(event)
+	assigned := event["external_id"]
+	AssignEventID(event)
+
+	assert.NoError(t, uuid.Validate(assigned.(string)))
+	assert.Equal(t, assigned, event["external_id"])
+}
+
+func Test_AssignEventID_WhenEventHasID_ThenKeepsIt(t *testing.T) {
+	event := models.Event{"event_id": "evt-1"}
+
+	AssignEventID(event)
+
+	assert.Equal(t, models.Event{"event_id": "evt-1"}, event)
+}
+
+func Test_Service_ProcessTransaction_WhenTimelineRecorderSet_ThenRecordsBeforeEvaluating(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockEvaluator := NewMockRuleEvaluator(ctrl)
+	mockTimeline := NewMockTimelineRecorder(ctrl)
+
+	service := NewService(mockRepo, mockEvaluator, nil, nil, mockTimeline)
+
+	ctx := context.Background()
+	event := models.Event{"event_id": "login-1", "event_type": "new_device_login", "user_id": "account-1"}
+
+	gomock.InOrder(
+		mockTimeline.EXPECT().RecordEvent(ctx, "account-1", gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, event models.TimelineEvent) error {
+				assert.Equal(t, "login-1", event.ID)
+				assert.Equal(t, "new_device_login", event.Type)
+				return nil
+			},
+		),
+		mockEvaluator.EXPECT().Evaluate(ctx, event).Return(&models.TransactionResult{Status: models.StatusApproved}, nil),
+		mockRepo.EXPECT().SaveTransaction(ctx, gomock.Any()).Return(nil),
+	)
+
+	err := service.ProcessTransaction(ctx, event)
+
+	require.NoError(t, err)
+}
+
+func Test_Service_ProcessTransaction_WhenTimelineRecordFailsOrEventUntyped_ThenStillProcesses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRepository(ctrl)
+	mockEvaluator := NewMockRuleEvaluator(ctrl)
+	mockTimeline := NewMockTimelineRecorder(ctrl)
+
+	service := NewService(mockRepo, mockEvaluator, nil, nil, mockTimeline)
+
+	ctx := context.Background()
+	typed := models.Event{"external_id": "tx-1", "type": "transfer", "origin": "account-1"}
+	untyped := models.Event{"external_id": "tx-2", "origin": "account-1"}
+
+	// Only the typed event is recorded, and its failure does not stop processing
+	mockTimeline.EXPECT().RecordEvent(ctx, "account-1", gomock.Any()).Return(errors.New("redis down"))
+	mockEvaluator.EXPECT().Evaluate(ctx, gomock.Any()).Return(&models.TransactionResult{Status: models.StatusApproved}, nil).Times(2)
+	mockRepo.EXPECT().SaveTransaction(ctx, gomock.Any()).Return(nil).Times(2)
+
+	require.NoError(t, service.ProcessTransaction(ctx, typed))
+	require.NoError(t, service.ProcessTransaction(ctx, untyped))
+}
+
+func Test_extractLocationFromEvent_WhenCoordinatesPresent_ThenReturnsLocation(t *testing.T) {
+	cases := map[string]models.Event{
+		"nested location": {"location": map[string]any{"lat": 48.85, "lon": 2.35}},
+		"nested geo":      {"geo": map[string]any{"latitude": 48.85, "longitude": 2.35}},
+		"top level":       {"lat": 48.85, "lng": 2.35},
+	}
+
+	for name, event := range cases {
+		t.Run(name, func(t *testing.T) {
+			location := extractLocationFromEvent(event)
+
+			require.NotNil(t, location)
+			assert.Equal(t, models.GeoPoint{Latitude: 48.85, Longitude: 2.35}, *location)
+		})
+	}
+}
+
+func Test_extractLocationFromEvent_WhenLongitudeMissing_ThenReturnsNil(t *testing.T) {
+	assert.Nil(t, extractLocationFromEvent(models.Event{"location": map[string]any{"lat": 48.85}}))
+}